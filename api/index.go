@@ -29,11 +29,14 @@ const (
 	CChainAlias = "c"
 )
 
+// indexResponse is served at the root of the v2 API
+type indexResponse struct {
+	NetworkID uint32                      `json:"network_id"`
+	Chains    map[string]models.ChainInfo `json:"chains"`
+}
+
 func newIndexResponse(networkID uint32, xChainID, cChainID, avaxAssetID ids.ID) ([]byte, error) {
-	return json.Marshal(&struct {
-		NetworkID uint32                      `json:"network_id"`
-		Chains    map[string]models.ChainInfo `json:"chains"`
-	}{
+	return json.Marshal(&indexResponse{
 		NetworkID: networkID,
 		Chains: map[string]models.ChainInfo{
 			xChainID.String(): {
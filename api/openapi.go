@@ -0,0 +1,396 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+)
+
+const (
+	OpenAPIVersion = "3.0.3"
+	OpenAPIPath    = "/openapi.json"
+
+	queryTag = "query"
+)
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	bigIntType        = reflect.TypeOf(big.Int{})
+	transactionSort   = reflect.TypeOf(params.TransactionSort(0))
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+
+	// queryDescriptions explain the query keys whose format isn't obvious
+	// from their type
+	queryDescriptions = map[string]string{
+		params.KeyStartTime:    "unix timestamp in seconds or RFC3339 time",
+		params.KeyEndTime:      "unix timestamp in seconds or RFC3339 time, defaults to now",
+		params.KeyIntervalSize: "minute, hour, day, week, month, year, all or a duration like 15m",
+		params.KeyLimit:        "number of results, at most 5000",
+		params.KeyAddress:      "bech32 address, with or without chain prefix",
+		params.KeySearchQuery:  "search term or prefix of the id",
+		params.KeyTime:         "unix timestamp in seconds or RFC3339 time, defaults to now",
+		params.KeyHeight:       "P-chain height, exclusive with time",
+		params.KeyStatus:       "pending or completed",
+		params.KeySpendable:    "only outputs which can be spent now",
+		params.KeyField:        "total, locked, unlocked, circulating or burned, returns the plain amount in whole tokens",
+	}
+)
+
+// OpenAPI is an OpenAPI 3 document
+type OpenAPI struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Servers    []OpenAPIServer                         `json:"servers"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+type OpenAPIComponents struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the subset of the OpenAPI schema object the generator emits
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// NewOpenAPI documents the v2 routes mounted at basePath
+func NewOpenAPI(basePath string) *OpenAPI {
+	g := &schemaGenerator{schemas: make(map[string]*Schema), names: make(map[string]reflect.Type)}
+	doc := &OpenAPI{
+		OpenAPI: OpenAPIVersion,
+		Info:    OpenAPIInfo{Title: "Magellan API", Version: "v2"},
+		Servers: []OpenAPIServer{{URL: basePath}},
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+	}
+
+	routes := append([]v2Route{
+		{method: http.MethodGet, path: "/", summary: "network and chains of the index", response: indexResponse{}},
+		{method: http.MethodGet, path: OpenAPIPath, summary: "this document", response: json.RawMessage{}},
+	}, v2Routes...)
+	for _, route := range routes {
+		specPath, op := g.operation(route)
+		if doc.Paths[specPath] == nil {
+			doc.Paths[specPath] = make(map[string]*OpenAPIOperation)
+		}
+		doc.Paths[specPath][strings.ToLower(route.method)] = op
+	}
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+func (g *schemaGenerator) operation(route v2Route) (string, *OpenAPIOperation) {
+	op := &OpenAPIOperation{
+		OperationID: operationID(route),
+		Summary:     route.summary,
+		Responses: map[string]*OpenAPIResponse{
+			"200": {
+				Description: "OK",
+				Content:     map[string]*OpenAPIMediaType{"application/json": {Schema: g.schema(reflect.TypeOf(route.response))}},
+			},
+			"default": {
+				Description: "error",
+				Content:     map[string]*OpenAPIMediaType{"application/json": {Schema: g.schema(reflect.TypeOf(ErrorResponse{}))}},
+			},
+		},
+	}
+
+	segments := strings.Split(route.path, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		name := strings.TrimPrefix(segment, ":")
+		segments[i] = "{" + name + "}"
+		op.Parameters = append(op.Parameters, &OpenAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	var query []*OpenAPIParameter
+	if route.params != nil {
+		query = queryParameters(reflect.TypeOf(route.params))
+	}
+
+	switch {
+	case route.body != nil:
+		op.Parameters = append(op.Parameters, query...)
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content:  map[string]*OpenAPIMediaType{"application/json": {Schema: g.schema(reflect.TypeOf(route.body))}},
+		}
+	case route.method == http.MethodPost && len(query) != 0:
+		// the api merges a json object of string arrays into the query
+		body := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for _, param := range query {
+			body.Properties[param.Name] = &Schema{
+				Type:        "array",
+				Description: param.Description,
+				Items:       &Schema{Type: "string"},
+			}
+		}
+		op.RequestBody = &OpenAPIRequestBody{
+			Content: map[string]*OpenAPIMediaType{"application/json": {Schema: body}},
+		}
+	default:
+		op.Parameters = append(op.Parameters, query...)
+	}
+	return strings.Join(segments, "/"), op
+}
+
+func operationID(route v2Route) string {
+	if route.path == "/" {
+		return "index"
+	}
+	var b strings.Builder
+	b.WriteString(strings.ToLower(route.method))
+	for _, segment := range strings.Split(route.path, "/") {
+		segment = strings.TrimSuffix(strings.TrimPrefix(segment, ":"), path.Ext(segment))
+		if segment == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return b.String()
+}
+
+// queryParameters documents the fields of a params struct with a query tag,
+// the untagged struct fields like ListParams are walked into
+func queryParameters(t reflect.Type) []*OpenAPIParameter {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var res []*OpenAPIParameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup(queryTag)
+		if !ok {
+			if field.Type.Kind() == reflect.Struct {
+				res = append(res, queryParameters(field.Type)...)
+			}
+			continue
+		}
+		param := &OpenAPIParameter{
+			Name:        name,
+			In:          "query",
+			Description: queryDescriptions[name],
+			Schema:      querySchema(field.Type),
+		}
+		if param.Schema.Type == "array" {
+			explode := true
+			param.Explode = &explode
+		}
+		res = append(res, param)
+	}
+	return res
+}
+
+func querySchema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType, durationType:
+		return &Schema{Type: "string"}
+	case bigIntType:
+		return &Schema{Type: "integer"}
+	case transactionSort:
+		return &Schema{
+			Type: "string",
+			Enum: []string{params.TransactionSortTimestampAscStr, params.TransactionSortTimestampDescStr},
+		}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: querySchema(t.Elem())}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// schemaGenerator derives the schemas of the responses from their json
+// encoding, named structs become components
+type schemaGenerator struct {
+	schemas map[string]*Schema
+	names   map[string]reflect.Type
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case bigIntType:
+		return &Schema{Type: "integer"}
+	case rawMessageType:
+		return &Schema{}
+	case reflect.TypeOf(models.Address("")):
+		return &Schema{Type: "string", Description: "bech32 address"}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.component(t)}
+	default:
+		// interfaces hold any value
+		return &Schema{}
+	}
+}
+
+// component registers the schema of a named struct once, it is added before
+// the fields are walked, so recursive types terminate
+func (g *schemaGenerator) component(t reflect.Type) string {
+	name := t.Name()
+	if other, ok := g.names[name]; ok && other != t {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	if _, ok := g.schemas[name]; ok {
+		return name
+	}
+	g.names[name] = t
+	schema := &Schema{}
+	g.schemas[name] = schema
+	*schema = *g.object(t)
+	return name
+}
+
+func (g *schemaGenerator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.fields(t, schema.Properties)
+	return schema
+}
+
+// fields adds the json fields of the struct, the fields of embedded structs
+// are inlined like encoding/json does
+func (g *schemaGenerator) fields(t reflect.Type, props map[string]*Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.fields(ft, props)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		props[name] = g.schema(field.Type)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chain4travel/magellan/services/indexes/params"
+	"github.com/gocraft/web"
+)
+
+func specPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimPrefix(segment, ":") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	router := web.New(Context{})
+	AddV2Routes(&Context{}, router, "/v2", []byte("{}"), nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2"+OpenAPIPath, nil))
+	if w.Code != 200 {
+		t.Fatal("unexpected status", w.Code)
+	}
+	doc := &OpenAPI{}
+	if err := json.Unmarshal(w.Body.Bytes(), doc); err != nil {
+		t.Fatal("decode fail", err)
+	}
+	if doc.OpenAPI != OpenAPIVersion || doc.Servers[0].URL != "/v2" {
+		t.Fatal("unexpected document", doc.OpenAPI, doc.Servers)
+	}
+
+	for _, route := range v2Routes {
+		if route.summary == "" || route.response == nil {
+			t.Errorf("%s %s: route lacks a summary or response", route.method, route.path)
+			continue
+		}
+		op := doc.Paths[specPath(route.path)][strings.ToLower(route.method)]
+		if op == nil {
+			t.Errorf("%s %s: missing in the specification", route.method, route.path)
+			continue
+		}
+		if op.Responses["200"].Content["application/json"].Schema == nil {
+			t.Errorf("%s %s: response has no schema", route.method, route.path)
+		}
+	}
+}
+
+// TestOpenAPIRouteCoverage fails when a route is added to the router in
+// v2.go without an entry in the route table the specification is built of
+func TestOpenAPIRouteCoverage(t *testing.T) {
+	doc := NewOpenAPI("/v2")
+
+	file, err := parser.ParseFile(token.NewFileSet(), "v2.go", nil, 0)
+	if err != nil {
+		t.Fatal("parse fail", err)
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		method := strings.ToLower(sel.Sel.Name)
+		switch method {
+		case "get", "post", "put", "delete", "patch":
+		default:
+			return true
+		}
+		var path string
+		switch arg := call.Args[0].(type) {
+		case *ast.BasicLit:
+			path, _ = strconv.Unquote(arg.Value)
+		case *ast.Ident:
+			if arg.Name == "OpenAPIPath" {
+				path = OpenAPIPath
+			}
+		}
+		if path == "" {
+			// routes of the table
+			return true
+		}
+		if doc.Paths[specPath(path)][method] == nil {
+			t.Errorf("%s %s: missing in the specification", sel.Sel.Name, path)
+		}
+		return true
+	})
+}
+
+func TestOpenAPIQueryKeys(t *testing.T) {
+	keys := map[string]bool{}
+	for _, key := range []string{
+		params.KeyID, params.KeyChainID, params.KeyAddress, params.KeyToAddress,
+		params.KeyFromAddress, params.KeyBlockStart, params.KeyBlockEnd, params.KeyHash,
+		params.KeyAlias, params.KeyAssetID, params.KeySearchQuery, params.KeySortBy,
+		params.KeyLimit, params.KeyOffset, params.KeySpent, params.KeyStartTime,
+		params.KeyEndTime, params.KeyIntervalSize, params.KeyDisableCount,
+		params.KeyDisableGenesis, params.KeyOutputOutputType, params.KeyOutputGroupID,
+		params.KeyTransactionID, params.KeyRaw, params.KeyTime, params.KeyHeight,
+		params.KeyNodeID, params.KeySubnetID, params.KeyStatus, params.KeySpendable,
+		params.KeyField,
+	} {
+		keys[key] = true
+	}
+
+	for _, route := range v2Routes {
+		if route.params == nil {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, param := range queryParameters(reflect.TypeOf(route.params)) {
+			if !keys[param.Name] {
+				t.Errorf("%s %s: unknown query key %s", route.method, route.path, param.Name)
+			}
+			if seen[param.Name] {
+				t.Errorf("%s %s: duplicate query key %s", route.method, route.path, param.Name)
+			}
+			seen[param.Name] = true
+		}
+	}
+
+	list := queryParameters(reflect.TypeOf(&params.ListTransactionsParams{}))
+	types := map[string]string{}
+	for _, param := range list {
+		types[param.Name] = param.Schema.Type
+	}
+	if types[params.KeyLimit] != "integer" || types[params.KeyChainID] != "array" ||
+		types[params.KeyDisableGenesis] != "boolean" || types[params.KeyStartTime] != "string" {
+		t.Fatal("unexpected query types", types)
+	}
+}
@@ -33,13 +33,15 @@ import (
 
 // Server is an HTTP server configured with various magellan APIs
 type Server struct {
-	sc     *servicesctrl.Control
-	server *http.Server
+	sc         *servicesctrl.Control
+	conf       cfg.Config
+	server     *http.Server
+	avaxReader *avax.Reader
 }
 
 // NewServer creates a new *Server based on the given config
 func NewServer(sc *servicesctrl.Control, conf cfg.Config) (*Server, error) {
-	router, err := newRouter(sc, conf)
+	router, avaxReader, err := newRouter(sc, conf)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +50,8 @@ func NewServer(sc *servicesctrl.Control, conf cfg.Config) (*Server, error) {
 	models.SetBech32HRP(conf.NetworkID)
 
 	return &Server{
-		sc: sc,
+		sc:   sc,
+		conf: conf,
 		server: &http.Server{
 			Addr:              conf.ListenAddr,
 			ReadTimeout:       5 * time.Second,
@@ -57,6 +60,7 @@ func NewServer(sc *servicesctrl.Control, conf cfg.Config) (*Server, error) {
 			Handler:           router,
 			ReadHeaderTimeout: 5 * time.Second,
 		},
+		avaxReader: avaxReader,
 	}, err
 }
 
@@ -76,7 +80,17 @@ func (s *Server) Close() error {
 	return s.server.Shutdown(ctx)
 }
 
-func newRouter(sc *servicesctrl.Control, conf cfg.Config) (*web.Router, error) {
+// AddChain serves the transactions of a chain found by the chain discovery
+func (s *Server) AddChain(chain cfg.Chain) error {
+	consumer, err := consumers.IndexerConsumer(s.conf.NetworkID, chain.VMType, chain.ID, &s.conf)
+	if err != nil {
+		return err
+	}
+	s.avaxReader.AddChain(chain.ID, consumer)
+	return nil
+}
+
+func newRouter(sc *servicesctrl.Control, conf cfg.Config) (*web.Router, *avax.Reader, error) {
 	sc.Log.Info("creating new router",
 		zap.Stringer("chainID", sc.GenesisContainer.XChainID),
 	)
@@ -97,7 +111,7 @@ func newRouter(sc *servicesctrl.Control, conf cfg.Config) (*web.Router, error) {
 		sc.GenesisContainer.AvaxAssetID,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	legacyIndexResponse, err := newLegacyIndexResponse(
@@ -106,29 +120,28 @@ func newRouter(sc *servicesctrl.Control, conf cfg.Config) (*web.Router, error) {
 		sc.GenesisContainer.AvaxAssetID,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Create connections and readers
 	connections, err := sc.DatabaseRO()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	cache := caching.NewCache()
-	delayCache := caching.NewDelayCache(cache)
+	delayCache := caching.NewDelayCache(sc.APICache)
 
 	consumersmap := make(map[string]services.Consumer)
 	for chid, chain := range conf.Chains {
 		consumer, err := consumers.IndexerConsumer(conf.NetworkID, chain.VMType, chid, &conf)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		consumersmap[chid] = consumer
 	}
 	avaxReader, err := avax.NewReader(conf.NetworkID, connections, consumersmap, sc)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	ctx := Context{sc: sc}
@@ -158,5 +171,5 @@ func newRouter(sc *servicesctrl.Control, conf cfg.Config) (*web.Router, error) {
 	AddV2Routes(&ctx, router, "/x", legacyIndexResponse, &sc.GenesisContainer.XChainID)
 	AddV2Routes(&ctx, router, "/X", legacyIndexResponse, &sc.GenesisContainer.XChainID)
 
-	return router, nil
+	return router, avaxReader, nil
 }
@@ -18,6 +18,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/chain4travel/magellan/caching"
 	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/models"
 	"github.com/chain4travel/magellan/services/indexes/params"
 	"github.com/chain4travel/magellan/utils"
 	"github.com/gocraft/web"
@@ -95,7 +97,7 @@ func AddV2Routes(ctx *Context, router *web.Router, path string, indexBytes []byt
 	utils.Prometheus.CounterInit(MetricSearchMillis, MetricSearchMillis)
 
 	v2ctx := V2Context{Context: ctx}
-	router.Subrouter(v2ctx, path).
+	subrouter := router.Subrouter(v2ctx, path).
 		Get("/", func(c *V2Context, resp web.ResponseWriter, _ *web.Request) {
 			if _, err := resp.Write(indexBytes); err != nil {
 				ctx.sc.Log.Warn("response write failed",
@@ -112,48 +114,103 @@ func AddV2Routes(ctx *Context, router *web.Router, path string, indexBytes []byt
 				c.version = 1
 			}
 			next(w, r)
-		}).
-		Get("/search", (*V2Context).Search).
-		Get("/aggregates", (*V2Context).Aggregate).
-		Get("/txfeeAggregates", (*V2Context).TxfeeAggregate).
-		Get("/transactions/aggregates", (*V2Context).Aggregate).
-		Get("/addressChains", (*V2Context).AddressChains).
-		Post("/addressChains", (*V2Context).AddressChainsPost).
-		Post("/validatorsInfo", (*V2Context).ValidatorsInfo).
-		Get("/activeAddresses", (*V2Context).ActiveAddresses).
-		Get("/uniqueAddresses", (*V2Context).UniqueAddresses).
-		Get("/averageBlockSize", (*V2Context).AverageBlockSize).
-		Get("/dailyTransactions", (*V2Context).DailyTransactions).
-		Get("/dailyGasUsed", (*V2Context).DailyGasUsed).
-		Get("/avgGasPriceUsed", (*V2Context).AvgGasPriceUsed).
-		Get("/dailyTokenTransfer", (*V2Context).DailyTokenTransfer).
-		Get("/dailyEmissions", (*V2Context).DailyEmissions).
-		Get("/networkEmissions", (*V2Context).NetworkEmissions).
-		Get("/transactionEmissions", (*V2Context).TransactionEmissions).
-		Get("/countryEmissions", (*V2Context).CountryEmissions).
-
-		// List and Get routes
-		Get("/transactions", (*V2Context).ListTransactions).
-		Post("/transactions", (*V2Context).ListTransactionsPost).
-		Get("/transactions/:id", (*V2Context).GetTransaction).
-		Get("/addresses", (*V2Context).ListAddresses).
-		Get("/addresses/:id", (*V2Context).GetAddress).
-		Get("/outputs", (*V2Context).ListOutputs).
-		Get("/outputs/:id", (*V2Context).GetOutput).
-		Get("/assets", (*V2Context).ListAssets).
-		Get("/assets/:id", (*V2Context).GetAsset).
-		Get("/atxdata/:id", (*V2Context).ATxData).
-		Get("/ptxdata/:id", (*V2Context).PTxData).
-		Get("/ctxdata/:id", (*V2Context).CTxData).
-		Get("/cblocks", (*V2Context).ListCBlocks).
-		Get("/ctransactions", (*V2Context).ListCTransactions).
-		Get("/cacheaddresscounts", (*V2Context).CacheAddressCounts).
-		Get("/cachetxscounts", (*V2Context).CacheTxCounts).
-		Get("/cacheassets", (*V2Context).CacheAssets).
-		Get("/cacheassetaggregates", (*V2Context).CacheAssetAggregates).
-		Get("/cacheaggregates/:id", (*V2Context).CacheAggregates).
-		Get("/multisigalias/:owners", (*V2Context).GetMultisigAlias).
-		Post("/rewards", (*V2Context).GetRewardPost)
+		})
+
+	// the specification describes the v2 responses only
+	if chainID == nil {
+		specBytes, err := json.Marshal(NewOpenAPI(path))
+		if err != nil {
+			ctx.sc.Log.Warn("openapi specification failed",
+				zap.Error(err),
+			)
+		}
+		subrouter.Get(OpenAPIPath, func(c *V2Context, resp web.ResponseWriter, _ *web.Request) {
+			WriteJSON(resp, specBytes)
+		})
+	}
+
+	for _, route := range v2Routes {
+		switch route.method {
+		case http.MethodGet:
+			subrouter.Get(route.path, route.handler)
+		case http.MethodPost:
+			subrouter.Post(route.path, route.handler)
+		}
+	}
+}
+
+// v2Route is a route of the v2 API. The params, body and response are zero
+// values of the types the handler parses and returns, they document the route
+// in the OpenAPI specification.
+type v2Route struct {
+	method   string
+	path     string
+	handler  interface{}
+	summary  string
+	params   params.Param
+	body     interface{}
+	response interface{}
+}
+
+// rewardsRequest is the body of the rewards route
+type rewardsRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+var v2Routes = []v2Route{
+	{http.MethodGet, "/search", (*V2Context).Search, "search transactions, addresses, outputs and assets", &params.SearchParams{}, nil, &models.SearchResults{}},
+	{http.MethodGet, "/aggregates", (*V2Context).Aggregate, "aggregated transaction volume", &params.AggregateParams{}, nil, &models.AggregatesHistogram{}},
+	{http.MethodGet, "/txfeeAggregates", (*V2Context).TxfeeAggregate, "aggregated transaction fees", &params.TxfeeAggregateParams{}, nil, &models.TxfeeAggregatesHistogram{}},
+	{http.MethodGet, "/fees/types", (*V2Context).FeeStats, "fee statistics per chain and transaction type", &params.FeeStatsParams{}, nil, &models.FeeStatsList{}},
+	{http.MethodGet, "/fees/burned", (*V2Context).BurnedFees, "burned fees over time and since genesis", &params.BurnedFeesParams{}, nil, &models.BurnedFeesHistogram{}},
+	{http.MethodGet, "/fees/cchain", (*V2Context).CChainFees, "C-chain fees split into base fee and tip", &params.FeeHistoryParams{}, nil, &models.CChainFeesHistogram{}},
+	{http.MethodGet, "/supply", (*V2Context).Supply, "current total, locked, unlocked and burned supply of an asset", &params.SupplyParams{}, nil, &models.Supply{}},
+	{http.MethodGet, "/supply/history", (*V2Context).SupplyHistory, "daily supply of an asset", &params.SupplyHistoryParams{}, nil, &models.SupplyHistory{}},
+	{http.MethodGet, "/transactions/aggregates", (*V2Context).Aggregate, "aggregated transaction volume", &params.AggregateParams{}, nil, &models.AggregatesHistogram{}},
+	{http.MethodGet, "/addressChains", (*V2Context).AddressChains, "chains the addresses are used on", &params.AddressChainsParams{}, nil, &models.AddressChains{}},
+	{http.MethodPost, "/addressChains", (*V2Context).AddressChainsPost, "chains the addresses are used on", &params.AddressChainsParams{}, nil, &models.AddressChains{}},
+	{http.MethodPost, "/validatorsInfo", (*V2Context).ValidatorsInfo, "validators with their location", nil, nil, &models.GeoIPValidators{}},
+	{http.MethodGet, "/subnets", (*V2Context).ListSubnets, "list subnets with their owners, chains and validators", &params.ListSubnetsParams{}, nil, &models.SubnetList{}},
+	{http.MethodGet, "/blockchains", (*V2Context).ListBlockchains, "list blockchains created on the P-chain", &params.ListBlockchainsParams{}, nil, &models.BlockchainList{}},
+	{http.MethodGet, "/utxos", (*V2Context).ListUTXOs, "list unspent outputs of addresses with their lock state", &params.ListUTXOsParams{}, nil, &models.UTXOList{}},
+	{http.MethodGet, "/crosschain", (*V2Context).ListCrossChainTransfers, "list outputs exported to another chain and their imports", &params.ListCrossChainTransfersParams{}, nil, &models.CrossChainTransferList{}},
+	{http.MethodGet, "/validators/at", (*V2Context).ValidatorsAt, "validator set, total stake and node weights at a time or P-chain height", &params.ValidatorsAtParams{}, nil, &models.ValidatorSet{}},
+	{http.MethodGet, "/activeAddresses", (*V2Context).ActiveAddresses, "active addresses per day", &params.StatisticsParams{}, nil, &models.AddressStruct{}},
+	{http.MethodGet, "/uniqueAddresses", (*V2Context).UniqueAddresses, "unique addresses per day", &params.StatisticsParams{}, nil, &models.AddressStruct{}},
+	{http.MethodGet, "/averageBlockSize", (*V2Context).AverageBlockSize, "average C-chain block size per day", &params.StatisticsParams{}, nil, []*models.AverageBlockSize{}},
+	{http.MethodGet, "/dailyTransactions", (*V2Context).DailyTransactions, "C-chain transactions per day", &params.StatisticsParams{}, nil, &models.StatisticsStruct{}},
+	{http.MethodGet, "/dailyGasUsed", (*V2Context).DailyGasUsed, "C-chain gas used per day", &params.StatisticsParams{}, nil, &models.StatisticsStruct{}},
+	{http.MethodGet, "/avgGasPriceUsed", (*V2Context).AvgGasPriceUsed, "average C-chain gas price per day", &params.StatisticsParams{}, nil, &models.StatisticsStruct{}},
+	{http.MethodGet, "/dailyTokenTransfer", (*V2Context).DailyTokenTransfer, "C-chain token transfers per day", &params.StatisticsParams{}, nil, []*models.TransactionsPerDate{}},
+	{http.MethodGet, "/dailyEmissions", (*V2Context).DailyEmissions, "emissions per day", &params.EmissionsParams{}, nil, &models.Emissions{}},
+	{http.MethodGet, "/networkEmissions", (*V2Context).NetworkEmissions, "emissions of the network", &params.EmissionsParams{}, nil, &models.Emissions{}},
+	{http.MethodGet, "/transactionEmissions", (*V2Context).TransactionEmissions, "emissions per transaction", &params.EmissionsParams{}, nil, &models.Emissions{}},
+	{http.MethodGet, "/countryEmissions", (*V2Context).CountryEmissions, "emissions per country", &params.EmissionsParams{}, nil, &models.Emissions{}},
+
+	// List and Get routes
+	{http.MethodGet, "/transactions", (*V2Context).ListTransactions, "list transactions", &params.ListTransactionsParams{}, nil, &models.TransactionList{}},
+	{http.MethodPost, "/transactions", (*V2Context).ListTransactionsPost, "list transactions", &params.ListTransactionsParams{}, nil, &models.TransactionList{}},
+	{http.MethodGet, "/transactions/:id", (*V2Context).GetTransaction, "get a transaction", nil, nil, &models.Transaction{}},
+	{http.MethodGet, "/addresses", (*V2Context).ListAddresses, "list addresses", &params.ListAddressesParams{}, nil, &models.AddressList{}},
+	{http.MethodGet, "/addresses/:id", (*V2Context).GetAddress, "get an address", &params.ListAddressesParams{}, nil, &models.AddressInfo{}},
+	{http.MethodGet, "/outputs", (*V2Context).ListOutputs, "list outputs", &params.ListOutputsParams{}, nil, &models.OutputList{}},
+	{http.MethodGet, "/outputs/:id", (*V2Context).GetOutput, "get an output", nil, nil, &models.Output{}},
+	{http.MethodGet, "/assets", (*V2Context).ListAssets, "list assets", &params.ListAssetsParams{}, nil, &models.AssetList{}},
+	{http.MethodGet, "/assets/:id", (*V2Context).GetAsset, "get an asset by id or alias", nil, nil, &models.Asset{}},
+	{http.MethodGet, "/assets/:id/holders", (*V2Context).AssetHolders, "addresses holding an asset, largest balance first", &params.AssetHoldersParams{}, nil, &models.AssetHolderList{}},
+	{http.MethodGet, "/assets/:id/holders/history", (*V2Context).HolderCountHistory, "daily number of addresses holding an asset", &params.HolderCountHistoryParams{}, nil, &models.HolderCountHistory{}},
+	{http.MethodGet, "/atxdata/:id", (*V2Context).ATxData, "raw data of a X-chain transaction", nil, nil, json.RawMessage{}},
+	{http.MethodGet, "/ptxdata/:id", (*V2Context).PTxData, "raw data of a P-chain block by height or id", nil, nil, json.RawMessage{}},
+	{http.MethodGet, "/ctxdata/:id", (*V2Context).CTxData, "raw data of a C-chain block by number", nil, nil, json.RawMessage{}},
+	{http.MethodGet, "/cblocks", (*V2Context).ListCBlocks, "list C-chain blocks with their transactions", &params.ListCBlocksParams{}, nil, &models.CBlockList{}},
+	{http.MethodGet, "/ctransactions", (*V2Context).ListCTransactions, "list C-chain transactions", &params.ListCTransactionsParams{}, nil, &models.CTransactionList{}},
+	{http.MethodGet, "/cacheaddresscounts", (*V2Context).CacheAddressCounts, "cached address counts per chain", nil, nil, []*models.ChainCounts{}},
+	{http.MethodGet, "/cachetxscounts", (*V2Context).CacheTxCounts, "cached transaction counts per chain", nil, nil, []*models.ChainCounts{}},
+	{http.MethodGet, "/cacheassets", (*V2Context).CacheAssets, "cached assets", nil, nil, []*models.Asset{}},
+	{http.MethodGet, "/cacheassetaggregates", (*V2Context).CacheAssetAggregates, "cached asset aggregates", nil, nil, []*models.AssetAggregate{}},
+	{http.MethodGet, "/cacheaggregates/:id", (*V2Context).CacheAggregates, "cached aggregates by tag", nil, nil, &models.AggregatesHistogram{}},
+	{http.MethodGet, "/multisigalias/:owners", (*V2Context).GetMultisigAlias, "multisig aliases of comma separated bech32 owners", nil, nil, &models.MultisigAliasList{}},
+	{http.MethodPost, "/rewards", (*V2Context).GetRewardPost, "rewards of the addresses", nil, &rewardsRequest{}, &[]models.Reward{}},
 }
 
 // AVAX
@@ -174,10 +231,134 @@ func (c *V2Context) ValidatorsInfo(w web.ResponseWriter, r *web.Request) {
 		return
 	}
 
+	geoIP := c.sc.GeoIP()
 	c.WriteCacheable(w, caching.Cacheable{
 		Key: c.cacheKeyForParams("geoIPValidatorsInfo", p),
 		CacheableFn: func(ctx context.Context) (interface{}, error) {
-			return utils.GetValidatorsGeoIPInfo(p.RPC, &c.sc.Services.GeoIP, c.sc.Logger())
+			return utils.GetValidatorsGeoIPInfo(p.RPC, &geoIP, c.sc.Logger())
+		},
+	})
+}
+
+func (c *V2Context) ValidatorsAt(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.ValidatorsAtParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+	if p.Time.IsZero() && p.Height == nil {
+		p.Time = time.Now().UTC().Truncate(time.Second)
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		TTL: 5 * time.Second,
+		Key: c.cacheKeyForParams("validators_at", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.ValidatorsAt(ctx, p)
+		},
+	})
+}
+
+func (c *V2Context) ListSubnets(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.ListSubnetsParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		TTL: 5 * time.Second,
+		Key: c.cacheKeyForParams("list_subnets", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.ListSubnets(ctx, p)
+		},
+	})
+}
+
+func (c *V2Context) ListBlockchains(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.ListBlockchainsParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		TTL: 5 * time.Second,
+		Key: c.cacheKeyForParams("list_blockchains", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.ListBlockchains(ctx, p)
+		},
+	})
+}
+
+func (c *V2Context) ListUTXOs(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.ListUTXOsParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		TTL: 5 * time.Second,
+		Key: c.cacheKeyForParams("list_utxos", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.ListUTXOs(ctx, p)
+		},
+	})
+}
+
+func (c *V2Context) ListCrossChainTransfers(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.ListCrossChainTransfersParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		TTL: 5 * time.Second,
+		Key: c.cacheKeyForParams("list_cross_chain_transfers", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.ListCrossChainTransfers(ctx, p)
 		},
 	})
 }
@@ -372,10 +553,10 @@ func (c *V2Context) DailyEmissions(w web.ResponseWriter, r *web.Request) {
 	}
 	key := fmt.Sprintf("Daily Emissions %s", p.ListParams.EndTime)
 	c.WriteCacheable(w, caching.Cacheable{
-		TTL: time.Duration(c.sc.ServicesCfg.CacheEmissionsInterval) * time.Hour,
+		TTL: c.sc.CacheEmissionsInterval(),
 		Key: c.cacheKeyForParams(key, p),
 		CacheableFn: func(ctx context.Context) (interface{}, error) {
-			return utils.GetDailyEmissions(p.ListParams.StartTime, p.ListParams.EndTime, c.sc.Services.InmutableInsights, c.sc.ServicesCfg.CaminoNode), nil
+			return utils.GetDailyEmissions(p.ListParams.StartTime, p.ListParams.EndTime, c.sc.InmutableInsights(), c.sc.ServicesCfg.CaminoNode), nil
 		},
 	})
 }
@@ -388,10 +569,10 @@ func (c *V2Context) CountryEmissions(w web.ResponseWriter, r *web.Request) {
 	}
 	key := fmt.Sprintf("Country Emissions %s", p.ListParams.EndTime)
 	c.WriteCacheable(w, caching.Cacheable{
-		TTL: time.Duration(c.sc.ServicesCfg.CacheEmissionsInterval) * time.Hour,
+		TTL: c.sc.CacheEmissionsInterval(),
 		Key: c.cacheKeyForParams(key, p),
 		CacheableFn: func(ctx context.Context) (interface{}, error) {
-			return utils.GetCountryEmissions(p.ListParams.StartTime, p.ListParams.EndTime, c.sc.Services.InmutableInsights, c.sc.ServicesCfg.CaminoNode)
+			return utils.GetCountryEmissions(p.ListParams.StartTime, p.ListParams.EndTime, c.sc.InmutableInsights(), c.sc.ServicesCfg.CaminoNode)
 		},
 	})
 }
@@ -405,10 +586,10 @@ func (c *V2Context) NetworkEmissions(w web.ResponseWriter, r *web.Request) {
 
 	key := fmt.Sprintf("Network Emissions %s", p.ListParams.EndTime)
 	c.WriteCacheable(w, caching.Cacheable{
-		TTL: time.Duration(c.sc.ServicesCfg.CacheEmissionsInterval) * time.Hour,
+		TTL: c.sc.CacheEmissionsInterval(),
 		Key: c.cacheKeyForParams(key, p),
 		CacheableFn: func(ctx context.Context) (interface{}, error) {
-			return utils.GetNetworkEmissions(p.ListParams.StartTime, p.ListParams.EndTime, c.sc.Services.InmutableInsights, c.sc.ServicesCfg.CaminoNode)
+			return utils.GetNetworkEmissions(p.ListParams.StartTime, p.ListParams.EndTime, c.sc.InmutableInsights(), c.sc.ServicesCfg.CaminoNode)
 		},
 	})
 }
@@ -421,10 +602,10 @@ func (c *V2Context) TransactionEmissions(w web.ResponseWriter, r *web.Request) {
 	}
 	key := fmt.Sprintf("Transaction Emissions %s", p.ListParams.EndTime)
 	c.WriteCacheable(w, caching.Cacheable{
-		TTL: time.Duration(c.sc.ServicesCfg.CacheEmissionsInterval) * time.Hour,
+		TTL: c.sc.CacheEmissionsInterval(),
 		Key: c.cacheKeyForParams(key, p),
 		CacheableFn: func(ctx context.Context) (interface{}, error) {
-			return utils.GetNetworkEmissionsPerTransaction(p.ListParams.StartTime, p.ListParams.EndTime, c.sc.Services.InmutableInsights, c.sc.ServicesCfg.CaminoNode)
+			return utils.GetNetworkEmissionsPerTransaction(p.ListParams.StartTime, p.ListParams.EndTime, c.sc.InmutableInsights(), c.sc.ServicesCfg.CaminoNode)
 		},
 	})
 }
@@ -478,7 +659,137 @@ func (c *V2Context) TxfeeAggregate(w web.ResponseWriter, r *web.Request) {
 	c.WriteCacheable(w, caching.Cacheable{
 		Key: c.cacheKeyForParams("aggregate_txfee", p),
 		CacheableFn: func(ctx context.Context) (interface{}, error) {
-			return c.avaxReader.TxfeeAggregate(c.sc.AggregatesCache, p)
+			return c.avaxReader.TxfeeAggregate(ctx, p)
+		},
+	})
+}
+
+func (c *V2Context) FeeStats(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.FeeStatsParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+	p.ChainIDs = params.ForValueChainID(c.chainID, p.ChainIDs)
+
+	c.WriteCacheable(w, caching.Cacheable{
+		Key: c.cacheKeyForParams("fee_stats", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.FeeStats(ctx, p)
+		},
+	})
+}
+
+func (c *V2Context) BurnedFees(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.BurnedFeesParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	p.ChainIDs = params.ForValueChainID(c.chainID, p.ChainIDs)
+	if len(p.ChainIDs) == 0 {
+		c.WriteErr(w, 400, fmt.Errorf("chainID is required"))
+		return
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		Key: c.cacheKeyForParams("burned_fees", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.BurnedFees(ctx, p)
+		},
+	})
+}
+
+func (c *V2Context) CChainFees(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.FeeHistoryParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		Key: c.cacheKeyForParams("cchain_fees", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.CChainFees(ctx, p)
+		},
+	})
+}
+
+func (c *V2Context) Supply(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.SupplyParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		TTL: 5 * time.Minute,
+		Key: c.cacheKeyForParams("supply", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			supply, err := c.avaxReader.Supply(ctx, p, c.avaxAssetID)
+			if err != nil || p.Field == "" {
+				return supply, err
+			}
+			// aggregators read a plain number of whole tokens
+			amount, _ := supply.Field(p.Field)
+			return amount.Denominated(supply.Denomination), nil
+		},
+	})
+}
+
+func (c *V2Context) SupplyHistory(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.SupplyHistoryParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		TTL: 5 * time.Minute,
+		Key: c.cacheKeyForParams("supply_history", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.SupplyHistory(ctx, p, c.avaxAssetID)
 		},
 	})
 }
@@ -509,7 +820,7 @@ func (c *V2Context) Aggregate(w web.ResponseWriter, r *web.Request) {
 	c.WriteCacheable(w, caching.Cacheable{
 		Key: c.cacheKeyForParams("aggregate", p),
 		CacheableFn: func(ctx context.Context) (interface{}, error) {
-			return c.avaxReader.Aggregate(c.sc.AggregatesCache, p)
+			return c.avaxReader.Aggregate(ctx, p)
 		},
 	})
 }
@@ -985,6 +1296,74 @@ func (c *V2Context) GetAsset(w web.ResponseWriter, r *web.Request) {
 	})
 }
 
+func (c *V2Context) AssetHolders(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+		utils.NewCounterObserveMillisCollect(MetricAssetMillis),
+		utils.NewCounterIncCollect(MetricAssetCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	// the balances are only kept by the balance manager of the reader feature
+	if !c.sc.IsAccumulateBalanceReader() {
+		c.WriteErr(w, 501, fmt.Errorf("asset holders require the accumulate_balance_reader feature"))
+		return
+	}
+
+	p := &params.AssetHoldersParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+	var err error
+	if p.AssetID, err = ids.FromString(r.PathParams["id"]); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		TTL: 5 * time.Minute,
+		Key: c.cacheKeyForParams("asset_holders", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.AssetHolders(ctx, p)
+		},
+	})
+}
+
+func (c *V2Context) HolderCountHistory(w web.ResponseWriter, r *web.Request) {
+	collectors := utils.NewCollectors(
+		utils.NewCounterObserveMillisCollect(MetricMillis),
+		utils.NewCounterIncCollect(MetricCount),
+		utils.NewCounterObserveMillisCollect(MetricAssetMillis),
+		utils.NewCounterIncCollect(MetricAssetCount),
+	)
+	defer func() {
+		_ = collectors.Collect()
+	}()
+
+	p := &params.HolderCountHistoryParams{}
+	if err := p.ForValues(c.version, r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+	var err error
+	if p.AssetID, err = ids.FromString(r.PathParams["id"]); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, caching.Cacheable{
+		TTL: 5 * time.Minute,
+		Key: c.cacheKeyForParams("holder_count_history", p),
+		CacheableFn: func(ctx context.Context) (interface{}, error) {
+			return c.avaxReader.HolderCountHistory(ctx, p)
+		},
+	})
+}
+
 // PVM
 func (c *V2Context) ListBlocks(w web.ResponseWriter, r *web.Request) {
 	collectors := utils.NewCollectors(
@@ -0,0 +1,286 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/servicesctrl"
+	"github.com/chain4travel/magellan/stream"
+	"github.com/chain4travel/magellan/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gocraft/dbr/v2"
+)
+
+const (
+	// BatchSize is the number of containers read from the node at once
+	BatchSize = stream.MaxTxRead
+
+	MetricMissingCountKey    = "audit_containers_missing"
+	MetricDuplicatedCountKey = "audit_containers_duplicated"
+	MetricEnqueuedCountKey   = "audit_containers_enqueued"
+)
+
+// ContainerReader reads containers from a node index
+type ContainerReader interface {
+	GetContainerRange(ctx context.Context, startIndex uint64, numToFetch int) ([]indexer.Container, error)
+}
+
+// Target is the node index of a chain, whose containers are looked up in the
+// table the indexer writes them to
+type Target struct {
+	ChainID      string
+	Topic        string
+	IndexerChain stream.IndexedChain
+	IndexerType  stream.IndexType
+
+	table  string
+	column string
+}
+
+// Targets returns the decision indexes of the configured chains
+func Targets(conf cfg.Config) []*Target {
+	var targets []*Target
+	for _, chain := range conf.Chains {
+		target := &Target{
+			ChainID: chain.ID,
+			Topic:   stream.GetTopicName(conf.NetworkID, chain.ID, stream.EventTypeDecisions),
+		}
+		switch chain.VMType {
+		case models.AVMName:
+			target.IndexerChain, target.IndexerType = stream.IndexXChain, stream.IndexTypeTransactions
+			target.table, target.column = db.TableTransactions, "id"
+		case models.PVMName:
+			target.IndexerChain, target.IndexerType = stream.IndexPChain, stream.IndexTypeBlocks
+			target.table, target.column = db.TablePvmBlocks, "id"
+		case models.CVMName:
+			target.IndexerChain, target.IndexerType = stream.IndexCChain, stream.IndexTypeBlocks
+			target.table, target.column = db.TableCvmBlocks, "hash"
+		default:
+			continue
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// NewContainerReader reads the node index of the target from the configured
+// nodes
+func NewContainerReader(conf cfg.Config, target *Target) *stream.NodeIndexer {
+	name := fmt.Sprintf("audit_%s", target.ChainID)
+	return stream.NewNodeIndexer(name, conf, stream.NodeIndexEndpoint(target.IndexerChain, target.IndexerType))
+}
+
+// Gap is a container of the node index which has no row in the chain table,
+// or more than one
+type Gap struct {
+	Index       uint64 `json:"index"`
+	ContainerID string `json:"containerID"`
+	Rows        uint64 `json:"rows"`
+	Enqueued    bool   `json:"enqueued,omitempty"`
+}
+
+// Report is the result of auditing a range of a node index
+type Report struct {
+	ChainID string `json:"chainID"`
+	Topic   string `json:"topic"`
+	From    uint64 `json:"from"`
+	To      uint64 `json:"to"`
+
+	Checked uint64 `json:"checked"`
+
+	// Pending are containers still waiting in tx_pool to be indexed
+	Pending    uint64 `json:"pending"`
+	Missing    []*Gap `json:"missing"`
+	Duplicated []*Gap `json:"duplicated"`
+
+	// settled is the index up to which all containers are indexed or
+	// reported, the containers after it may still be pending
+	settled uint64
+}
+
+type Auditor struct {
+	sc    *servicesctrl.Control
+	conf  cfg.Config
+	conns *utils.Connections
+}
+
+func NewAuditor(sc *servicesctrl.Control, conf cfg.Config, conns *utils.Connections) *Auditor {
+	utils.Prometheus.CounterInit(MetricMissingCountKey, "containers missing in the chain tables")
+	utils.Prometheus.CounterInit(MetricDuplicatedCountKey, "containers with duplicated rows in the chain tables")
+	utils.Prometheus.CounterInit(MetricEnqueuedCountKey, "missing containers enqueued for indexing")
+	return &Auditor{
+		sc:    sc,
+		conf:  conf,
+		conns: conns,
+	}
+}
+
+// Produced returns the last index of the node index the producer of the
+// target has written to tx_pool, it returns false if nothing was produced
+func (a *Auditor) Produced(ctx context.Context, target *Target) (uint64, bool, error) {
+	sess, err := a.conns.Primary().NewSession("audit_produced", cfg.DBTimeout)
+	if err != nil {
+		return 0, false, err
+	}
+	nodeIndex, err := a.sc.Persist.QueryNodeIndex(ctx, sess, &db.NodeIndex{Instance: a.conf.NodeInstance, Topic: target.Topic})
+	switch {
+	case errors.Is(err, dbr.ErrNotFound):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, err
+	}
+	return nodeIndex.Idx, true, nil
+}
+
+// Audit looks up the containers from index from up to index to of the node
+// index in the chain table. With enqueue the missing containers are written to
+// tx_pool again.
+func (a *Auditor) Audit(ctx context.Context, target *Target, reader ContainerReader, from uint64, to uint64, enqueue bool) (*Report, error) {
+	report := &Report{
+		ChainID: target.ChainID,
+		Topic:   target.Topic,
+		From:    from,
+		To:      to,
+		settled: from,
+	}
+	if from > to {
+		return report, nil
+	}
+
+	sess, err := a.conns.Primary().NewSession("audit", cfg.DBTimeout)
+	if err != nil {
+		return report, err
+	}
+
+	pending := false
+	for start := from; start <= to; {
+		numToFetch := BatchSize
+		if to-start+1 < uint64(numToFetch) {
+			numToFetch = int(to - start + 1)
+		}
+		containers, err := reader.GetContainerRange(ctx, start, numToFetch)
+		if err != nil {
+			return report, err
+		}
+		if len(containers) == 0 {
+			break
+		}
+
+		txPools := make([]*db.TxPool, 0, len(containers))
+		keys := make([]string, 0, len(containers))
+		for _, container := range containers {
+			txPool := stream.ContainerTxPool(a.conf.NetworkID, target.ChainID, target.Topic, target.IndexerChain, container)
+			txPools = append(txPools, txPool)
+			key := txPool.MsgKey
+			if target.IndexerChain == stream.IndexCChain {
+				// the c-chain container id is the block hash
+				key = common.BytesToHash(container.ID[:]).Hex()
+			}
+			keys = append(keys, key)
+		}
+
+		// tx_pool is read first, the indexer only removes a row after it
+		// indexed the container
+		inTxPool, err := a.inTxPool(ctx, sess, txPools)
+		if err != nil {
+			return report, err
+		}
+		rows, err := a.rows(ctx, sess, target, keys)
+		if err != nil {
+			return report, err
+		}
+
+		for i, txPool := range txPools {
+			idx := start + uint64(i)
+			gap := &Gap{Index: idx, ContainerID: txPool.MsgKey, Rows: rows[keys[i]]}
+			switch {
+			case gap.Rows > 1:
+				report.Duplicated = append(report.Duplicated, gap)
+				_ = utils.Prometheus.CounterInc(MetricDuplicatedCountKey)
+			case gap.Rows == 1:
+			case inTxPool[txPool.ID]:
+				report.Pending++
+				pending = true
+			default:
+				report.Missing = append(report.Missing, gap)
+				_ = utils.Prometheus.CounterInc(MetricMissingCountKey)
+				if enqueue {
+					if err = stream.UpdateTxPool(cfg.DBTimeout, a.conns, a.sc.Persist, txPool, a.sc); err != nil {
+						return report, err
+					}
+					gap.Enqueued = true
+					_ = utils.Prometheus.CounterInc(MetricEnqueuedCountKey)
+				}
+			}
+			if !pending {
+				report.settled = idx + 1
+			}
+		}
+
+		report.Checked += uint64(len(containers))
+		start += uint64(len(containers))
+	}
+	return report, nil
+}
+
+func (a *Auditor) inTxPool(ctx context.Context, sess *dbr.Session, txPools []*db.TxPool) (map[string]bool, error) {
+	ids := make([]string, 0, len(txPools))
+	for _, txPool := range txPools {
+		ids = append(ids, txPool.ID)
+	}
+	var found []string
+	_, err := sess.
+		Select("id").
+		From(db.TableTxPool).
+		Where("id IN ?", ids).
+		LoadContext(ctx, &found)
+	if err != nil {
+		return nil, err
+	}
+	inTxPool := make(map[string]bool, len(found))
+	for _, id := range found {
+		inTxPool[id] = true
+	}
+	return inTxPool, nil
+}
+
+type keyRows struct {
+	RowKey   string
+	RowCount uint64
+}
+
+func (a *Auditor) rows(ctx context.Context, sess *dbr.Session, target *Target, keys []string) (map[string]uint64, error) {
+	var found []*keyRows
+	_, err := sess.
+		Select(target.column+" AS row_key", "COUNT(*) AS row_count").
+		From(target.table).
+		Where(target.column+" IN ?", keys).
+		GroupBy(target.column).
+		LoadContext(ctx, &found)
+	if err != nil {
+		return nil, err
+	}
+	rows := make(map[string]uint64, len(found))
+	for _, f := range found {
+		rows[f.RowKey] = f.RowCount
+	}
+	return rows, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/servicesctrl"
+	"github.com/chain4travel/magellan/stream"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+type containers []indexer.Container
+
+func (c containers) GetContainerRange(_ context.Context, startIndex uint64, numToFetch int) ([]indexer.Container, error) {
+	end := startIndex + uint64(numToFetch)
+	if end > uint64(len(c)) {
+		end = uint64(len(c))
+	}
+	return c[startIndex:end], nil
+}
+
+func TestAudit(t *testing.T) {
+	conns, sess := sqlitetest.New(t)
+
+	conf := cfg.Config{
+		NetworkID: 1,
+		Chains:    cfg.Chains{"pchain": {ID: "pchain", VMType: models.PVMName}},
+	}
+	sc := &servicesctrl.Control{Log: logging.NoLog{}, Persist: db.NewPersist()}
+	targets := Targets(conf)
+	if len(targets) != 1 {
+		t.Fatal("expected a target for the p-chain")
+	}
+	target := targets[0]
+
+	// container 0 is indexed, 1 is waiting in tx_pool and 2 is lost
+	node := containers{
+		{Bytes: []byte("block0"), Timestamp: 1},
+		{Bytes: []byte("block1"), Timestamp: 2},
+		{Bytes: []byte("block2"), Timestamp: 3},
+	}
+	ctx := context.Background()
+	txPools := make([]*db.TxPool, 0, len(node))
+	for _, container := range node {
+		txPools = append(txPools, stream.ContainerTxPool(conf.NetworkID, target.ChainID, target.Topic, target.IndexerChain, container))
+	}
+	if err := sc.Persist.InsertPvmBlocks(ctx, sess, &db.PvmBlocks{ID: txPools[0].MsgKey, ChainID: "pchain", CreatedAt: time.Unix(1, 0)}, false); err != nil {
+		t.Fatal("insert fail", err)
+	}
+	if err := sc.Persist.InsertTxPool(ctx, sess, txPools[1]); err != nil {
+		t.Fatal("insert fail", err)
+	}
+
+	auditor := NewAuditor(sc, conf, conns)
+	report, err := auditor.Audit(ctx, target, node, 0, 2, false)
+	if err != nil {
+		t.Fatal("audit fail", err)
+	}
+	if report.Checked != 3 || report.Pending != 1 || len(report.Missing) != 1 || report.Missing[0].Index != 2 {
+		t.Fatal("unexpected report", report)
+	}
+	if report.settled != 1 {
+		t.Fatal("expected the audit to settle before the pending container", report.settled)
+	}
+
+	// the lost container is enqueued and reported as pending afterwards
+	report, err = auditor.Audit(ctx, target, node, 2, 2, true)
+	if err != nil || len(report.Missing) != 1 || !report.Missing[0].Enqueued {
+		t.Fatal("enqueue fail", err)
+	}
+	report, err = auditor.Audit(ctx, target, node, 2, 2, false)
+	if err != nil || report.Pending != 1 || len(report.Missing) != 0 {
+		t.Fatal("expected the enqueued container to be pending", err)
+	}
+}
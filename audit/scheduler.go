@@ -0,0 +1,157 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/servicesctrl"
+	"github.com/chain4travel/magellan/stream"
+	"github.com/chain4travel/magellan/utils"
+)
+
+const (
+	// roundSize is the number of containers of a chain audited per run
+	roundSize = 10 * BatchSize
+
+	// cursorInstanceSuffix marks the node_index rows holding the position of
+	// the background audit
+	cursorInstanceSuffix = "-audit"
+)
+
+// Scheduler audits the node indexes of all chains in the background. It works
+// through each index from the start in rounds and keeps its position in the
+// node_index table, so it continues where it stopped after a restart.
+type Scheduler struct {
+	sc             *servicesctrl.Control
+	conf           cfg.Config
+	auditor        *Auditor
+	conns          *utils.Connections
+	targets        []*Target
+	readers        []*stream.NodeIndexer
+	runningControl utils.Running
+}
+
+func NewScheduler(sc *servicesctrl.Control, conf cfg.Config) (*Scheduler, error) {
+	conns, err := sc.Database()
+	if err != nil {
+		return nil, err
+	}
+	s := &Scheduler{
+		sc:             sc,
+		conf:           conf,
+		auditor:        NewAuditor(sc, conf, conns),
+		conns:          conns,
+		targets:        Targets(conf),
+		runningControl: utils.NewRunning(),
+	}
+	for _, target := range s.targets {
+		s.readers = append(s.readers, NewContainerReader(conf, target))
+	}
+	return s, nil
+}
+
+func (s *Scheduler) Listen() error {
+	s.sc.Log.Info("starting audit scheduler")
+	defer s.sc.Log.Info("exiting audit scheduler")
+
+	interval := time.Duration(s.conf.Audit.Interval) * time.Second
+	for !s.runningControl.IsStopped() {
+		for i, target := range s.targets {
+			if s.runningControl.IsStopped() {
+				break
+			}
+			if err := s.round(target, s.readers[i]); err != nil {
+				s.sc.Log.Warn("audit failed",
+					zap.String("chainID", target.ChainID),
+					zap.Error(err),
+				)
+			}
+		}
+
+		// wait for the interval, but notice a close in time
+		for waited := time.Duration(0); waited < interval && !s.runningControl.IsStopped(); waited += time.Second {
+			time.Sleep(time.Second)
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) Close() error {
+	s.runningControl.Close()
+	for _, reader := range s.readers {
+		reader.Close()
+	}
+	return s.conns.Close()
+}
+
+// round audits the next containers of the target and moves the position of
+// the audit past the ones which are settled
+func (s *Scheduler) round(target *Target, reader *stream.NodeIndexer) error {
+	ctx, cancelFn := context.WithTimeout(context.Background(), stream.IndexerTimeout)
+	defer cancelFn()
+
+	produced, ok, err := s.auditor.Produced(ctx, target)
+	if err != nil || !ok {
+		return err
+	}
+
+	sess, err := s.conns.Primary().NewSession("audit_cursor", cfg.DBTimeout)
+	if err != nil {
+		return err
+	}
+	cursor := &db.NodeIndex{Instance: s.conf.NodeInstance + cursorInstanceSuffix, Topic: target.Topic, Idx: 0}
+	if err = s.sc.Persist.InsertNodeIndex(ctx, sess, cursor, false); err != nil {
+		return err
+	}
+	if cursor, err = s.sc.Persist.QueryNodeIndex(ctx, sess, cursor); err != nil {
+		return err
+	}
+
+	to := cursor.Idx + roundSize - 1
+	if to > produced {
+		to = produced
+	}
+	report, err := s.auditor.Audit(ctx, target, reader, cursor.Idx, to, s.conf.Audit.Enqueue)
+	if err != nil {
+		return err
+	}
+	for _, gap := range report.Missing {
+		s.sc.Log.Warn("container missing",
+			zap.String("chainID", target.ChainID),
+			zap.Uint64("index", gap.Index),
+			zap.String("containerID", gap.ContainerID),
+			zap.Bool("enqueued", gap.Enqueued),
+		)
+	}
+	for _, gap := range report.Duplicated {
+		s.sc.Log.Warn("container indexed more than once",
+			zap.String("chainID", target.ChainID),
+			zap.Uint64("index", gap.Index),
+			zap.String("containerID", gap.ContainerID),
+			zap.Uint64("rows", gap.Rows),
+		)
+	}
+
+	if report.settled == cursor.Idx {
+		return nil
+	}
+	cursor.Idx = report.settled
+	return s.sc.Persist.UpdateNodeIndex(ctx, sess, cursor)
+}
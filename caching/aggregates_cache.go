@@ -1,457 +1,250 @@
 package caching
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"math"
-	"net/url"
 	"strings"
 	"time"
 
 	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
 	"github.com/chain4travel/magellan/models"
-	"github.com/chain4travel/magellan/services/indexes/params"
 	"github.com/chain4travel/magellan/utils"
 	"github.com/gocraft/dbr/v2"
 )
 
 const (
-	MaxAggregateIntervalCount = 20000
-)
+	// RollupBatchDuration bounds the range of source rows scanned by a single
+	// rollup query, so the initial backfill of a chain is split into chunks.
+	RollupBatchDuration = 24 * time.Hour
+
+	// RollupRescanWindow is the time before the latest hourly bucket which is
+	// aggregated again on every run, for the rows indexed late
+	RollupRescanWindow = 24 * time.Hour
+
+	// RollupInterval is the time between two rollup updates. The rollups have
+	// hour resolution and each run rescans the RollupRescanWindow, so they are
+	// not updated with the cache interval of the other aggregates.
+	RollupInterval = 5 * time.Minute
 
-var (
-	ErrAggregateIntervalCountTooLarge = errors.New("requesting too many intervals")
-	ErrFailedToParseStringAsBigInt    = errors.New("failed to parse string to big.Int")
+	rollupHourSeconds = int64(time.Hour / time.Second)
+	rollupDaySeconds  = 24 * rollupHourSeconds
 )
 
+// AggregatesCache maintains the persistent aggregate rollups the readers
+// answer Aggregate and TxfeeAggregate from, and the daily statistics.
 type AggregatesCache interface {
-	GetAggregateTransactionsMap() map[string]map[string]uint64
-	GetAggregateFeesMap() map[string]map[string]uint64
-	InitCacheStorage(cfg.Chains)
-	GetAggregatesFeesAndUpdate(map[string]cfg.Chain, *utils.Connections, string, time.Time, time.Time, string) error
-	GetAggregatesAndUpdate(map[string]cfg.Chain, *utils.Connections, string, time.Time, time.Time, string) error
+	UpdateRollups(context.Context, *utils.Connections, map[string]cfg.Chain, time.Time) error
 	UpdateStatistics(*utils.Connections, map[string]cfg.Chain) error
 }
 
 type aggregatesCache struct {
-	aggregateTransactionsMap map[string]map[string]uint64
-	aggregateFeesMap         map[string]map[string]uint64
+	persist db.Persist
+
+	// cchainID is the evm chain the cvm tables hold
+	cchainID string
 }
 
-func NewAggregatesCache() AggregatesCache {
+// NewAggregatesCache returns the cache of the rollups. Only the evm chain with
+// cchainID is rolled up from the cvm tables, any evm chain if it is empty.
+func NewAggregatesCache(persist db.Persist, cchainID string) AggregatesCache {
 	return &aggregatesCache{
-		aggregateTransactionsMap: make(map[string]map[string]uint64),
-		aggregateFeesMap:         make(map[string]map[string]uint64),
+		persist:  persist,
+		cchainID: cchainID,
 	}
 }
 
-func (ac *aggregatesCache) GetAggregateTransactionsMap() map[string]map[string]uint64 {
-	return ac.aggregateTransactionsMap
-}
-
-func (ac *aggregatesCache) GetAggregateFeesMap() map[string]map[string]uint64 {
-	return ac.aggregateFeesMap
-}
-
-func (ac *aggregatesCache) InitCacheStorage(chains cfg.Chains) {
-	aggregateTransMap := ac.aggregateTransactionsMap
-	aggregateFeesMap := ac.aggregateFeesMap
-
-	for id := range chains {
-		aggregateTransMap[id] = map[string]uint64{}
-		aggregateFeesMap[id] = map[string]uint64{}
-
-		// we initialize the value for all 3 chains
-		aggregateTransMap[id]["day"] = 0
-		aggregateTransMap[id]["week"] = 0
-		aggregateTransMap[id]["month"] = 0
-
-		// we initialize the value for all 3 chains also here
-		aggregateFeesMap[id]["day"] = 0
-		aggregateFeesMap[id]["week"] = 0
-		aggregateFeesMap[id]["month"] = 0
-	}
+type rollupBucket struct {
+	BucketID         int64
+	TransactionCount uint64
+	Txfee            uint64
 }
 
-//gocyclo:ignore
-func (ac *aggregatesCache) GetAggregatesAndUpdate(chains map[string]cfg.Chain, conns *utils.Connections, chainid string, startTime time.Time, endTime time.Time, rangeKeyType string) error {
-	chainIds := []string{chainid}
-	// Validate params and set defaults if necessary
-	if startTime.IsZero() {
-		var err error
-		startTime, err = getFirstTransactionTime(conns, chainIds)
-		if err != nil {
-			return err
-		}
-	}
-
-	intervals := models.AggregatesList{}
-	urlv := url.Values{}
-	intervalSize, err := params.GetQueryInterval(urlv, params.KeyIntervalSize)
+// UpdateRollups brings the hourly and daily rollups of every chain up to now.
+// The hourly buckets of the RollupRescanWindow before the latest one are
+// always recomputed, so rows indexed late are counted and repeated runs are
+// safe.
+func (ac *aggregatesCache) UpdateRollups(ctx context.Context, conns *utils.Connections, chains map[string]cfg.Chain, now time.Time) error {
+	dbRunner, err := conns.DB().NewSession("update_aggregate_rollups", cfg.DBTimeout)
 	if err != nil {
 		return err
 	}
 
-	// Ensure the interval count requested isn't too large
-	intervalSeconds := int64(intervalSize.Seconds())
-	requestedIntervalCount := 0
-	if intervalSeconds != 0 {
-		requestedIntervalCount = int(math.Ceil(endTime.Sub(startTime).Seconds() / intervalSize.Seconds()))
-		if requestedIntervalCount > MaxAggregateIntervalCount {
-			return ErrAggregateIntervalCountTooLarge
-		}
-		if requestedIntervalCount < 1 {
-			requestedIntervalCount = 1
-		}
-	}
-
-	// Split chains
-	var avmChains, cvmChains []string
-	if len(chainIds) == 0 {
-		for id, chain := range chains {
-			switch chain.VMType {
-			case models.CVMName:
-				cvmChains = append(cvmChains, id)
-			default:
-				avmChains = append(avmChains, id)
-			}
-		}
-	} else {
-		for _, id := range chainIds {
-			chain, exist := chains[id]
-			if exist {
-				switch chain.VMType {
-				case models.CVMName:
-					cvmChains = append(cvmChains, id)
-				default:
-					avmChains = append(avmChains, id)
-				}
-			}
+	for id, chain := range chains {
+		// the cvm tables have no chain id, they hold the transactions of the
+		// C-chain only
+		if chain.VMType == models.CVMName && ac.cchainID != "" && id != ac.cchainID {
+			continue
 		}
-	}
-
-	var dbRunner *dbr.Session
-
-	if conns != nil {
-		dbRunner = conns.DB().NewSessionForEventReceiver(conns.Stream().NewJob("get_transaction_aggregates_histogram"))
-	} else {
-		dbRunner, err = conns.DB().NewSession("get_transaction_aggregates_histogram", cfg.DBTimeout)
-		if err != nil {
+		if err := ac.updateChainRollups(ctx, dbRunner, id, chain.VMType, now.UTC()); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	var builder *dbr.SelectStmt
-
-	if len(avmChains) > 0 {
-		columns := []string{
-			"COUNT(DISTINCT(avm_transactions.id)) AS transaction_count",
-		}
-
-		if requestedIntervalCount > 0 {
-			columns = append(columns, fmt.Sprintf(
-				"FLOOR((UNIX_TIMESTAMP(avm_transactions.created_at)-%d) / %d) AS interval_id",
-				startTime.Unix(),
-				intervalSeconds))
-		}
-
-		builder = dbRunner.
-			Select(columns...).
-			From("avm_transactions").
-			Where("avm_transactions.created_at >= ?", startTime).
-			Where("avm_transactions.created_at < ?", endTime)
-
-		if len(chainIds) != 0 {
-			builder.Where("avm_transactions.chain_id IN ?", avmChains)
-		}
-
-		if requestedIntervalCount > 0 {
-			builder.
-				GroupBy("interval_id").
-				OrderAsc("interval_id").
-				Limit(uint64(requestedIntervalCount))
-		}
-
-		_, err = builder.Load(&intervals)
-		if err != nil {
-			return err
-		}
+func (ac *aggregatesCache) updateChainRollups(ctx context.Context, dbRunner *dbr.Session, chainID string, vmType string, now time.Time) error {
+	from, err := getLastRollupTime(ctx, dbRunner, chainID)
+	if err != nil {
+		return err
 	}
-
-	if len(cvmChains) > 0 {
-		// 1st step: we obtain the first block from the inputted date range
-		builder = dbRunner.
-			Select("block").
-			From("cvm_blocks").
-			Where("cvm_blocks.created_at >= ?", startTime).
-			Where("cvm_blocks.created_at < ?", endTime).
-			OrderBy("block asc").
-			Limit(1)
-
-		firstBlockValue := models.BlockValue{}
-
-		_, err = builder.Load(&firstBlockValue)
-		if err != nil {
-			return err
-		}
-
-		// 2nd step: we obtain the last block
-		// we get the last block from the cache(cam_last_block_cache db table)
-		builder = dbRunner.
-			Select("current_block as block").
-			From("cam_last_block_cache").
-			Where("chainid=?", chainIds[0])
-
-		lastBlockValue := models.BlockValue{}
-		_, err = builder.Load(&lastBlockValue)
+	if !from.IsZero() {
+		from = from.Add(-RollupRescanWindow)
+	} else {
+		from, err = getFirstTransactionTime(ctx, dbRunner, chainID, vmType)
 		if err != nil {
 			return err
 		}
-
-		if lastBlockValue.Block <= firstBlockValue.Block {
-			lastBlockValue.Block = firstBlockValue.Block
-		}
-
-		// handle edge case in case we do not have a transaction block during this range
-		if firstBlockValue.Block <= 0 {
-			lastBlockValue.Block = firstBlockValue.Block
-		}
-
-		// handle edge case in case we do not have a transaction block during this range
-		if firstBlockValue.Block <= 0 {
-			lastBlockValue.Block = firstBlockValue.Block
-		}
-
-		// 3rd step: we obtain the count of the transactions based on the block range we acquired from the previous steps
-		// we will construct based on the block numbers the relevant block_idx filters since this is our main index in the cvm_transactions_txdata table
-		builder = dbRunner.
-			Select("count(*) as  transaction_count").
-			From("cvm_transactions_txdata").
-			Where("cvm_transactions_txdata.block_idx >= concat(?,'000')", firstBlockValue.Block).
-			Where("cvm_transactions_txdata.block_idx <= concat(?,'999')", lastBlockValue.Block)
-
-		cvmIntervals := models.AggregatesList{}
-
-		_, err = builder.Load(&cvmIntervals)
-		if err != nil {
-			return err
+		// nothing indexed for this chain yet
+		if from.IsZero() {
+			return nil
 		}
-
-		intervals = append(intervals, cvmIntervals[0])
 	}
+	from = from.Truncate(time.Hour)
+	end := now.Truncate(time.Hour).Add(time.Hour)
 
-	// This check should never fail if the SQL query is correct, but added for
-	// robustness to prevent panics if the invariant does not hold.
-	if len(intervals) > 0 {
-		intervals[0].StartTime = startTime
-		intervals[0].EndTime = endTime
-		aggs := &models.AggregatesHistogram{
-			Aggregates: intervals[0],
-			StartTime:  startTime,
-			EndTime:    endTime,
+	for start := from; start.Before(end); start = start.Add(RollupBatchDuration) {
+		stop := start.Add(RollupBatchDuration)
+		if stop.After(end) {
+			stop = end
 		}
-		ac.aggregateTransactionsMap[chainid][rangeKeyType] = aggs.Aggregates.TransactionCount
-		return nil
-	}
-	aggs := &models.AggregatesHistogram{
-		StartTime: startTime,
-		EndTime:   endTime,
-	}
-	ac.aggregateTransactionsMap[chainid][rangeKeyType] = aggs.Aggregates.TransactionCount
-	return nil
-}
 
-//gocyclo:ignore
-func (ac *aggregatesCache) GetAggregatesFeesAndUpdate(chains map[string]cfg.Chain, conns *utils.Connections, chainid string, startTime time.Time, endTime time.Time, rangeKeyType string) error {
-	chainIds := []string{chainid}
-	// Validate params and set defaults if necessary
-	if startTime.IsZero() {
-		var err error
-		startTime, err = getFirstTransactionTime(conns, chainIds)
+		buckets, err := getHourlyBuckets(ctx, dbRunner, chainID, vmType, start, stop)
 		if err != nil {
 			return err
 		}
-	}
-
-	intervals := models.TxfeeAggregatesList{}
-	urlv := url.Values{}
-	intervalSize, err := params.GetQueryInterval(urlv, params.KeyIntervalSize)
-	if err != nil {
-		return err
-	}
-
-	// Ensure the interval count requested isn't too large
-	intervalSeconds := int64(intervalSize.Seconds())
-	requestedIntervalCount := 0
-	if intervalSeconds != 0 {
-		requestedIntervalCount = int(math.Ceil(endTime.Sub(startTime).Seconds() / intervalSize.Seconds()))
-		if requestedIntervalCount > MaxAggregateIntervalCount {
-			return ErrAggregateIntervalCountTooLarge
-		}
-		if requestedIntervalCount < 1 {
-			requestedIntervalCount = 1
-		}
-	}
-
-	// Split chains
-	var avmChains, cvmChains []string
-	if len(chainIds) == 0 {
-		for id, chain := range chains {
-			switch chain.VMType {
-			case models.CVMName:
-				cvmChains = append(cvmChains, id)
-			default:
-				avmChains = append(avmChains, id)
+		for _, bucket := range buckets {
+			rollup := &db.AggregateRollup{
+				ChainID:          chainID,
+				BucketAt:         time.Unix(bucket.BucketID*rollupHourSeconds, 0).UTC(),
+				TransactionCount: bucket.TransactionCount,
+				Txfee:            bucket.Txfee,
+				UpdatedAt:        now,
 			}
-		}
-	} else {
-		for _, id := range chainIds {
-			chain, exist := chains[id]
-			if exist {
-				switch chain.VMType {
-				case models.CVMName:
-					cvmChains = append(cvmChains, id)
-				default:
-					avmChains = append(avmChains, id)
-				}
+			if err := ac.persist.InsertAggregateRollupHourly(ctx, dbRunner, rollup, true); err != nil {
+				return err
 			}
 		}
 	}
 
-	// Build the query and load the base data
-	dbRunner, err := conns.DB().NewSession("get_txfee_aggregates_histogram", cfg.DBTimeout)
+	// the daily rollups are summed up from the hourly ones of the touched days
+	var buckets []*rollupBucket
+	_, err = dbRunner.
+		Select(
+			fmt.Sprintf("FLOOR(%s / %d) AS bucket_id", db.UnixTimestamp(dbRunner.Dialect, "bucket_at"), rollupDaySeconds),
+			db.CastUnsigned(dbRunner.Dialect, "SUM(transaction_count)")+" AS transaction_count",
+			db.CastUnsigned(dbRunner.Dialect, "SUM(txfee)")+" AS txfee",
+		).
+		From(db.TableAggregateRollupsHourly).
+		Where("chain_id = ?", chainID).
+		Where("bucket_at >= ?", from.Truncate(24*time.Hour)).
+		GroupBy("bucket_id").
+		LoadContext(ctx, &buckets)
 	if err != nil {
 		return err
 	}
-
-	var builder *dbr.SelectStmt
-
-	if len(avmChains) > 0 {
-		columns := []string{
-			"CAST(COALESCE(SUM(avm_transactions.txfee), 0) AS UNSIGNED) AS txfee",
-		}
-
-		if requestedIntervalCount > 0 {
-			columns = append(columns, fmt.Sprintf(
-				"FLOOR((UNIX_TIMESTAMP(avm_transactions.created_at)-%d) / %d) AS interval_id",
-				startTime.Unix(),
-				intervalSeconds))
-		}
-
-		builder = dbRunner.
-			Select(columns...).
-			From("avm_transactions").
-			Where("avm_transactions.created_at >= ?", startTime).
-			Where("avm_transactions.created_at < ?", endTime)
-
-		if requestedIntervalCount > 0 {
-			builder.
-				GroupBy("interval_id").
-				OrderAsc("interval_id").
-				Limit(uint64(requestedIntervalCount))
-		}
-
-		if len(chainIds) != 0 {
-			builder.Where("avm_transactions.chain_id IN ?", chainIds)
+	for _, bucket := range buckets {
+		rollup := &db.AggregateRollup{
+			ChainID:          chainID,
+			BucketAt:         time.Unix(bucket.BucketID*rollupDaySeconds, 0).UTC(),
+			TransactionCount: bucket.TransactionCount,
+			Txfee:            bucket.Txfee,
+			UpdatedAt:        now,
 		}
-
-		_, err = builder.Load(&intervals)
-		if err != nil {
+		if err := ac.persist.InsertAggregateRollupDaily(ctx, dbRunner, rollup, true); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	if len(cvmChains) > 0 {
-		// 1st step: we obtain the first block from the inputted date range
-		builder = dbRunner.
-			Select("block").
-			From("cvm_blocks").
-			Where("cvm_blocks.created_at >= ?", startTime).
-			Where("cvm_blocks.created_at < ?", endTime).
-			OrderBy("block asc").
-			Limit(1)
-
-		firstBlockValue := models.BlockValue{}
-
-		_, err = builder.Load(&firstBlockValue)
-		if err != nil {
-			return err
-		}
+func getHourlyBuckets(ctx context.Context, dbRunner *dbr.Session, chainID string, vmType string, startTime time.Time, endTime time.Time) ([]*rollupBucket, error) {
+	var builder *dbr.SelectStmt
+	bucketColumn := fmt.Sprintf("FLOOR(%s / %d) AS bucket_id", db.UnixTimestamp(dbRunner.Dialect, "created_at"), rollupHourSeconds)
 
-		// 2nd step: we obtain the last block from the inputted date range(initially creating a query but will be substituted with the last blockid from the Node)
-		// we get the last block from the cache(cam_last_block_cache db table)
+	switch vmType {
+	case models.CVMName:
+		// the fee is stored as gas price in wei, we aggregate it in nAVAX like the avm transactions
+		txfee := db.CastUnsigned(dbRunner.Dialect, "(gas_price / 1000000000) * gas_used")
 		builder = dbRunner.
-			Select("current_block as block").
-			From("cam_last_block_cache").
-			Where("chainid=?", chainIds[0])
-
-		lastBlockValue := models.BlockValue{}
-		_, err = builder.Load(&lastBlockValue)
-		if err != nil {
-			return err
-		}
-
-		if lastBlockValue.Block <= firstBlockValue.Block {
-			lastBlockValue.Block = firstBlockValue.Block
-		}
-
-		// handle edge case in case we do not have a transaction block during this range
-		if firstBlockValue.Block <= 0 {
-			lastBlockValue.Block = firstBlockValue.Block
-		}
-
-		// handle edge case in case we do not have a transaction block during this range
-		if firstBlockValue.Block <= 0 {
-			lastBlockValue.Block = firstBlockValue.Block
-		}
-
-		// 3rd step: we obtain the count of the transactions based on the block range we acquired from the previous steps
-		// we will construct based on the block numbers the relevant block_idx filters since this is our main index in the cvm_transactions_txdata table
+			Select(
+				bucketColumn,
+				"COUNT(*) AS transaction_count",
+				db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM("+txfee+"), 0)")+" AS txfee",
+			).
+			From(db.TableCvmTransactionsTxdata)
+	default:
 		builder = dbRunner.
-			Select("cast((cvm_transactions_txdata.gas_price / 1000000000) * cvm_transactions_txdata.gas_used AS UNSIGNED) as txfee").
-			From("cvm_transactions_txdata").
-			Where("cvm_transactions_txdata.block_idx >= concat(?,'000')", firstBlockValue.Block).
-			Where("cvm_transactions_txdata.block_idx <= concat(?,'999')", lastBlockValue.Block)
+			Select(
+				bucketColumn,
+				"COUNT(*) AS transaction_count",
+				db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM(txfee), 0)")+" AS txfee",
+			).
+			From(db.TableTransactions).
+			Where("chain_id = ?", chainID)
+	}
+
+	var buckets []*rollupBucket
+	_, err := builder.
+		Where("created_at >= ?", startTime).
+		Where("created_at < ?", endTime).
+		GroupBy("bucket_id").
+		LoadContext(ctx, &buckets)
+	return buckets, err
+}
 
-		cvmIntervals := models.TxfeeAggregatesList{}
+// CountTransactions returns the number of transactions of the chain created
+// within the range and their fees, counted from the indexed rows like the
+// hourly rollups. It answers the parts of a range which don't cover a whole
+// hour.
+func CountTransactions(ctx context.Context, dbRunner *dbr.Session, chainID string, vmType string, startTime time.Time, endTime time.Time) (*db.AggregateRollup, error) {
+	buckets, err := getHourlyBuckets(ctx, dbRunner, chainID, vmType, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	totals := &db.AggregateRollup{ChainID: chainID}
+	for _, bucket := range buckets {
+		totals.TransactionCount += bucket.TransactionCount
+		totals.Txfee += bucket.Txfee
+	}
+	return totals, nil
+}
 
-		_, err = builder.Load(&cvmIntervals)
-		if err != nil {
-			return err
-		}
+func getLastRollupTime(ctx context.Context, dbRunner *dbr.Session, chainID string) (time.Time, error) {
+	var ts float64
+	err := dbRunner.
+		Select("COALESCE("+db.UnixTimestamp(dbRunner.Dialect, "MAX(bucket_at)")+", 0)").
+		From(db.TableAggregateRollupsHourly).
+		Where("chain_id = ?", chainID).
+		LoadOneContext(ctx, &ts)
+	if err != nil || ts == 0 {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(math.Floor(ts)), 0).UTC(), nil
+}
 
-		// we calculate the sum of the fees here because of the db cost
-		var totalVolume uint64
-		for _, interval := range cvmIntervals {
-			// Add to the overall aggregates counts
-			totalVolume += interval.Txfee
-		}
+func getFirstTransactionTime(ctx context.Context, dbRunner *dbr.Session, chainID string, vmType string) (time.Time, error) {
+	var ts float64
+	var builder *dbr.SelectStmt
 
-		if len(cvmIntervals) > 0 {
-			intervals = append(intervals, cvmIntervals[0])
-			intervals[0].Txfee = totalVolume
-		}
+	switch vmType {
+	case models.CVMName:
+		builder = dbRunner.
+			Select("COALESCE(" + db.UnixTimestamp(dbRunner.Dialect, "MIN(created_at)") + ", 0)").
+			From(db.TableCvmTransactionsTxdata)
+	default:
+		builder = dbRunner.
+			Select("COALESCE("+db.UnixTimestamp(dbRunner.Dialect, "MIN(created_at)")+", 0)").
+			From(db.TableTransactions).
+			Where("chain_id = ?", chainID)
 	}
 
-	// This check should never fail if the SQL query is correct, but added for
-	// robustness to prevent panics if the invariant does not hold.
-	if len(intervals) > 0 {
-		intervals[0].StartTime = startTime
-		intervals[0].EndTime = endTime
-		aggs := &models.TxfeeAggregatesHistogram{
-			TxfeeAggregates: intervals[0],
-			StartTime:       startTime,
-			EndTime:         endTime,
-		}
-		ac.aggregateFeesMap[chainid][rangeKeyType] = aggs.TxfeeAggregates.Txfee
-		return nil
-	}
-	aggs := &models.TxfeeAggregatesHistogram{
-		StartTime: startTime,
-		EndTime:   endTime,
+	err := builder.LoadOneContext(ctx, &ts)
+	if err != nil || ts == 0 {
+		return time.Time{}, err
 	}
-	ac.aggregateFeesMap[chainid][rangeKeyType] = aggs.TxfeeAggregates.Txfee
-	return nil
+	return time.Unix(int64(math.Floor(ts)), 0).UTC(), nil
 }
 
 func (ac *aggregatesCache) UpdateStatistics(conn *utils.Connections, chains map[string]cfg.Chain) error {
@@ -629,25 +422,3 @@ func getLatestAddressActive(dbRunner *dbr.Session, maxDate time.Time) ([]*models
 
 	return addressFrom, addressTo
 }
-
-func getFirstTransactionTime(conns *utils.Connections, chainIDs []string) (time.Time, error) {
-	dbRunner, err := conns.DB().NewSession("get_first_transaction_time", cfg.RequestTimeout)
-	if err != nil {
-		return time.Time{}, err
-	}
-
-	var ts float64
-	builder := dbRunner.
-		Select("COALESCE(UNIX_TIMESTAMP(MIN(created_at)), 0)").
-		From("avm_transactions")
-
-	if len(chainIDs) > 0 {
-		builder.Where("avm_transactions.chain_id IN ?", chainIDs)
-	}
-
-	err = builder.LoadOne(&ts)
-	if err != nil {
-		return time.Time{}, err
-	}
-	return time.Unix(int64(math.Floor(ts)), 0).UTC(), nil
-}
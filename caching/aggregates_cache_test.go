@@ -0,0 +1,96 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package caching
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+func TestUpdateRollups(t *testing.T) {
+	conns, sess := sqlitetest.New(t)
+
+	ctx := context.Background()
+	persist := db.NewPersist()
+	now := time.Unix(3600*1000, 0).UTC()
+	chains := map[string]cfg.Chain{
+		"x":     {ID: "x", VMType: models.AVMName},
+		"c":     {ID: "c", VMType: models.CVMName},
+		"other": {ID: "other", VMType: models.CVMName},
+	}
+
+	insertTx := func(id string, createdAt time.Time) {
+		err := persist.InsertTransactions(ctx, sess, &db.Transactions{
+			ID: id, ChainID: "x", Type: "base", Txfee: 1, CreatedAt: createdAt,
+		}, false)
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+	insertTx("tx1", now.Add(-2*time.Hour))
+	insertTx("tx2", now)
+	for _, err := range []error{
+		persist.InsertCvmAccount(ctx, sess, &db.CvmAccount{Address: "from"}, false),
+		persist.InsertCvmAccount(ctx, sess, &db.CvmAccount{Address: "to"}, false),
+		persist.InsertCvmTransactionsTxdata(ctx, sess, &db.CvmTransactionsTxdata{
+			Hash: "0x1", Block: "1", FromAddr: "from", ToAddr: "to", GasUsed: 21000,
+			GasPrice: 1000000000, CreatedAt: now.Add(-time.Hour),
+		}, false),
+	} {
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	cache := NewAggregatesCache(persist, "c")
+	if err := cache.UpdateRollups(ctx, conns, chains, now); err != nil {
+		t.Fatal("update fail", err)
+	}
+
+	// a transaction indexed late before the latest bucket is counted
+	insertTx("tx3", now.Add(-2*time.Hour))
+	if err := cache.UpdateRollups(ctx, conns, chains, now); err != nil {
+		t.Fatal("update fail", err)
+	}
+	rollup, err := persist.QueryAggregateRollupHourly(ctx, sess, &db.AggregateRollup{ChainID: "x", BucketAt: now.Add(-2 * time.Hour)})
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if rollup.TransactionCount != 2 || rollup.Txfee != 2 {
+		t.Fatal("unexpected rollup", rollup)
+	}
+
+	// the cvm transactions are rolled up for the C-chain only
+	rollup, err = persist.QueryAggregateRollupHourly(ctx, sess, &db.AggregateRollup{ChainID: "c", BucketAt: now.Add(-time.Hour)})
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if rollup.TransactionCount != 1 || rollup.Txfee != 21000 {
+		t.Fatal("unexpected cvm rollup", rollup)
+	}
+	var others int
+	err = sess.Select("COUNT(*)").
+		From(db.TableAggregateRollupsHourly).
+		Where("chain_id = ?", "other").
+		LoadOneContext(ctx, &others)
+	if err != nil || others != 0 {
+		t.Fatal("unexpected rollups of the other evm chain", others, err)
+	}
+}
@@ -47,6 +47,9 @@ func KeyFromParts(parts ...string) string {
 type Cache interface {
 	Get(context.Context, string) ([]byte, error)
 	Set(context.Context, string, []byte, time.Duration) error
+
+	// Flush drops all cached responses
+	Flush()
 }
 
 type cacheContainer struct {
@@ -69,3 +72,7 @@ func (c *cacheContainer) Set(_ context.Context, key string, bytes []byte, ttl ti
 	c.cache.Put(key, bytes, ttl)
 	return nil
 }
+
+func (c *cacheContainer) Flush() {
+	c.cache.Flush()
+}
@@ -16,7 +16,6 @@ package cfg
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -32,8 +31,16 @@ var (
 	ErrChainsConfigIDNotString     = errors.New("Chain config ID is not a string")
 	ErrChainsConfigAliasNotString  = errors.New("Chain config alias is not a string")
 	ErrChainsConfigVMNotString     = errors.New("Chain config vm type is not a string")
+
+	// ErrBrokerIndexNotPersistent is returned for broker.index with a broker
+	// which loses the messages published while the indexer is down
+	ErrBrokerIndexNotPersistent = errors.New("broker index needs a persistent broker")
 )
 
+// brokerDriverNATS is the broker driver of core NATS, which doesn't persist
+// messages
+const brokerDriverNATS = "nats"
+
 type Aggregates struct {
 	AggregateMerge    uint64 `json:"AggregateMerge"`
 	StartTime         string `json:"startTime"`
@@ -70,15 +77,23 @@ type Config struct {
 	Services                `json:"services"`
 	MetricsListenAddr       string `json:"metricsListenAddr"`
 	AdminListenAddr         string `json:"adminListenAddr"`
+	AdminToken              string `json:"adminToken"`
 	Features                map[string]struct{}
-	CchainID                string `json:"cchainId"`
-	CaminoNode              string `json:"caminoNode"`
-	NodeInstance            string `json:"nodeInstance"`
-	CacheUpdateInterval     uint64 `json:"cacheUpdateInterval"`
-	CacheStatisticsInterval uint64 `json:"cacheStatisticsInterval"`
-	CacheEmissionsInterval  uint64 `json:"cacheEmissionsInterval"`
-	AP5Activation           uint64 `json:"ap5Activation"`
-	BanffActivation         uint64 `json:"banffActivation"`
+	CchainID                string   `json:"cchainId"`
+	CaminoNode              string   `json:"caminoNode"`
+	CaminoNodes             []string `json:"caminoNodes"`
+	NodeInstance            string   `json:"nodeInstance"`
+	CacheUpdateInterval     uint64   `json:"cacheUpdateInterval"`
+	CacheStatisticsInterval uint64   `json:"cacheStatisticsInterval"`
+	CacheEmissionsInterval  uint64   `json:"cacheEmissionsInterval"`
+	AP5Activation           uint64   `json:"ap5Activation"`
+	BanffActivation         uint64   `json:"banffActivation"`
+	Retention               `json:"retention"`
+	Outbox                  `json:"outbox"`
+	NodeFailover            `json:"nodeFailover"`
+	Audit                   `json:"audit"`
+	Discovery               `json:"discovery"`
+	Labels                  `json:"labels"`
 }
 
 type Chain struct {
@@ -94,6 +109,7 @@ type Services struct {
 	*DB               `json:"db"`
 	InmutableInsights EndpointService `json:"inmutableInsights"`
 	GeoIP             EndpointService `json:"geoIP"`
+	Broker            `json:"broker"`
 }
 
 type EndpointService struct {
@@ -106,9 +122,112 @@ type API struct {
 }
 
 type DB struct {
-	DSN    string `json:"dsn"`
-	RODSN  string `json:"rodsn"`
-	Driver string `json:"driver"`
+	DSN    string   `json:"dsn"`
+	RODSN  string   `json:"rodsn"`
+	RODSNs []string `json:"rodsns"`
+	Driver string   `json:"driver"`
+
+	// MaxReplicaLag is how many seconds a replica may fall behind the primary
+	// before reads are no longer routed to it
+	MaxReplicaLag uint64 `json:"maxReplicaLag"`
+}
+
+// Retention configures how long data which is only needed for a while is kept.
+// A value of 0 days keeps the data forever.
+type Retention struct {
+	// TxPoolDays is the age after which processed tx_pool rows are deleted
+	TxPoolDays uint64 `json:"txPoolDays"`
+
+	// SerializationDays is the age after which the raw serialization of cvm
+	// blocks and transactions is dropped, the decoded columns are kept
+	SerializationDays uint64 `json:"serializationDays"`
+
+	// Interval is the number of seconds between two retention runs
+	Interval uint64 `json:"interval"`
+}
+
+// Broker configures the message broker the producers publish the containers
+// to, in addition to the tx_pool table
+type Broker struct {
+	// Driver is kafka, nats or memory, no broker is used if it is empty
+	Driver string   `json:"driver"`
+	URLs   []string `json:"urls"`
+
+	// Group is the consumer group the indexer reads the topics with
+	Group string `json:"group"`
+
+	// Index makes the indexer consume the containers from the broker, the
+	// producers then no longer write them to tx_pool
+	Index bool `json:"index"`
+}
+
+// Outbox configures the indexed events written along with the transactions
+// and the relay which delivers them
+type Outbox struct {
+	// Sink is http, file or broker, no events are written if it is empty
+	Sink string `json:"sink"`
+
+	// Target is the url of the http sink, the path of the file sink or the
+	// topic of the broker sink
+	Target string `json:"target"`
+}
+
+// Audit configures the background check that every container of the node
+// index produced rows in the chain tables
+type Audit struct {
+	// Interval is the number of seconds between two audit runs, the audit is
+	// disabled if it is 0
+	Interval uint64 `json:"interval"`
+
+	// Enqueue makes the audit add missing containers to tx_pool again, so
+	// they get indexed
+	Enqueue bool `json:"enqueue"`
+}
+
+// Discovery configures the lookup of the blockchains created on the P-chain,
+// producers and consumers are started for the ones with a supported VM which
+// are not in the chains config
+type Discovery struct {
+	// Source is index to read the blockchains indexed from the P-chain, or
+	// node to read them with platform.getBlockchains. Chains are not
+	// discovered if it is empty
+	Source string `json:"source"`
+
+	// Interval is the number of seconds between two lookups
+	Interval uint64 `json:"interval"`
+}
+
+// Labels configures the address labels
+type Labels struct {
+	// SeedFile is the path of a json array of labels, which the api stores on
+	// start
+	SeedFile string `json:"seedFile"`
+}
+
+// NodeFailover configures how the node clients switch between the nodes of
+// CaminoNode and CaminoNodes
+type NodeFailover struct {
+	// CheckInterval is the number of seconds between two health checks
+	CheckInterval uint64 `json:"checkInterval"`
+
+	// MaxLag is how many containers a node may fall behind the most advanced
+	// node before it is no longer used
+	MaxLag uint64 `json:"maxLag"`
+}
+
+// NodeURLs returns the url of CaminoNode followed by the ones of CaminoNodes,
+// in the order they are preferred
+func (c *Config) NodeURLs() []string {
+	urls := make([]string, 0, 1+len(c.CaminoNodes))
+	seen := make(map[string]struct{}, 1+len(c.CaminoNodes))
+	for _, url := range append([]string{c.CaminoNode}, c.CaminoNodes...) {
+		if _, ok := seen[url]; ok || url == "" {
+			continue
+		}
+		seen[url] = struct{}{}
+		urls = append(urls, url)
+	}
+	return urls
 }
 
 type Filter struct {
@@ -129,6 +248,13 @@ func NewFromFile(filePath string) (*Config, error) {
 	servicesDBViper := newSubViper(servicesViper, keysServicesDB)
 	servicesGeoIPViper := newSubViper(servicesViper, keyServicesGeoIP)
 	servicesInmutableViper := newSubViper(servicesViper, keyServicesInmutable)
+	servicesBrokerViper := newSubViper(servicesViper, keysServicesBroker)
+	retentionViper := newSubViper(v, keysRetention)
+	outboxViper := newSubViper(v, keysOutbox)
+	nodeFailoverViper := newSubViper(v, keysNodeFailover)
+	auditViper := newSubViper(v, keysAudit)
+	discoveryViper := newSubViper(v, keysDiscovery)
+	labelsViper := newSubViper(v, keysLabels)
 
 	// Get chains config
 	chains, err := newChainsConfig(v)
@@ -137,22 +263,67 @@ func NewFromFile(filePath string) (*Config, error) {
 	}
 
 	// Build logging config
+	logLevel, err := logging.ToLevel(v.GetString(keysLogLevel))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", keysLogLevel, err)
+	}
+	displayLevel, err := logging.ToLevel(v.GetString(keysLogDisplayLevel))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", keysLogDisplayLevel, err)
+	}
 	loggingConf := logging.Config{
-		DisplayLevel: logging.Info,
-		LogLevel:     logging.Debug,
+		DisplayLevel: displayLevel,
+		LogLevel:     logLevel,
 	}
 	loggingConf.Directory = v.GetString(keysLogDirectory)
 
-	dbdsn := servicesDBViper.GetString(keysServicesDBDSN)
+	// the dsns and tokens may be file:// references to secrets
+	dbdsn, err := readSecret(keysServicesDBDSN, servicesDBViper.GetString(keysServicesDBDSN))
+	if err != nil {
+		return nil, err
+	}
 	dbrodsn := dbdsn
 	if servicesDBViper.Get(keysServicesDBRODSN) != nil {
-		dbrodsn = servicesDBViper.GetString(keysServicesDBRODSN)
+		dbrodsn, err = readSecret(keysServicesDBRODSN, servicesDBViper.GetString(keysServicesDBRODSN))
+		if err != nil {
+			return nil, err
+		}
+	}
+	dbrodsns := servicesDBViper.GetStringSlice(keysServicesDBRODSNs)
+	for i, dsn := range dbrodsns {
+		if dbrodsns[i], err = readSecret(keysServicesDBRODSNs, dsn); err != nil {
+			return nil, err
+		}
+	}
+	if len(dbrodsns) == 0 {
+		dbrodsns = []string{dbrodsn}
+	} else if servicesDBViper.Get(keysServicesDBRODSN) == nil {
+		dbrodsn = dbrodsns[0]
 	}
 
+	// the tokens are read from the file unless set in the environment, the
+	// legacy variables only apply without a MAGELLAN_ one. Only the file and
+	// the secrets are read again on a reload.
 	urlEndpointGeoIP := servicesGeoIPViper.GetString(keyServicesEndpoint)
-	tokenGeoIP := os.Getenv(fmt.Sprintf("%sGeoIP", keyServicesToken))
+	tokenGeoIP := servicesGeoIPViper.GetString(keyServicesToken)
+	if token := legacyTokenEnv(keyServicesGeoIP, "GeoIP"); token != "" {
+		tokenGeoIP = token
+	}
+	if tokenGeoIP, err = readSecret(keyServicesToken, tokenGeoIP); err != nil {
+		return nil, err
+	}
 	urlEndpointInmutable := servicesInmutableViper.GetString(keyServicesEndpoint)
-	tokenInmutable := os.Getenv(fmt.Sprintf("%sInmutable", keyServicesToken))
+	tokenInmutable := servicesInmutableViper.GetString(keyServicesToken)
+	if token := legacyTokenEnv(keyServicesInmutable, "Inmutable"); token != "" {
+		tokenInmutable = token
+	}
+	if tokenInmutable, err = readSecret(keyServicesToken, tokenInmutable); err != nil {
+		return nil, err
+	}
+	adminToken, err := readSecret(keysServicesAdminToken, v.GetString(keysServicesAdminToken))
+	if err != nil {
+		return nil, err
+	}
 
 	features := v.GetStringSlice(keysFeatures)
 	featuresMap := make(map[string]struct{})
@@ -164,6 +335,14 @@ func NewFromFile(filePath string) (*Config, error) {
 		featuresMap[featurec] = struct{}{}
 	}
 
+	// the producers skip tx_pool with broker.index, so a broker which drops
+	// messages would lose containers
+	brokerDriver := servicesBrokerViper.GetString(keysServicesBrokerDriver)
+	brokerIndex := servicesBrokerViper.GetBool(keysServicesBrokerIndex)
+	if brokerIndex && brokerDriver == brokerDriverNATS {
+		return nil, fmt.Errorf("%w: %s", ErrBrokerIndexNotPersistent, brokerDriver)
+	}
+
 	networkID := v.GetUint32(keysNetworkID)
 	ap5Activation := version.GetApricotPhase5Time(networkID).Unix()
 	banffActivation := version.GetBanffTime(networkID).Unix()
@@ -175,6 +354,7 @@ func NewFromFile(filePath string) (*Config, error) {
 		Chains:            chains,
 		MetricsListenAddr: v.GetString(keysServicesMetricsListenAddr),
 		AdminListenAddr:   v.GetString(keysServicesAdminListenAddr),
+		AdminToken:        adminToken,
 		Services: Services{
 			Logging: loggingConf,
 			API: API{
@@ -184,6 +364,9 @@ func NewFromFile(filePath string) (*Config, error) {
 				Driver: servicesDBViper.GetString(keysServicesDBDriver),
 				DSN:    dbdsn,
 				RODSN:  dbrodsn,
+				RODSNs: dbrodsns,
+
+				MaxReplicaLag: uint64(servicesDBViper.GetInt(keysServicesDBMaxReplicaLag)),
 			},
 			GeoIP: EndpointService{
 				URLEndpoint:        urlEndpointGeoIP,
@@ -193,14 +376,45 @@ func NewFromFile(filePath string) (*Config, error) {
 				URLEndpoint:        urlEndpointInmutable,
 				AuthorizationToken: tokenInmutable,
 			},
+			Broker: Broker{
+				Driver: brokerDriver,
+				URLs:   servicesBrokerViper.GetStringSlice(keysServicesBrokerURLs),
+				Group:  servicesBrokerViper.GetString(keysServicesBrokerGroup),
+				Index:  brokerIndex,
+			},
 		},
 		CchainID:                v.GetString(keysStreamProducerCchainID),
 		CaminoNode:              v.GetString(keysStreamProducerCaminoNode),
+		CaminoNodes:             v.GetStringSlice(keysStreamProducerCaminoNodes),
 		NodeInstance:            v.GetString(keysStreamProducerNodeInstance),
 		CacheUpdateInterval:     uint64(v.GetInt(keysCacheUpdateInterval)),
 		CacheStatisticsInterval: uint64(v.GetInt(keysCacheStatisticsInterval)),
 		CacheEmissionsInterval:  uint64(v.GetInt(keysCacheEmissionsInterval)),
 		AP5Activation:           uint64(ap5Activation),
 		BanffActivation:         uint64(banffActivation),
+		Retention: Retention{
+			TxPoolDays:        uint64(retentionViper.GetInt(keysRetentionTxPoolDays)),
+			SerializationDays: uint64(retentionViper.GetInt(keysRetentionSerializationDays)),
+			Interval:          uint64(retentionViper.GetInt(keysRetentionInterval)),
+		},
+		Outbox: Outbox{
+			Sink:   outboxViper.GetString(keysOutboxSink),
+			Target: outboxViper.GetString(keysOutboxTarget),
+		},
+		NodeFailover: NodeFailover{
+			CheckInterval: uint64(nodeFailoverViper.GetInt(keysNodeFailoverCheckInterval)),
+			MaxLag:        uint64(nodeFailoverViper.GetInt(keysNodeFailoverMaxLag)),
+		},
+		Audit: Audit{
+			Interval: uint64(auditViper.GetInt(keysAuditInterval)),
+			Enqueue:  auditViper.GetBool(keysAuditEnqueue),
+		},
+		Discovery: Discovery{
+			Source:   discoveryViper.GetString(keysDiscoverySource),
+			Interval: uint64(discoveryViper.GetInt(keysDiscoveryInterval)),
+		},
+		Labels: Labels{
+			SeedFile: labelsViper.GetString(keysLabelsSeedFile),
+		},
 	}, nil
 }
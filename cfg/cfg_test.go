@@ -0,0 +1,36 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBrokerIndex(t *testing.T) {
+	c := newTestConfig(t, `{"services": {"broker": {"driver": "kafka", "index": true}}}`)
+	if !c.Broker.Index || c.Broker.Driver != "kafka" {
+		t.Fatal("unexpected broker", c.Broker)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"services": {"broker": {"driver": "nats", "index": true}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFromFile(path); !errors.Is(err, ErrBrokerIndexNotPersistent) {
+		t.Fatal("expected broker index error", err)
+	}
+}
@@ -19,8 +19,23 @@ const defaultJSON = `{
   "cacheStatisticsInterval": "1",
   "cacheEmissionsInterval": "1",
   "logDirectory": "/tmp/magellan/logs",
+  "logLevel": "debug",
+  "logDisplayLevel": "info",
   "listenAddr": ":8080",
   "chains": {},
+  "retention": {
+    "interval": "3600"
+  },
+  "nodeFailover": {
+    "checkInterval": "10",
+    "maxLag": "100"
+  },
+  "audit": {
+    "interval": "600"
+  },
+  "discovery": {
+    "interval": "60"
+  },
   "services": {
     "db": {
       "dsn": "root:password@tcp(127.0.0.1:3306)/magellan_dev",
@@ -0,0 +1,212 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cfg
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// secretFilePrefix marks a value which is read from a file, e.g. a dsn or
+	// a token mounted from a secret
+	secretFilePrefix = "file://"
+
+	// redacted replaces the secrets of a redacted config
+	redacted = "***"
+)
+
+// envKey is a key of the config file which can be set in the environment
+type envKey struct {
+	path []string
+
+	// list values are comma separated in the environment
+	list bool
+}
+
+// envKeys are the keys which can be set in the environment. The variable is
+// the path of the key in upper case, joined by underscores and prefixed with
+// MAGELLAN_, e.g. MAGELLAN_SERVICES_DB_DSN for services.db.dsn. The chains are
+// set with MAGELLAN_CHAINS_<NAME>_ID and MAGELLAN_CHAINS_<NAME>_VMTYPE.
+var envKeys = []envKey{
+	{path: []string{keysNetworkID}},
+	{path: []string{keysLogDirectory}},
+	{path: []string{keysLogLevel}},
+	{path: []string{keysLogDisplayLevel}},
+	{path: []string{keysFeatures}, list: true},
+	{path: []string{keysServicesAPIListenAddr}},
+	{path: []string{keysServicesAdminListenAddr}},
+	{path: []string{keysServicesAdminToken}},
+	{path: []string{keysServicesMetricsListenAddr}},
+	{path: []string{keysServices, keysServicesDB, keysServicesDBDriver}},
+	{path: []string{keysServices, keysServicesDB, keysServicesDBDSN}},
+	{path: []string{keysServices, keysServicesDB, keysServicesDBRODSN}},
+	{path: []string{keysServices, keysServicesDB, keysServicesDBRODSNs}, list: true},
+	{path: []string{keysServices, keysServicesDB, keysServicesDBMaxReplicaLag}},
+	{path: []string{keysServices, keysServicesBroker, keysServicesBrokerDriver}},
+	{path: []string{keysServices, keysServicesBroker, keysServicesBrokerURLs}, list: true},
+	{path: []string{keysServices, keysServicesBroker, keysServicesBrokerGroup}},
+	{path: []string{keysServices, keysServicesBroker, keysServicesBrokerIndex}},
+	{path: []string{keysServices, keyServicesGeoIP, keyServicesEndpoint}},
+	{path: []string{keysServices, keyServicesGeoIP, keyServicesToken}},
+	{path: []string{keysServices, keyServicesInmutable, keyServicesEndpoint}},
+	{path: []string{keysServices, keyServicesInmutable, keyServicesToken}},
+	{path: []string{keysStreamProducerCaminoNode}},
+	{path: []string{keysStreamProducerCaminoNodes}, list: true},
+	{path: []string{keysStreamProducerNodeInstance}},
+	{path: []string{keysStreamProducerCchainID}},
+	{path: []string{keysCacheUpdateInterval}},
+	{path: []string{keysCacheStatisticsInterval}},
+	{path: []string{keysCacheEmissionsInterval}},
+	{path: []string{keysRetention, keysRetentionTxPoolDays}},
+	{path: []string{keysRetention, keysRetentionSerializationDays}},
+	{path: []string{keysRetention, keysRetentionInterval}},
+	{path: []string{keysOutbox, keysOutboxSink}},
+	{path: []string{keysOutbox, keysOutboxTarget}},
+	{path: []string{keysNodeFailover, keysNodeFailoverCheckInterval}},
+	{path: []string{keysNodeFailover, keysNodeFailoverMaxLag}},
+	{path: []string{keysAudit, keysAuditInterval}},
+	{path: []string{keysAudit, keysAuditEnqueue}},
+	{path: []string{keysDiscovery, keysDiscoverySource}},
+	{path: []string{keysDiscovery, keysDiscoveryInterval}},
+	{path: []string{keysLabels, keysLabelsSeedFile}},
+}
+
+// envVar returns the name of the environment variable of the key path
+func envVar(path ...string) string {
+	return strings.ToUpper(appName + "_" + strings.Join(path, "_"))
+}
+
+// envOverrides returns the keys set in environ, nested like in the config
+// file. Empty variables are ignored.
+func envOverrides(environ []string) map[string]interface{} {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if ok && v != "" {
+			env[k] = v
+		}
+	}
+
+	overrides := make(map[string]interface{})
+	for _, key := range envKeys {
+		v, ok := env[envVar(key.path...)]
+		if !ok {
+			continue
+		}
+		if !key.list {
+			setPath(overrides, key.path, v)
+			continue
+		}
+		var list []interface{}
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		setPath(overrides, key.path, list)
+	}
+
+	// the name only groups the id and vm type of a chain, the chains of the
+	// file are overridden by their key
+	chainsPrefix := envVar(keysChains) + "_"
+	for k, v := range env {
+		if !strings.HasPrefix(k, chainsPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(k, chainsPrefix)
+		for _, field := range []string{keysChainsID, keysChainsVMType} {
+			suffix := "_" + strings.ToUpper(field)
+			if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+				setPath(overrides, []string{keysChains, strings.ToLower(strings.TrimSuffix(name, suffix)), field}, v)
+			}
+		}
+	}
+	return overrides
+}
+
+func setPath(m map[string]interface{}, path []string, v interface{}) {
+	for _, k := range path[:len(path)-1] {
+		sub, ok := m[k].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			m[k] = sub
+		}
+		m = sub
+	}
+	m[path[len(path)-1]] = v
+}
+
+// legacyTokenEnv returns the authorization token of the service set in the
+// authorizationToken<suffix> variable, unless the MAGELLAN_ variable of the
+// token is set which takes precedence
+func legacyTokenEnv(service string, suffix string) string {
+	if os.Getenv(envVar(keysServices, service, keyServicesToken)) != "" {
+		return ""
+	}
+	return os.Getenv(keyServicesToken + suffix)
+}
+
+// readSecret returns the content of the file a file:// value refers to, other
+// values are returned as they are
+func readSecret(key string, value string) (string, error) {
+	if !strings.HasPrefix(value, secretFilePrefix) {
+		return value, nil
+	}
+	b, err := os.ReadFile(strings.TrimPrefix(value, secretFilePrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// Redacted returns a copy of the config whose dsns and tokens, and the query
+// of the endpoints which may carry a key, are masked, so it can be printed
+func (c Config) Redacted() Config {
+	redact := func(v string) string {
+		if v == "" {
+			return v
+		}
+		return redacted
+	}
+	redactEndpoint := func(service EndpointService) EndpointService {
+		service.AuthorizationToken = redact(service.AuthorizationToken)
+		if u, err := url.Parse(service.URLEndpoint); err == nil && u.RawQuery != "" {
+			query := u.Query()
+			for k := range query {
+				query.Set(k, redacted)
+			}
+			u.RawQuery = query.Encode()
+			service.URLEndpoint = u.String()
+		}
+		return service
+	}
+
+	c.AdminToken = redact(c.AdminToken)
+	c.GeoIP = redactEndpoint(c.GeoIP)
+	c.InmutableInsights = redactEndpoint(c.InmutableInsights)
+	if c.DB != nil {
+		db := *c.DB
+		db.DSN = redact(db.DSN)
+		db.RODSN = redact(db.RODSN)
+		db.RODSNs = make([]string, 0, len(c.DB.RODSNs))
+		for _, dsn := range c.DB.RODSNs {
+			db.RODSNs = append(db.RODSNs, redact(dsn))
+		}
+		c.DB = &db
+	}
+	return c
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	dsnFile := filepath.Join(dir, "dsn")
+	if err := os.WriteFile(dsnFile, []byte("root:secret@tcp(db:3306)/magellan\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MAGELLAN_NETWORKID", "5")
+	t.Setenv("MAGELLAN_FEATURES", "aggregate_cache, disable_bootstrap")
+	t.Setenv("MAGELLAN_SERVICES_DB_DSN", "file://"+dsnFile)
+	t.Setenv("MAGELLAN_SERVICES_DB_RO_DSNS", "ro1,ro2")
+	t.Setenv("MAGELLAN_SERVICES_DB_MAX_REPLICA_LAG", "30")
+	t.Setenv("MAGELLAN_SERVICES_GEOIP_AUTHORIZATIONTOKEN", "file://"+tokenFile)
+	// the MAGELLAN_ variable takes precedence over the legacy one
+	t.Setenv("authorizationTokenGeoIP", "legacy")
+	t.Setenv("MAGELLAN_AUDIT_ENQUEUE", "true")
+	t.Setenv("MAGELLAN_CHAINS_X_ID", "2JVSBoinj9C2J33VntvzYtVJNZdN2NKiwwKjcumHUWEb5DbBrm")
+	t.Setenv("MAGELLAN_CHAINS_X_VMTYPE", "avm")
+	t.Setenv("MAGELLAN_CHAINS_11111111111111111111111111111111LPOYY_VMTYPE", "pvm")
+
+	c := newTestConfig(t, `{
+		"networkID": 1,
+		"chains": {
+			"11111111111111111111111111111111LpoYY": {"id": "11111111111111111111111111111111LpoYY", "vmType": "avm"}
+		},
+		"services": {
+			"db": {"driver": "mysql", "dsn": "root:password@tcp(127.0.0.1:3306)/magellan"},
+			"geoIP": {"urlEndpoint": "http://geoip"}
+		}
+	}`)
+
+	if c.NetworkID != 5 || !c.Audit.Enqueue {
+		t.Fatal("unexpected config", c.NetworkID, c.Audit)
+	}
+	if !reflect.DeepEqual(c.Features, map[string]struct{}{"aggregate_cache": {}, "disable_bootstrap": {}}) {
+		t.Fatal("unexpected features", c.Features)
+	}
+	expectedDB := DB{
+		Driver:        "mysql",
+		DSN:           "root:secret@tcp(db:3306)/magellan",
+		RODSN:         "ro1",
+		RODSNs:        []string{"ro1", "ro2"},
+		MaxReplicaLag: 30,
+	}
+	if !reflect.DeepEqual(*c.DB, expectedDB) {
+		t.Fatal("unexpected db", c.DB)
+	}
+	expectedGeoIP := EndpointService{URLEndpoint: "http://geoip", AuthorizationToken: "token"}
+	if c.Services.GeoIP != expectedGeoIP {
+		t.Fatal("unexpected geoIP", c.Services.GeoIP)
+	}
+	expectedChains := Chains{
+		"11111111111111111111111111111111LpoYY":              {ID: "11111111111111111111111111111111LpoYY", VMType: "pvm"},
+		"2JVSBoinj9C2J33VntvzYtVJNZdN2NKiwwKjcumHUWEb5DbBrm": {ID: "2JVSBoinj9C2J33VntvzYtVJNZdN2NKiwwKjcumHUWEb5DbBrm", VMType: "avm"},
+	}
+	if !reflect.DeepEqual(c.Chains, expectedChains) {
+		t.Fatal("unexpected chains", c.Chains)
+	}
+
+	// a missing secret fails the config
+	t.Setenv("MAGELLAN_SERVICES_DB_DSN", "file://"+filepath.Join(dir, "missing"))
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFromFile(path); err == nil {
+		t.Fatal("missing secret not reported")
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	c := Config{
+		AdminToken: "admin",
+		Services: Services{
+			DB:    &DB{Driver: "mysql", DSN: "root:secret@tcp(db:3306)/magellan", RODSNs: []string{"ro"}},
+			GeoIP: EndpointService{URLEndpoint: "http://geoip/json?key=secret", AuthorizationToken: "token"},
+		},
+	}
+	r := c.Redacted()
+
+	if r.AdminToken != redacted || r.DB.DSN != redacted || r.DB.RODSN != "" || r.DB.RODSNs[0] != redacted || r.DB.Driver != "mysql" {
+		t.Fatal("unexpected redacted db", r.AdminToken, r.DB)
+	}
+	if r.GeoIP.AuthorizationToken != redacted || r.GeoIP.URLEndpoint != "http://geoip/json?key=%2A%2A%2A" {
+		t.Fatal("unexpected redacted geoIP", r.GeoIP)
+	}
+	// the config itself is kept
+	if c.DB.DSN == redacted || c.DB.RODSNs[0] != "ro" || c.GeoIP.AuthorizationToken != "token" {
+		t.Fatal("config changed", c.DB, c.GeoIP)
+	}
+}
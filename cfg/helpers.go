@@ -6,14 +6,13 @@ package cfg
 import (
 	"bytes"
 	"log"
+	"os"
 
 	"github.com/spf13/viper"
 )
 
 func newViper() *viper.Viper {
 	v := viper.NewWithOptions(viper.KeyDelimiter("_"))
-	v.SetEnvPrefix(appName)
-	v.AutomaticEnv()
 	v.SetConfigType("json")
 
 	// Add defaults
@@ -34,6 +33,13 @@ func newViperFromFile(filePath string) (*viper.Viper, error) {
 		return nil, err
 	}
 
+	// the MAGELLAN_ variables take precedence over the file
+	if overrides := envOverrides(os.Environ()); len(overrides) != 0 {
+		if err := v.MergeConfigMap(overrides); err != nil {
+			return nil, err
+		}
+	}
+
 	return v, nil
 }
 
@@ -46,9 +52,6 @@ func newSubViper(v *viper.Viper, name string) *viper.Viper {
 	if v == nil {
 		return newViper()
 	}
-
-	v.SetEnvPrefix(appName + "_" + name)
-	v.AutomaticEnv()
 	return v
 }
 
@@ -14,9 +14,11 @@
 package cfg
 
 const (
-	keysNetworkID    = "networkID"
-	keysLogDirectory = "logDirectory"
-	keysFeatures     = "features"
+	keysNetworkID       = "networkID"
+	keysLogDirectory    = "logDirectory"
+	keysLogLevel        = "logLevel"
+	keysLogDisplayLevel = "logDisplayLevel"
+	keysFeatures        = "features"
 
 	keysChains       = "chains"
 	keysChainsID     = "id"
@@ -26,12 +28,22 @@ const (
 
 	keysServicesAPIListenAddr     = "listenAddr"
 	keysServicesAdminListenAddr   = "adminListenAddr"
+	keysServicesAdminToken        = "adminToken"
 	keysServicesMetricsListenAddr = "metricsListenAddr"
 
 	keysServicesDB       = "db"
 	keysServicesDBDriver = "driver"
 	keysServicesDBDSN    = "dsn"
 	keysServicesDBRODSN  = "ro_dsn"
+	keysServicesDBRODSNs = "ro_dsns"
+
+	keysServicesDBMaxReplicaLag = "max_replica_lag"
+
+	keysServicesBroker       = "broker"
+	keysServicesBrokerDriver = "driver"
+	keysServicesBrokerURLs   = "urls"
+	keysServicesBrokerGroup  = "group"
+	keysServicesBrokerIndex  = "index"
 
 	keyServicesInmutable = "inmutableInsights"
 	keyServicesGeoIP     = "geoIP"
@@ -39,6 +51,7 @@ const (
 	keyServicesToken     = "authorizationToken"
 
 	keysStreamProducerCaminoNode   = "caminoNode"
+	keysStreamProducerCaminoNodes  = "caminoNodes"
 	keysStreamProducerNodeInstance = "nodeInstance"
 
 	keysStreamProducerCchainID = "cchainID"
@@ -46,4 +59,28 @@ const (
 	keysCacheUpdateInterval     = "cacheUpdateInterval"
 	keysCacheStatisticsInterval = "cacheStatisticsInterval"
 	keysCacheEmissionsInterval  = "cacheEmissionsInterval"
+
+	keysRetention                  = "retention"
+	keysRetentionTxPoolDays        = "txPoolDays"
+	keysRetentionSerializationDays = "serializationDays"
+	keysRetentionInterval          = "interval"
+
+	keysOutbox       = "outbox"
+	keysOutboxSink   = "sink"
+	keysOutboxTarget = "target"
+
+	keysNodeFailover              = "nodeFailover"
+	keysNodeFailoverCheckInterval = "checkInterval"
+	keysNodeFailoverMaxLag        = "maxLag"
+
+	keysAudit         = "audit"
+	keysAuditInterval = "interval"
+	keysAuditEnqueue  = "enqueue"
+
+	keysDiscovery         = "discovery"
+	keysDiscoverySource   = "source"
+	keysDiscoveryInterval = "interval"
+
+	keysLabels         = "labels"
+	keysLabelsSeedFile = "seedFile"
 )
@@ -0,0 +1,109 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cfg
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// ReloadableFeatures are the features which are switched on a reload, the
+// others are only read on start
+var ReloadableFeatures = map[string]struct{}{
+	"accumulate_balance_reader": {},
+	"aggregate_cache":           {},
+}
+
+// RestartFields returns the fields which differ between the running and the
+// reloaded config and are only read on start. The log levels, cache
+// intervals, reloadable features and the external service endpoints are
+// applied on a reload.
+func RestartFields(running *Config, reloaded *Config) ([]string, error) {
+	a, err := restartValues(running)
+	if err != nil {
+		return nil, err
+	}
+	b, err := restartValues(reloaded)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			fields = append(fields, k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// restartValues returns the values of the fields which are only read on
+// start, keyed by their path in the json of the config
+func restartValues(c *Config) (map[string]string, error) {
+	v := *c
+	v.Services.Logging.LogLevel = 0
+	v.Services.Logging.DisplayLevel = 0
+	v.Services.GeoIP = EndpointService{}
+	v.Services.InmutableInsights = EndpointService{}
+	v.CacheUpdateInterval = 0
+	v.CacheStatisticsInterval = 0
+	v.CacheEmissionsInterval = 0
+	v.Features = make(map[string]struct{}, len(c.Features))
+	for feature := range c.Features {
+		if _, ok := ReloadableFeatures[feature]; !ok {
+			v.Features[feature] = struct{}{}
+		}
+	}
+
+	b, err := json.Marshal(&v)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(b, &tree); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	if err := flatten("", tree, values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func flatten(prefix string, tree map[string]interface{}, values map[string]string) error {
+	for k, v := range tree {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if sub, ok := v.(map[string]interface{}); ok && len(sub) != 0 {
+			if err := flatten(path, sub, values); err != nil {
+				return err
+			}
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		values[path] = string(b)
+	}
+	return nil
+}
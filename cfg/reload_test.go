@@ -0,0 +1,70 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newTestConfig(t *testing.T, config string) *Config {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewFromFile(path)
+	if err != nil {
+		t.Fatal("config fail", err)
+	}
+	return c
+}
+
+func TestRestartFields(t *testing.T) {
+	running := newTestConfig(t, `{
+		"features": ["aggregate_cache"],
+		"services": {"geoIP": {"urlEndpoint": "http://a", "authorizationToken": "a"}}
+	}`)
+	if running.Logging.LogLevel != logging.Debug || running.Services.GeoIP.AuthorizationToken != "a" {
+		t.Fatal("unexpected config", running.Logging, running.Services.GeoIP)
+	}
+
+	reloaded := newTestConfig(t, `{
+		"logLevel": "warn",
+		"cacheUpdateInterval": 60,
+		"features": ["accumulate_balance_reader"],
+		"services": {"geoIP": {"urlEndpoint": "http://b", "authorizationToken": "b"}}
+	}`)
+	fields, err := RestartFields(running, reloaded)
+	if err != nil || len(fields) != 0 {
+		t.Fatal("unexpected restart fields", fields, err)
+	}
+
+	reloaded = newTestConfig(t, `{
+		"listenAddr": ":8081",
+		"features": ["aggregate_cache", "disable_bootstrap"],
+		"audit": {"interval": 60}
+	}`)
+	fields, err = RestartFields(running, reloaded)
+	if err != nil {
+		t.Fatal("restart fields fail", err)
+	}
+	expected := []string{"Features.disable_bootstrap", "audit.interval", "services.api.listenAddr"}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Fatal("unexpected restart fields", fields)
+	}
+}
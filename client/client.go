@@ -0,0 +1,158 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package client is a typed Go client of the magellan v2 API. Requests take
+// the params types the API parses them into and responses decode into the
+// models the API serves, so a change of either breaks the build of the
+// callers instead of their runtime.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chain4travel/magellan/api"
+)
+
+const (
+	// DefaultPath is the path the v2 API is mounted at
+	DefaultPath = "/v2"
+
+	DefaultTimeout = 30 * time.Second
+
+	// maxErrorBody bounds how much of a failed response is read
+	maxErrorBody = 64 * 1024
+)
+
+// Error is returned for responses with a non 2xx status. The fields of the
+// api.ErrorResponse are set when the body held one.
+type Error struct {
+	StatusCode int
+	api.ErrorResponse
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("magellan: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("magellan: status %d: %s", e.StatusCode, e.Message)
+}
+
+// Client calls the v2 API of a magellan instance. It is safe for concurrent
+// use.
+type Client struct {
+	baseURL string
+	path    string
+	http    *http.Client
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient replaces the http client requests are sent with
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = httpClient
+	}
+}
+
+// WithPath mounts the client at another path than DefaultPath, e.g. "/x" for
+// the v1 compatible X-chain API
+func WithPath(path string) Option {
+	return func(c *Client) {
+		c.path = strings.TrimSuffix(path, "/")
+	}
+}
+
+// New creates a client for the magellan instance at baseURL, e.g.
+// "http://localhost:8080"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		path:    DefaultPath,
+		http:    &http.Client{Timeout: DefaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) get(ctx context.Context, path string, q url.Values, out interface{}) error {
+	u := c.baseURL + c.path + path
+	if len(q) != 0 {
+		u += "?" + q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, out)
+}
+
+// post sends body as json, the API merges the keys of a json object of string
+// arrays into the query
+func (c *Client) post(ctx context.Context, path string, body interface{}, out interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+c.path+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.do(req, out)
+}
+
+func (c *Client) do(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decodeError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if err != nil {
+		return apiErr
+	}
+	if json.Unmarshal(b, &apiErr.ErrorResponse) != nil || apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(b))
+	}
+	if apiErr.Code == 0 {
+		apiErr.Code = resp.StatusCode
+	}
+	return apiErr
+}
@@ -0,0 +1,149 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/api"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+)
+
+func TestValuesRoundTrip(t *testing.T) {
+	spent := true
+	p := &params.ListOutputsParams{
+		ListParams: params.ListParams{
+			Limit:     10,
+			StartTime: time.Unix(100, 0).UTC(),
+		},
+		ChainIDs: []string{"chain1", "chain2"},
+		Spent:    &spent,
+	}
+
+	parsed := &params.ListOutputsParams{}
+	if err := parsed.ForValues(2, outputsValues(p)); err != nil {
+		t.Fatal("parse fail", err)
+	}
+	if parsed.ListParams.Limit != 10 ||
+		!parsed.ListParams.StartTime.Equal(p.ListParams.StartTime) ||
+		len(parsed.ChainIDs) != 2 ||
+		parsed.Spent == nil || !*parsed.Spent {
+		t.Fatal("round trip fail", parsed)
+	}
+
+	agg := &params.AggregateParams{IntervalSize: params.IntervalDay}
+	if got := aggregateValues(agg).Get(params.KeyIntervalSize); got != "day" {
+		t.Fatal("unexpected interval", got)
+	}
+}
+
+func TestError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.WriteErr(w, 400, "invalid offset")
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL).ListAssets(context.Background(), nil)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatal("expected api error", err)
+	}
+	if apiErr.StatusCode != 400 || apiErr.Code != 400 || apiErr.Message != "invalid offset" {
+		t.Fatal("unexpected error", apiErr)
+	}
+}
+
+func TestTransactionIterator(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/transactions" {
+			t.Error("unexpected path", r.URL.Path)
+		}
+		requests++
+		res := &models.TransactionList{}
+		switch r.URL.Query().Get(params.KeyStartTime) {
+		case "":
+			next := "startTime=2&limit=2&sort=timestamp-asc"
+			res.Transactions = []*models.Transaction{{ID: "a"}, {ID: "b"}}
+			res.Next = &next
+		case "2":
+			res.Transactions = []*models.Transaction{{ID: "c"}}
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	it := New(srv.URL).Transactions(&params.ListTransactionsParams{ListParams: params.ListParams{Limit: 2}})
+	var got []models.StringID
+	for it.Next(context.Background()) {
+		got = append(got, it.Transaction().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal("iterate fail", err)
+	}
+	if fmt.Sprint(got) != "[a b c]" || requests != 2 {
+		t.Fatal("unexpected transactions", got, requests)
+	}
+}
+
+func TestCTransactionIterator(t *testing.T) {
+	// blocks 3, 2, 2, 1 with a page size of 2, block 2 spans two pages
+	txs := []*models.CTransactionData{
+		{Hash: "0x4", Block: "3"},
+		{Hash: "0x3", Block: "2"},
+		{Hash: "0x2", Block: "2"},
+		{Hash: "0x1", Block: "1"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := &params.ListCTransactionsParams{}
+		if err := p.ForValues(2, r.URL.Query()); err != nil {
+			api.WriteErr(w, 400, err.Error())
+			return
+		}
+		res := &models.CTransactionList{}
+		for _, tx := range txs {
+			block, _ := new(big.Int).SetString(tx.Block, 10)
+			if p.BlockEnd != nil && block.Cmp(p.BlockEnd) > 0 ||
+				p.BlockStart != nil && block.Cmp(p.BlockStart) < 0 {
+				continue
+			}
+			if len(res.Transactions) < p.ListParams.Limit {
+				res.Transactions = append(res.Transactions, tx)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	it := New(srv.URL).CTransactions(&params.ListCTransactionsParams{ListParams: params.ListParams{Limit: 2}})
+	var got []string
+	for it.Next(context.Background()) {
+		got = append(got, it.Transaction().Hash)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal("iterate fail", err)
+	}
+	if fmt.Sprint(got) != "[0x4 0x3 0x2 0x1]" {
+		t.Fatal("unexpected transactions", got)
+	}
+}
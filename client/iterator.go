@@ -0,0 +1,215 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+)
+
+// ErrBlockTooLarge is returned by a CTransactionIterator when a single block
+// holds more transactions than the API returns at once
+var ErrBlockTooLarge = errors.New("block has more transactions than a page")
+
+// TransactionIterator walks all pages of a transaction listing by following
+// the next link of the API
+//
+//	it := c.Transactions(p)
+//	for it.Next(ctx) {
+//		tx := it.Transaction()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type TransactionIterator struct {
+	c    *Client
+	q    url.Values
+	page []*models.Transaction
+	idx  int
+	cur  *models.Transaction
+	done bool
+	err  error
+}
+
+// Transactions returns an iterator over the transactions matching p, the
+// limit of p is the size of a page
+func (c *Client) Transactions(p *params.ListTransactionsParams) *TransactionIterator {
+	q := transactionsValues(p)
+	// pages follow the time of the last transaction
+	q.Del(params.KeyOffset)
+	return &TransactionIterator{c: c, q: q}
+}
+
+// Next advances to the next transaction, fetching the next page when needed.
+// It returns false at the end or on error.
+func (it *TransactionIterator) Next(ctx context.Context) bool {
+	for it.idx >= len(it.page) {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch(ctx)
+	}
+	it.cur = it.page[it.idx]
+	it.idx++
+	return true
+}
+
+func (it *TransactionIterator) fetch(ctx context.Context) {
+	res := &models.TransactionList{}
+	if it.err = it.c.get(ctx, "/transactions", it.q, res); it.err != nil {
+		return
+	}
+	it.page, it.idx = res.Transactions, 0
+	if res.Next == nil || len(res.Transactions) == 0 {
+		it.done = true
+		return
+	}
+	next, err := url.ParseQuery(*res.Next)
+	if err != nil {
+		it.err = fmt.Errorf("invalid next link %q: %w", *res.Next, err)
+		return
+	}
+	if next.Encode() == it.q.Encode() {
+		it.done = true
+		return
+	}
+	it.q = next
+}
+
+// Transaction returns the current transaction
+func (it *TransactionIterator) Transaction() *models.Transaction {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration
+func (it *TransactionIterator) Err() error {
+	return it.err
+}
+
+// CTransactionIterator walks the C-chain transactions matching the params
+// from the newest to the oldest. The API has no next link for them, so the
+// pages are cut at block boundaries: the lowest block of a page is left for
+// the next page, which ends at that block.
+type CTransactionIterator struct {
+	c     *Client
+	p     params.ListCTransactionsParams
+	limit int
+	page  []*models.CTransactionData
+	idx   int
+	cur   *models.CTransactionData
+	done  bool
+	err   error
+}
+
+// CTransactions returns an iterator over the C-chain transactions matching
+// p, the limit of p is the size of a page
+func (c *Client) CTransactions(p *params.ListCTransactionsParams) *CTransactionIterator {
+	it := &CTransactionIterator{c: c, limit: params.PaginationMaxLimit}
+	if p != nil {
+		it.p = *p
+	}
+	it.p.ListParams.Offset = 0
+	if it.p.ListParams.Limit > 0 {
+		it.limit = it.p.ListParams.Limit
+	}
+	return it
+}
+
+// Next advances to the next transaction, fetching the next page when needed.
+// It returns false at the end or on error.
+func (it *CTransactionIterator) Next(ctx context.Context) bool {
+	for it.idx >= len(it.page) {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch(ctx)
+	}
+	it.cur = it.page[it.idx]
+	it.idx++
+	return true
+}
+
+func (it *CTransactionIterator) fetch(ctx context.Context) {
+	txs, err := it.list(ctx, &it.p)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.idx = 0
+	if len(txs) < it.limit {
+		it.page, it.done = txs, true
+		return
+	}
+
+	var lowest *big.Int
+	for _, tx := range txs {
+		block, ok := new(big.Int).SetString(tx.Block, 10)
+		if !ok {
+			it.err = fmt.Errorf("invalid block %q of transaction %s", tx.Block, tx.Hash)
+			return
+		}
+		if lowest == nil || block.Cmp(lowest) < 0 {
+			lowest = block
+		}
+	}
+	it.page = make([]*models.CTransactionData, 0, len(txs))
+	for _, tx := range txs {
+		if tx.Block != lowest.String() {
+			it.page = append(it.page, tx)
+		}
+	}
+	if len(it.page) != 0 {
+		it.p.BlockEnd = lowest
+		return
+	}
+
+	// the block fills the page on its own, read it at once
+	p := it.p
+	p.ListParams.Limit = params.PaginationMaxLimit
+	p.BlockStart, p.BlockEnd = lowest, lowest
+	if it.page, it.err = it.list(ctx, &p); it.err != nil {
+		return
+	}
+	if len(it.page) >= params.PaginationMaxLimit {
+		it.err = ErrBlockTooLarge
+		return
+	}
+	it.p.BlockEnd = new(big.Int).Sub(lowest, big.NewInt(1))
+	if it.p.BlockEnd.Sign() < 0 || (it.p.BlockStart != nil && it.p.BlockEnd.Cmp(it.p.BlockStart) < 0) {
+		it.done = true
+	}
+}
+
+func (it *CTransactionIterator) list(ctx context.Context, p *params.ListCTransactionsParams) ([]*models.CTransactionData, error) {
+	res := &models.CTransactionList{}
+	if err := it.c.get(ctx, "/ctransactions", cTransactionsValues(p), res); err != nil {
+		return nil, err
+	}
+	return res.Transactions, nil
+}
+
+// Transaction returns the current transaction
+func (it *CTransactionIterator) Transaction() *models.CTransactionData {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration
+func (it *CTransactionIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,371 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/chain4travel/magellan/api"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+)
+
+// Index is the response of the root of the API
+type Index struct {
+	NetworkID uint32                      `json:"network_id"`
+	Chains    map[string]models.ChainInfo `json:"chains"`
+}
+
+func (c *Client) Index(ctx context.Context) (*Index, error) {
+	res := &Index{}
+	return res, c.get(ctx, "/", nil, res)
+}
+
+// OpenAPI returns the OpenAPI specification of the API
+func (c *Client) OpenAPI(ctx context.Context) (*api.OpenAPI, error) {
+	res := &api.OpenAPI{}
+	return res, c.get(ctx, api.OpenAPIPath, nil, res)
+}
+
+func (c *Client) Search(ctx context.Context, p *params.SearchParams) (*models.SearchResults, error) {
+	var q url.Values
+	if p != nil {
+		q = listValues(&p.ListParams)
+	}
+	res := &models.SearchResults{}
+	return res, c.get(ctx, "/search", q, res)
+}
+
+func (c *Client) Aggregate(ctx context.Context, p *params.AggregateParams) (*models.AggregatesHistogram, error) {
+	res := &models.AggregatesHistogram{}
+	return res, c.get(ctx, "/aggregates", aggregateValues(p), res)
+}
+
+// TransactionAggregate is served by the same handler as Aggregate
+func (c *Client) TransactionAggregate(ctx context.Context, p *params.AggregateParams) (*models.AggregatesHistogram, error) {
+	res := &models.AggregatesHistogram{}
+	return res, c.get(ctx, "/transactions/aggregates", aggregateValues(p), res)
+}
+
+func (c *Client) TxfeeAggregate(ctx context.Context, p *params.TxfeeAggregateParams) (*models.TxfeeAggregatesHistogram, error) {
+	res := &models.TxfeeAggregatesHistogram{}
+	return res, c.get(ctx, "/txfeeAggregates", txfeeAggregateValues(p), res)
+}
+
+func (c *Client) FeeStats(ctx context.Context, p *params.FeeStatsParams) (*models.FeeStatsList, error) {
+	res := &models.FeeStatsList{}
+	return res, c.get(ctx, "/fees/types", feeStatsValues(p), res)
+}
+
+func (c *Client) BurnedFees(ctx context.Context, p *params.BurnedFeesParams) (*models.BurnedFeesHistogram, error) {
+	res := &models.BurnedFeesHistogram{}
+	return res, c.get(ctx, "/fees/burned", burnedFeesValues(p), res)
+}
+
+func (c *Client) CChainFees(ctx context.Context, p *params.FeeHistoryParams) (*models.CChainFeesHistogram, error) {
+	res := &models.CChainFeesHistogram{}
+	return res, c.get(ctx, "/fees/cchain", feeHistoryValues(p), res)
+}
+
+// Supply returns the latest supply of the asset, the field of the params is
+// ignored, SupplyField reads a single amount
+func (c *Client) Supply(ctx context.Context, p *params.SupplyParams) (*models.Supply, error) {
+	q := supplyValues(p)
+	q.Del(params.KeyField)
+	res := &models.Supply{}
+	return res, c.get(ctx, "/supply", q, res)
+}
+
+// SupplyField returns the amount of the supply field of the params in whole
+// tokens
+func (c *Client) SupplyField(ctx context.Context, p *params.SupplyParams) (json.Number, error) {
+	var res json.Number
+	return res, c.get(ctx, "/supply", supplyValues(p), &res)
+}
+
+func (c *Client) SupplyHistory(ctx context.Context, p *params.SupplyHistoryParams) (*models.SupplyHistory, error) {
+	res := &models.SupplyHistory{}
+	return res, c.get(ctx, "/supply/history", supplyHistoryValues(p), res)
+}
+
+func (c *Client) AddressChains(ctx context.Context, p *params.AddressChainsParams) (*models.AddressChains, error) {
+	res := &models.AddressChains{}
+	return res, c.get(ctx, "/addressChains", addressChainsValues(p), res)
+}
+
+// AddressChainsPost sends the params in the body, for address lists too long
+// for a query
+func (c *Client) AddressChainsPost(ctx context.Context, p *params.AddressChainsParams) (*models.AddressChains, error) {
+	res := &models.AddressChains{}
+	return res, c.post(ctx, "/addressChains", addressChainsValues(p), res)
+}
+
+func (c *Client) ValidatorsInfo(ctx context.Context) (models.GeoIPValidators, error) {
+	var res models.GeoIPValidators
+	return res, c.post(ctx, "/validatorsInfo", nil, &res)
+}
+
+// ValidatorsAt returns the validator set at the time or P-chain height of p,
+// the current one when p is nil
+func (c *Client) ValidatorsAt(ctx context.Context, p *params.ValidatorsAtParams) (*models.ValidatorSet, error) {
+	res := &models.ValidatorSet{}
+	return res, c.get(ctx, "/validators/at", validatorsAtValues(p), res)
+}
+
+func (c *Client) ListSubnets(ctx context.Context, p *params.ListSubnetsParams) (*models.SubnetList, error) {
+	var q url.Values
+	if p != nil {
+		q = listValues(&p.ListParams)
+	}
+	res := &models.SubnetList{}
+	return res, c.get(ctx, "/subnets", q, res)
+}
+
+func (c *Client) ListBlockchains(ctx context.Context, p *params.ListBlockchainsParams) (*models.BlockchainList, error) {
+	res := &models.BlockchainList{}
+	return res, c.get(ctx, "/blockchains", blockchainsValues(p), res)
+}
+
+func (c *Client) ListUTXOs(ctx context.Context, p *params.ListUTXOsParams) (*models.UTXOList, error) {
+	res := &models.UTXOList{}
+	return res, c.get(ctx, "/utxos", utxosValues(p), res)
+}
+
+func (c *Client) ListCrossChainTransfers(ctx context.Context, p *params.ListCrossChainTransfersParams) (*models.CrossChainTransferList, error) {
+	res := &models.CrossChainTransferList{}
+	return res, c.get(ctx, "/crosschain", crossChainTransfersValues(p), res)
+}
+
+//
+// Statistics
+//
+
+func statisticsValues(p *params.StatisticsParams) url.Values {
+	if p == nil {
+		return nil
+	}
+	return listValues(&p.ListParams)
+}
+
+func (c *Client) ActiveAddresses(ctx context.Context, p *params.StatisticsParams) (*models.AddressStruct, error) {
+	res := &models.AddressStruct{}
+	return res, c.get(ctx, "/activeAddresses", statisticsValues(p), res)
+}
+
+func (c *Client) UniqueAddresses(ctx context.Context, p *params.StatisticsParams) (*models.AddressStruct, error) {
+	res := &models.AddressStruct{}
+	return res, c.get(ctx, "/uniqueAddresses", statisticsValues(p), res)
+}
+
+func (c *Client) AverageBlockSize(ctx context.Context, p *params.StatisticsParams) ([]*models.AverageBlockSize, error) {
+	var res []*models.AverageBlockSize
+	return res, c.get(ctx, "/averageBlockSize", statisticsValues(p), &res)
+}
+
+func (c *Client) DailyTransactions(ctx context.Context, p *params.StatisticsParams) (*models.StatisticsStruct, error) {
+	res := &models.StatisticsStruct{}
+	return res, c.get(ctx, "/dailyTransactions", statisticsValues(p), res)
+}
+
+func (c *Client) DailyGasUsed(ctx context.Context, p *params.StatisticsParams) (*models.StatisticsStruct, error) {
+	res := &models.StatisticsStruct{}
+	return res, c.get(ctx, "/dailyGasUsed", statisticsValues(p), res)
+}
+
+func (c *Client) AvgGasPriceUsed(ctx context.Context, p *params.StatisticsParams) (*models.StatisticsStruct, error) {
+	res := &models.StatisticsStruct{}
+	return res, c.get(ctx, "/avgGasPriceUsed", statisticsValues(p), res)
+}
+
+func (c *Client) DailyTokenTransfer(ctx context.Context, p *params.StatisticsParams) ([]*models.TransactionsPerDate, error) {
+	var res []*models.TransactionsPerDate
+	return res, c.get(ctx, "/dailyTokenTransfer", statisticsValues(p), &res)
+}
+
+//
+// Emissions
+//
+
+func emissionsValues(p *params.EmissionsParams) url.Values {
+	if p == nil {
+		return nil
+	}
+	return listValues(&p.ListParams)
+}
+
+func (c *Client) DailyEmissions(ctx context.Context, p *params.EmissionsParams) (*models.Emissions, error) {
+	res := &models.Emissions{}
+	return res, c.get(ctx, "/dailyEmissions", emissionsValues(p), res)
+}
+
+func (c *Client) NetworkEmissions(ctx context.Context, p *params.EmissionsParams) (*models.Emissions, error) {
+	res := &models.Emissions{}
+	return res, c.get(ctx, "/networkEmissions", emissionsValues(p), res)
+}
+
+func (c *Client) TransactionEmissions(ctx context.Context, p *params.EmissionsParams) (*models.Emissions, error) {
+	res := &models.Emissions{}
+	return res, c.get(ctx, "/transactionEmissions", emissionsValues(p), res)
+}
+
+func (c *Client) CountryEmissions(ctx context.Context, p *params.EmissionsParams) (*models.Emissions, error) {
+	res := &models.Emissions{}
+	return res, c.get(ctx, "/countryEmissions", emissionsValues(p), res)
+}
+
+//
+// List and Get
+//
+
+func (c *Client) ListTransactions(ctx context.Context, p *params.ListTransactionsParams) (*models.TransactionList, error) {
+	res := &models.TransactionList{}
+	return res, c.get(ctx, "/transactions", transactionsValues(p), res)
+}
+
+// ListTransactionsPost sends the params in the body, for address lists too
+// long for a query
+func (c *Client) ListTransactionsPost(ctx context.Context, p *params.ListTransactionsParams) (*models.TransactionList, error) {
+	res := &models.TransactionList{}
+	return res, c.post(ctx, "/transactions", transactionsValues(p), res)
+}
+
+func (c *Client) GetTransaction(ctx context.Context, id ids.ID) (*models.Transaction, error) {
+	res := &models.Transaction{}
+	return res, c.get(ctx, "/transactions/"+id.String(), nil, res)
+}
+
+func (c *Client) ListAddresses(ctx context.Context, p *params.ListAddressesParams) (*models.AddressList, error) {
+	res := &models.AddressList{}
+	return res, c.get(ctx, "/addresses", addressesValues(p), res)
+}
+
+// GetAddress returns the balances of the address, which may be bech32 with or
+// without chain prefix. The chains of p narrow the balances, p may be nil.
+func (c *Client) GetAddress(ctx context.Context, address string, p *params.ListAddressesParams) (*models.AddressInfo, error) {
+	q := addressesValues(p)
+	q.Del(params.KeyAddress)
+	res := &models.AddressInfo{}
+	return res, c.get(ctx, "/addresses/"+url.PathEscape(address), q, res)
+}
+
+func (c *Client) ListOutputs(ctx context.Context, p *params.ListOutputsParams) (*models.OutputList, error) {
+	res := &models.OutputList{}
+	return res, c.get(ctx, "/outputs", outputsValues(p), res)
+}
+
+func (c *Client) GetOutput(ctx context.Context, id ids.ID) (*models.Output, error) {
+	res := &models.Output{}
+	return res, c.get(ctx, "/outputs/"+id.String(), nil, res)
+}
+
+func (c *Client) ListAssets(ctx context.Context, p *params.ListAssetsParams) (*models.AssetList, error) {
+	res := &models.AssetList{}
+	return res, c.get(ctx, "/assets", assetsValues(p), res)
+}
+
+// GetAsset returns the asset by id or alias
+func (c *Client) GetAsset(ctx context.Context, idOrAlias string) (*models.Asset, error) {
+	res := &models.Asset{}
+	return res, c.get(ctx, "/assets/"+url.PathEscape(idOrAlias), nil, res)
+}
+
+// AssetHolders returns the addresses holding the asset of the params
+func (c *Client) AssetHolders(ctx context.Context, p *params.AssetHoldersParams) (*models.AssetHolderList, error) {
+	res := &models.AssetHolderList{}
+	return res, c.get(ctx, "/assets/"+p.AssetID.String()+"/holders", assetHoldersValues(p), res)
+}
+
+func (c *Client) HolderCountHistory(ctx context.Context, p *params.HolderCountHistoryParams) (*models.HolderCountHistory, error) {
+	res := &models.HolderCountHistory{}
+	return res, c.get(ctx, "/assets/"+p.AssetID.String()+"/holders/history", listValues(&p.ListParams), res)
+}
+
+// ATxData returns the raw data of a X-chain transaction
+func (c *Client) ATxData(ctx context.Context, id ids.ID) (json.RawMessage, error) {
+	var res json.RawMessage
+	return res, c.get(ctx, "/atxdata/"+id.String(), nil, &res)
+}
+
+// PTxData returns the raw data of a P-chain block by its height or id
+func (c *Client) PTxData(ctx context.Context, heightOrID string) (json.RawMessage, error) {
+	var res json.RawMessage
+	return res, c.get(ctx, "/ptxdata/"+url.PathEscape(heightOrID), nil, &res)
+}
+
+// CTxData returns the raw data of a C-chain block by its number, any other
+// value returns the latest block
+func (c *Client) CTxData(ctx context.Context, number string) (json.RawMessage, error) {
+	var res json.RawMessage
+	return res, c.get(ctx, "/ctxdata/"+url.PathEscape(number), nil, &res)
+}
+
+func (c *Client) ListCBlocks(ctx context.Context, p *params.ListCBlocksParams) (*models.CBlockList, error) {
+	res := &models.CBlockList{}
+	return res, c.get(ctx, "/cblocks", cBlocksValues(p), res)
+}
+
+func (c *Client) ListCTransactions(ctx context.Context, p *params.ListCTransactionsParams) (*models.CTransactionList, error) {
+	res := &models.CTransactionList{}
+	return res, c.get(ctx, "/ctransactions", cTransactionsValues(p), res)
+}
+
+//
+// Aggregate caches
+//
+
+func (c *Client) CacheAddressCounts(ctx context.Context) ([]*models.ChainCounts, error) {
+	var res []*models.ChainCounts
+	return res, c.get(ctx, "/cacheaddresscounts", nil, &res)
+}
+
+func (c *Client) CacheTxCounts(ctx context.Context) ([]*models.ChainCounts, error) {
+	var res []*models.ChainCounts
+	return res, c.get(ctx, "/cachetxscounts", nil, &res)
+}
+
+func (c *Client) CacheAssets(ctx context.Context) ([]*models.Asset, error) {
+	var res []*models.Asset
+	return res, c.get(ctx, "/cacheassets", nil, &res)
+}
+
+func (c *Client) CacheAssetAggregates(ctx context.Context) ([]*models.AssetAggregate, error) {
+	var res []*models.AssetAggregate
+	return res, c.get(ctx, "/cacheassetaggregates", nil, &res)
+}
+
+func (c *Client) CacheAggregates(ctx context.Context, tag string) (*models.AggregatesHistogram, error) {
+	res := &models.AggregatesHistogram{}
+	return res, c.get(ctx, "/cacheaggregates/"+url.PathEscape(tag), nil, res)
+}
+
+//
+// Camino
+//
+
+// GetMultisigAlias returns the multisig aliases owned by the bech32 owner
+// addresses
+func (c *Client) GetMultisigAlias(ctx context.Context, owners []string) (*models.MultisigAliasList, error) {
+	res := &models.MultisigAliasList{}
+	return res, c.get(ctx, "/multisigalias/"+url.PathEscape(strings.Join(owners, ",")), nil, res)
+}
+
+// GetRewards returns the rewards of the bech32 addresses
+func (c *Client) GetRewards(ctx context.Context, addresses []string) ([]models.Reward, error) {
+	var res []models.Reward
+	return res, c.post(ctx, "/rewards", map[string][]string{"addresses": addresses}, &res)
+}
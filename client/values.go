@@ -0,0 +1,329 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"math/big"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/chain4travel/magellan/api"
+	"github.com/chain4travel/magellan/services/indexes/params"
+)
+
+// The encoders below are the inverse of the ForValues methods of the params
+// types: zero fields are left out, so the API applies its defaults.
+
+func listValues(p *params.ListParams) url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.ID != nil {
+		q.Set(params.KeyID, p.ID.String())
+	}
+	if p.Query != "" {
+		q.Set(params.KeySearchQuery, p.Query)
+	}
+	if p.Limit > 0 {
+		q.Set(params.KeyLimit, strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		q.Set(params.KeyOffset, strconv.Itoa(p.Offset))
+	}
+	if p.DisableCounting {
+		q.Set(params.KeyDisableCount, "true")
+	}
+	if !p.StartTime.IsZero() {
+		q.Set(params.KeyStartTime, strconv.FormatInt(p.StartTime.Unix(), 10))
+	}
+	if !p.EndTime.IsZero() {
+		q.Set(params.KeyEndTime, strconv.FormatInt(p.EndTime.Unix(), 10))
+	}
+	return q
+}
+
+func addShortIDs(q url.Values, key string, addrs []ids.ShortID) {
+	for _, addr := range addrs {
+		q.Add(key, addr.String())
+	}
+}
+
+func addStrings(q url.Values, key string, vals []string) {
+	for _, val := range vals {
+		q.Add(key, val)
+	}
+}
+
+func setBigInt(q url.Values, key string, n *big.Int) {
+	if n != nil {
+		q.Set(key, n.String())
+	}
+}
+
+func setInterval(q url.Values, interval time.Duration) {
+	if interval == 0 {
+		return
+	}
+	for name, d := range params.IntervalNames {
+		if d == interval {
+			q.Set(params.KeyIntervalSize, name)
+			return
+		}
+	}
+	q.Set(params.KeyIntervalSize, interval.String())
+}
+
+func aggregateValues(p *params.AggregateParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addStrings(q, params.KeyChainID, p.ChainIDs)
+	if p.AssetID != nil {
+		q.Set(params.KeyAssetID, p.AssetID.String())
+	}
+	setInterval(q, p.IntervalSize)
+	return q
+}
+
+func txfeeAggregateValues(p *params.TxfeeAggregateParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addStrings(q, params.KeyChainID, p.ChainIDs)
+	setInterval(q, p.IntervalSize)
+	return q
+}
+
+func feeStatsValues(p *params.FeeStatsParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addStrings(q, params.KeyChainID, p.ChainIDs)
+	return q
+}
+
+func feeHistoryValues(p *params.FeeHistoryParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	setInterval(q, p.IntervalSize)
+	return q
+}
+
+func burnedFeesValues(p *params.BurnedFeesParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := feeHistoryValues(&p.FeeHistoryParams)
+	addStrings(q, params.KeyChainID, p.ChainIDs)
+	return q
+}
+
+func assetHoldersValues(p *params.AssetHoldersParams) url.Values {
+	q := listValues(&p.ListParams)
+	addStrings(q, params.KeyChainID, p.ChainIDs)
+	return q
+}
+
+func supplyValues(p *params.SupplyParams) url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.AssetID != nil {
+		q.Set(params.KeyAssetID, p.AssetID.String())
+	}
+	if p.Field != "" {
+		q.Set(params.KeyField, p.Field)
+	}
+	return q
+}
+
+func supplyHistoryValues(p *params.SupplyHistoryParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	if p.AssetID != nil {
+		q.Set(params.KeyAssetID, p.AssetID.String())
+	}
+	return q
+}
+
+func transactionsValues(p *params.ListTransactionsParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addStrings(q, params.KeyChainID, p.ChainIDs)
+	addShortIDs(q, params.KeyAddress, p.Addresses)
+	if p.AssetID != nil {
+		q.Set(params.KeyAssetID, p.AssetID.String())
+	}
+	for _, outputType := range p.OutputOutputTypes {
+		q.Add(params.KeyOutputOutputType, strconv.FormatUint(outputType, 10))
+	}
+	for _, groupID := range p.OutputGroupIDs {
+		q.Add(params.KeyOutputGroupID, strconv.FormatUint(groupID, 10))
+	}
+	if p.DisableGenesis {
+		q.Set(params.KeyDisableGenesis, "true")
+	}
+	if p.Raw {
+		q.Set(params.KeyRaw, "true")
+	}
+	q.Set(params.KeySortBy, p.Sort.String())
+	return q
+}
+
+func cTransactionsValues(p *params.ListCTransactionsParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addStrings(q, params.KeyAddress, p.CAddresses)
+	addStrings(q, params.KeyToAddress, p.CAddressesTo)
+	addStrings(q, params.KeyFromAddress, p.CAddressesFrom)
+	addStrings(q, params.KeyHash, p.Hashes)
+	setBigInt(q, params.KeyBlockStart, p.BlockStart)
+	setBigInt(q, params.KeyBlockEnd, p.BlockEnd)
+	q.Set(params.KeySortBy, p.Sort.String())
+	return q
+}
+
+func cBlocksValues(p *params.ListCBlocksParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	// the second limit is the number of transactions
+	if p.TxLimit > 0 {
+		if p.ListParams.Limit <= 0 {
+			q.Set(params.KeyLimit, strconv.Itoa(api.DefaultLimit))
+		}
+		q.Add(params.KeyLimit, strconv.Itoa(p.TxLimit))
+	}
+	addStrings(q, params.KeyAddress, p.CAddresses)
+	setBigInt(q, params.KeyBlockStart, p.BlockStart)
+	setBigInt(q, params.KeyBlockEnd, p.BlockEnd)
+	if p.TxID > 0 {
+		q.Set(params.KeyTransactionID, strconv.FormatUint(uint64(p.TxID), 10))
+	}
+	return q
+}
+
+func assetsValues(p *params.ListAssetsParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	if p.Alias != "" {
+		q.Set(params.KeyAlias, p.Alias)
+	}
+	return q
+}
+
+func addressesValues(p *params.ListAddressesParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addStrings(q, params.KeyChainID, p.ChainIDs)
+	if p.Address != nil {
+		q.Set(params.KeyAddress, p.Address.String())
+	}
+	return q
+}
+
+func addressChainsValues(p *params.AddressChainsParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addShortIDs(q, params.KeyAddress, p.Addresses)
+	return q
+}
+
+func outputsValues(p *params.ListOutputsParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addStrings(q, params.KeyChainID, p.ChainIDs)
+	addShortIDs(q, params.KeyAddress, p.Addresses)
+	if p.Spent != nil {
+		q.Set(params.KeySpent, strconv.FormatBool(*p.Spent))
+	}
+	return q
+}
+
+func validatorsAtValues(p *params.ValidatorsAtParams) url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if !p.Time.IsZero() {
+		q.Set(params.KeyTime, strconv.FormatInt(p.Time.Unix(), 10))
+	}
+	if p.Height != nil {
+		q.Set(params.KeyHeight, strconv.FormatUint(*p.Height, 10))
+	}
+	addStrings(q, params.KeyNodeID, p.NodeIDs)
+	return q
+}
+
+func blockchainsValues(p *params.ListBlockchainsParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addStrings(q, params.KeySubnetID, p.SubnetIDs)
+	return q
+}
+
+func crossChainTransfersValues(p *params.ListCrossChainTransfersParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addShortIDs(q, params.KeyAddress, p.Addresses)
+	addStrings(q, params.KeyChainID, p.ChainIDs)
+	if p.Status != "" {
+		q.Set(params.KeyStatus, p.Status)
+	}
+	return q
+}
+
+func utxosValues(p *params.ListUTXOsParams) url.Values {
+	if p == nil {
+		return url.Values{}
+	}
+	q := listValues(&p.ListParams)
+	addShortIDs(q, params.KeyAddress, p.Addresses)
+	addStrings(q, params.KeyChainID, p.ChainIDs)
+	if p.AssetID != nil {
+		q.Set(params.KeyAssetID, p.AssetID.String())
+	}
+	if p.Spendable {
+		q.Set(params.KeySpendable, "true")
+	}
+	return q
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -19,15 +20,21 @@ import (
 
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/chain4travel/magellan/api"
+	"github.com/chain4travel/magellan/audit"
 	"github.com/chain4travel/magellan/balance"
 	"github.com/chain4travel/magellan/caching"
 	"github.com/chain4travel/magellan/cfg"
 	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/discovery"
 	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/outbox"
+	"github.com/chain4travel/magellan/reload"
 	"github.com/chain4travel/magellan/servicesctrl"
+	"github.com/chain4travel/magellan/snapshot"
 	"github.com/chain4travel/magellan/stream"
 	"github.com/chain4travel/magellan/stream/consumers"
 	"github.com/chain4travel/magellan/utils"
+	"github.com/chain4travel/magellan/validate"
 	"github.com/go-sql-driver/mysql"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/gorilla/rpc/v2"
@@ -38,6 +45,7 @@ import (
 	magellanRpc "github.com/chain4travel/magellan/rpc"
 	_ "github.com/golang-migrate/migrate/v4/database"
 	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
@@ -57,16 +65,40 @@ const (
 	envCmdUse  = "env"
 	envCmdDesc = "Displays information about the Magellan environment"
 
+	verifyCmdUse  = "verify"
+	verifyCmdDesc = "Compares the node indexes with the indexed data and reports missing containers"
+
+	verifyChainFlag   = "chain"
+	verifyFromFlag    = "from"
+	verifyToFlag      = "to"
+	verifyEnqueueFlag = "enqueue"
+
+	snapshotCmdUse        = "snapshot"
+	snapshotCmdDesc       = "Exports and imports snapshots of the index database"
+	snapshotExportCmdUse  = "export"
+	snapshotExportCmdDesc = "Writes all tables of the database to a snapshot archive"
+	snapshotImportCmdUse  = "import"
+	snapshotImportCmdDesc = "Loads a snapshot archive into the database"
+
+	snapshotFileFlag     = "file"
+	snapshotTruncateFlag = "truncate"
+
+	configCmdUse          = "config"
+	configCmdDesc         = "Runs config commands"
+	configValidateCmdUse  = "validate"
+	configValidateCmdDesc = "Checks the config file against the node, the databases and the external services and reports the problems"
+
 	defaultReplayQueueSize    = int(2000)
 	defaultReplayQueueThreads = int(4)
 
 	mysqlMigrationFlag          = "run-mysql-migration"
 	mysqlMigrationFlagShorthand = "m"
-	mysqlMigrationFlagDesc      = "Executes migration scripts for the configured mysql database. This might change the schema, use with caution. For more info on migration have a look at the migrations folder in services/db/migrations/ and check the latest commits"
+	mysqlMigrationFlagDesc      = "Executes migration scripts for the configured database. This might change the schema, use with caution. For more info on migration have a look at the migrations folders in services/db/ and check the latest commits"
 
 	mysqlMigrationPathFlag          = "mysql-migration-path"
 	mysqlMigrationPathFlagShorthand = "p"
 	mysqlMigrationPathDefault       = "services/db/migrations"
+	postgresMigrationPathDefault    = "services/db/migrations_postgres"
 )
 
 func main() {
@@ -86,6 +118,7 @@ func execute() error {
 		runMysqlMigration  = func() *bool { s := false; return &s }()
 		mysqlMigrationPath = func() *string { s := mysqlMigrationPathDefault; return &s }()
 		configFile         = func() *string { s := ""; return &s }()
+		configWatcher      *reload.Watcher
 		replayqueuesize    = func() *int { i := defaultReplayQueueSize; return &i }()
 		replayqueuethreads = func() *int { i := defaultReplayQueueThreads; return &i }()
 		cmd                = &cobra.Command{
@@ -108,7 +141,16 @@ func execute() error {
 
 				if *runMysqlMigration {
 					dbConfig := c.Services.DB
-					migrationErr := migrateMysql(fmt.Sprintf("%s://%s", dbConfig.Driver, dbConfig.DSN), *mysqlMigrationPath)
+					migrationDSN := fmt.Sprintf("%s://%s", dbConfig.Driver, dbConfig.DSN)
+					migrationPath := *mysqlMigrationPath
+					if dbConfig.Driver == utils.DriverPostgres {
+						// postgres DSNs are urls already and have their own migration set
+						migrationDSN = dbConfig.DSN
+						if !cmd.Flags().Changed(mysqlMigrationPathFlag) {
+							migrationPath = postgresMigrationPathDefault
+						}
+					}
+					migrationErr := migrateDB(migrationDSN, migrationPath)
 					if migrationErr != nil {
 						log.Fatalf("Failed to run migration: %v", migrationErr)
 					}
@@ -124,7 +166,8 @@ func execute() error {
 				serviceControl.Features = c.Features
 				persist := db.NewPersist()
 				serviceControl.BalanceManager = balance.NewManager(persist, serviceControl)
-				serviceControl.AggregatesCache = caching.NewAggregatesCache()
+				serviceControl.AggregatesCache = caching.NewAggregatesCache(persist, c.CchainID)
+				serviceControl.APICache = caching.NewCache()
 				err = serviceControl.Init(c.NetworkID)
 				if err != nil {
 					log.Fatalln("Failed to create service control", ":", err.Error())
@@ -132,6 +175,16 @@ func execute() error {
 
 				*config = *c
 
+				configWatcher = reload.New(alog, *configFile, c, func(reloaded *cfg.Config) {
+					serviceControl.Reload(reloaded)
+					if err := lf.SetLogLevel("magellan", reloaded.Logging.LogLevel); err != nil {
+						alog.Warn("setting log level failed", zap.Error(err))
+					}
+					if err := lf.SetDisplayLevel("magellan", reloaded.Logging.DisplayLevel); err != nil {
+						alog.Warn("setting display level failed", zap.Error(err))
+					}
+				})
+
 				if config.MetricsListenAddr != "" {
 					sm := http.NewServeMux()
 					sm.Handle("/metrics", promhttp.Handler())
@@ -151,16 +204,12 @@ func execute() error {
 					)
 				}
 				if config.AdminListenAddr != "" {
-					rpcServer := rpc.NewServer()
-					codec := json2.NewCodec()
-					rpcServer.RegisterCodec(codec, "application/json")
-					rpcServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-					api := magellanRpc.NewAPI(alog)
-					if err := rpcServer.RegisterService(api, "api"); err != nil {
+					adminHandler, err := newAdminHandler(serviceControl, config, alog)
+					if err != nil {
 						log.Fatalln("Failed to start admin listener", err.Error())
 					}
 					sm := http.NewServeMux()
-					sm.Handle("/api", rpcServer)
+					sm.Handle("/api", adminHandler)
 					go func() {
 						server := &http.Server{
 							Handler:           sm,
@@ -177,6 +226,15 @@ func execute() error {
 		}
 	)
 
+	// the config is only watched by the long running api and stream commands
+	watchConfig := func() {
+		go func() {
+			if err := configWatcher.Listen(); err != nil {
+				serviceControl.Log.Warn("config watcher failed", zap.Error(err))
+			}
+		}()
+	}
+
 	// Add flags and commands
 	cmd.PersistentFlags().StringVarP(configFile, "config", "c", "config.json", "config file")
 	cmd.PersistentFlags().IntVarP(replayqueuesize, "replayqueuesize", "", defaultReplayQueueSize, fmt.Sprintf("replay queue size default %d", defaultReplayQueueSize))
@@ -187,9 +245,12 @@ func execute() error {
 	cmd.PersistentFlags().StringVarP(mysqlMigrationPath, mysqlMigrationPathFlag, mysqlMigrationPathFlagShorthand, mysqlMigrationPathDefault, "path for mysql migrations")
 
 	cmd.AddCommand(
-		createStreamCmds(serviceControl, config, &runErr),
-		createAPICmds(serviceControl, config, &runErr),
-		createEnvCmds(config, &runErr))
+		createStreamCmds(serviceControl, config, watchConfig, &runErr),
+		createAPICmds(serviceControl, config, watchConfig, &runErr),
+		createEnvCmds(config, &runErr),
+		createVerifyCmds(serviceControl, config, &runErr),
+		createSnapshotCmds(serviceControl, config, &runErr),
+		createConfigCmds(configFile, &runErr))
 
 	// Execute the command and return the runErr to the caller
 	if err := cmd.Execute(); err != nil {
@@ -199,35 +260,45 @@ func execute() error {
 	return runErr
 }
 
-func createAPICmds(sc *servicesctrl.Control, config *cfg.Config, runErr *error) *cobra.Command {
+func createAPICmds(sc *servicesctrl.Control, config *cfg.Config, watchConfig func(), runErr *error) *cobra.Command {
 	return &cobra.Command{
 		Use:   apiCmdUse,
 		Short: apiCmdDesc,
 		Long:  apiCmdDesc,
 		Run: func(cmd *cobra.Command, args []string) {
-			go func() {
-				err := sc.StartCacheScheduler(config)
-				if err != nil {
-					return
-				}
-			}()
+			watchConfig()
 			go func() {
 				err := sc.StartStatisticsScheduler(config)
 				if err != nil {
 					return
 				}
 			}()
+			if err := sc.SeedLabels(config); err != nil {
+				*runErr = err
+				return
+			}
 			lc, err := api.NewServer(sc, *config)
 			if err != nil {
 				*runErr = err
 				return
 			}
+			if config.Discovery.Source != "" {
+				d, err := apiChainDiscovery(sc, config, lc)
+				if err != nil {
+					*runErr = err
+					return
+				}
+				go func() {
+					_ = d.Listen()
+				}()
+				defer d.Close()
+			}
 			runListenCloser(lc)
 		},
 	}
 }
 
-func createStreamCmds(sc *servicesctrl.Control, config *cfg.Config, runErr *error) *cobra.Command {
+func createStreamCmds(sc *servicesctrl.Control, config *cfg.Config, watchConfig func(), runErr *error) *cobra.Command {
 	streamCmd := &cobra.Command{
 		Use:   streamCmdUse,
 		Short: streamCmdDesc,
@@ -244,6 +315,31 @@ func createStreamCmds(sc *servicesctrl.Control, config *cfg.Config, runErr *erro
 		Short: streamIndexerCmdDesc,
 		Long:  streamIndexerCmdDesc,
 		Run: func(cmd *cobra.Command, arg []string) {
+			watchConfig()
+			go func() {
+				err := sc.StartRollupScheduler(config)
+				if err != nil {
+					sc.Log.Warn("aggregate rollup scheduler failed", zap.Error(err))
+				}
+			}()
+			go func() {
+				err := sc.StartRetentionScheduler(config)
+				if err != nil {
+					sc.Log.Warn("retention scheduler failed", zap.Error(err))
+				}
+			}()
+			go func() {
+				err := sc.StartSupplyScheduler(config)
+				if err != nil {
+					sc.Log.Warn("supply scheduler failed", zap.Error(err))
+				}
+			}()
+			go func() {
+				err := sc.StartHeartbeatScheduler(config)
+				if err != nil {
+					sc.Log.Warn("replica heartbeat scheduler failed", zap.Error(err))
+				}
+			}()
 			runStreamProcessorManagers(
 				sc,
 				config,
@@ -267,33 +363,127 @@ func createStreamCmds(sc *servicesctrl.Control, config *cfg.Config, runErr *erro
 func producerFactories(sc *servicesctrl.Control, cfg *cfg.Config) []utils.ListenCloser {
 	var factories []utils.ListenCloser
 	for _, v := range cfg.Chains {
-		switch v.VMType {
-		case models.AVMName:
-			p, err := stream.NewProducerChain(sc, *cfg, v.ID, stream.EventTypeDecisions, stream.IndexTypeTransactions, stream.IndexXChain)
-			if err != nil {
-				panic(err)
+		producers, err := chainProducers(sc, cfg, v, "")
+		if err != nil {
+			panic(err)
+		}
+		factories = append(factories, producers...)
+	}
+	if cfg.Outbox.Sink != "" {
+		factories = append(factories, outboxRelay(sc, cfg))
+	}
+	if cfg.Audit.Interval > 0 {
+		s, err := audit.NewScheduler(sc, *cfg)
+		if err != nil {
+			panic(err)
+		}
+		factories = append(factories, s)
+	}
+	return factories
+}
+
+// chainProducers creates the producers of a chain, they read the node index
+// under the alias, or under the alias of the primary network chain if it is
+// empty
+func chainProducers(sc *servicesctrl.Control, cfg *cfg.Config, chain cfg.Chain, alias string) ([]utils.ListenCloser, error) {
+	type producer struct {
+		eventType    stream.EventType
+		indexType    stream.IndexType
+		indexedChain stream.IndexedChain
+	}
+	var producers []producer
+	switch chain.VMType {
+	case models.AVMName:
+		producers = []producer{
+			{stream.EventTypeDecisions, stream.IndexTypeTransactions, stream.IndexXChain},
+			{stream.EventTypeConsensus, stream.IndexTypeVertices, stream.IndexXChain},
+		}
+	case models.PVMName:
+		producers = []producer{{stream.EventTypeDecisions, stream.IndexTypeBlocks, stream.IndexPChain}}
+	case models.CVMName:
+		producers = []producer{{stream.EventTypeDecisions, stream.IndexTypeBlocks, stream.IndexCChain}}
+	}
+
+	var factories []utils.ListenCloser
+	for _, v := range producers {
+		chainAlias := alias
+		if chainAlias == "" {
+			chainAlias = v.indexedChain.String()
+		}
+		p, err := stream.NewProducerChainAlias(sc, *cfg, chain.ID, v.eventType, v.indexType, v.indexedChain, chainAlias)
+		if err != nil {
+			for _, f := range factories {
+				_ = f.Close()
 			}
-			factories = append(factories, p)
-			p, err = stream.NewProducerChain(sc, *cfg, v.ID, stream.EventTypeConsensus, stream.IndexTypeVertices, stream.IndexXChain)
-			if err != nil {
-				panic(err)
+			return nil, err
+		}
+		factories = append(factories, p)
+	}
+	return factories, nil
+}
+
+// chainDiscovery starts the producers and consumers of the chains it discovers
+// on the P-chain and registers them for the schedulers and the api server, if
+// it runs in this process
+func chainDiscovery(sc *servicesctrl.Control, config *cfg.Config, indexer consumers.Indexer, consumerFactories []consumers.ConsumerFactory, server *api.Server) (utils.ListenCloser, error) {
+	source, err := discovery.NewSource(sc, *config)
+	if err != nil {
+		return nil, err
+	}
+	return discovery.New(sc.Log, *config, source, func(chain discovery.Chain) ([]utils.ListenCloser, error) {
+		chainConfig := cfg.Chain{ID: chain.ID, VMType: chain.VMType()}
+
+		// the bootstrap only knows the genesis of the primary network
+		if chain.Primary() {
+			if err := consumers.BootstrapChain(sc, config.NetworkID, config, chainConfig, consumerFactories); err != nil {
+				return nil, err
 			}
-			factories = append(factories, p)
-		case models.PVMName:
-			p, err := stream.NewProducerChain(sc, *cfg, v.ID, stream.EventTypeDecisions, stream.IndexTypeBlocks, stream.IndexPChain)
-			if err != nil {
-				panic(err)
+		}
+
+		producers, err := chainProducers(sc, config, chainConfig, chain.Alias())
+		if err != nil {
+			return nil, err
+		}
+		if err := indexer.AddChain(chainConfig); err != nil {
+			for _, p := range producers {
+				_ = p.Close()
 			}
-			factories = append(factories, p)
-		case models.CVMName:
-			p, err := stream.NewProducerChain(sc, *cfg, v.ID, stream.EventTypeDecisions, stream.IndexTypeBlocks, stream.IndexCChain)
-			if err != nil {
-				panic(err)
+			return nil, err
+		}
+		sc.AddChain(chainConfig)
+		if server != nil {
+			if err := server.AddChain(chainConfig); err != nil {
+				return producers, err
 			}
-			factories = append(factories, p)
 		}
+		return producers, nil
+	}), nil
+}
+
+// apiChainDiscovery registers the chains the stream process discovers with the
+// api server, it starts no producers or consumers
+func apiChainDiscovery(sc *servicesctrl.Control, config *cfg.Config, server *api.Server) (utils.ListenCloser, error) {
+	source, err := discovery.NewSource(sc, *config)
+	if err != nil {
+		return nil, err
 	}
-	return factories
+	return discovery.New(sc.Log, *config, source, func(chain discovery.Chain) ([]utils.ListenCloser, error) {
+		chainConfig := cfg.Chain{ID: chain.ID, VMType: chain.VMType()}
+		sc.AddChain(chainConfig)
+		return nil, server.AddChain(chainConfig)
+	}), nil
+}
+
+func outboxRelay(sc *servicesctrl.Control, cfg *cfg.Config) utils.ListenCloser {
+	sink, err := outbox.NewSink(cfg.Outbox, cfg.Broker)
+	if err != nil {
+		panic(err)
+	}
+	conns, err := sc.Database()
+	if err != nil {
+		panic(err)
+	}
+	return outbox.NewRelay(conns, sink, sc.Log)
 }
 
 func createEnvCmds(config *cfg.Config, runErr *error) *cobra.Command {
@@ -302,7 +492,8 @@ func createEnvCmds(config *cfg.Config, runErr *error) *cobra.Command {
 		Short: envCmdDesc,
 		Long:  envCmdDesc,
 		Run: func(_ *cobra.Command, _ []string) {
-			configBytes, err := json.MarshalIndent(config, "", "    ")
+			// the secrets are masked, the output ends up in logs
+			configBytes, err := json.MarshalIndent(config.Redacted(), "", "    ")
 			if err != nil {
 				*runErr = err
 				return
@@ -313,6 +504,224 @@ func createEnvCmds(config *cfg.Config, runErr *error) *cobra.Command {
 	}
 }
 
+func createVerifyCmds(sc *servicesctrl.Control, config *cfg.Config, runErr *error) *cobra.Command {
+	var (
+		chainID string
+		from    uint64
+		to      uint64
+		enqueue bool
+	)
+	cmd := &cobra.Command{
+		Use:   verifyCmdUse,
+		Short: verifyCmdDesc,
+		Long:  verifyCmdDesc,
+		Run: func(cmd *cobra.Command, _ []string) {
+			conns, err := sc.Database()
+			if err != nil {
+				*runErr = err
+				return
+			}
+			defer conns.Close()
+
+			ctx := context.Background()
+			auditor := audit.NewAuditor(sc, *config, conns)
+			var (
+				reports []*audit.Report
+				missing int
+			)
+			for _, target := range audit.Targets(*config) {
+				if chainID != "" && target.ChainID != chainID {
+					continue
+				}
+				last := to
+				if !cmd.Flags().Changed(verifyToFlag) {
+					produced, ok, err := auditor.Produced(ctx, target)
+					if err != nil {
+						*runErr = err
+						return
+					}
+					if !ok {
+						continue
+					}
+					last = produced
+				}
+
+				reader := audit.NewContainerReader(*config, target)
+				report, err := auditor.Audit(ctx, target, reader, from, last, enqueue)
+				reader.Close()
+				if err != nil {
+					*runErr = err
+					return
+				}
+				reports = append(reports, report)
+				missing += len(report.Missing)
+			}
+
+			reportBytes, err := json.MarshalIndent(reports, "", "    ")
+			if err != nil {
+				*runErr = err
+				return
+			}
+			fmt.Println(string(reportBytes))
+
+			if missing > 0 {
+				*runErr = fmt.Errorf("%d containers missing", missing)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&chainID, verifyChainFlag, "", "only verify the chain with this id")
+	cmd.Flags().Uint64Var(&from, verifyFromFlag, 0, "first container index to verify")
+	cmd.Flags().Uint64Var(&to, verifyToFlag, 0, "last container index to verify, defaults to the last produced one")
+	cmd.Flags().BoolVar(&enqueue, verifyEnqueueFlag, false, "add the missing containers to tx_pool for re-indexing")
+	return cmd
+}
+
+func createSnapshotCmds(sc *servicesctrl.Control, config *cfg.Config, runErr *error) *cobra.Command {
+	var (
+		file     string
+		truncate bool
+	)
+	snapshotCmd := &cobra.Command{
+		Use:   snapshotCmdUse,
+		Short: snapshotCmdDesc,
+		Long:  snapshotCmdDesc,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+			os.Exit(0)
+		},
+	}
+	snapshotCmd.PersistentFlags().StringVar(&file, snapshotFileFlag, "magellan-snapshot.tar.gz", "path of the snapshot archive")
+
+	exportCmd := &cobra.Command{
+		Use:   snapshotExportCmdUse,
+		Short: snapshotExportCmdDesc,
+		Long:  snapshotExportCmdDesc,
+		Run: func(_ *cobra.Command, _ []string) {
+			conns, err := sc.Database()
+			if err != nil {
+				*runErr = err
+				return
+			}
+			defer conns.Close()
+
+			f, err := os.Create(file)
+			if err != nil {
+				*runErr = err
+				return
+			}
+			manifest, err := snapshot.Export(context.Background(), conns.Primary(), config.NetworkID, f)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				_ = os.Remove(file)
+				*runErr = err
+				return
+			}
+			sc.Log.Info("snapshot exported",
+				zap.String("file", file),
+				zap.Int64("schemaVersion", manifest.SchemaVersion),
+				zap.Int("tables", len(manifest.Tables)),
+			)
+		},
+	}
+
+	importCmd := &cobra.Command{
+		Use:   snapshotImportCmdUse,
+		Short: snapshotImportCmdDesc,
+		Long:  snapshotImportCmdDesc,
+		Run: func(_ *cobra.Command, _ []string) {
+			conns, err := sc.Database()
+			if err != nil {
+				*runErr = err
+				return
+			}
+			defer conns.Close()
+
+			manifest, err := snapshot.Import(context.Background(), conns.Primary(), config.NetworkID, file, truncate)
+			if err != nil {
+				*runErr = err
+				return
+			}
+			sc.Log.Info("snapshot imported",
+				zap.String("file", file),
+				zap.Time("createdAt", manifest.CreatedAt),
+				zap.Int("tables", len(manifest.Tables)),
+			)
+		},
+	}
+	importCmd.Flags().BoolVar(&truncate, snapshotTruncateFlag, false, "delete the rows of the tables before importing")
+
+	snapshotCmd.AddCommand(exportCmd, importCmd)
+	return snapshotCmd
+}
+
+func createConfigCmds(configFile *string, runErr *error) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   configCmdUse,
+		Short: configCmdDesc,
+		Long:  configCmdDesc,
+		// the config is checked, not loaded, so a broken config is reported
+		// instead of stopping the command
+		PersistentPreRun: func(*cobra.Command, []string) {},
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+			os.Exit(0)
+		},
+	}
+
+	validateCmd := &cobra.Command{
+		Use:   configValidateCmdUse,
+		Short: configValidateCmdDesc,
+		Long:  configValidateCmdDesc,
+		Run: func(_ *cobra.Command, _ []string) {
+			report := validate.Validate(context.Background(), *configFile)
+
+			reportBytes, err := json.MarshalIndent(report, "", "    ")
+			if err != nil {
+				*runErr = err
+				return
+			}
+			fmt.Println(string(reportBytes))
+
+			if !report.Valid {
+				*runErr = fmt.Errorf("config %s is invalid", *configFile)
+			}
+		},
+	}
+
+	configCmd.AddCommand(validateCmd)
+	return configCmd
+}
+
+// newAdminHandler creates the json rpc handler of the admin listener. The
+// labels and the operations on the indexer are only served with an admin
+// token, which then authorizes all requests.
+func newAdminHandler(sc *servicesctrl.Control, config *cfg.Config, alog logging.Logger) (http.Handler, error) {
+	rpcServer := rpc.NewServer()
+	codec := json2.NewCodec()
+	rpcServer.RegisterCodec(codec, "application/json")
+	rpcServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	if err := rpcServer.RegisterService(magellanRpc.NewAPI(alog), "api"); err != nil {
+		return nil, err
+	}
+	if config.AdminToken == "" {
+		alog.Warn("no adminToken configured, the admin and labels services are disabled")
+		return rpcServer, nil
+	}
+	conns, err := sc.Database()
+	if err != nil {
+		return nil, err
+	}
+	if err := rpcServer.RegisterService(magellanRpc.NewLabelsAPI(alog, sc.Persist, conns), "labels"); err != nil {
+		return nil, err
+	}
+	if err := rpcServer.RegisterService(magellanRpc.NewAdminAPI(alog, sc, *config, conns), "admin"); err != nil {
+		return nil, err
+	}
+	return magellanRpc.Authorize(config.AdminToken, rpcServer), nil
+}
+
 // runListenCloser runs the ListenCloser until signaled to stop
 func runListenCloser(lc utils.ListenCloser) {
 	// Start listening in the background
@@ -367,19 +776,33 @@ func runStreamProcessorManagers(
 
 		runningControl := utils.NewRunning()
 
-		err = consumers.IndexerFactories(sc, config, factoriesChainDB, factoriesInstDB, wg, runningControl)
+		indexerFactories := consumers.IndexerFactories
+		if config.Broker.Driver != "" && config.Broker.Index {
+			indexerFactories = consumers.BrokerIndexerFactories
+		}
+		indexer, err := indexerFactories(sc, config, factoriesChainDB, factoriesInstDB, wg, runningControl)
 		if err != nil {
 			*runError = err
 			return
 		}
 
+		var server *api.Server
 		if len(arg) > 0 && arg[0] == "api" {
-			lc, err := api.NewServer(sc, *config)
+			server, err = api.NewServer(sc, *config)
 			if err != nil {
 				log.Fatalln("API listen error:", err.Error())
 			} else {
-				listenCloseFactories = append(listenCloseFactories, lc)
+				listenCloseFactories = append(listenCloseFactories, server)
+			}
+		}
+
+		if config.Discovery.Source != "" {
+			d, err := chainDiscovery(sc, config, indexer, consumerFactories, server)
+			if err != nil {
+				*runError = err
+				return
 			}
+			listenCloseFactories = append(listenCloseFactories, d)
 		}
 
 		for _, listenCloseFactory := range listenCloseFactories {
@@ -420,9 +843,9 @@ func (m *MysqlLogger) Print(v ...interface{}) {
 	)
 }
 
-func migrateMysql(mysqlDSN, migrationsPath string) error {
+func migrateDB(dsn, migrationsPath string) error {
 	migrationSource := fmt.Sprintf("file://%v", migrationsPath)
-	migrater, migraterErr := migrate.New(migrationSource, mysqlDSN)
+	migrater, migraterErr := migrate.New(migrationSource, dsn)
 	if migraterErr != nil {
 		return migraterErr
 	}
@@ -431,7 +854,7 @@ func migrateMysql(mysqlDSN, migrationsPath string) error {
 		if err != migrate.ErrNoChange {
 			return err
 		}
-		log.Println("[mysql]: no new migrations to execute")
+		log.Println("[db]: no new migrations to execute")
 	}
 
 	return nil
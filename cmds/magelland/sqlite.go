@@ -0,0 +1,19 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build sqlite
+
+package main
+
+// sqlite driver, registered as utils.SqliteDriverName, it needs cgo
+import _ "github.com/chain4travel/magellan/utils/sqlite"
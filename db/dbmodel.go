@@ -56,6 +56,17 @@ const (
 	TableMultisigAliases                = "multisig_aliases"
 	TableReward                         = "reward"
 	TableRewardOwner                    = "reward_owner"
+	TableAggregateRollupsHourly         = "aggregate_rollups_hourly"
+	TableAggregateRollupsDaily          = "aggregate_rollups_daily"
+	TableOutboxEvents                   = "outbox_events"
+	TableSubnets                        = "subnets"
+	TableSubnetOwners                   = "subnet_owners"
+	TableBlockchains                    = "blockchains"
+	TableSubnetValidators               = "subnet_validators"
+	TableCrossChainTransfers            = "cross_chain_transfers"
+	TableSupplyHistory                  = "supply_history"
+	TableAddressLabels                  = "address_labels"
+	TableReplicaHeartbeat               = "replica_heartbeat"
 )
 
 type Persist interface {
@@ -262,6 +273,11 @@ type Persist interface {
 		*TransactionsValidator,
 		bool,
 	) error
+	UpdateTransactionsValidatorRemoved(
+		context.Context,
+		dbr.SessionRunner,
+		*TransactionsValidator,
+	) error
 
 	QueryTransactionsBlock(
 		context.Context,
@@ -376,6 +392,10 @@ type Persist interface {
 		dbr.SessionRunner,
 		*TxPool,
 	) error
+	QueryTxPoolBacklog(
+		context.Context,
+		dbr.SessionRunner,
+	) ([]*TxPoolBacklog, error)
 
 	QueryKeyValueStore(
 		context.Context,
@@ -440,6 +460,131 @@ type Persist interface {
 		dbr.SessionRunner,
 		*Reward,
 	) error
+
+	QueryAggregateRollupHourly(
+		context.Context,
+		dbr.SessionRunner,
+		*AggregateRollup,
+	) (*AggregateRollup, error)
+	InsertAggregateRollupHourly(
+		context.Context,
+		dbr.SessionRunner,
+		*AggregateRollup,
+		bool,
+	) error
+
+	QueryAggregateRollupDaily(
+		context.Context,
+		dbr.SessionRunner,
+		*AggregateRollup,
+	) (*AggregateRollup, error)
+	InsertAggregateRollupDaily(
+		context.Context,
+		dbr.SessionRunner,
+		*AggregateRollup,
+		bool,
+	) error
+
+	QueryOutboxEvent(
+		context.Context,
+		dbr.SessionRunner,
+		*OutboxEvent,
+	) (*OutboxEvent, error)
+	InsertOutboxEvent(
+		context.Context,
+		dbr.SessionRunner,
+		*OutboxEvent,
+	) error
+	RemoveOutboxEvent(
+		context.Context,
+		dbr.SessionRunner,
+		*OutboxEvent,
+	) error
+
+	InsertSubnet(
+		context.Context,
+		dbr.SessionRunner,
+		*Subnet,
+		bool,
+	) error
+	UpdateSubnetAssetID(
+		context.Context,
+		dbr.SessionRunner,
+		*Subnet,
+	) error
+	InsertSubnetOwner(
+		context.Context,
+		dbr.SessionRunner,
+		*SubnetOwner,
+	) error
+
+	InsertBlockchain(
+		context.Context,
+		dbr.SessionRunner,
+		*Blockchain,
+		bool,
+	) error
+
+	InsertSubnetValidator(
+		context.Context,
+		dbr.SessionRunner,
+		*SubnetValidator,
+		bool,
+	) error
+	UpdateSubnetValidatorRemoved(
+		context.Context,
+		dbr.SessionRunner,
+		*SubnetValidator,
+	) error
+
+	InsertCrossChainTransferExport(
+		context.Context,
+		dbr.SessionRunner,
+		*CrossChainTransfer,
+	) error
+	InsertCrossChainTransferImport(
+		context.Context,
+		dbr.SessionRunner,
+		*CrossChainTransfer,
+	) error
+
+	QuerySupply(
+		context.Context,
+		dbr.SessionRunner,
+		*Supply,
+	) (*Supply, error)
+	InsertSupply(
+		context.Context,
+		dbr.SessionRunner,
+		*Supply,
+	) error
+
+	QueryAddressLabels(
+		context.Context,
+		dbr.SessionRunner,
+		[]string,
+	) ([]*AddressLabel, error)
+	InsertAddressLabel(
+		context.Context,
+		dbr.SessionRunner,
+		*AddressLabel,
+	) error
+	DeleteAddressLabel(
+		context.Context,
+		dbr.SessionRunner,
+		string,
+	) error
+
+	QueryReplicaHeartbeat(
+		context.Context,
+		dbr.SessionRunner,
+		*ReplicaHeartbeat,
+	) (*ReplicaHeartbeat, error)
+	InsertReplicaHeartbeat(
+		context.Context,
+		dbr.SessionRunner,
+		*ReplicaHeartbeat,
+	) error
 }
 
 type persist struct{}
@@ -507,7 +652,7 @@ func (p *persist) InsertTransactions(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableTransactions).
 		Pair("id", v.ID).
 		Pair("chain_id", v.ChainID).
@@ -518,8 +663,7 @@ func (p *persist) InsertTransactions(
 		Pair("txfee", v.Txfee).
 		Pair("genesis", v.Genesis).
 		Pair("network_id", v.NetworkID).
-		Pair("status", v.Status).
-		ExecContext(ctx)
+		Pair("status", v.Status))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableTransactions, false, err)
 	}
@@ -585,7 +729,7 @@ func (p *persist) InsertOutputsRedeeming(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableOutputsRedeeming).
 		Pair("id", v.ID).
 		Pair("redeemed_at", v.RedeemedAt).
@@ -595,8 +739,7 @@ func (p *persist) InsertOutputsRedeeming(
 		Pair("intx", v.Intx).
 		Pair("asset_id", v.AssetID).
 		Pair("created_at", v.CreatedAt).
-		Pair("chain_id", v.ChainID).
-		ExecContext(ctx)
+		Pair("chain_id", v.ChainID))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableOutputsRedeeming, false, err)
 	}
@@ -636,6 +779,8 @@ type Outputs struct {
 	Frozen        bool
 	Stakeableout  bool
 	Genesisutxo   bool
+	DepositTxID   string
+	BondTxID      string
 	CreatedAt     time.Time
 }
 
@@ -662,6 +807,8 @@ func (p *persist) QueryOutputs(
 		"frozen",
 		"stakeableout",
 		"genesisutxo",
+		"deposit_tx_id",
+		"bond_tx_id",
 		"created_at",
 	).From(TableOutputs).
 		Where("id=?", q.ID).
@@ -676,7 +823,7 @@ func (p *persist) InsertOutputs(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableOutputs).
 		Pair("id", v.ID).
 		Pair("chain_id", v.ChainID).
@@ -694,8 +841,9 @@ func (p *persist) InsertOutputs(
 		Pair("frozen", v.Frozen).
 		Pair("stakeableout", v.Stakeableout).
 		Pair("genesisutxo", v.Genesisutxo).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("deposit_tx_id", v.DepositTxID).
+		Pair("bond_tx_id", v.BondTxID).
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableOutputs, false, err)
 	}
@@ -717,6 +865,8 @@ func (p *persist) InsertOutputs(
 			Set("frozen", v.Frozen).
 			Set("stakeableout", v.Stakeableout).
 			Set("genesisutxo", v.Genesisutxo).
+			Set("deposit_tx_id", v.DepositTxID).
+			Set("bond_tx_id", v.BondTxID).
 			Set("created_at", v.CreatedAt).
 			Where("id = ?", v.ID).
 			ExecContext(ctx)
@@ -766,7 +916,7 @@ func (p *persist) InsertAssets(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableAssets).
 		Pair("id", v.ID).
 		Pair("chain_Id", v.ChainID).
@@ -775,8 +925,7 @@ func (p *persist) InsertAssets(
 		Pair("denomination", v.Denomination).
 		Pair("alias", v.Alias).
 		Pair("current_supply", v.CurrentSupply).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableAssets, false, err)
 	}
@@ -830,13 +979,12 @@ func (p *persist) InsertAddresses(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableAddresses).
 		Pair("address", v.Address).
 		Pair("public_key", v.PublicKey).
 		Pair("created_at", v.CreatedAt).
-		Pair("updated_at", v.UpdatedAt).
-		ExecContext(ctx)
+		Pair("updated_at", v.UpdatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableAddresses, false, err)
 	}
@@ -887,13 +1035,12 @@ func (p *persist) InsertAddressChain(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableAddressChain).
 		Pair("address", v.Address).
 		Pair("chain_id", v.ChainID).
 		Pair("created_at", v.CreatedAt).
-		Pair("updated_at", v.UpdatedAt).
-		ExecContext(ctx)
+		Pair("updated_at", v.UpdatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableAddressChain, false, err)
 	}
@@ -953,7 +1100,7 @@ func (p *persist) InsertOutputAddresses(
 	if v.RedeemingSignature != nil {
 		stmt = stmt.Pair("redeeming_signature", v.RedeemingSignature)
 	}
-	_, err = stmt.ExecContext(ctx)
+	_, err = insertIgnore(ctx, sess, stmt)
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableOutputAddresses, false, err)
 	}
@@ -1024,13 +1171,12 @@ func (p *persist) InsertTransactionsEpoch(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableTransactionsEpochs).
 		Pair("id", v.ID).
 		Pair("epoch", v.Epoch).
 		Pair("vertex_id", v.VertexID).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableTransactionsEpochs, false, err)
 	}
@@ -1091,10 +1237,9 @@ func (p *persist) InsertCvmBlocks(
 	v *CvmBlocks,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertBySql("insert into "+TableCvmBlocks+" (block,hash,chain_id,evm_tx,atomic_tx,serialization,created_at, proposer,proposer_time,size) values("+v.Block+",?,?,?,?,?,?,?,?,?)",
-			v.Hash, v.ChainID, v.EvmTx, v.AtomicTx, v.Serialization, v.CreatedAt, v.Proposer, v.ProposerTime, v.Size).
-		ExecContext(ctx)
+			v.Hash, v.ChainID, v.EvmTx, v.AtomicTx, v.Serialization, v.CreatedAt, v.Proposer, v.ProposerTime, v.Size))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableCvmBlocks, false, err)
 	}
@@ -1156,10 +1301,9 @@ func (p *persist) InsertCamLastBlockCache(
 			return EventErr(TableCamLastBlockCache, true, err)
 		}
 	} else {
-		_, err = sess.
+		_, err = insertIgnore(ctx, sess, sess.
 			InsertBySql("insert into "+TableCamLastBlockCache+" (current_block,chainid) values("+v.CurrentBlock+",?)",
-				v.ChainID).
-			ExecContext(ctx)
+				v.ChainID))
 		if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 			return EventErr(TableCamLastBlockCache, false, err)
 		}
@@ -1208,7 +1352,7 @@ func (p *persist) InsertCvmAddresses(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableCvmAddresses).
 		Pair("id", v.ID).
 		Pair("type", v.Type).
@@ -1218,8 +1362,7 @@ func (p *persist) InsertCvmAddresses(
 		Pair("asset_id", v.AssetID).
 		Pair("amount", v.Amount).
 		Pair("nonce", v.Nonce).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableCvmAddresses, false, err)
 	}
@@ -1276,10 +1419,9 @@ func (p *persist) InsertCvmTransactionsAtomic(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertBySql("insert into "+TableCvmTransactionsAtomic+" (transaction_id,block,chain_id,type,created_at) values(?,"+v.Block+",?,?,?)",
-			v.TransactionID, v.ChainID, v.Type, v.CreatedAt).
-		ExecContext(ctx)
+			v.TransactionID, v.ChainID, v.Type, v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableCvmTransactionsAtomic, false, err)
 	}
@@ -1306,6 +1448,7 @@ type CvmTransactionsTxdata struct {
 	Status        uint16
 	GasUsed       uint64
 	GasPrice      uint64
+	BaseFee       uint64
 	Serialization []byte
 	Receipt       []byte
 	CreatedAt     time.Time
@@ -1328,6 +1471,7 @@ func (p *persist) QueryCvmTransactionsTxdata(
 		"status",
 		"gas_used",
 		"gas_price",
+		"base_fee",
 		"serialization",
 		"receipt",
 		"created_at",
@@ -1346,7 +1490,7 @@ func (p *persist) InsertCvmTransactionsTxdata(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableCvmTransactionsTxdata).
 		Pair("hash", v.Hash).
 		Pair("block", v.Block).
@@ -1358,10 +1502,10 @@ func (p *persist) InsertCvmTransactionsTxdata(
 		Pair("status", v.Status).
 		Pair("gas_used", v.GasUsed).
 		Pair("gas_price", v.GasPrice).
+		Pair("base_fee", v.BaseFee).
 		Pair("serialization", v.Serialization).
 		Pair("receipt", v.Receipt).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableCvmTransactionsTxdata, false, err)
 	}
@@ -1377,6 +1521,7 @@ func (p *persist) InsertCvmTransactionsTxdata(
 			Set("status", v.Status).
 			Set("gas_used", v.GasUsed).
 			Set("gas_price", v.GasPrice).
+			Set("base_fee", v.BaseFee).
 			Set("serialization", v.Serialization).
 			Set("receipt", v.Receipt).
 			Set("created_at", v.CreatedAt).
@@ -1419,22 +1564,20 @@ func (p *persist) InsertCvmAccount(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	stmt := sess.
 		InsertInto(TableCvmAccounts).
 		Pair("address", v.Address).
 		Pair("tx_count", v.TxCount).
-		Pair("creation_tx", v.CreationTx).
-		ExecContext(ctx)
-	if err == nil {
+		Pair("creation_tx", v.CreationTx)
+	if !upd {
+		_, err = stmt.ExecContext(ctx)
+		if err != nil {
+			return EventErr(TableCvmAccounts, false, err)
+		}
 		return nil
-	} else if !upd || !utils.ErrIsDuplicateEntryError(err) {
-		return EventErr(TableCvmAccounts, false, err)
 	}
-	_, err = sess.
-		Update(TableCvmAccounts).
-		IncrBy("tx_count", v.TxCount).
-		Where("address=?", v.Address).
-		ExecContext(ctx)
+	_, err = upsert(ctx, sess, stmt, []string{"address"},
+		"tx_count="+TableCvmAccounts+".tx_count+excluded.tx_count")
 	if err != nil {
 		return EventErr(TableCvmAccounts, true, err)
 	}
@@ -1482,7 +1625,7 @@ func (p *persist) InsertPvmBlocks(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TablePvmBlocks).
 		Pair("id", v.ID).
 		Pair("chain_id", v.ChainID).
@@ -1492,8 +1635,7 @@ func (p *persist) InsertPvmBlocks(
 		Pair("serialization", v.Serialization).
 		Pair("height", v.Height).
 		Pair("proposer", v.Proposer).
-		Pair("proposer_time", v.ProposerTime).
-		ExecContext(ctx)
+		Pair("proposer_time", v.ProposerTime))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TablePvmBlocks, false, err)
 	}
@@ -1523,6 +1665,9 @@ type TransactionsValidator struct {
 	NodeID    string
 	Start     uint64
 	End       uint64
+	Weight    uint64
+	Delegator bool
+	RemovedAt *time.Time
 	CreatedAt time.Time
 }
 
@@ -1537,6 +1682,9 @@ func (p *persist) QueryTransactionsValidator(
 		"node_id",
 		"start",
 		"end",
+		"weight",
+		"delegator",
+		"removed_at",
 		"created_at",
 	).From(TableTransactionsValidator).
 		Where("id=?", q.ID).
@@ -1551,14 +1699,15 @@ func (p *persist) InsertTransactionsValidator(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableTransactionsValidator).
 		Pair("id", v.ID).
 		Pair("node_id", v.NodeID).
 		Pair("start", v.Start).
 		Pair("end", v.End).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("weight", v.Weight).
+		Pair("delegator", v.Delegator).
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableTransactionsValidator, false, err)
 	}
@@ -1568,6 +1717,8 @@ func (p *persist) InsertTransactionsValidator(
 			Set("node_id", v.NodeID).
 			Set("start", v.Start).
 			Set("end", v.End).
+			Set("weight", v.Weight).
+			Set("delegator", v.Delegator).
 			Set("created_at", v.CreatedAt).
 			Where("id = ?", v.ID).
 			ExecContext(ctx)
@@ -1578,6 +1729,25 @@ func (p *persist) InsertTransactionsValidator(
 	return nil
 }
 
+// UpdateTransactionsValidatorRemoved records the time the validator or
+// delegator of the transaction left the validator set, which is the time of its
+// reward transaction
+func (p *persist) UpdateTransactionsValidatorRemoved(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *TransactionsValidator,
+) error {
+	_, err := sess.
+		Update(TableTransactionsValidator).
+		Set("removed_at", v.RemovedAt).
+		Where("id = ?", v.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return EventErr(TableTransactionsValidator, true, err)
+	}
+	return nil
+}
+
 type TransactionsBlock struct {
 	ID        string
 	TxBlockID string
@@ -1607,12 +1777,11 @@ func (p *persist) InsertTransactionsBlock(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableTransactionsBlock).
 		Pair("id", v.ID).
 		Pair("tx_block_id", v.TxBlockID).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableTransactionsBlock, false, err)
 	}
@@ -1659,12 +1828,11 @@ func (p *persist) InsertAddressBech32(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableAddressBech32).
 		Pair("address", v.Address).
 		Pair("bech32_address", v.Bech32Address).
-		Pair("updated_at", v.UpdatedAt).
-		ExecContext(ctx)
+		Pair("updated_at", v.UpdatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableAddressBech32, false, err)
 	}
@@ -1726,15 +1894,14 @@ func (p *persist) InsertOutputAddressAccumulateOut(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableOutputAddressAccumulateOut).
 		Pair("id", v.ID).
 		Pair("output_id", v.OutputID).
 		Pair("address", v.Address).
 		Pair("transaction_id", v.TransactionID).
 		Pair("output_index", v.OutputIndex).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableOutputAddressAccumulateOut, false, err)
 	}
@@ -1784,15 +1951,14 @@ func (p *persist) InsertOutputAddressAccumulateIn(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableOutputAddressAccumulateIn).
 		Pair("id", v.ID).
 		Pair("output_id", v.OutputID).
 		Pair("address", v.Address).
 		Pair("transaction_id", v.TransactionID).
 		Pair("output_index", v.OutputIndex).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableOutputAddressAccumulateIn, false, err)
 	}
@@ -1873,15 +2039,14 @@ func (p *persist) InsertOutputTxsAccumulate(
 	v *OutputTxsAccumulate,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableOutputTxsAccumulate).
 		Pair("id", v.ID).
 		Pair("chain_id", v.ChainID).
 		Pair("asset_id", v.AssetID).
 		Pair("address", v.Address).
 		Pair("transaction_id", v.TransactionID).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableOutputTxsAccumulate, false, err)
 	}
@@ -1931,14 +2096,13 @@ func (p *persist) InsertAccumulateBalancesReceived(
 	v *AccumulateBalancesAmount,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableAccumulateBalancesReceived).
 		Pair("id", v.ID).
 		Pair("chain_id", v.ChainID).
 		Pair("asset_id", v.AssetID).
 		Pair("address", v.Address).
-		Pair("updated_at", v.UpdatedAt).
-		ExecContext(ctx)
+		Pair("updated_at", v.UpdatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableAccumulateBalancesReceived, false, err)
 	}
@@ -1972,14 +2136,13 @@ func (p *persist) InsertAccumulateBalancesSent(
 	v *AccumulateBalancesAmount,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableAccumulateBalancesSent).
 		Pair("id", v.ID).
 		Pair("chain_id", v.ChainID).
 		Pair("asset_id", v.AssetID).
 		Pair("address", v.Address).
-		Pair("updated_at", v.UpdatedAt).
-		ExecContext(ctx)
+		Pair("updated_at", v.UpdatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableAccumulateBalancesSent, false, err)
 	}
@@ -2027,14 +2190,13 @@ func (p *persist) InsertAccumulateBalancesTransactions(
 	v *AccumulateBalancesTransactions,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableAccumulateBalancesTransactions).
 		Pair("id", v.ID).
 		Pair("chain_id", v.ChainID).
 		Pair("asset_id", v.AssetID).
 		Pair("address", v.Address).
-		Pair("updated_at", v.UpdatedAt).
-		ExecContext(ctx)
+		Pair("updated_at", v.UpdatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableAccumulateBalancesTransactions, false, err)
 	}
@@ -2084,7 +2246,7 @@ func (p *persist) InsertTxPool(
 	v *TxPool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableTxPool).
 		Pair("id", v.ID).
 		Pair("network_id", v.NetworkID).
@@ -2092,8 +2254,7 @@ func (p *persist) InsertTxPool(
 		Pair("msg_key", v.MsgKey).
 		Pair("serialization", v.Serialization).
 		Pair("topic", v.Topic).
-		Pair("created_at", v.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", v.CreatedAt))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableTxPool, false, err)
 	}
@@ -2118,6 +2279,44 @@ func (p *persist) RemoveTxPool(
 	return nil
 }
 
+// TxPoolBacklog is the number of containers of a topic waiting in the tx_pool
+// to be indexed
+type TxPoolBacklog struct {
+	Topic    string
+	Count    uint64
+	OldestAt time.Time
+}
+
+func (p *persist) QueryTxPoolBacklog(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+) ([]*TxPoolBacklog, error) {
+	var backlog []*TxPoolBacklog
+	_, err := sess.Select(
+		"topic",
+		"COUNT(*) AS count",
+	).From(TableTxPool).
+		GroupBy("topic").
+		OrderAsc("topic").
+		LoadContext(ctx, &backlog)
+	if err != nil {
+		return nil, err
+	}
+	// read by itself, sqlite loses the type of the column in MIN
+	for _, v := range backlog {
+		err = sess.Select("created_at").
+			From(TableTxPool).
+			Where("topic=?", v.Topic).
+			OrderAsc("created_at").
+			Limit(1).
+			LoadOneContext(ctx, &v.OldestAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return backlog, nil
+}
+
 type KeyValueStore struct {
 	K string
 	V string
@@ -2144,11 +2343,10 @@ func (p *persist) InsertKeyValueStore(
 	v *KeyValueStore,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableKeyValueStore).
 		Pair("k", v.K).
-		Pair("v", v.V).
-		ExecContext(ctx)
+		Pair("v", v.V))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableKeyValueStore, false, err)
 	}
@@ -2185,12 +2383,11 @@ func (p *persist) InsertNodeIndex(
 	upd bool,
 ) error {
 	var err error
-	_, err = sess.
+	_, err = insertIgnore(ctx, sess, sess.
 		InsertInto(TableNodeIndex).
 		Pair("instance", v.Instance).
 		Pair("topic", v.Topic).
-		Pair("idx", v.Idx).
-		ExecContext(ctx)
+		Pair("idx", v.Idx))
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableNodeIndex, false, err)
 	}
@@ -2253,14 +2450,13 @@ type MultisigAlias struct {
 
 func (p *persist) InsertMultisigAlias(ctx context.Context, session dbr.SessionRunner, alias *MultisigAlias) error {
 	var err error
-	_, err = session.
+	_, err = insertIgnore(ctx, session, session.
 		InsertInto(TableMultisigAliases).
 		Pair("alias", alias.Alias).
 		Pair("memo", alias.Memo).
 		Pair("owner", alias.Owner).
 		Pair("transaction_id", alias.TransactionID).
-		Pair("created_at", alias.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", alias.CreatedAt))
 
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableMultisigAliases, false, err)
@@ -2321,12 +2517,11 @@ type RewardOwner struct {
 
 func (p *persist) InsertRewardOwner(ctx context.Context, session dbr.SessionRunner, owner *RewardOwner) error {
 	var err error
-	_, err = session.
+	_, err = insertIgnore(ctx, session, session.
 		InsertInto(TableRewardOwner).
 		Pair("address", owner.Address).
 		Pair("hash", owner.Hash).
-		Pair("created_at", owner.CreatedAt).
-		ExecContext(ctx)
+		Pair("created_at", owner.CreatedAt))
 
 	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
 		return EventErr(TableRewardOwner, false, err)
@@ -2351,15 +2546,16 @@ type Reward struct {
 
 func (p *persist) InsertReward(ctx context.Context, session dbr.SessionRunner, reward *Reward) error {
 	var err error
-	_, err = session.
-		InsertBySql("INSERT INTO "+TableReward+" (reward_owner_bytes, reward_owner_hash, tx_id, type, updated_at) VALUES(?,?,?,?,?) ON DUPLICATE KEY UPDATE updated_at=?",
-			reward.RewardOwnerBytes,
-			reward.RewardOwnerHash,
-			reward.TxID,
-			reward.Type,
-			reward.CreatedAt,
-			reward.CreatedAt,
-		).ExecContext(ctx)
+	_, err = upsert(ctx, session, session.
+		InsertInto(TableReward).
+		Pair("reward_owner_bytes", reward.RewardOwnerBytes).
+		Pair("reward_owner_hash", reward.RewardOwnerHash).
+		Pair("tx_id", reward.TxID).
+		Pair("type", reward.Type).
+		Pair("updated_at", reward.CreatedAt),
+		[]string{"reward_owner_hash", "tx_id", "type"},
+		"updated_at=excluded.updated_at",
+	)
 	if err != nil {
 		return EventErr(TableReward, false, err)
 	}
@@ -2390,3 +2586,616 @@ func (p *persist) DeactivateReward(ctx context.Context, session dbr.SessionRunne
 
 	return p.InsertReward(ctx, session, v)
 }
+
+type AggregateRollup struct {
+	ChainID          string
+	BucketAt         time.Time
+	TransactionCount uint64
+	Txfee            uint64
+	UpdatedAt        time.Time
+}
+
+func (p *persist) QueryAggregateRollupHourly(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	q *AggregateRollup,
+) (*AggregateRollup, error) {
+	return p.queryAggregateRollup(ctx, sess, TableAggregateRollupsHourly, q)
+}
+
+func (p *persist) InsertAggregateRollupHourly(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *AggregateRollup,
+	upd bool,
+) error {
+	return p.insertAggregateRollup(ctx, sess, TableAggregateRollupsHourly, v, upd)
+}
+
+func (p *persist) QueryAggregateRollupDaily(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	q *AggregateRollup,
+) (*AggregateRollup, error) {
+	return p.queryAggregateRollup(ctx, sess, TableAggregateRollupsDaily, q)
+}
+
+func (p *persist) InsertAggregateRollupDaily(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *AggregateRollup,
+	upd bool,
+) error {
+	return p.insertAggregateRollup(ctx, sess, TableAggregateRollupsDaily, v, upd)
+}
+
+func (p *persist) queryAggregateRollup(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	table string,
+	q *AggregateRollup,
+) (*AggregateRollup, error) {
+	v := &AggregateRollup{}
+	err := sess.Select(
+		"chain_id",
+		"bucket_at",
+		"transaction_count",
+		"txfee",
+		"updated_at",
+	).From(table).
+		Where("chain_id=? and bucket_at=?", q.ChainID, q.BucketAt).
+		LoadOneContext(ctx, v)
+	return v, err
+}
+
+func (p *persist) insertAggregateRollup(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	table string,
+	v *AggregateRollup,
+	upd bool,
+) error {
+	var err error
+	_, err = insertIgnore(ctx, sess, sess.
+		InsertInto(table).
+		Pair("chain_id", v.ChainID).
+		Pair("bucket_at", v.BucketAt).
+		Pair("transaction_count", v.TransactionCount).
+		Pair("txfee", v.Txfee).
+		Pair("updated_at", v.UpdatedAt))
+	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
+		return EventErr(table, false, err)
+	}
+	if upd {
+		_, err = sess.
+			Update(table).
+			Set("transaction_count", v.TransactionCount).
+			Set("txfee", v.Txfee).
+			Set("updated_at", v.UpdatedAt).
+			Where("chain_id=? and bucket_at=?", v.ChainID, v.BucketAt).
+			ExecContext(ctx)
+		if err != nil {
+			return EventErr(table, true, err)
+		}
+	}
+	return nil
+}
+
+// OutboxEvent is an event for downstream systems, written in the transaction
+// which indexes its subject and removed once it has been delivered
+type OutboxEvent struct {
+	ID        string
+	ChainID   string
+	TxID      string
+	Type      string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+func (b *OutboxEvent) ComputeID() {
+	idsv := fmt.Sprintf("%s:%s:%s", b.ChainID, b.TxID, b.Type)
+	id := ids.ID(hashing.ComputeHash256Array([]byte(idsv)))
+	b.ID = id.String()
+}
+
+func (p *persist) QueryOutboxEvent(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	q *OutboxEvent,
+) (*OutboxEvent, error) {
+	v := &OutboxEvent{}
+	err := sess.Select(
+		"id",
+		"chain_id",
+		"tx_id",
+		"type",
+		"payload",
+		"created_at",
+	).From(TableOutboxEvents).
+		Where("id=?", q.ID).
+		LoadOneContext(ctx, v)
+	return v, err
+}
+
+func (p *persist) InsertOutboxEvent(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *OutboxEvent,
+) error {
+	var err error
+	_, err = insertIgnore(ctx, sess, sess.
+		InsertInto(TableOutboxEvents).
+		Pair("id", v.ID).
+		Pair("chain_id", v.ChainID).
+		Pair("tx_id", v.TxID).
+		Pair("type", v.Type).
+		Pair("payload", v.Payload).
+		Pair("created_at", v.CreatedAt))
+	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
+		return EventErr(TableOutboxEvents, false, err)
+	}
+
+	return nil
+}
+
+func (p *persist) RemoveOutboxEvent(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *OutboxEvent,
+) error {
+	_, err := sess.
+		DeleteFrom(TableOutboxEvents).
+		Where("id=?", v.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return EventErr(TableOutboxEvents, false, err)
+	}
+
+	return nil
+}
+
+type Subnet struct {
+	ID        string
+	Threshold uint32
+	Locktime  uint64
+	AssetID   string
+	CreatedAt time.Time
+}
+
+func (p *persist) InsertSubnet(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *Subnet,
+	upd bool,
+) error {
+	var err error
+	_, err = insertIgnore(ctx, sess, sess.
+		InsertInto(TableSubnets).
+		Pair("id", v.ID).
+		Pair("threshold", v.Threshold).
+		Pair("locktime", v.Locktime).
+		Pair("created_at", v.CreatedAt))
+	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
+		return EventErr(TableSubnets, false, err)
+	}
+	if upd {
+		_, err = sess.
+			Update(TableSubnets).
+			Set("threshold", v.Threshold).
+			Set("locktime", v.Locktime).
+			Set("created_at", v.CreatedAt).
+			Where("id = ?", v.ID).
+			ExecContext(ctx)
+		if err != nil {
+			return EventErr(TableSubnets, true, err)
+		}
+	}
+	return nil
+}
+
+// UpdateSubnetAssetID sets the staking asset of a subnet transformed into a
+// permissionless subnet
+func (p *persist) UpdateSubnetAssetID(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *Subnet,
+) error {
+	_, err := sess.
+		Update(TableSubnets).
+		Set("asset_id", v.AssetID).
+		Where("id = ?", v.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return EventErr(TableSubnets, true, err)
+	}
+	return nil
+}
+
+type SubnetOwner struct {
+	SubnetID  string
+	Address   string
+	CreatedAt time.Time
+}
+
+func (p *persist) InsertSubnetOwner(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *SubnetOwner,
+) error {
+	_, err := insertIgnore(ctx, sess, sess.
+		InsertInto(TableSubnetOwners).
+		Pair("subnet_id", v.SubnetID).
+		Pair("address", v.Address).
+		Pair("created_at", v.CreatedAt))
+	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
+		return EventErr(TableSubnetOwners, false, err)
+	}
+	return nil
+}
+
+type Blockchain struct {
+	ID          string
+	SubnetID    string
+	Name        string
+	VMID        string `db:"vm_id"`
+	FxIDs       string `db:"fx_ids"`
+	GenesisHash string
+	CreatedAt   time.Time
+}
+
+func (p *persist) InsertBlockchain(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *Blockchain,
+	upd bool,
+) error {
+	var err error
+	_, err = insertIgnore(ctx, sess, sess.
+		InsertInto(TableBlockchains).
+		Pair("id", v.ID).
+		Pair("subnet_id", v.SubnetID).
+		Pair("name", v.Name).
+		Pair("vm_id", v.VMID).
+		Pair("fx_ids", v.FxIDs).
+		Pair("genesis_hash", v.GenesisHash).
+		Pair("created_at", v.CreatedAt))
+	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
+		return EventErr(TableBlockchains, false, err)
+	}
+	if upd {
+		_, err = sess.
+			Update(TableBlockchains).
+			Set("subnet_id", v.SubnetID).
+			Set("name", v.Name).
+			Set("vm_id", v.VMID).
+			Set("fx_ids", v.FxIDs).
+			Set("genesis_hash", v.GenesisHash).
+			Set("created_at", v.CreatedAt).
+			Where("id = ?", v.ID).
+			ExecContext(ctx)
+		if err != nil {
+			return EventErr(TableBlockchains, true, err)
+		}
+	}
+	return nil
+}
+
+type SubnetValidator struct {
+	ID        string
+	SubnetID  string
+	NodeID    string
+	Weight    uint64
+	Start     uint64
+	End       uint64
+	RemovedAt *time.Time
+	CreatedAt time.Time
+}
+
+func (p *persist) InsertSubnetValidator(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *SubnetValidator,
+	upd bool,
+) error {
+	var err error
+	_, err = insertIgnore(ctx, sess, sess.
+		InsertInto(TableSubnetValidators).
+		Pair("id", v.ID).
+		Pair("subnet_id", v.SubnetID).
+		Pair("node_id", v.NodeID).
+		Pair("weight", v.Weight).
+		Pair("start", v.Start).
+		Pair("end", v.End).
+		Pair("created_at", v.CreatedAt))
+	if err != nil && !utils.ErrIsDuplicateEntryError(err) {
+		return EventErr(TableSubnetValidators, false, err)
+	}
+	if upd {
+		_, err = sess.
+			Update(TableSubnetValidators).
+			Set("subnet_id", v.SubnetID).
+			Set("node_id", v.NodeID).
+			Set("weight", v.Weight).
+			Set("start", v.Start).
+			Set("end", v.End).
+			Set("created_at", v.CreatedAt).
+			Where("id = ?", v.ID).
+			ExecContext(ctx)
+		if err != nil {
+			return EventErr(TableSubnetValidators, true, err)
+		}
+	}
+	return nil
+}
+
+// UpdateSubnetValidatorRemoved records the removal of the node of v from the
+// validators of the subnet of v
+func (p *persist) UpdateSubnetValidatorRemoved(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *SubnetValidator,
+) error {
+	_, err := sess.
+		Update(TableSubnetValidators).
+		Set("removed_at", v.RemovedAt).
+		Where("subnet_id = ? AND node_id = ? AND removed_at IS NULL", v.SubnetID, v.NodeID).
+		ExecContext(ctx)
+	if err != nil {
+		return EventErr(TableSubnetValidators, true, err)
+	}
+	return nil
+}
+
+// CrossChainTransfer is an output exported to another chain, it is completed
+// once the import consuming it is indexed
+type CrossChainTransfer struct {
+	ID                 string
+	ExportTxID         string
+	ImportTxID         string
+	SourceChainID      string
+	DestinationChainID string
+	AssetID            string
+	Amount             uint64
+	ExportedAt         *time.Time
+	ImportedAt         *time.Time
+	CreatedAt          time.Time
+}
+
+// InsertCrossChainTransferExport records the export of the output. The chains
+// are indexed independently, so the import may have been recorded first, the
+// export columns are then set on the existing row.
+func (p *persist) InsertCrossChainTransferExport(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *CrossChainTransfer,
+) error {
+	_, err := upsert(ctx, sess, sess.
+		InsertInto(TableCrossChainTransfers).
+		Pair("id", v.ID).
+		Pair("export_tx_id", v.ExportTxID).
+		Pair("source_chain_id", v.SourceChainID).
+		Pair("destination_chain_id", v.DestinationChainID).
+		Pair("asset_id", v.AssetID).
+		Pair("amount", v.Amount).
+		Pair("exported_at", v.ExportedAt).
+		Pair("created_at", v.CreatedAt),
+		[]string{"id"},
+		"export_tx_id=excluded.export_tx_id",
+		"exported_at=excluded.exported_at",
+	)
+	if err != nil {
+		return EventErr(TableCrossChainTransfers, false, err)
+	}
+	return nil
+}
+
+// InsertCrossChainTransferImport records the import of the output, on the row
+// of its export if that was recorded already
+func (p *persist) InsertCrossChainTransferImport(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *CrossChainTransfer,
+) error {
+	_, err := upsert(ctx, sess, sess.
+		InsertInto(TableCrossChainTransfers).
+		Pair("id", v.ID).
+		Pair("import_tx_id", v.ImportTxID).
+		Pair("source_chain_id", v.SourceChainID).
+		Pair("destination_chain_id", v.DestinationChainID).
+		Pair("asset_id", v.AssetID).
+		Pair("amount", v.Amount).
+		Pair("imported_at", v.ImportedAt).
+		Pair("created_at", v.CreatedAt),
+		[]string{"id"},
+		"import_tx_id=excluded.import_tx_id",
+		"imported_at=excluded.imported_at",
+	)
+	if err != nil {
+		return EventErr(TableCrossChainTransfers, false, err)
+	}
+	return nil
+}
+
+// Supply is the supply of an asset on a day, it is updated during the day and
+// kept as its history afterwards
+type Supply struct {
+	AssetID   string
+	BucketAt  time.Time
+	Total     uint64
+	Locked    uint64
+	Deposited uint64
+	Bonded    uint64
+	Burned    uint64
+	Genesis   uint64
+	Holders   uint64
+	UpdatedAt time.Time
+}
+
+func (p *persist) QuerySupply(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	q *Supply,
+) (*Supply, error) {
+	v := &Supply{}
+	err := sess.Select(
+		"asset_id",
+		"bucket_at",
+		"total",
+		"locked",
+		"deposited",
+		"bonded",
+		"burned",
+		"genesis",
+		"holders",
+		"updated_at",
+	).From(TableSupplyHistory).
+		Where("asset_id=? and bucket_at=?", q.AssetID, q.BucketAt).
+		LoadOneContext(ctx, v)
+	return v, err
+}
+
+func (p *persist) InsertSupply(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *Supply,
+) error {
+	_, err := upsert(ctx, sess, sess.
+		InsertInto(TableSupplyHistory).
+		Pair("asset_id", v.AssetID).
+		Pair("bucket_at", v.BucketAt).
+		Pair("total", v.Total).
+		Pair("locked", v.Locked).
+		Pair("deposited", v.Deposited).
+		Pair("bonded", v.Bonded).
+		Pair("burned", v.Burned).
+		Pair("genesis", v.Genesis).
+		Pair("holders", v.Holders).
+		Pair("updated_at", v.UpdatedAt),
+		[]string{"asset_id", "bucket_at"},
+		"total=excluded.total",
+		"locked=excluded.locked",
+		"deposited=excluded.deposited",
+		"bonded=excluded.bonded",
+		"burned=excluded.burned",
+		"genesis=excluded.genesis",
+		"holders=excluded.holders",
+		"updated_at=excluded.updated_at",
+	)
+	if err != nil {
+		return EventErr(TableSupplyHistory, false, err)
+	}
+	return nil
+}
+
+// AddressLabel names an address known to the operators. X and P-chain
+// addresses are stored as short ids like the output addresses, C-chain
+// addresses as lower case hex with the 0x prefix.
+type AddressLabel struct {
+	Address   string
+	Name      string
+	Category  string
+	Notes     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// QueryAddressLabels returns the labels of the addresses, or all labels if no
+// address is given
+func (p *persist) QueryAddressLabels(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	addresses []string,
+) ([]*AddressLabel, error) {
+	var v []*AddressLabel
+	builder := sess.Select(
+		"address",
+		"name",
+		"category",
+		"notes",
+		"created_at",
+		"updated_at",
+	).From(TableAddressLabels).
+		OrderAsc("address")
+	if len(addresses) != 0 {
+		builder.Where("address IN ?", addresses)
+	}
+	_, err := builder.LoadContext(ctx, &v)
+	return v, err
+}
+
+func (p *persist) InsertAddressLabel(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *AddressLabel,
+) error {
+	_, err := upsert(ctx, sess, sess.
+		InsertInto(TableAddressLabels).
+		Pair("address", v.Address).
+		Pair("name", v.Name).
+		Pair("category", v.Category).
+		Pair("notes", v.Notes).
+		Pair("created_at", v.CreatedAt).
+		Pair("updated_at", v.UpdatedAt),
+		[]string{"address"},
+		"name=excluded.name",
+		"category=excluded.category",
+		"notes=excluded.notes",
+		"updated_at=excluded.updated_at",
+	)
+	if err != nil {
+		return EventErr(TableAddressLabels, false, err)
+	}
+	return nil
+}
+
+func (p *persist) DeleteAddressLabel(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	address string,
+) error {
+	_, err := sess.DeleteFrom(TableAddressLabels).Where("address=?", address).ExecContext(ctx)
+	if err != nil {
+		return EventErr(TableAddressLabels, false, err)
+	}
+	return nil
+}
+
+// ReplicaHeartbeat is the row the stream indexer updates on the primary, a
+// read replica lags behind by how much older its copy of the row is.
+type ReplicaHeartbeat struct {
+	ID        uint16
+	UpdatedAt time.Time
+}
+
+func (p *persist) QueryReplicaHeartbeat(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	q *ReplicaHeartbeat,
+) (*ReplicaHeartbeat, error) {
+	v := &ReplicaHeartbeat{}
+	err := sess.Select(
+		"id",
+		"updated_at",
+	).From(TableReplicaHeartbeat).
+		Where("id=?", q.ID).
+		LoadOneContext(ctx, v)
+	return v, err
+}
+
+func (p *persist) InsertReplicaHeartbeat(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	v *ReplicaHeartbeat,
+) error {
+	_, err := upsert(ctx, sess, sess.
+		InsertInto(TableReplicaHeartbeat).
+		Pair("id", v.ID).
+		Pair("updated_at", v.UpdatedAt),
+		[]string{"id"},
+		"updated_at=excluded.updated_at",
+	)
+	if err != nil {
+		return EventErr(TableReplicaHeartbeat, false, err)
+	}
+	return nil
+}
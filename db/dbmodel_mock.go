@@ -5,6 +5,7 @@ package db
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/gocraft/dbr/v2"
@@ -43,6 +44,17 @@ type MockPersist struct {
 	MultisigAlias                  map[string]*MultisigAlias
 	RewardOwner                    map[string]*RewardOwner
 	Reward                         map[string]*Reward
+	AggregateRollupsHourly         map[string]*AggregateRollup
+	AggregateRollupsDaily          map[string]*AggregateRollup
+	OutboxEvents                   map[string]*OutboxEvent
+	Subnets                        map[string]*Subnet
+	SubnetOwners                   map[string]*SubnetOwner
+	Blockchains                    map[string]*Blockchain
+	SubnetValidators               map[string]*SubnetValidator
+	CrossChainTransfers            map[string]*CrossChainTransfer
+	Supplies                       map[string]*Supply
+	AddressLabels                  map[string]*AddressLabel
+	ReplicaHeartbeats              map[uint16]*ReplicaHeartbeat
 }
 
 func NewPersistMock() *MockPersist {
@@ -75,6 +87,17 @@ func NewPersistMock() *MockPersist {
 		KeyValueStore:                  make(map[string]*KeyValueStore),
 		NodeIndex:                      make(map[string]*NodeIndex),
 		MultisigAlias:                  make(map[string]*MultisigAlias),
+		AggregateRollupsHourly:         make(map[string]*AggregateRollup),
+		AggregateRollupsDaily:          make(map[string]*AggregateRollup),
+		OutboxEvents:                   make(map[string]*OutboxEvent),
+		Subnets:                        make(map[string]*Subnet),
+		SubnetOwners:                   make(map[string]*SubnetOwner),
+		Blockchains:                    make(map[string]*Blockchain),
+		SubnetValidators:               make(map[string]*SubnetValidator),
+		CrossChainTransfers:            make(map[string]*CrossChainTransfer),
+		Supplies:                       make(map[string]*Supply),
+		AddressLabels:                  make(map[string]*AddressLabel),
+		ReplicaHeartbeats:              make(map[uint16]*ReplicaHeartbeat),
 	}
 }
 
@@ -389,6 +412,15 @@ func (m *MockPersist) InsertTransactionsValidator(ctx context.Context, runner db
 	return nil
 }
 
+func (m *MockPersist) UpdateTransactionsValidatorRemoved(ctx context.Context, runner dbr.SessionRunner, v *TransactionsValidator) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if fv, present := m.TransactionsValidator[v.ID]; present {
+		fv.RemovedAt = v.RemovedAt
+	}
+	return nil
+}
+
 func (m *MockPersist) QueryTransactionsBlock(ctx context.Context, runner dbr.SessionRunner, v *TransactionsBlock) (*TransactionsBlock, error) {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
@@ -569,6 +601,29 @@ func (m *MockPersist) RemoveTxPool(ctx context.Context, runner dbr.SessionRunner
 	return nil
 }
 
+func (m *MockPersist) QueryTxPoolBacklog(ctx context.Context, runner dbr.SessionRunner) ([]*TxPoolBacklog, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	topics := make(map[string]*TxPoolBacklog)
+	for _, v := range m.TxPool {
+		b, ok := topics[v.Topic]
+		if !ok {
+			b = &TxPoolBacklog{Topic: v.Topic, OldestAt: v.CreatedAt}
+			topics[v.Topic] = b
+		}
+		b.Count++
+		if v.CreatedAt.Before(b.OldestAt) {
+			b.OldestAt = v.CreatedAt
+		}
+	}
+	backlog := make([]*TxPoolBacklog, 0, len(topics))
+	for _, b := range topics {
+		backlog = append(backlog, b)
+	}
+	sort.Slice(backlog, func(i, j int) bool { return backlog[i].Topic < backlog[j].Topic })
+	return backlog, nil
+}
+
 func (m *MockPersist) QueryKeyValueStore(ctx context.Context, runner dbr.SessionRunner, v *KeyValueStore) (*KeyValueStore, error) {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
@@ -665,3 +720,225 @@ func (m *MockPersist) InsertReward(ctx context.Context, session dbr.SessionRunne
 	m.Reward[nv.RewardOwnerHash] = nv
 	return nil
 }
+
+func (m *MockPersist) QueryAggregateRollupHourly(ctx context.Context, runner dbr.SessionRunner, v *AggregateRollup) (*AggregateRollup, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if v, present := m.AggregateRollupsHourly[v.ChainID+":"+v.BucketAt.String()]; present {
+		return v, nil
+	}
+	return nil, nil
+}
+
+func (m *MockPersist) InsertAggregateRollupHourly(ctx context.Context, runner dbr.SessionRunner, v *AggregateRollup, b bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	nv := &AggregateRollup{}
+	*nv = *v
+	m.AggregateRollupsHourly[v.ChainID+":"+v.BucketAt.String()] = nv
+	return nil
+}
+
+func (m *MockPersist) QueryAggregateRollupDaily(ctx context.Context, runner dbr.SessionRunner, v *AggregateRollup) (*AggregateRollup, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if v, present := m.AggregateRollupsDaily[v.ChainID+":"+v.BucketAt.String()]; present {
+		return v, nil
+	}
+	return nil, nil
+}
+
+func (m *MockPersist) InsertAggregateRollupDaily(ctx context.Context, runner dbr.SessionRunner, v *AggregateRollup, b bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	nv := &AggregateRollup{}
+	*nv = *v
+	m.AggregateRollupsDaily[v.ChainID+":"+v.BucketAt.String()] = nv
+	return nil
+}
+
+func (m *MockPersist) QueryOutboxEvent(ctx context.Context, runner dbr.SessionRunner, v *OutboxEvent) (*OutboxEvent, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if v, present := m.OutboxEvents[v.ID]; present {
+		return v, nil
+	}
+	return nil, nil
+}
+
+func (m *MockPersist) InsertOutboxEvent(ctx context.Context, runner dbr.SessionRunner, v *OutboxEvent) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	nv := &OutboxEvent{}
+	*nv = *v
+	m.OutboxEvents[v.ID] = nv
+	return nil
+}
+
+func (m *MockPersist) RemoveOutboxEvent(ctx context.Context, runner dbr.SessionRunner, v *OutboxEvent) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.OutboxEvents, v.ID)
+	return nil
+}
+
+func (m *MockPersist) InsertSubnet(ctx context.Context, runner dbr.SessionRunner, v *Subnet, b bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	nv := &Subnet{}
+	*nv = *v
+	if fv, present := m.Subnets[v.ID]; present {
+		nv.AssetID = fv.AssetID
+	}
+	m.Subnets[v.ID] = nv
+	return nil
+}
+
+func (m *MockPersist) UpdateSubnetAssetID(ctx context.Context, runner dbr.SessionRunner, v *Subnet) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if fv, present := m.Subnets[v.ID]; present {
+		fv.AssetID = v.AssetID
+	}
+	return nil
+}
+
+func (m *MockPersist) InsertSubnetOwner(ctx context.Context, runner dbr.SessionRunner, v *SubnetOwner) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	nv := &SubnetOwner{}
+	*nv = *v
+	m.SubnetOwners[v.SubnetID+":"+v.Address] = nv
+	return nil
+}
+
+func (m *MockPersist) InsertBlockchain(ctx context.Context, runner dbr.SessionRunner, v *Blockchain, b bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	nv := &Blockchain{}
+	*nv = *v
+	m.Blockchains[v.ID] = nv
+	return nil
+}
+
+func (m *MockPersist) InsertSubnetValidator(ctx context.Context, runner dbr.SessionRunner, v *SubnetValidator, b bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	nv := &SubnetValidator{}
+	*nv = *v
+	m.SubnetValidators[v.ID] = nv
+	return nil
+}
+
+func (m *MockPersist) UpdateSubnetValidatorRemoved(ctx context.Context, runner dbr.SessionRunner, v *SubnetValidator) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, fv := range m.SubnetValidators {
+		if fv.SubnetID == v.SubnetID && fv.NodeID == v.NodeID && fv.RemovedAt == nil {
+			fv.RemovedAt = v.RemovedAt
+		}
+	}
+	return nil
+}
+
+func (m *MockPersist) InsertCrossChainTransferExport(ctx context.Context, runner dbr.SessionRunner, v *CrossChainTransfer) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if fv, present := m.CrossChainTransfers[v.ID]; present {
+		fv.ExportTxID = v.ExportTxID
+		fv.ExportedAt = v.ExportedAt
+		return nil
+	}
+	nv := &CrossChainTransfer{}
+	*nv = *v
+	m.CrossChainTransfers[v.ID] = nv
+	return nil
+}
+
+func (m *MockPersist) InsertCrossChainTransferImport(ctx context.Context, runner dbr.SessionRunner, v *CrossChainTransfer) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if fv, present := m.CrossChainTransfers[v.ID]; present {
+		fv.ImportTxID = v.ImportTxID
+		fv.ImportedAt = v.ImportedAt
+		return nil
+	}
+	nv := &CrossChainTransfer{}
+	*nv = *v
+	m.CrossChainTransfers[v.ID] = nv
+	return nil
+}
+
+func (m *MockPersist) QuerySupply(ctx context.Context, runner dbr.SessionRunner, v *Supply) (*Supply, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if v, present := m.Supplies[v.AssetID+":"+v.BucketAt.String()]; present {
+		return v, nil
+	}
+	return nil, nil
+}
+
+func (m *MockPersist) InsertSupply(ctx context.Context, runner dbr.SessionRunner, v *Supply) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	nv := &Supply{}
+	*nv = *v
+	m.Supplies[v.AssetID+":"+v.BucketAt.String()] = nv
+	return nil
+}
+
+func (m *MockPersist) QueryAddressLabels(ctx context.Context, runner dbr.SessionRunner, addresses []string) ([]*AddressLabel, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var res []*AddressLabel
+	if len(addresses) == 0 {
+		for _, v := range m.AddressLabels {
+			res = append(res, v)
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Address < res[j].Address })
+		return res, nil
+	}
+	for _, address := range addresses {
+		if v, present := m.AddressLabels[address]; present {
+			res = append(res, v)
+		}
+	}
+	return res, nil
+}
+
+func (m *MockPersist) InsertAddressLabel(ctx context.Context, runner dbr.SessionRunner, v *AddressLabel) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	nv := &AddressLabel{}
+	*nv = *v
+	if fv, present := m.AddressLabels[v.Address]; present {
+		nv.CreatedAt = fv.CreatedAt
+	}
+	m.AddressLabels[v.Address] = nv
+	return nil
+}
+
+func (m *MockPersist) DeleteAddressLabel(ctx context.Context, runner dbr.SessionRunner, address string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.AddressLabels, address)
+	return nil
+}
+
+func (m *MockPersist) QueryReplicaHeartbeat(ctx context.Context, runner dbr.SessionRunner, v *ReplicaHeartbeat) (*ReplicaHeartbeat, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if v, present := m.ReplicaHeartbeats[v.ID]; present {
+		return v, nil
+	}
+	return nil, nil
+}
+
+func (m *MockPersist) InsertReplicaHeartbeat(ctx context.Context, runner dbr.SessionRunner, v *ReplicaHeartbeat) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	nv := &ReplicaHeartbeat{}
+	*nv = *v
+	m.ReplicaHeartbeats[v.ID] = nv
+	return nil
+}
@@ -20,12 +20,7 @@ import (
 	"time"
 
 	"github.com/chain4travel/magellan/models"
-	"github.com/gocraft/dbr/v2"
-)
-
-const (
-	TestDB  = "mysql"
-	TestDSN = "root:password@tcp(127.0.0.1:3306)/magellan_test?parseTime=true"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
 )
 
 func TestTransaction(t *testing.T) {
@@ -45,19 +40,13 @@ func TestTransaction(t *testing.T) {
 	v.NetworkID = 1
 	v.Status = 1
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableTransactions).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertTransactions(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertTransactions(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryTransactions(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryTransactions(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -73,11 +62,11 @@ func TestTransaction(t *testing.T) {
 	v.Txfee = 2
 	v.Genesis = false
 	v.NetworkID = 2
-	err = p.InsertTransactions(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertTransactions(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryTransactions(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryTransactions(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -109,19 +98,13 @@ func TestOutputsRedeeming(t *testing.T) {
 	v.ChainID = "cid1"
 	v.CreatedAt = tm
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableOutputsRedeeming).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertOutputsRedeeming(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertOutputsRedeeming(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryOutputsRedeeming(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryOutputsRedeeming(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -136,11 +119,11 @@ func TestOutputsRedeeming(t *testing.T) {
 	v.AssetID = "aid2"
 	v.ChainID = "cid2"
 
-	err = p.InsertOutputsRedeeming(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertOutputsRedeeming(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryOutputsRedeeming(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryOutputsRedeeming(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -176,19 +159,13 @@ func TestOutputs(t *testing.T) {
 	v.Genesisutxo = true
 	v.CreatedAt = tm
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableOutputs).Exec()
-
-	err = p.InsertOutputs(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertOutputs(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryOutputs(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryOutputs(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -212,11 +189,11 @@ func TestOutputs(t *testing.T) {
 	v.Stakeableout = false
 	v.Genesisutxo = false
 
-	err = p.InsertOutputs(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertOutputs(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryOutputs(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryOutputs(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -243,19 +220,13 @@ func TestAssets(t *testing.T) {
 	v.CurrentSupply = 1
 	v.CreatedAt = tm
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableAssets).Exec()
-
-	err = p.InsertAssets(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertAssets(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryAssets(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryAssets(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -271,11 +242,11 @@ func TestAssets(t *testing.T) {
 	v.CurrentSupply = 2
 	v.CreatedAt = tm
 
-	err = p.InsertAssets(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertAssets(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryAssets(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryAssets(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -305,19 +276,13 @@ func TestAddresses(t *testing.T) {
 	v.CreatedAt = tm
 	v.UpdatedAt = tmu
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableAddresses).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertAddresses(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertAddresses(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryAddresses(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryAddresses(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -331,11 +296,11 @@ func TestAddresses(t *testing.T) {
 	v.CreatedAt = tm
 	v.UpdatedAt = tmu.Add(1 * time.Minute)
 
-	err = p.InsertAddresses(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertAddresses(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryAddresses(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryAddresses(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -362,19 +327,13 @@ func TestAddressChain(t *testing.T) {
 	v.CreatedAt = tm
 	v.UpdatedAt = tmu
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableAddressChain).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertAddressChain(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertAddressChain(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryAddressChain(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryAddressChain(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -386,11 +345,11 @@ func TestAddressChain(t *testing.T) {
 	v.CreatedAt = tm
 	v.UpdatedAt = tmu.Add(1 * time.Minute)
 
-	err = p.InsertAddressChain(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertAddressChain(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryAddressChain(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryAddressChain(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -408,13 +367,7 @@ func TestOutputAddresses(t *testing.T) {
 	tm := time.Now().UTC().Truncate(1 * time.Second)
 	tmu := time.Now().UTC().Truncate(1 * time.Second)
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableOutputAddresses).Exec()
+	_, sess := sqlitetest.New(t)
 
 	v := &OutputAddresses{}
 	v.OutputID = "oid1"
@@ -422,11 +375,11 @@ func TestOutputAddresses(t *testing.T) {
 	v.CreatedAt = tm
 	v.UpdatedAt = tmu
 
-	err = p.InsertOutputAddresses(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertOutputAddresses(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryOutputAddresses(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryOutputAddresses(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -443,11 +396,11 @@ func TestOutputAddresses(t *testing.T) {
 	v.CreatedAt = tm
 	v.UpdatedAt = tmu.Add(1 * time.Minute)
 
-	err = p.InsertOutputAddresses(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertOutputAddresses(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryOutputAddresses(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryOutputAddresses(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -462,11 +415,11 @@ func TestOutputAddresses(t *testing.T) {
 	v.CreatedAt = tm
 	v.UpdatedAt = tmu.Add(2 * time.Minute)
 
-	err = p.InsertOutputAddresses(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertOutputAddresses(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryOutputAddresses(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryOutputAddresses(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -484,11 +437,11 @@ func TestOutputAddresses(t *testing.T) {
 	v.CreatedAt = tm
 	v.UpdatedAt = tmu.Add(3 * time.Minute)
 
-	err = p.UpdateOutputAddresses(ctx, rawDBConn.NewSession(stream), v)
+	err = p.UpdateOutputAddresses(ctx, sess, v)
 	if err != nil {
 		t.Fatal("update fail", err)
 	}
-	fv, err = p.QueryOutputAddresses(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryOutputAddresses(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -511,19 +464,13 @@ func TestTransactionsEpoch(t *testing.T) {
 	v.VertexID = "vid1"
 	v.CreatedAt = tm
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableTransactionsEpochs).Exec()
-
-	err = p.InsertTransactionsEpoch(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertTransactionsEpoch(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryTransactionsEpoch(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryTransactionsEpoch(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -535,11 +482,11 @@ func TestTransactionsEpoch(t *testing.T) {
 	v.VertexID = "vid2"
 	v.CreatedAt = tm
 
-	err = p.InsertTransactionsEpoch(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertTransactionsEpoch(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryTransactionsEpoch(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryTransactionsEpoch(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -562,19 +509,13 @@ func TestCvmBlocks(t *testing.T) {
 	v.Hash = "0x"
 	v.CreatedAt = tm
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableCvmBlocks).Exec()
-
-	err = p.InsertCvmBlocks(ctx, rawDBConn.NewSession(stream), v)
+	err := p.InsertCvmBlocks(ctx, sess, v)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryCvmBlock(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryCvmBlock(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -599,19 +540,13 @@ func TestCvmAddresses(t *testing.T) {
 	v.Nonce = 3
 	v.CreatedAt = tm
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableCvmAddresses).Exec()
-
-	err = p.InsertCvmAddresses(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertCvmAddresses(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryCvmAddresses(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryCvmAddresses(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -628,11 +563,11 @@ func TestCvmAddresses(t *testing.T) {
 	v.Nonce = 4
 	v.CreatedAt = tm
 
-	err = p.InsertCvmAddresses(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertCvmAddresses(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryCvmAddresses(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryCvmAddresses(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -656,19 +591,13 @@ func TestCvmTransactions(t *testing.T) {
 	v.Block = "1"
 	v.CreatedAt = tm
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableCvmTransactionsAtomic).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertCvmTransactionsAtomic(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertCvmTransactionsAtomic(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryCvmTransactionsAtomic(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryCvmTransactionsAtomic(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -684,11 +613,11 @@ func TestCvmTransactions(t *testing.T) {
 	v.Block = "2"
 	v.CreatedAt = tm
 
-	err = p.InsertCvmTransactionsAtomic(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertCvmTransactionsAtomic(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryCvmTransactionsAtomic(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryCvmTransactionsAtomic(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -718,23 +647,17 @@ func TestCvmTransactionsTxdata(t *testing.T) {
 	v.CreatedAt = tm
 	v.Serialization = []byte("test123")
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableCvmTransactionsTxdata).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertCvmAccount(ctx, rawDBConn.NewSession(stream), &CvmAccount{}, true)
+	err := p.InsertCvmAccount(ctx, sess, &CvmAccount{}, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	err = p.InsertCvmTransactionsTxdata(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertCvmTransactionsTxdata(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryCvmTransactionsTxdata(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryCvmTransactionsTxdata(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -746,11 +669,11 @@ func TestCvmTransactionsTxdata(t *testing.T) {
 	v.CreatedAt = tm
 	v.Serialization = []byte("test456")
 
-	err = p.InsertCvmTransactionsTxdata(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertCvmTransactionsTxdata(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryCvmTransactionsTxdata(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryCvmTransactionsTxdata(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -781,19 +704,13 @@ func TestPvmBlocks(t *testing.T) {
 	v.Serialization = []byte("ser1")
 	v.CreatedAt = tm
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TablePvmBlocks).Exec()
-
-	err = p.InsertPvmBlocks(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertPvmBlocks(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryPvmBlocks(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryPvmBlocks(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -807,11 +724,11 @@ func TestPvmBlocks(t *testing.T) {
 	v.Serialization = []byte("ser2")
 	v.CreatedAt = tm
 
-	err = p.InsertPvmBlocks(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertPvmBlocks(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryPvmBlocks(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryPvmBlocks(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -835,19 +752,13 @@ func TestTransactionsValidator(t *testing.T) {
 	v.End = 2
 	v.CreatedAt = tm
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableTransactionsValidator).Exec()
-
-	err = p.InsertTransactionsValidator(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertTransactionsValidator(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryTransactionsValidator(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryTransactionsValidator(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -860,11 +771,11 @@ func TestTransactionsValidator(t *testing.T) {
 	v.End = 3
 	v.CreatedAt = tm
 
-	err = p.InsertTransactionsValidator(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertTransactionsValidator(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryTransactionsValidator(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryTransactionsValidator(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -886,19 +797,13 @@ func TestTransactionsBlock(t *testing.T) {
 	v.TxBlockID = "txb1"
 	v.CreatedAt = tm
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableTransactionsBlock).Exec()
-
-	err = p.InsertTransactionsBlock(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertTransactionsBlock(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryTransactionsBlock(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryTransactionsBlock(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -909,11 +814,11 @@ func TestTransactionsBlock(t *testing.T) {
 	v.TxBlockID = "txb2"
 	v.CreatedAt = tm
 
-	err = p.InsertTransactionsBlock(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertTransactionsBlock(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryTransactionsBlock(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryTransactionsBlock(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -935,19 +840,13 @@ func TestAddressBech32(t *testing.T) {
 	v.Bech32Address = "badr1"
 	v.UpdatedAt = tmu
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableAddressBech32).Exec()
-
-	err = p.InsertAddressBech32(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertAddressBech32(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryAddressBech32(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryAddressBech32(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -958,11 +857,11 @@ func TestAddressBech32(t *testing.T) {
 	v.Bech32Address = "badr2"
 	v.UpdatedAt = tmu.Add(1 * time.Minute)
 
-	err = p.InsertAddressBech32(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertAddressBech32(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryAddressBech32(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryAddressBech32(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -987,19 +886,13 @@ func TestOutputAddressAccumulateOut(t *testing.T) {
 
 	v.ComputeID()
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableOutputAddressAccumulateOut).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertOutputAddressAccumulateOut(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertOutputAddressAccumulateOut(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryOutputAddressAccumulateOut(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryOutputAddressAccumulateOut(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1010,11 +903,11 @@ func TestOutputAddressAccumulateOut(t *testing.T) {
 	v.OutputIndex = 3
 	v.TransactionID = "tr3"
 
-	err = p.InsertOutputAddressAccumulateOut(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertOutputAddressAccumulateOut(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryOutputAddressAccumulateOut(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryOutputAddressAccumulateOut(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1039,19 +932,13 @@ func TestOutputAddressAccumulateIn(t *testing.T) {
 
 	v.ComputeID()
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableOutputAddressAccumulateIn).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertOutputAddressAccumulateIn(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertOutputAddressAccumulateIn(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryOutputAddressAccumulateIn(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryOutputAddressAccumulateIn(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1062,11 +949,11 @@ func TestOutputAddressAccumulateIn(t *testing.T) {
 	v.OutputIndex = 3
 	v.TransactionID = "tr3"
 
-	err = p.InsertOutputAddressAccumulateIn(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertOutputAddressAccumulateIn(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryOutputAddressAccumulateIn(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryOutputAddressAccumulateIn(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1091,19 +978,13 @@ func TestOutputTxsAccumulate(t *testing.T) {
 
 	v.ComputeID()
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableOutputTxsAccumulate).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertOutputTxsAccumulate(ctx, rawDBConn.NewSession(stream), v)
+	err := p.InsertOutputTxsAccumulate(ctx, sess, v)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryOutputTxsAccumulate(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryOutputTxsAccumulate(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1126,19 +1007,13 @@ func TestAccumulateBalancesReceived(t *testing.T) {
 
 	v.ComputeID()
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableAccumulateBalancesReceived).Exec()
-
-	err = p.InsertAccumulateBalancesReceived(ctx, rawDBConn.NewSession(stream), v)
+	err := p.InsertAccumulateBalancesReceived(ctx, sess, v)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryAccumulateBalancesReceived(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryAccumulateBalancesReceived(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1161,19 +1036,13 @@ func TestAccumulateBalancesSent(t *testing.T) {
 
 	v.ComputeID()
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableAccumulateBalancesSent).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertAccumulateBalancesSent(ctx, rawDBConn.NewSession(stream), v)
+	err := p.InsertAccumulateBalancesSent(ctx, sess, v)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryAccumulateBalancesSent(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryAccumulateBalancesSent(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1195,19 +1064,13 @@ func TestAccumulateBalancesTransactions(t *testing.T) {
 
 	v.ComputeID()
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableAccumulateBalancesTransactions).Exec()
-
-	err = p.InsertAccumulateBalancesTransactions(ctx, rawDBConn.NewSession(stream), v)
+	err := p.InsertAccumulateBalancesTransactions(ctx, sess, v)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryAccumulateBalancesTransactions(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryAccumulateBalancesTransactions(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1230,19 +1093,13 @@ func TestTxPool(t *testing.T) {
 
 	v.ComputeID()
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableTxPool).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertTxPool(ctx, rawDBConn.NewSession(stream), v)
+	err := p.InsertTxPool(ctx, sess, v)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryTxPool(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryTxPool(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1259,19 +1116,13 @@ func TestKeyValueStore(t *testing.T) {
 	v.K = "k"
 	v.V = "v"
 
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableKeyValueStore).Exec()
+	_, sess := sqlitetest.New(t)
 
-	err = p.InsertKeyValueStore(ctx, rawDBConn.NewSession(stream), v)
+	err := p.InsertKeyValueStore(ctx, sess, v)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryKeyValueStore(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryKeyValueStore(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1289,19 +1140,13 @@ func TestNodeIndex(t *testing.T) {
 	v.Topic = "top"
 	v.Idx = 1
 
-	stream := &dbr.NullEventReceiver{}
+	_, sess := sqlitetest.New(t)
 
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableNodeIndex).Exec()
-
-	err = p.InsertNodeIndex(ctx, rawDBConn.NewSession(stream), v, true)
+	err := p.InsertNodeIndex(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err := p.QueryNodeIndex(ctx, rawDBConn.NewSession(stream), v)
+	fv, err := p.QueryNodeIndex(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1311,11 +1156,11 @@ func TestNodeIndex(t *testing.T) {
 
 	v.Idx = 2
 
-	err = p.InsertNodeIndex(ctx, rawDBConn.NewSession(stream), v, true)
+	err = p.InsertNodeIndex(ctx, sess, v, true)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryNodeIndex(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryNodeIndex(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1327,11 +1172,11 @@ func TestNodeIndex(t *testing.T) {
 	}
 
 	v.Idx = 3
-	err = p.UpdateNodeIndex(ctx, rawDBConn.NewSession(stream), v)
+	err = p.UpdateNodeIndex(ctx, sess, v)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	fv, err = p.QueryNodeIndex(ctx, rawDBConn.NewSession(stream), v)
+	fv, err = p.QueryNodeIndex(ctx, sess, v)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
@@ -1346,13 +1191,7 @@ func TestNodeIndex(t *testing.T) {
 func TestInsertMultisigAlias(t *testing.T) {
 	p := NewPersist()
 	ctx := context.Background()
-	stream := &dbr.NullEventReceiver{}
-
-	rawDBConn, err := dbr.Open(TestDB, TestDSN, stream)
-	if err != nil {
-		t.Fatal("db fail", err)
-	}
-	_, _ = rawDBConn.NewSession(stream).DeleteFrom(TableMultisigAliases).Exec()
+	_, sess := sqlitetest.New(t)
 
 	v := &MultisigAlias{}
 	v.Alias = "abcdefghijklmnopqrstABCDEF1234567"
@@ -1362,25 +1201,79 @@ func TestInsertMultisigAlias(t *testing.T) {
 	v.TransactionID = "abcdefghijklmnopqrstABCDEF1234567abcdefghijklmnop"
 	v.CreatedAt = time.Now().UTC().Truncate(1 * time.Second)
 
-	err = p.InsertMultisigAlias(ctx, rawDBConn.NewSession(stream), v)
+	err := p.InsertMultisigAlias(ctx, sess, v)
 	if err != nil {
 		t.Fatal("insert fail", err)
 	}
-	err = p.InsertAddressBech32(ctx, rawDBConn.NewSession(stream), &AddressBech32{Address: v.Alias, Bech32Address: "kopernikus1vscyf7czawylztn6ghhg0z27swwewxgzgpcxvy", UpdatedAt: time.Now().UTC().Truncate(1 * time.Second)}, false)
+	err = p.InsertAddressBech32(ctx, sess, &AddressBech32{Address: v.Alias, Bech32Address: "kopernikus1vscyf7czawylztn6ghhg0z27swwewxgzgpcxvy", UpdatedAt: time.Now().UTC().Truncate(1 * time.Second)}, false)
 	if err != nil {
 		t.Fatal("insert address bech32 fail", err)
 	}
 
 	owners := []string{v.Owner}
-	fv, err := p.QueryMultisigAliasesForOwners(ctx, rawDBConn.NewSession(stream), owners)
+	fv, err := p.QueryMultisigAliasesForOwners(ctx, sess, owners)
 	if err != nil {
 		t.Fatal("query fail", err)
 	}
 	if !reflect.DeepEqual(v.Bech32Address, (*fv)[0].Bech32Address) {
 		t.Fatal("compare fail")
 	}
-	err = p.DeleteMultisigAlias(ctx, rawDBConn.NewSession(stream), v.Alias)
+	err = p.DeleteMultisigAlias(ctx, sess, v.Alias)
 	if err != nil {
 		t.Fatal("delete fail", err)
 	}
 }
+
+func TestAggregateRollups(t *testing.T) {
+	p := NewPersist()
+	ctx := context.Background()
+	tm := time.Now().UTC().Truncate(1 * time.Hour)
+
+	v := &AggregateRollup{}
+	v.ChainID = "cid1"
+	v.BucketAt = tm
+	v.TransactionCount = 1
+	v.Txfee = 2
+	v.UpdatedAt = tm
+
+	_, sess := sqlitetest.New(t)
+
+	err := p.InsertAggregateRollupHourly(ctx, sess, v, false)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+	fv, err := p.QueryAggregateRollupHourly(ctx, sess, v)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if !reflect.DeepEqual(*v, *fv) {
+		t.Fatal("compare fail")
+	}
+
+	v.TransactionCount = 3
+	v.Txfee = 4
+
+	err = p.InsertAggregateRollupHourly(ctx, sess, v, true)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+	fv, err = p.QueryAggregateRollupHourly(ctx, sess, v)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if fv.TransactionCount != 3 || fv.Txfee != 4 {
+		t.Fatal("compare fail")
+	}
+
+	err = p.InsertAggregateRollupDaily(ctx, sess, v, true)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+	fv, err = p.QueryAggregateRollupDaily(ctx, sess, v)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if !reflect.DeepEqual(*v, *fv) {
+		t.Fatal("compare fail")
+	}
+}
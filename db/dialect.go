@@ -0,0 +1,90 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+)
+
+var excludedColumnRegex = regexp.MustCompile(`excluded\.(\w+)`)
+
+// hasOnConflict reports whether the dialect uses the ON CONFLICT clause of
+// postgres, which sqlite supports as well.
+func hasOnConflict(d dbr.Dialect) bool {
+	return d == dialect.PostgreSQL || d == dialect.SQLite3
+}
+
+// insertIgnore executes an insert whose duplicate key errors are ignored by the
+// caller. Postgres aborts the surrounding transaction on any failed statement,
+// so there the conflict is resolved inside the statement instead.
+func insertIgnore(ctx context.Context, sess dbr.SessionRunner, stmt *dbr.InsertStmt) (sql.Result, error) {
+	if !hasOnConflict(stmt.Dialect) {
+		return stmt.ExecContext(ctx)
+	}
+	buf := dbr.NewBuffer()
+	if err := stmt.Build(stmt.Dialect, buf); err != nil {
+		return nil, err
+	}
+	return sess.
+		InsertBySql(buf.String()+" ON CONFLICT DO NOTHING", buf.Value()...).
+		ExecContext(ctx)
+}
+
+// upsert executes an insert and applies the assignments to the existing row
+// when it conflicts on the key columns. Assignments refer to the values of the
+// insert as excluded.<column>, which is rewritten to VALUES(<column>) on mysql.
+func upsert(
+	ctx context.Context,
+	sess dbr.SessionRunner,
+	stmt *dbr.InsertStmt,
+	keys []string,
+	assignments ...string,
+) (sql.Result, error) {
+	buf := dbr.NewBuffer()
+	if err := stmt.Build(stmt.Dialect, buf); err != nil {
+		return nil, err
+	}
+
+	query := buf.String()
+	if hasOnConflict(stmt.Dialect) {
+		query += " ON CONFLICT (" + strings.Join(keys, ",") + ") DO UPDATE SET " + strings.Join(assignments, ",")
+	} else {
+		query += " ON DUPLICATE KEY UPDATE " + excludedColumnRegex.ReplaceAllString(strings.Join(assignments, ","), "VALUES($1)")
+	}
+	return sess.InsertBySql(query, buf.Value()...).ExecContext(ctx)
+}
+
+// UnixTimestamp returns the expression of the unix seconds of the timestamp
+// expression expr. Sqlite registers UNIX_TIMESTAMP like mysql has it.
+func UnixTimestamp(d dbr.Dialect, expr string) string {
+	if d == dialect.PostgreSQL {
+		return "CAST(EXTRACT(EPOCH FROM " + expr + ") AS BIGINT)"
+	}
+	return "UNIX_TIMESTAMP(" + expr + ")"
+}
+
+// CastUnsigned returns the expression casting expr to an integer, e.g. a SUM
+// which mysql returns as decimal. Postgres has no unsigned types.
+func CastUnsigned(d dbr.Dialect, expr string) string {
+	if d == dialect.PostgreSQL {
+		return "CAST(" + expr + " AS BIGINT)"
+	}
+	return "CAST(" + expr + " AS UNSIGNED)"
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package db
+
+import (
+	"github.com/gocraft/dbr/v2"
+)
+
+// holderBalance is the balance of an address on the selected chains
+const holderBalance = "SUM(accumulate_balances_received.total_amount - COALESCE(accumulate_balances_sent.total_amount, 0))"
+
+// HolderBalances selects the addresses with a positive balance as balance, from
+// the balances kept by the balance manager. The caller restricts it to an asset
+// and optionally to chains with conditions on accumulate_balances_received.
+func HolderBalances(sess dbr.SessionRunner) *dbr.SelectStmt {
+	return sess.
+		Select("accumulate_balances_received.address", holderBalance+" AS balance").
+		From(TableAccumulateBalancesReceived).
+		LeftJoin(TableAccumulateBalancesSent, "accumulate_balances_received.id = accumulate_balances_sent.id").
+		GroupBy("accumulate_balances_received.address").
+		Having(holderBalance + " > 0")
+}
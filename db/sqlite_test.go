@@ -0,0 +1,325 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/utils"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+func TestSqliteSchema(t *testing.T) {
+	conf := sqlitetest.Config(t)
+	for i := 0; i < 2; i++ {
+		// a second connection must find the schema in place
+		conns, err := utils.NewDBFromConfig(conf, false)
+		if err != nil {
+			t.Fatal("db fail", err)
+		}
+		_ = conns.Close()
+	}
+}
+
+func TestSqliteTransaction(t *testing.T) {
+	p := NewPersist()
+	ctx := context.Background()
+	_, sess := sqlitetest.New(t)
+	tm := time.Now().UTC().Truncate(1 * time.Second)
+
+	v := &Transactions{}
+	v.ID = "id"
+	v.ChainID = "cid1"
+	v.Type = "txtype"
+	v.Memo = []byte("memo")
+	v.CanonicalSerialization = []byte("cs")
+	v.Txfee = 1
+	v.Genesis = true
+	v.CreatedAt = tm
+	v.NetworkID = 1
+	v.Status = 1
+
+	err := p.InsertTransactions(ctx, sess, v, false)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+	// duplicates are ignored
+	err = p.InsertTransactions(ctx, sess, v, false)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+	fv, err := p.QueryTransactions(ctx, sess, v)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if !reflect.DeepEqual(*v, *fv) {
+		t.Fatal("compare fail")
+	}
+
+	v.Txfee = 2
+	v.Genesis = false
+	err = p.InsertTransactions(ctx, sess, v, true)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+	fv, err = p.QueryTransactions(ctx, sess, v)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if !reflect.DeepEqual(*v, *fv) {
+		t.Fatal("compare fail")
+	}
+}
+
+func TestSqliteCvmAccount(t *testing.T) {
+	p := NewPersist()
+	ctx := context.Background()
+	_, sess := sqlitetest.New(t)
+
+	v := &CvmAccount{
+		Address: "0x0000000000000000000000000000000000000001",
+		TxCount: 1,
+	}
+	err := p.InsertCvmAccount(ctx, sess, v, true)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+	v.TxCount = 2
+	err = p.InsertCvmAccount(ctx, sess, v, true)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+	fv, err := p.QueryCvmAccount(ctx, sess, v)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if fv.TxCount != 3 {
+		t.Fatal("compare fail")
+	}
+	if err = p.InsertCvmAccount(ctx, sess, v, false); !utils.ErrIsDuplicateEntryError(err) {
+		t.Fatal("expected duplicate entry error", err)
+	}
+}
+
+func TestSqliteNodeIndex(t *testing.T) {
+	p := NewPersist()
+	ctx := context.Background()
+	_, sess := sqlitetest.New(t)
+
+	v := &NodeIndex{}
+	v.Instance = "def"
+	v.Topic = "top"
+	v.Idx = 1
+
+	err := p.InsertNodeIndex(ctx, sess, v, true)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+	v.Idx = 2
+	err = p.InsertNodeIndex(ctx, sess, v, true)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+	fv, err := p.QueryNodeIndex(ctx, sess, v)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if !reflect.DeepEqual(*v, *fv) {
+		t.Fatal("compare fail")
+	}
+}
+
+func TestSqliteTxPoolBacklog(t *testing.T) {
+	p := NewPersist()
+	ctx := context.Background()
+	_, sess := sqlitetest.New(t)
+	tm := time.Now().UTC().Truncate(1 * time.Second)
+
+	for i, topic := range []string{"b", "a", "b"} {
+		v := &TxPool{
+			MsgKey:        string(rune('k' + i)),
+			Topic:         topic,
+			Serialization: []byte("s"),
+			CreatedAt:     tm.Add(time.Duration(-i) * time.Minute),
+		}
+		v.ComputeID()
+		if err := p.InsertTxPool(ctx, sess, v); err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	backlog, err := p.QueryTxPoolBacklog(ctx, sess)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if len(backlog) != 2 || backlog[0].Topic != "a" || backlog[1].Count != 2 {
+		t.Fatal("unexpected backlog", backlog)
+	}
+	if !backlog[1].OldestAt.Equal(tm.Add(-2 * time.Minute)) {
+		t.Fatal("unexpected oldest", backlog[1].OldestAt)
+	}
+}
+
+func TestSqliteAggregateRollups(t *testing.T) {
+	p := NewPersist()
+	ctx := context.Background()
+	_, sess := sqlitetest.New(t)
+	tm := time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	v := &AggregateRollup{
+		ChainID:          "cid",
+		BucketAt:         tm,
+		TransactionCount: 5,
+		Txfee:            10,
+		UpdatedAt:        tm,
+	}
+	err := p.InsertAggregateRollupHourly(ctx, sess, v, true)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+
+	var bucket int64
+	err = sess.Select("FLOOR(UNIX_TIMESTAMP(bucket_at) / 3600)").
+		From(TableAggregateRollupsHourly).
+		Where("chain_id=?", v.ChainID).
+		LoadOneContext(ctx, &bucket)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if bucket != tm.Unix()/3600 {
+		t.Fatal("compare fail")
+	}
+}
+
+func TestSqliteSupply(t *testing.T) {
+	p := NewPersist()
+	ctx := context.Background()
+	_, sess := sqlitetest.New(t)
+	tm := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	v := &Supply{
+		AssetID:   "aid",
+		BucketAt:  tm,
+		Total:     100,
+		Locked:    30,
+		Deposited: 20,
+		Bonded:    10,
+		Burned:    5,
+		Genesis:   90,
+		Holders:   3,
+		UpdatedAt: tm,
+	}
+	if err := p.InsertSupply(ctx, sess, v); err != nil {
+		t.Fatal("insert fail", err)
+	}
+
+	// the supply of the day is overwritten by later runs
+	v.Total = 110
+	v.Holders = 4
+	v.UpdatedAt = tm.Add(time.Hour)
+	if err := p.InsertSupply(ctx, sess, v); err != nil {
+		t.Fatal("insert fail", err)
+	}
+
+	fv, err := p.QuerySupply(ctx, sess, v)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if !reflect.DeepEqual(*v, *fv) {
+		t.Fatal("compare fail", fv)
+	}
+}
+
+func TestSqliteAddressLabels(t *testing.T) {
+	p := NewPersist()
+	ctx := context.Background()
+	_, sess := sqlitetest.New(t)
+	tm := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	v := &AddressLabel{
+		Address:   "addr",
+		Name:      "exchange",
+		Category:  "exchange",
+		Notes:     "hot wallet",
+		CreatedAt: tm,
+		UpdatedAt: tm,
+	}
+	if err := p.InsertAddressLabel(ctx, sess, v); err != nil {
+		t.Fatal("insert fail", err)
+	}
+	other := &AddressLabel{Address: "0xabc", Name: "bridge", Category: "bridge", CreatedAt: tm, UpdatedAt: tm}
+	if err := p.InsertAddressLabel(ctx, sess, other); err != nil {
+		t.Fatal("insert fail", err)
+	}
+
+	// relabeling keeps the creation time
+	v.Name = "exchange cold"
+	v.CreatedAt = tm.Add(time.Hour)
+	v.UpdatedAt = tm.Add(time.Hour)
+	if err := p.InsertAddressLabel(ctx, sess, v); err != nil {
+		t.Fatal("insert fail", err)
+	}
+	v.CreatedAt = tm
+
+	fv, err := p.QueryAddressLabels(ctx, sess, []string{"addr", "unknown"})
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if len(fv) != 1 || !reflect.DeepEqual(*v, *fv[0]) {
+		t.Fatal("compare fail", fv)
+	}
+	all, err := p.QueryAddressLabels(ctx, sess, nil)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if len(all) != 2 || all[0].Address != "0xabc" {
+		t.Fatal("compare fail", all)
+	}
+
+	if err := p.DeleteAddressLabel(ctx, sess, "addr"); err != nil {
+		t.Fatal("delete fail", err)
+	}
+	if fv, err = p.QueryAddressLabels(ctx, sess, []string{"addr"}); err != nil || len(fv) != 0 {
+		t.Fatal("delete fail", fv, err)
+	}
+}
+
+func TestSqliteReplicaHeartbeat(t *testing.T) {
+	p := NewPersist()
+	ctx := context.Background()
+	_, sess := sqlitetest.New(t)
+	tm := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	v := &ReplicaHeartbeat{ID: 1, UpdatedAt: tm}
+	if err := p.InsertReplicaHeartbeat(ctx, sess, v); err != nil {
+		t.Fatal("insert fail", err)
+	}
+
+	// every beat overwrites the single row
+	v.UpdatedAt = tm.Add(time.Second)
+	if err := p.InsertReplicaHeartbeat(ctx, sess, v); err != nil {
+		t.Fatal("insert fail", err)
+	}
+
+	fv, err := p.QueryReplicaHeartbeat(ctx, sess, v)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if !reflect.DeepEqual(*v, *fv) {
+		t.Fatal("compare fail", fv)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/servicesctrl"
+	"github.com/chain4travel/magellan/utils"
+)
+
+const (
+	SourceIndex = "index"
+	SourceNode  = "node"
+
+	lookupTimeout = time.Minute
+)
+
+var ErrUnknownSource = errors.New("unknown discovery source")
+
+// Chain is a blockchain created on the P-chain
+type Chain struct {
+	ID       string
+	SubnetID string
+	Name     string
+	VMID     string
+}
+
+// VMType is the name of the VM the chain is indexed with, or empty if the VM
+// isn't supported
+func (c Chain) VMType() string {
+	switch c.VMID {
+	case constants.AVMID.String():
+		return models.AVMName
+	case constants.EVMID.String():
+		return models.CVMName
+	}
+	return ""
+}
+
+// Primary is true for the chains of the primary network
+func (c Chain) Primary() bool {
+	return c.SubnetID == constants.PrimaryNetworkID.String()
+}
+
+// Alias is the name the node serves the apis of the chain under, the chains of
+// a subnet are only served under their ID
+func (c Chain) Alias() string {
+	if c.Primary() {
+		switch c.VMType() {
+		case models.AVMName:
+			return "X"
+		case models.CVMName:
+			return "C"
+		}
+	}
+	return c.ID
+}
+
+// Source lists the blockchains created on the P-chain
+type Source interface {
+	Chains(ctx context.Context) ([]Chain, error)
+}
+
+// NewSource returns the source configured in conf
+func NewSource(sc *servicesctrl.Control, conf cfg.Config) (Source, error) {
+	switch conf.Discovery.Source {
+	case SourceIndex:
+		conns, err := sc.Database()
+		if err != nil {
+			return nil, err
+		}
+		return &indexSource{conns: conns, fallback: &nodeSource{urls: conf.NodeURLs()}}, nil
+	case SourceNode:
+		return &nodeSource{urls: conf.NodeURLs()}, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownSource, conf.Discovery.Source)
+}
+
+// indexSource reads the blockchains the pvm writer indexed. The table is only
+// filled by the pvm writer since migration 58, a database indexed before has
+// no rows until a re-index, then the chains are read from the fallback.
+type indexSource struct {
+	conns    *utils.Connections
+	fallback Source
+}
+
+func (s *indexSource) Close() error {
+	return s.conns.Close()
+}
+
+func (s *indexSource) Chains(ctx context.Context) ([]Chain, error) {
+	sess, err := s.conns.DB().NewSession("discovery", cfg.DBTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*db.Blockchain
+	_, err = sess.Select("id", "subnet_id", "name", "vm_id").
+		From(db.TableBlockchains).
+		OrderAsc("created_at").
+		LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 && s.fallback != nil {
+		return s.fallback.Chains(ctx)
+	}
+
+	chains := make([]Chain, 0, len(rows))
+	for _, row := range rows {
+		chains = append(chains, Chain{ID: row.ID, SubnetID: row.SubnetID, Name: row.Name, VMID: row.VMID})
+	}
+	return chains, nil
+}
+
+// nodeSource reads the blockchains with platform.getBlockchains, from the
+// first node which answers
+type nodeSource struct {
+	urls []string
+}
+
+func (s *nodeSource) Chains(ctx context.Context) ([]Chain, error) {
+	var err error
+	for _, url := range s.urls {
+		var blockchains []platformvm.APIBlockchain
+		blockchains, err = platformvm.NewClient(url).GetBlockchains(ctx)
+		if err != nil {
+			continue
+		}
+
+		chains := make([]Chain, 0, len(blockchains))
+		for _, blockchain := range blockchains {
+			chains = append(chains, Chain{
+				ID:       blockchain.ID.String(),
+				SubnetID: blockchain.SubnetID.String(),
+				Name:     blockchain.Name,
+				VMID:     blockchain.VMID.String(),
+			})
+		}
+		return chains, nil
+	}
+	return nil, err
+}
+
+// StartFunc starts the producers and consumers of a discovered chain, and
+// returns the workers it started
+type StartFunc func(chain Chain) ([]utils.ListenCloser, error)
+
+// Discovery looks up the blockchains created on the P-chain in an interval and
+// starts the chains with a supported VM which are not running yet
+type Discovery struct {
+	log      logging.Logger
+	source   Source
+	start    StartFunc
+	interval time.Duration
+
+	// started holds the configured chains and the ones started since
+	started     map[string]struct{}
+	unsupported map[string]struct{}
+	evm         bool
+
+	lock    sync.Mutex
+	workers []utils.ListenCloser
+
+	runningControl utils.Running
+}
+
+func New(log logging.Logger, conf cfg.Config, source Source, start StartFunc) *Discovery {
+	d := &Discovery{
+		log:            log,
+		source:         source,
+		start:          start,
+		interval:       time.Duration(conf.Discovery.Interval) * time.Second,
+		started:        make(map[string]struct{}),
+		unsupported:    make(map[string]struct{}),
+		runningControl: utils.NewRunning(),
+	}
+	for id, chain := range conf.Chains {
+		d.started[id] = struct{}{}
+		if chain.VMType == models.CVMName {
+			d.evm = true
+		}
+	}
+	return d
+}
+
+func (d *Discovery) Listen() error {
+	d.log.Info("starting chain discovery")
+	defer d.log.Info("exiting chain discovery")
+
+	for !d.runningControl.IsStopped() {
+		if err := d.Discover(); err != nil {
+			d.log.Warn("chain discovery failed", zap.Error(err))
+		}
+
+		// wait for the interval, but notice a close in time
+		for waited := time.Duration(0); waited < d.interval && !d.runningControl.IsStopped(); waited += time.Second {
+			time.Sleep(time.Second)
+		}
+	}
+	return nil
+}
+
+func (d *Discovery) Close() error {
+	d.runningControl.Close()
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, worker := range d.workers {
+		if err := worker.Close(); err != nil {
+			d.log.Warn("closing discovered chain failed", zap.Error(err))
+		}
+	}
+	if closer, ok := d.source.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Discover starts the chains which were created since the last lookup
+func (d *Discovery) Discover() error {
+	ctx, cancelFn := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancelFn()
+
+	chains, err := d.source.Chains(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, chain := range chains {
+		if _, ok := d.started[chain.ID]; ok {
+			continue
+		}
+		if _, ok := d.unsupported[chain.ID]; ok {
+			continue
+		}
+		vmType := chain.VMType()
+		switch {
+		case vmType == "":
+			d.unsupported[chain.ID] = struct{}{}
+			d.log.Info("skipping chain with unsupported vm",
+				zap.String("chainID", chain.ID),
+				zap.String("vmID", chain.VMID),
+			)
+			continue
+		case vmType == models.CVMName && d.evm:
+			// the cvm tables hold the blocks of a single evm chain
+			d.unsupported[chain.ID] = struct{}{}
+			d.log.Info("skipping evm chain, an evm chain is indexed already",
+				zap.String("chainID", chain.ID),
+			)
+			continue
+		}
+
+		workers, err := d.start(chain)
+		if err != nil {
+			return err
+		}
+		d.lock.Lock()
+		d.workers = append(d.workers, workers...)
+		d.lock.Unlock()
+		for _, worker := range workers {
+			go func(chainID string, worker utils.ListenCloser) {
+				if err := worker.Listen(); err != nil {
+					d.log.Warn("discovered chain failed",
+						zap.String("chainID", chainID),
+						zap.Error(err),
+					)
+				}
+			}(chain.ID, worker)
+		}
+		d.started[chain.ID] = struct{}{}
+		if vmType == models.CVMName {
+			d.evm = true
+		}
+		d.log.Info("started discovered chain",
+			zap.String("chainID", chain.ID),
+			zap.String("name", chain.Name),
+			zap.String("vmType", vmType),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/utils"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+type staticSource []Chain
+
+func (s *staticSource) Chains(context.Context) ([]Chain, error) {
+	return *s, nil
+}
+
+func TestDiscover(t *testing.T) {
+	primary := constants.PrimaryNetworkID.String()
+	subnet := ids.ID{1}.String()
+	xchain := Chain{ID: ids.ID{2}.String(), SubnetID: primary, VMID: constants.AVMID.String()}
+	cchain := Chain{ID: ids.ID{3}.String(), SubnetID: primary, VMID: constants.EVMID.String()}
+	subnetAVM := Chain{ID: ids.ID{4}.String(), SubnetID: subnet, VMID: constants.AVMID.String()}
+	subnetEVM := Chain{ID: ids.ID{5}.String(), SubnetID: subnet, VMID: constants.EVMID.String()}
+	custom := Chain{ID: ids.ID{6}.String(), SubnetID: subnet, VMID: ids.ID{7}.String()}
+
+	if xchain.Alias() != "X" || cchain.Alias() != "C" || subnetAVM.Alias() != subnetAVM.ID {
+		t.Fatal("unexpected aliases", xchain.Alias(), cchain.Alias(), subnetAVM.Alias())
+	}
+
+	conf := cfg.Config{Chains: cfg.Chains{xchain.ID: {ID: xchain.ID, VMType: models.AVMName}}}
+	source := &staticSource{xchain, subnetAVM, custom}
+	var started []string
+	d := New(logging.NoLog{}, conf, source, func(chain Chain) ([]utils.ListenCloser, error) {
+		started = append(started, chain.ID)
+		return nil, nil
+	})
+
+	// the configured X-chain and the unsupported vm are skipped
+	if err := d.Discover(); err != nil {
+		t.Fatal("discover fail", err)
+	}
+	if len(started) != 1 || started[0] != subnetAVM.ID {
+		t.Fatal("unexpected started chains", started)
+	}
+
+	// only the first evm chain is started, chains are started once
+	*source = append(*source, cchain, subnetEVM)
+	if err := d.Discover(); err != nil {
+		t.Fatal("discover fail", err)
+	}
+	if len(started) != 2 || started[1] != cchain.ID {
+		t.Fatal("unexpected started chains", started)
+	}
+}
+
+func TestIndexSourceFallback(t *testing.T) {
+	conns, _ := sqlitetest.New(t)
+
+	nodeChain := Chain{ID: ids.ID{1}.String(), SubnetID: constants.PrimaryNetworkID.String(), VMID: constants.AVMID.String()}
+	source := &indexSource{conns: conns, fallback: &staticSource{nodeChain}}
+
+	// the blockchains were not indexed yet
+	ctx := context.Background()
+	chains, err := source.Chains(ctx)
+	if err != nil {
+		t.Fatal("chains fail", err)
+	}
+	if len(chains) != 1 || chains[0] != nodeChain {
+		t.Fatal("unexpected fallback chains", chains)
+	}
+
+	sess, err := conns.DB().NewSession("test", time.Minute)
+	if err != nil {
+		t.Fatal("session fail", err)
+	}
+	indexed := &db.Blockchain{
+		ID: ids.ID{2}.String(), SubnetID: constants.PrimaryNetworkID.String(), Name: "X", VMID: constants.AVMID.String(), CreatedAt: time.Unix(1000, 0).UTC(),
+	}
+	if err := db.NewPersist().InsertBlockchain(ctx, sess, indexed, false); err != nil {
+		t.Fatal("insert fail", err)
+	}
+	chains, err = source.Chains(ctx)
+	if err != nil {
+		t.Fatal("chains fail", err)
+	}
+	if len(chains) != 1 || chains[0].ID != indexed.ID {
+		t.Fatal("unexpected indexed chains", chains)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package labels
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+	"github.com/gocraft/dbr/v2"
+)
+
+var (
+	ErrInvalidAddress  = errors.New("invalid address, expected a bech32 or 0x address")
+	ErrInvalidCategory = fmt.Errorf("invalid category, expected one of %s", strings.Join(Categories, ", "))
+	ErrMissingName     = errors.New("label name is required")
+)
+
+// Categories are the categories a label may have, it may also have none
+var Categories = []string{
+	models.LabelCategoryExchange,
+	models.LabelCategoryBridge,
+	models.LabelCategoryValidator,
+	models.LabelCategoryTreasury,
+}
+
+// Label is a label of the seed file and of the admin api
+type Label struct {
+	Address  string `json:"address"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Notes    string `json:"notes"`
+}
+
+// Record validates the label and returns it as stored
+func (l *Label) Record(now time.Time) (*db.AddressLabel, error) {
+	address, err := StoredAddress(l.Address)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(l.Name) == "" {
+		return nil, ErrMissingName
+	}
+	category := strings.ToLower(strings.TrimSpace(l.Category))
+	if category != "" && !validCategory(category) {
+		return nil, ErrInvalidCategory
+	}
+	return &db.AddressLabel{
+		Address:   address,
+		Name:      strings.TrimSpace(l.Name),
+		Category:  category,
+		Notes:     l.Notes,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func validCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// StoredAddress returns the address as stored with its label. X and P-chain
+// addresses are accepted as bech32, with or without chain prefix.
+func StoredAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		b, err := hex.DecodeString(address[2:])
+		if err != nil || len(b) != 20 {
+			return "", ErrInvalidAddress
+		}
+		return "0x" + hex.EncodeToString(b), nil
+	}
+	id, err := params.AddressFromString(address)
+	if err != nil {
+		return "", ErrInvalidAddress
+	}
+	return id.String(), nil
+}
+
+// DisplayAddress returns the stored address as shown in the responses
+func DisplayAddress(stored string) string {
+	if strings.HasPrefix(stored, "0x") {
+		return stored
+	}
+	bech32, err := models.Address(stored).MarshalString()
+	if err != nil {
+		return stored
+	}
+	return string(bech32)
+}
+
+// Model returns the stored label as shown in the responses
+func Model(v *db.AddressLabel) *models.AddressLabel {
+	return &models.AddressLabel{
+		Address:  DisplayAddress(v.Address),
+		Name:     v.Name,
+		Category: v.Category,
+		Notes:    v.Notes,
+	}
+}
+
+// Seed stores the labels of the seed file, a json array of labels. Labels of
+// the file overwrite the labels of their addresses set by the admin api.
+func Seed(ctx context.Context, persist db.Persist, sess dbr.SessionRunner, path string, now time.Time) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var seed []*Label
+	if err := json.Unmarshal(data, &seed); err != nil {
+		return 0, fmt.Errorf("invalid labels seed file %s: %w", path, err)
+	}
+	// a broken file stores none of its labels
+	records := make([]*db.AddressLabel, 0, len(seed))
+	for i, label := range seed {
+		v, err := label.Record(now)
+		if err != nil {
+			return 0, fmt.Errorf("invalid label %d of %s: %w", i, path, err)
+		}
+		records = append(records, v)
+	}
+	for i, v := range records {
+		if err := persist.InsertAddressLabel(ctx, sess, v); err != nil {
+			return i, err
+		}
+	}
+	return len(records), nil
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package labels
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+)
+
+func TestStoredAddress(t *testing.T) {
+	id := ids.ShortID{1}
+	bech32, err := address.FormatBech32(models.Bech32HRP, id.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{bech32, "X-" + bech32, "P-" + bech32} {
+		stored, err := StoredAddress(s)
+		if err != nil || stored != id.String() {
+			t.Fatal("unexpected stored address", s, stored, err)
+		}
+		if DisplayAddress(stored) != bech32 {
+			t.Fatal("unexpected displayed address", DisplayAddress(stored))
+		}
+	}
+
+	stored, err := StoredAddress("0x00000000000000000000000000000000000000AB")
+	if err != nil || stored != "0x00000000000000000000000000000000000000ab" || DisplayAddress(stored) != stored {
+		t.Fatal("unexpected stored c-chain address", stored, err)
+	}
+
+	for _, s := range []string{"", "0x1234", "0xzz000000000000000000000000000000000000ab", "nope"} {
+		if _, err := StoredAddress(s); !errors.Is(err, ErrInvalidAddress) {
+			t.Fatal("expected invalid address", s, err)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	tm := time.Unix(1000, 0).UTC()
+	v, err := (&Label{Address: "0x00000000000000000000000000000000000000ab", Name: " Bridge ", Category: "Bridge"}).Record(tm)
+	if err != nil {
+		t.Fatal("record fail", err)
+	}
+	if v.Name != "Bridge" || v.Category != models.LabelCategoryBridge || !v.UpdatedAt.Equal(tm) {
+		t.Fatal("unexpected record", v)
+	}
+	if _, err := (&Label{Address: v.Address}).Record(tm); !errors.Is(err, ErrMissingName) {
+		t.Fatal("expected missing name", err)
+	}
+	if _, err := (&Label{Address: v.Address, Name: "x", Category: "casino"}).Record(tm); !errors.Is(err, ErrInvalidCategory) {
+		t.Fatal("expected invalid category", err)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	persist := db.NewPersistMock()
+	tm := time.Unix(1000, 0).UTC()
+	path := filepath.Join(t.TempDir(), "labels.json")
+
+	seed := `[
+		{"address": "0x00000000000000000000000000000000000000ab", "name": "bridge", "category": "bridge"},
+		{"address": "0x00000000000000000000000000000000000000cd", "name": "treasury", "category": "treasury", "notes": "multisig"}
+	]`
+	if err := os.WriteFile(path, []byte(seed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	count, err := Seed(ctx, persist, nil, path, tm)
+	if err != nil || count != 2 {
+		t.Fatal("seed fail", count, err)
+	}
+	if v := persist.AddressLabels["0x00000000000000000000000000000000000000cd"]; v == nil || v.Notes != "multisig" {
+		t.Fatal("unexpected label", v)
+	}
+
+	// a broken file stores none of its labels
+	broken := `[{"address": "0x00000000000000000000000000000000000000ef", "name": "ok"}, {"address": "nope", "name": "broken"}]`
+	if err := os.WriteFile(path, []byte(broken), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Seed(ctx, persist, nil, path, tm); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatal("expected invalid address", err)
+	}
+	if len(persist.AddressLabels) != 2 {
+		t.Fatal("unexpected labels", persist.AddressLabels)
+	}
+}
@@ -127,9 +127,31 @@ type AddressInfo struct {
 
 	Assets map[StringID]AssetInfo `json:"assets"`
 
+	Label *AddressLabel `json:"label,omitempty"`
+
 	Score uint64 `json:"-"`
 }
 
+// AddressLabel names an address known to the operators. X and P-chain
+// addresses are shown as bech32, C-chain addresses as lower case hex.
+type AddressLabel struct {
+	Address  string `json:"address"`
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// AddressLabels are the labels of the addresses of a response, keyed by the
+// address as shown by AddressLabel
+type AddressLabels map[string]*AddressLabel
+
+const (
+	LabelCategoryExchange  = "exchange"
+	LabelCategoryBridge    = "bridge"
+	LabelCategoryValidator = "validator"
+	LabelCategoryTreasury  = "treasury"
+)
+
 type AddressChainInfo struct {
 	Address   Address   `json:"address"`
 	ChainID   StringID  `json:"chainID"`
@@ -160,3 +182,146 @@ type ChainCounts struct {
 	ChainID StringID `json:"chainID"`
 	Total   string   `json:"total"`
 }
+
+const (
+	CrossChainTransferPending   = "pending"
+	CrossChainTransferCompleted = "completed"
+)
+
+// CrossChainTransfer is an output exported from the source chain, it is
+// completed once an import on the destination chain consumed it
+type CrossChainTransfer struct {
+	UTXOID             StringID    `json:"utxoID"`
+	ExportTxID         StringID    `json:"exportTxID,omitempty"`
+	ImportTxID         StringID    `json:"importTxID,omitempty"`
+	SourceChainID      StringID    `json:"sourceChainID"`
+	DestinationChainID StringID    `json:"destinationChainID"`
+	AssetID            StringID    `json:"assetID"`
+	Amount             TokenAmount `json:"amount"`
+	Addresses          []Address   `json:"addresses"`
+	Status             string      `json:"status"`
+	ExportedAt         *time.Time  `json:"exportedAt,omitempty"`
+	ImportedAt         *time.Time  `json:"importedAt,omitempty"`
+
+	// Latency is the number of seconds between the export and the import
+	Latency *float64 `json:"latency,omitempty"`
+}
+
+type CrossChainTransferList struct {
+	Transfers []*CrossChainTransfer `json:"transfers"`
+}
+
+// Supply is the supply of an asset on a day in its smallest unit. The unlocked
+// supply is the circulating one, the burned amount are the fees burned.
+type Supply struct {
+	AssetID      StringID    `json:"assetID"`
+	Denomination uint8       `json:"denomination"`
+	Date         time.Time   `json:"date"`
+	Total        TokenAmount `json:"total"`
+	Locked       TokenAmount `json:"locked"`
+	Deposited    TokenAmount `json:"deposited"`
+	Bonded       TokenAmount `json:"bonded"`
+	Unlocked     TokenAmount `json:"unlocked"`
+	Burned       TokenAmount `json:"burned"`
+	Genesis      TokenAmount `json:"genesis"`
+	Holders      uint64      `json:"holders"`
+	UpdatedAt    time.Time   `json:"updatedAt"`
+}
+
+// Field returns the amount of a supply field by its json name
+func (s *Supply) Field(name string) (TokenAmount, bool) {
+	switch name {
+	case SupplyFieldTotal:
+		return s.Total, true
+	case SupplyFieldLocked:
+		return s.Locked, true
+	case SupplyFieldUnlocked, SupplyFieldCirculating:
+		return s.Unlocked, true
+	case SupplyFieldBurned:
+		return s.Burned, true
+	}
+	return "", false
+}
+
+type SupplyHistory struct {
+	AssetID   StringID  `json:"assetID"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Supplies  []*Supply `json:"supplies"`
+}
+
+// AssetHolder is an address holding an asset, the percentage is its share of
+// the supply of the asset
+type AssetHolder struct {
+	Address    Address     `json:"address"`
+	Balance    TokenAmount `json:"balance"`
+	Percentage float64     `json:"percentage"`
+}
+
+type AssetHolderList struct {
+	AssetID     StringID       `json:"assetID"`
+	Supply      TokenAmount    `json:"supply"`
+	HolderCount uint64         `json:"holderCount"`
+	Holders     []*AssetHolder `json:"holders"`
+}
+
+type HolderCount struct {
+	Date        time.Time `json:"date"`
+	HolderCount uint64    `json:"holderCount"`
+}
+
+type HolderCountHistory struct {
+	AssetID      StringID       `json:"assetID"`
+	StartTime    time.Time      `json:"startTime"`
+	EndTime      time.Time      `json:"endTime"`
+	HolderCounts []*HolderCount `json:"holderCounts"`
+}
+
+const (
+	SupplyFieldTotal       = "total"
+	SupplyFieldLocked      = "locked"
+	SupplyFieldUnlocked    = "unlocked"
+	SupplyFieldCirculating = "circulating"
+	SupplyFieldBurned      = "burned"
+)
+
+const (
+	LockTypeDeposited       = "deposited"
+	LockTypeBonded          = "bonded"
+	LockTypeDepositedBonded = "deposited_bonded"
+)
+
+// UTXO is an unspent output with the state which decides whether it can be
+// spent
+type UTXO struct {
+	ID            StringID    `json:"id"`
+	TransactionID StringID    `json:"transactionID"`
+	OutputIndex   uint64      `json:"outputIndex"`
+	ChainID       StringID    `json:"chainID"`
+	AssetID       StringID    `json:"assetID"`
+	OutputType    OutputType  `json:"outputType"`
+	Amount        TokenAmount `json:"amount"`
+	Addresses     []Address   `json:"addresses"`
+	Threshold     uint64      `json:"threshold"`
+	Locktime      uint64      `json:"locktime"`
+	StakeLocktime uint64      `json:"stakeLocktime"`
+	Stake         bool        `json:"stake"`
+	Frozen        bool        `json:"frozen"`
+
+	// LockType is deposited, bonded or deposited_bonded for the locked
+	// outputs of camino, along with the transactions holding the lock
+	LockType    string   `json:"lockType,omitempty"`
+	DepositTxID StringID `json:"depositTxID,omitempty"`
+	BondTxID    StringID `json:"bondTxID,omitempty"`
+
+	// Atomic marks an output exported to the chain which still has to be
+	// imported before it can be spent there
+	Atomic bool `json:"atomic"`
+
+	Spendable bool      `json:"spendable"`
+	CreatedAt time.Time `json:"timestamp"`
+}
+
+type UTXOList struct {
+	UTXOs []*UTXO `json:"utxos"`
+}
@@ -50,6 +50,8 @@ type TransactionList struct {
 	EndTime time.Time `json:"endTime"`
 
 	Next *string `json:"next,omitempty"`
+
+	Labels AddressLabels `json:"labels,omitempty"`
 }
 
 type EmissionsResult struct {
@@ -143,6 +145,8 @@ type CTransactionList struct {
 	// EndTime is the calculated end time rounded to the nearest
 	// TransactionRoundDuration.
 	EndTime time.Time `json:"endTime"`
+
+	Labels AddressLabels `json:"labels,omitempty"`
 }
 
 type AssetList struct {
@@ -220,6 +224,9 @@ type SearchResults struct {
 
 	// Results is a list of SearchResult
 	Results SearchResultSet `json:"results"`
+
+	// Labels are the labels of the addresses of the results
+	Labels AddressLabels `json:"labels,omitempty"`
 }
 
 type SearchResultSet []SearchResult
@@ -273,6 +280,58 @@ type TxfeeAggregates struct {
 
 type TxfeeAggregatesList []TxfeeAggregates
 
+// FeeStats are the fee statistics in nAVAX of the transactions of one type on
+// a chain
+type FeeStats struct {
+	ChainID          StringID `json:"chainID"`
+	Type             string   `json:"type"`
+	TransactionCount uint64   `json:"transactionCount"`
+	Total            uint64   `json:"total"`
+	Min              uint64   `json:"min"`
+	Avg              uint64   `json:"avg"`
+	P95              uint64   `json:"p95"`
+	Max              uint64   `json:"max"`
+}
+
+type FeeStatsList struct {
+	StartTime time.Time   `json:"startTime"`
+	EndTime   time.Time   `json:"endTime"`
+	Stats     []*FeeStats `json:"stats"`
+}
+
+// BurnedFees are the fees in nAVAX burned within the time range, and since
+// genesis up to its end
+type BurnedFees struct {
+	StartTime  time.Time `json:"startTime"`
+	EndTime    time.Time `json:"endTime"`
+	Burned     uint64    `json:"burned"`
+	Cumulative uint64    `json:"cumulative"`
+}
+
+type BurnedFeesHistogram struct {
+	BurnedFees   BurnedFees    `json:"burnedFees"`
+	IntervalSize time.Duration `json:"intervalSize,omitempty"`
+	Intervals    []BurnedFees  `json:"intervals,omitempty"`
+}
+
+// CChainFees split the fees in nAVAX of the C-chain transactions within the
+// time range into the base fee of their blocks and the tip above it
+type CChainFees struct {
+	StartTime        time.Time `json:"startTime"`
+	EndTime          time.Time `json:"endTime"`
+	TransactionCount uint64    `json:"transactionCount"`
+	GasUsed          uint64    `json:"gasUsed"`
+	Total            uint64    `json:"total"`
+	BaseFee          uint64    `json:"baseFee"`
+	Tip              uint64    `json:"tip"`
+}
+
+type CChainFeesHistogram struct {
+	Fees         CChainFees    `json:"fees"`
+	IntervalSize time.Duration `json:"intervalSize,omitempty"`
+	Intervals    []CChainFees  `json:"intervals,omitempty"`
+}
+
 type AggregatesHistogram struct {
 	Aggregates   Aggregates    `json:"aggregates"`
 	IntervalSize time.Duration `json:"intervalSize,omitempty"`
@@ -0,0 +1,43 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package models
+
+import "time"
+
+const EventTypeTransactionIndexed = "transaction_indexed"
+
+// TransactionIndexedEvent is the event delivered to downstream systems for
+// every indexed transaction, in the same form for all chains
+type TransactionIndexedEvent struct {
+	Type      string    `json:"type"`
+	ChainID   string    `json:"chainID"`
+	TxID      string    `json:"txID"`
+	TxType    string    `json:"txType"`
+	Timestamp time.Time `json:"timestamp"`
+	Txfee     uint64    `json:"txfee"`
+
+	Inputs  []*EventAmount `json:"inputs"`
+	Outputs []*EventAmount `json:"outputs"`
+}
+
+// EventAmount is an amount of an asset moved from or to the addresses. The
+// addresses are bech32 encoded, the hex addresses of the c-chain accounts are
+// listed as caddresses. The amount is a decimal string, c-chain values don't
+// fit into 64 bits.
+type EventAmount struct {
+	Addresses  []Address `json:"addresses,omitempty"`
+	CAddresses []string  `json:"caddresses,omitempty"`
+	AssetID    string    `json:"assetID,omitempty"`
+	Amount     string    `json:"amount"`
+}
@@ -46,3 +46,66 @@ type CurrentValidatorsParams struct {
 type PeersParams struct {
 	NodeIDs []string `json:"nodeIDs"`
 }
+
+// ValidatorSet is the primary network validator set at a moment in the past
+type ValidatorSet struct {
+	Time       time.Time         `json:"time"`
+	Height     *uint64           `json:"height,omitempty"`
+	TotalStake TokenAmount       `json:"totalStake"`
+	Validators []*ValidatorStake `json:"validators"`
+}
+
+// ValidatorStake is the stake of a node in a ValidatorSet, the weight
+// delegated to the node is included in TotalWeight
+type ValidatorStake struct {
+	NodeID          string      `json:"nodeID"`
+	TxID            StringID    `json:"txID"`
+	StartTime       time.Time   `json:"startTime"`
+	EndTime         time.Time   `json:"endTime"`
+	Weight          TokenAmount `json:"weight"`
+	DelegatedWeight TokenAmount `json:"delegatedWeight"`
+	Delegators      uint64      `json:"delegators"`
+	TotalWeight     TokenAmount `json:"totalWeight"`
+}
+
+// Subnet is a subnet created by a CreateSubnetTx with its chains and the
+// validators it had over time
+type Subnet struct {
+	ID          StringID           `json:"id"`
+	Owners      []Address          `json:"owners"`
+	Threshold   uint32             `json:"threshold"`
+	Locktime    uint64             `json:"locktime"`
+	AssetID     StringID           `json:"assetID,omitempty"`
+	Blockchains []StringID         `json:"blockchains"`
+	Validators  []*SubnetValidator `json:"validators"`
+	CreatedAt   time.Time          `json:"createdAt"`
+}
+
+type SubnetValidator struct {
+	TxID      StringID    `json:"txID"`
+	NodeID    string      `json:"nodeID"`
+	Weight    TokenAmount `json:"weight"`
+	StartTime time.Time   `json:"startTime"`
+	EndTime   time.Time   `json:"endTime"`
+	RemovedAt *time.Time  `json:"removedAt,omitempty"`
+}
+
+type SubnetList struct {
+	Subnets []*Subnet `json:"subnets"`
+}
+
+// Blockchain is a chain created by a CreateChainTx, its id is the id of the
+// transaction
+type Blockchain struct {
+	ID          StringID   `json:"id"`
+	SubnetID    StringID   `json:"subnetID"`
+	Name        string     `json:"name"`
+	VMID        StringID   `json:"vmID"`
+	FxIDs       []StringID `json:"fxIDs"`
+	GenesisHash string     `json:"genesisHash"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+type BlockchainList struct {
+	Blockchains []*Blockchain `json:"blockchains"`
+}
@@ -16,6 +16,7 @@ package models
 import (
 	"encoding/json"
 	"strconv"
+	"strings"
 
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
 
@@ -108,3 +109,18 @@ type TokenAmount string
 func TokenAmountForUint64(i uint64) TokenAmount {
 	return TokenAmount(strconv.Itoa(int(i)))
 }
+
+// Denominated returns the amount in whole tokens of an asset with the given
+// denomination, 1500000000 of an asset with 9 decimals is 1.5
+func (a TokenAmount) Denominated(denomination uint8) json.Number {
+	digits := strings.TrimLeft(string(a), "0")
+	if len(digits) <= int(denomination) {
+		digits = strings.Repeat("0", int(denomination)-len(digits)+1) + digits
+	}
+	point := len(digits) - int(denomination)
+	fraction := strings.TrimRight(digits[point:], "0")
+	if fraction == "" {
+		return json.Number(digits[:point])
+	}
+	return json.Number(digits[:point] + "." + fraction)
+}
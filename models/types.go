@@ -81,6 +81,7 @@ var (
 	ResultTypeCBlock      SearchResultType = "cBlock"
 	ResultTypeCTrans      SearchResultType = "cTransaction"
 	ResultTypeCAddress    SearchResultType = "cAddress"
+	ResultTypeLabel       SearchResultType = "label"
 
 	TypeUnknown = "unknown"
 )
@@ -154,3 +154,12 @@ func (c *Client) ReadReceipt(txHash string, rpcTimeout time.Duration) (*Extended
 
 	return result, nil
 }
+
+// BlockNumber returns the height of the last accepted block
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	var result hexutil.Uint64
+	if err := c.rpcClient.CallContext(ctx, &result, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+	return uint64(result), nil
+}
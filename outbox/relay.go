@@ -0,0 +1,131 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// BatchSize is the maximum number of events delivered at once
+	BatchSize = 100
+
+	relayIdleInterval  = time.Second
+	relayRetryInterval = 5 * time.Second
+
+	MetricDeliveredCountKey = "outbox_events_delivered"
+	MetricFailureCountKey   = "outbox_delivery_failure"
+)
+
+// Relay delivers the events of the outbox table to a sink and removes them
+// once delivered, in the order they were indexed
+type Relay struct {
+	conns          *utils.Connections
+	sink           Sink
+	log            logging.Logger
+	runningControl utils.Running
+}
+
+func NewRelay(conns *utils.Connections, sink Sink, log logging.Logger) *Relay {
+	utils.Prometheus.CounterInit(MetricDeliveredCountKey, "outbox events delivered")
+	utils.Prometheus.CounterInit(MetricFailureCountKey, "outbox deliveries failed")
+	return &Relay{
+		conns:          conns,
+		sink:           sink,
+		log:            log,
+		runningControl: utils.NewRunning(),
+	}
+}
+
+func (r *Relay) Listen() error {
+	r.log.Info("starting outbox relay")
+	defer r.log.Info("exiting outbox relay")
+
+	for !r.runningControl.IsStopped() {
+		delivered, err := r.RelayBatch(context.Background())
+		switch {
+		case err != nil:
+			_ = utils.Prometheus.CounterInc(MetricFailureCountKey)
+			r.log.Warn("outbox delivery failed", zap.Error(err))
+			time.Sleep(relayRetryInterval)
+		case delivered == 0:
+			time.Sleep(relayIdleInterval)
+		}
+	}
+	return nil
+}
+
+func (r *Relay) Close() error {
+	r.runningControl.Close()
+	errs := r.sink.Close()
+	if err := r.conns.Close(); err != nil {
+		return err
+	}
+	return errs
+}
+
+// RelayBatch delivers the oldest batch of events and returns how many were
+// delivered
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	sess, err := r.conns.Primary().NewSession("outbox_relay", cfg.DBTimeout)
+	if err != nil {
+		return 0, err
+	}
+
+	var events []*db.OutboxEvent
+	_, err = sess.Select(
+		"id",
+		"chain_id",
+		"tx_id",
+		"type",
+		"payload",
+		"created_at",
+	).From(db.TableOutboxEvents).
+		OrderAsc("created_at").
+		OrderAsc("id").
+		Limit(BatchSize).
+		LoadContext(ctx, &events)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	if err = r.sink.Deliver(ctx, events); err != nil {
+		return 0, err
+	}
+
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	_, err = sess.
+		DeleteFrom(db.TableOutboxEvents).
+		Where("id IN ?", ids).
+		ExecContext(ctx)
+	if err != nil {
+		// the batch is delivered again, which at least once delivery allows
+		return 0, err
+	}
+
+	for range events {
+		_ = utils.Prometheus.CounterInc(MetricDeliveredCountKey)
+	}
+	return len(events), nil
+}
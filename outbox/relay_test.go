@@ -0,0 +1,102 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+type failingSink struct{}
+
+func (failingSink) Deliver(context.Context, []*db.OutboxEvent) error { return errors.New("down") }
+func (failingSink) Close() error                                     { return nil }
+
+func TestRelayBatch(t *testing.T) {
+	dir := t.TempDir()
+	conns, sess := sqlitetest.New(t)
+
+	p := db.NewPersist()
+	ctx := context.Background()
+	for i, txID := range []string{"tx1", "tx2"} {
+		event := &db.OutboxEvent{
+			ChainID:   "xchain",
+			TxID:      txID,
+			Type:      "transaction_indexed",
+			Payload:   []byte(`{"txID":"` + txID + `"}`),
+			CreatedAt: time.Unix(int64(i), 0).UTC(),
+		}
+		event.ComputeID()
+		if err := p.InsertOutboxEvent(ctx, sess, event); err != nil {
+			t.Fatal("insert fail", err)
+		}
+		// duplicates of a redelivered container are ignored
+		if err := p.InsertOutboxEvent(ctx, sess, event); err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	// events stay in the outbox until they are delivered
+	failing := &Relay{conns: conns, sink: failingSink{}, log: logging.NoLog{}}
+	if _, err := failing.RelayBatch(ctx); err == nil {
+		t.Fatal("expected delivery failure")
+	}
+
+	target := filepath.Join(dir, "events.jsonl")
+	sink, err := NewSink(cfg.Outbox{Sink: SinkFile, Target: target}, cfg.Broker{})
+	if err != nil {
+		t.Fatal("sink fail", err)
+	}
+	defer sink.Close()
+
+	relay := &Relay{conns: conns, sink: sink, log: logging.NoLog{}}
+	delivered, err := relay.RelayBatch(ctx)
+	if err != nil || delivered != 2 {
+		t.Fatal("relay fail", delivered, err)
+	}
+	delivered, err = relay.RelayBatch(ctx)
+	if err != nil || delivered != 0 {
+		t.Fatal("expected empty outbox", delivered, err)
+	}
+
+	content, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal("read fail", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(content)), "\n"); len(lines) != 2 || lines[0] != `{"txID":"tx1"}` {
+		t.Fatal("compare fail", lines)
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	if _, err := NewSink(cfg.Outbox{Sink: SinkHTTP}, cfg.Broker{}); !errors.Is(err, ErrNoSinkTarget) {
+		t.Fatal("expected no target", err)
+	}
+	if _, err := NewSink(cfg.Outbox{Sink: "unknown", Target: "x"}, cfg.Broker{}); !errors.Is(err, ErrUnknownSink) {
+		t.Fatal("expected unknown sink", err)
+	}
+	if _, err := NewSink(cfg.Outbox{Sink: SinkBroker, Target: "events"}, cfg.Broker{}); err == nil {
+		t.Fatal("expected missing broker")
+	}
+}
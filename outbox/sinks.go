@@ -0,0 +1,156 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/stream"
+)
+
+const (
+	SinkHTTP   = "http"
+	SinkFile   = "file"
+	SinkBroker = "broker"
+
+	httpSinkTimeout = 30 * time.Second
+)
+
+var (
+	ErrUnknownSink  = errors.New("unknown outbox sink")
+	ErrNoSinkTarget = errors.New("outbox sink target is empty")
+)
+
+// Sink delivers outbox events to a downstream system. A batch is removed from
+// the outbox only once Deliver returned without error, so a sink may see an
+// event more than once.
+type Sink interface {
+	Deliver(ctx context.Context, events []*db.OutboxEvent) error
+	Close() error
+}
+
+// NewSink creates the configured sink, the broker sink publishes over the
+// broker of the services config
+func NewSink(conf cfg.Outbox, brokerConf cfg.Broker) (Sink, error) {
+	if conf.Target == "" {
+		return nil, ErrNoSinkTarget
+	}
+	switch conf.Sink {
+	case SinkHTTP:
+		return &httpSink{url: conf.Target, client: &http.Client{Timeout: httpSinkTimeout}}, nil
+	case SinkFile:
+		f, err := os.OpenFile(conf.Target, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		return &fileSink{file: f}, nil
+	case SinkBroker:
+		broker, err := stream.NewBroker(brokerConf)
+		if err != nil {
+			return nil, err
+		}
+		if broker == nil {
+			return nil, stream.ErrNoBroker
+		}
+		return &brokerSink{broker: broker, topic: conf.Target}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSink, conf.Sink)
+	}
+}
+
+// httpSink posts a batch as json array of the event payloads
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpSink) Deliver(ctx context.Context, events []*db.OutboxEvent) error {
+	payloads := make([]json.RawMessage, 0, len(events))
+	for _, event := range events {
+		payloads = append(payloads, event.Payload)
+	}
+	body, err := json.Marshal(payloads)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("outbox http sink: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error { return nil }
+
+// fileSink appends the event payloads as json lines
+type fileSink struct {
+	file *os.File
+}
+
+func (s *fileSink) Deliver(_ context.Context, events []*db.OutboxEvent) error {
+	var buf bytes.Buffer
+	for _, event := range events {
+		buf.Write(event.Payload)
+		buf.WriteByte('\n')
+	}
+	if _, err := s.file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *fileSink) Close() error { return s.file.Close() }
+
+// brokerSink publishes the event payloads to a broker topic, keyed by the
+// event id
+type brokerSink struct {
+	broker stream.Broker
+	topic  string
+}
+
+func (s *brokerSink) Deliver(ctx context.Context, events []*db.OutboxEvent) error {
+	msgs := make([]*stream.BrokerMessage, 0, len(events))
+	for _, event := range events {
+		msgs = append(msgs, &stream.BrokerMessage{
+			Key:       event.ID,
+			ChainID:   event.ChainID,
+			Body:      event.Payload,
+			Timestamp: event.CreatedAt,
+		})
+	}
+	return s.broker.Publish(ctx, s.topic, msgs...)
+}
+
+func (s *brokerSink) Close() error { return s.broker.Close() }
@@ -0,0 +1,157 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/fsnotify/fsnotify"
+)
+
+// settleTime is how long the config file has to be unchanged before it is
+// read, editors and config maps write it in several steps
+const settleTime = 500 * time.Millisecond
+
+// ApplyFunc applies the reloaded config to the running services
+type ApplyFunc func(config *cfg.Config)
+
+// Watcher reads the config file again on SIGHUP and when it changes. The
+// settings which need no restart are applied, the changed fields which need
+// one are logged.
+type Watcher struct {
+	log     logging.Logger
+	path    string
+	running *cfg.Config
+	apply   ApplyFunc
+
+	lock     sync.Mutex
+	checksum [sha256.Size]byte
+
+	sigCh  chan os.Signal
+	doneCh chan struct{}
+}
+
+func New(log logging.Logger, path string, running *cfg.Config, apply ApplyFunc) *Watcher {
+	w := &Watcher{
+		log:     log,
+		path:    path,
+		running: running,
+		apply:   apply,
+		sigCh:   make(chan os.Signal, 1),
+		doneCh:  make(chan struct{}),
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		w.checksum = sha256.Sum256(data)
+	}
+	return w
+}
+
+func (w *Watcher) Listen() error {
+	// the directory is watched, the file may be replaced instead of written
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		return err
+	}
+
+	signal.Notify(w.sigCh, syscall.SIGHUP)
+	defer signal.Stop(w.sigCh)
+
+	w.log.Info("watching config file", zap.String("file", w.path))
+
+	settle := time.NewTimer(settleTime)
+	settle.Stop()
+	for {
+		select {
+		case <-w.sigCh:
+			w.log.Info("config reload requested")
+			w.Reload()
+		case <-watcher.Events:
+			settle.Reset(settleTime)
+		case <-settle.C:
+			if w.changed() {
+				w.Reload()
+			}
+		case err := <-watcher.Errors:
+			w.log.Warn("watching config file failed", zap.Error(err))
+		case <-w.doneCh:
+			return nil
+		}
+	}
+}
+
+func (w *Watcher) Close() error {
+	close(w.doneCh)
+	return nil
+}
+
+// changed is true if the content of the file changed since it was last read
+func (w *Watcher) changed() bool {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return false
+	}
+	checksum := sha256.Sum256(data)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return !bytes.Equal(checksum[:], w.checksum[:])
+}
+
+// Reload reads the config file and applies it. A config which can't be read
+// is not applied, the running settings are kept.
+func (w *Watcher) Reload() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		w.log.Warn("config reload failed", zap.Error(err))
+		return false
+	}
+	w.checksum = sha256.Sum256(data)
+
+	reloaded, err := cfg.NewFromFile(w.path)
+	if err != nil {
+		w.log.Warn("config reload failed", zap.Error(err))
+		return false
+	}
+	// compared with the config the services were started with, so a field
+	// is reported until it is restarted or changed back
+	fields, err := cfg.RestartFields(w.running, reloaded)
+	if err != nil {
+		w.log.Warn("config reload failed", zap.Error(err))
+		return false
+	}
+	if len(fields) != 0 {
+		w.log.Warn("config fields changed which are only applied on restart", zap.Strings("fields", fields))
+	}
+	w.apply(reloaded)
+	w.log.Info("config reloaded", zap.String("file", w.path))
+	return true
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/chain4travel/magellan/cfg"
+)
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	write := func(config string) {
+		if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"cacheUpdateInterval": 5}`)
+	running, err := cfg.NewFromFile(path)
+	if err != nil {
+		t.Fatal("config fail", err)
+	}
+
+	var applied *cfg.Config
+	w := New(logging.NoLog{}, path, running, func(config *cfg.Config) { applied = config })
+	if w.changed() {
+		t.Fatal("unexpected change")
+	}
+
+	write(`{"cacheUpdateInterval": 60, "listenAddr": ":8081"}`)
+	if !w.changed() || !w.Reload() {
+		t.Fatal("reload fail")
+	}
+	if applied == nil || applied.CacheUpdateInterval != 60 || w.changed() {
+		t.Fatal("unexpected config", applied)
+	}
+
+	// a broken config keeps the running settings
+	applied = nil
+	write(`{"logLevel": "loud"}`)
+	if w.Reload() || applied != nil {
+		t.Fatal("unexpected reload", applied)
+	}
+}
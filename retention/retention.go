@@ -0,0 +1,319 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package retention
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gocraft/dbr/v2"
+)
+
+const (
+	// BatchSize bounds the number of rows deleted or updated by one statement,
+	// so pruning a large backlog doesn't hold locks for long.
+	BatchSize = 1000
+
+	// DefaultInterval is the time between two runs if no interval is
+	// configured
+	DefaultInterval = time.Hour
+
+	// MinInterval is the shortest time between two runs, shorter intervals
+	// are raised to it
+	MinInterval = time.Minute
+
+	day = 24 * time.Hour
+
+	// consensusTopicSuffix marks the tx_pool rows of the x-chain vertices
+	consensusTopicSuffix = "-consensus"
+)
+
+// Report sums up what a retention run removed. Bytes are the lengths of the
+// removed serializations, the space is handed back to the database and only
+// shows up on disk once the database compacts the tables.
+type Report struct {
+	TxPoolRows  uint64 `json:"txPoolRows"`
+	TxPoolBytes uint64 `json:"txPoolBytes"`
+
+	CvmBlockRows  uint64 `json:"cvmBlockRows"`
+	CvmBlockBytes uint64 `json:"cvmBlockBytes"`
+
+	CvmTransactionRows  uint64 `json:"cvmTransactionRows"`
+	CvmTransactionBytes uint64 `json:"cvmTransactionBytes"`
+}
+
+// ReclaimedBytes is the total size of the serializations removed by the run
+func (r *Report) ReclaimedBytes() uint64 {
+	return r.TxPoolBytes + r.CvmBlockBytes + r.CvmTransactionBytes
+}
+
+// Pruner applies the retention policies to the database
+type Pruner struct {
+	conf   cfg.Retention
+	chains map[string]cfg.Chain
+}
+
+func NewPruner(conf cfg.Retention, chains map[string]cfg.Chain) *Pruner {
+	return &Pruner{
+		conf:   conf,
+		chains: chains,
+	}
+}
+
+// Enabled reports whether any retention policy is configured
+func (p *Pruner) Enabled() bool {
+	return p.conf.TxPoolDays > 0 || p.conf.SerializationDays > 0
+}
+
+// Interval returns the time between two runs, DefaultInterval if no interval
+// is configured and at least MinInterval
+func (p *Pruner) Interval() time.Duration {
+	interval := time.Duration(p.conf.Interval) * time.Second
+	if interval == 0 {
+		return DefaultInterval
+	}
+	if interval < MinInterval {
+		return MinInterval
+	}
+	return interval
+}
+
+// Run prunes everything older than the configured retention relative to now.
+func (p *Pruner) Run(ctx context.Context, conns *utils.Connections, now time.Time) (*Report, error) {
+	report := &Report{}
+
+	dbRunner, err := conns.Primary().NewSession("retention", cfg.DBTimeout)
+	if err != nil {
+		return report, err
+	}
+
+	if p.conf.TxPoolDays > 0 {
+		cutoff := now.Add(-time.Duration(p.conf.TxPoolDays) * day)
+		if err := p.pruneTxPool(ctx, dbRunner, cutoff, report); err != nil {
+			return report, err
+		}
+	}
+
+	if p.conf.SerializationDays > 0 {
+		cutoff := now.Add(-time.Duration(p.conf.SerializationDays) * day)
+		report.CvmTransactionRows, report.CvmTransactionBytes, err = dropSerialization(ctx, dbRunner, db.TableCvmTransactionsTxdata, "hash", cutoff)
+		if err != nil {
+			return report, err
+		}
+		report.CvmBlockRows, report.CvmBlockBytes, err = dropSerialization(ctx, dbRunner, db.TableCvmBlocks, "hash", cutoff)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+type txPoolRow struct {
+	ID        string
+	ChainID   string
+	MsgKey    string
+	Topic     string
+	Size      uint64
+	CreatedAt time.Time
+}
+
+// pruneTxPool deletes the tx_pool rows older than cutoff whose container has
+// been indexed. Rows which were never processed are kept, the consumers still
+// pick them up on restart.
+func (p *Pruner) pruneTxPool(ctx context.Context, dbRunner *dbr.Session, cutoff time.Time, report *Report) error {
+	var last *txPoolRow
+	for {
+		var rows []*txPoolRow
+		sq := dbRunner.Select(
+			"id",
+			"chain_id",
+			"msg_key",
+			"topic",
+			"COALESCE(LENGTH(serialization), 0) AS size",
+			"created_at",
+		).
+			From(db.TableTxPool).
+			Where("created_at < ?", cutoff)
+		if last != nil {
+			sq = sq.Where("(created_at > ? OR (created_at = ? AND id > ?))", last.CreatedAt, last.CreatedAt, last.ID)
+		}
+		_, err := sq.
+			OrderAsc("created_at").
+			OrderAsc("id").
+			Limit(BatchSize).
+			LoadContext(ctx, &rows)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		last = rows[len(rows)-1]
+
+		processed, err := p.processedTxPoolRows(ctx, dbRunner, rows)
+		if err != nil {
+			return err
+		}
+		if len(processed) == 0 {
+			continue
+		}
+
+		poolIDs := make([]string, 0, len(processed))
+		for _, row := range processed {
+			poolIDs = append(poolIDs, row.ID)
+			report.TxPoolBytes += row.Size
+		}
+		_, err = dbRunner.
+			DeleteFrom(db.TableTxPool).
+			Where("id IN ?", poolIDs).
+			ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+		report.TxPoolRows += uint64(len(poolIDs))
+	}
+}
+
+// indexedLookup is a column whose values are the msg_keys of the indexed
+// containers of one kind
+type indexedLookup struct {
+	table  string
+	column string
+}
+
+// processedTxPoolRows returns the rows whose container shows up in the table
+// its consumer writes to. Rows of chains which aren't configured are kept.
+func (p *Pruner) processedTxPoolRows(ctx context.Context, dbRunner *dbr.Session, rows []*txPoolRow) ([]*txPoolRow, error) {
+	// the key a row is found under in its lookup column
+	keys := make(map[indexedLookup]map[string][]*txPoolRow)
+	for _, row := range rows {
+		chain, ok := p.chains[row.ChainID]
+		if !ok {
+			continue
+		}
+
+		key := row.MsgKey
+		var lookup indexedLookup
+		switch chain.VMType {
+		case models.AVMName:
+			if strings.HasSuffix(row.Topic, consensusTopicSuffix) {
+				lookup = indexedLookup{db.TableTransactionsEpochs, "vertex_id"}
+			} else {
+				lookup = indexedLookup{db.TableTransactions, "id"}
+			}
+		case models.PVMName:
+			lookup = indexedLookup{db.TablePvmBlocks, "id"}
+		case models.CVMName:
+			// the c-chain container id is the block hash
+			id, err := ids.FromString(row.MsgKey)
+			if err != nil {
+				continue
+			}
+			lookup = indexedLookup{db.TableCvmBlocks, "hash"}
+			key = common.BytesToHash(id[:]).Hex()
+		default:
+			continue
+		}
+
+		if keys[lookup] == nil {
+			keys[lookup] = make(map[string][]*txPoolRow)
+		}
+		keys[lookup][key] = append(keys[lookup][key], row)
+	}
+
+	var processed []*txPoolRow
+	for lookup, rowsByKey := range keys {
+		values := make([]string, 0, len(rowsByKey))
+		for key := range rowsByKey {
+			values = append(values, key)
+		}
+
+		var found []string
+		_, err := dbRunner.
+			Select("DISTINCT "+lookup.column).
+			From(lookup.table).
+			Where(lookup.column+" IN ?", values).
+			LoadContext(ctx, &found)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range found {
+			processed = append(processed, rowsByKey[key]...)
+		}
+	}
+	return processed, nil
+}
+
+type serializationRow struct {
+	RowKey    string
+	Size      uint64
+	CreatedAt time.Time
+}
+
+// dropSerialization clears the serialization column of the rows of table
+// created before cutoff. It returns the number of rows cleared and the size of
+// the serializations removed.
+func dropSerialization(ctx context.Context, dbRunner *dbr.Session, table string, keyColumn string, cutoff time.Time) (uint64, uint64, error) {
+	var count, size uint64
+	var last *serializationRow
+	for {
+		var rows []*serializationRow
+		sq := dbRunner.Select(
+			keyColumn+" AS row_key",
+			"LENGTH(serialization) AS size",
+			"created_at",
+		).
+			From(table).
+			Where("created_at < ?", cutoff).
+			Where("serialization IS NOT NULL")
+		if last != nil {
+			sq = sq.Where("(created_at > ? OR (created_at = ? AND "+keyColumn+" > ?))", last.CreatedAt, last.CreatedAt, last.RowKey)
+		}
+		_, err := sq.
+			OrderAsc("created_at").
+			OrderAsc(keyColumn).
+			Limit(BatchSize).
+			LoadContext(ctx, &rows)
+		if err != nil {
+			return count, size, err
+		}
+		if len(rows) == 0 {
+			return count, size, nil
+		}
+		last = rows[len(rows)-1]
+
+		keys := make([]string, 0, len(rows))
+		for _, row := range rows {
+			keys = append(keys, row.RowKey)
+			size += row.Size
+		}
+		_, err = dbRunner.
+			Update(table).
+			Set("serialization", nil).
+			Where(keyColumn+" IN ?", keys).
+			ExecContext(ctx)
+		if err != nil {
+			return count, size, err
+		}
+		count += uint64(len(rows))
+	}
+}
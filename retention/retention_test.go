@@ -0,0 +1,130 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+func TestPrunerRun(t *testing.T) {
+	conns, sess := sqlitetest.New(t)
+
+	p := db.NewPersist()
+	ctx := context.Background()
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-10 * day)
+
+	// an indexed and a pending container, both past the retention
+	for _, msgKey := range []string{"indexed", "pending"} {
+		txPool := &db.TxPool{
+			ChainID:       "xchain",
+			MsgKey:        msgKey,
+			Serialization: []byte("container"),
+			Topic:         "1-xchain-decisions",
+			CreatedAt:     old,
+		}
+		txPool.ComputeID()
+		if err := p.InsertTxPool(ctx, sess, txPool); err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+	if err := p.InsertTransactions(ctx, sess, &db.Transactions{ID: "indexed", ChainID: "xchain", CreatedAt: old}, false); err != nil {
+		t.Fatal("insert fail", err)
+	}
+
+	for _, address := range []string{"0x01", "0x02"} {
+		if err := p.InsertCvmAccount(ctx, sess, &db.CvmAccount{Address: address}, true); err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+	for i, createdAt := range []time.Time{old, now} {
+		txdata := &db.CvmTransactionsTxdata{
+			Hash:          []string{"0xold", "0xnew"}[i],
+			Block:         "1",
+			Idx:           uint64(i),
+			FromAddr:      "0x01",
+			ToAddr:        "0x02",
+			Serialization: []byte("serialization"),
+			CreatedAt:     createdAt,
+		}
+		if err := p.InsertCvmTransactionsTxdata(ctx, sess, txdata, false); err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	pruner := NewPruner(cfg.Retention{TxPoolDays: 7, SerializationDays: 7}, map[string]cfg.Chain{
+		"xchain": {ID: "xchain", VMType: models.AVMName},
+	})
+	report, err := pruner.Run(ctx, conns, now)
+	if err != nil {
+		t.Fatal("retention fail", err)
+	}
+
+	if report.TxPoolRows != 1 || report.TxPoolBytes != uint64(len("container")) {
+		t.Fatal("tx_pool report fail", report)
+	}
+	if report.CvmTransactionRows != 1 || report.CvmTransactionBytes != uint64(len("serialization")) {
+		t.Fatal("cvm transaction report fail", report)
+	}
+	if report.ReclaimedBytes() != uint64(len("container")+len("serialization")) {
+		t.Fatal("reclaimed bytes fail", report)
+	}
+
+	var msgKeys []string
+	if _, err = sess.Select("msg_key").From(db.TableTxPool).LoadContext(ctx, &msgKeys); err != nil {
+		t.Fatal("query fail", err)
+	}
+	if len(msgKeys) != 1 || msgKeys[0] != "pending" {
+		t.Fatal("tx_pool fail", msgKeys)
+	}
+
+	for _, hash := range []string{"0xold", "0xnew"} {
+		fv, err := p.QueryCvmTransactionsTxdata(ctx, sess, &db.CvmTransactionsTxdata{Hash: hash})
+		if err != nil {
+			t.Fatal("query fail", err)
+		}
+		if (len(fv.Serialization) == 0) != (hash == "0xold") {
+			t.Fatal("serialization fail", hash)
+		}
+	}
+
+	// a second run has nothing left to do
+	report, err = pruner.Run(ctx, conns, now)
+	if err != nil {
+		t.Fatal("retention fail", err)
+	}
+	if report.ReclaimedBytes() != 0 {
+		t.Fatal("second run fail", report)
+	}
+}
+
+func TestPrunerInterval(t *testing.T) {
+	for interval, expected := range map[uint64]time.Duration{
+		0:    DefaultInterval,
+		1:    MinInterval,
+		3600: time.Hour,
+	} {
+		pruner := NewPruner(cfg.Retention{TxPoolDays: 1, Interval: interval}, nil)
+		if pruner.Interval() != expected {
+			t.Fatal("unexpected interval", interval, pruner.Interval())
+		}
+	}
+}
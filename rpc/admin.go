@@ -0,0 +1,219 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+
+package rpc
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/servicesctrl"
+	"github.com/chain4travel/magellan/stream"
+	"github.com/chain4travel/magellan/utils"
+	"github.com/gocraft/dbr/v2"
+	"go.uber.org/zap"
+)
+
+var (
+	errMissingChainID         = errors.New("chainID is required")
+	errProducersRunning       = errors.New("the producers of the chain have to be paused first")
+	errUnknownTopic           = errors.New("no node index for the topic")
+	errBalanceManagerDisabled = errors.New("the accumulate_balance_indexer feature is not enabled")
+)
+
+type ChainArgs struct {
+	ChainID string `json:"chainID"`
+}
+
+type PausedReply struct {
+	Producers []string `json:"producers"`
+	Consumers []string `json:"consumers"`
+}
+
+type NodeIndexArgs struct {
+	Topic string `json:"topic"`
+	Index uint64 `json:"index"`
+}
+
+type TxPoolTopic struct {
+	Topic    string    `json:"topic"`
+	Count    uint64    `json:"count"`
+	OldestAt time.Time `json:"oldestAt"`
+}
+
+type TxPoolBacklogReply struct {
+	Topics []*TxPoolTopic `json:"topics"`
+}
+
+type LogLevelArgs struct {
+	Level string `json:"level"`
+}
+
+// AdminAPI controls the indexer while it runs. Pauses are kept by the process
+// only, they end with it and apply to the workers it runs.
+type AdminAPI struct {
+	log   logging.Logger
+	sc    *servicesctrl.Control
+	conf  cfg.Config
+	conns *utils.Connections
+}
+
+func NewAdminAPI(log logging.Logger, sc *servicesctrl.Control, conf cfg.Config, conns *utils.Connections) *AdminAPI {
+	return &AdminAPI{log: log, sc: sc, conf: conf, conns: conns}
+}
+
+func (service *AdminAPI) pause(kind string, chainID string, paused bool) error {
+	if chainID == "" {
+		return errMissingChainID
+	}
+	if paused {
+		service.sc.Pauses.Pause(kind, chainID)
+	} else {
+		service.sc.Pauses.Resume(kind, chainID)
+	}
+	service.log.Info("Admin: pause changed",
+		zap.String("kind", kind),
+		zap.String("chainID", chainID),
+		zap.Bool("paused", paused),
+	)
+	return nil
+}
+
+// PauseProducers stops reading the node index of the chain
+func (service *AdminAPI) PauseProducers(_ *http.Request, args *ChainArgs, reply *SuccessResponse) error {
+	if err := service.pause(utils.PauseProducers, args.ChainID, true); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ResumeProducers continues reading the node index of the chain, from the
+// node index stored for its topics
+func (service *AdminAPI) ResumeProducers(_ *http.Request, args *ChainArgs, reply *SuccessResponse) error {
+	if err := service.pause(utils.PauseProducers, args.ChainID, false); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// PauseConsumers stops indexing the containers of the chain, they are kept in
+// the tx_pool or the broker meanwhile
+func (service *AdminAPI) PauseConsumers(_ *http.Request, args *ChainArgs, reply *SuccessResponse) error {
+	if err := service.pause(utils.PauseConsumers, args.ChainID, true); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ResumeConsumers continues indexing the containers of the chain
+func (service *AdminAPI) ResumeConsumers(_ *http.Request, args *ChainArgs, reply *SuccessResponse) error {
+	if err := service.pause(utils.PauseConsumers, args.ChainID, false); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// Paused returns the chains whose producers or consumers are paused
+func (service *AdminAPI) Paused(_ *http.Request, _ *struct{}, reply *PausedReply) error {
+	reply.Producers = service.sc.Pauses.Paused(utils.PauseProducers)
+	reply.Consumers = service.sc.Pauses.Paused(utils.PauseConsumers)
+	return nil
+}
+
+// ResetNodeIndex sets the node index the producers of the topic continue from
+// once resumed, the producers of its chain have to be paused
+func (service *AdminAPI) ResetNodeIndex(r *http.Request, args *NodeIndexArgs, reply *SuccessResponse) error {
+	chainID, err := stream.TopicChainID(service.conf.NetworkID, args.Topic)
+	if err != nil {
+		return err
+	}
+	if !service.sc.Pauses.IsPaused(utils.PauseProducers, chainID) {
+		return errProducersRunning
+	}
+	sess, err := service.conns.DB().NewSession("admin_node_index", cfg.RequestTimeout)
+	if err != nil {
+		return err
+	}
+	nodeIndex := &db.NodeIndex{Instance: service.conf.NodeInstance, Topic: args.Topic}
+	previous, err := service.sc.Persist.QueryNodeIndex(r.Context(), sess, nodeIndex)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return errUnknownTopic
+	}
+	if err != nil {
+		return err
+	}
+	nodeIndex.Idx = args.Index
+	if err := service.sc.Persist.UpdateNodeIndex(r.Context(), sess, nodeIndex); err != nil {
+		return err
+	}
+	service.log.Info("Admin: node index reset",
+		zap.String("topic", args.Topic),
+		zap.Uint64("previous", previous.Idx),
+		zap.Uint64("index", args.Index),
+	)
+	reply.Success = true
+	return nil
+}
+
+// TxPoolBacklog returns the containers per topic waiting to be indexed
+func (service *AdminAPI) TxPoolBacklog(r *http.Request, _ *struct{}, reply *TxPoolBacklogReply) error {
+	sess, err := service.conns.DB().NewSession("admin_tx_pool", cfg.RequestTimeout)
+	if err != nil {
+		return err
+	}
+	backlog, err := service.sc.Persist.QueryTxPoolBacklog(r.Context(), sess)
+	if err != nil {
+		return err
+	}
+	reply.Topics = make([]*TxPoolTopic, 0, len(backlog))
+	for _, v := range backlog {
+		reply.Topics = append(reply.Topics, &TxPoolTopic{Topic: v.Topic, Count: v.Count, OldestAt: v.OldestAt})
+	}
+	return nil
+}
+
+// RunBalanceManager triggers a run of the balance manager
+func (service *AdminAPI) RunBalanceManager(_ *http.Request, _ *struct{}, reply *SuccessResponse) error {
+	if !service.sc.IsAccumulateBalanceIndexer {
+		return errBalanceManagerDisabled
+	}
+	service.sc.BalanceManager.Exec()
+	service.log.Info("Admin: balance manager triggered")
+	reply.Success = true
+	return nil
+}
+
+// FlushCaches drops the cached api responses
+func (service *AdminAPI) FlushCaches(_ *http.Request, _ *struct{}, reply *SuccessResponse) error {
+	service.sc.APICache.Flush()
+	service.log.Info("Admin: api caches flushed")
+	reply.Success = true
+	return nil
+}
+
+// SetLogLevel changes the level of the log
+func (service *AdminAPI) SetLogLevel(_ *http.Request, args *LogLevelArgs, reply *SuccessResponse) error {
+	level, err := logging.ToLevel(args.Level)
+	if err != nil {
+		return err
+	}
+	service.log.SetLevel(level)
+	service.log.Info("Admin: log level set", zap.Stringer("level", level))
+	reply.Success = true
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	handler := Authorize("secret", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for header, expected := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"secret":        http.StatusUnauthorized,
+		"Bearer":        http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"Bearer secret": http.StatusNoContent,
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/api", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal("unexpected status", header, w.Code)
+		}
+	}
+}
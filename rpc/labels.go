@@ -0,0 +1,109 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+
+package rpc
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/labels"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/utils"
+	"go.uber.org/zap"
+)
+
+type LabelAddress struct {
+	Address string `json:"address"`
+}
+
+type LabelAddresses struct {
+	Addresses []string `json:"addresses"`
+}
+
+type LabelList struct {
+	Labels []*models.AddressLabel `json:"labels"`
+}
+
+// LabelsAPI manages the address labels. The api caches its responses, a
+// changed label shows once the cached responses expire.
+type LabelsAPI struct {
+	log     logging.Logger
+	persist db.Persist
+	conns   *utils.Connections
+}
+
+func NewLabelsAPI(log logging.Logger, persist db.Persist, conns *utils.Connections) *LabelsAPI {
+	return &LabelsAPI{log: log, persist: persist, conns: conns}
+}
+
+// Set labels the address, replacing its label
+func (service *LabelsAPI) Set(r *http.Request, args *labels.Label, reply *models.AddressLabel) error {
+	v, err := args.Record(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	sess, err := service.conns.DB().NewSession("labels_set", cfg.RequestTimeout)
+	if err != nil {
+		return err
+	}
+	if err := service.persist.InsertAddressLabel(r.Context(), sess, v); err != nil {
+		return err
+	}
+	service.log.Info("Admin: label set", zap.String("address", v.Address), zap.String("name", v.Name))
+	*reply = *labels.Model(v)
+	return nil
+}
+
+// Delete removes the label of the address
+func (service *LabelsAPI) Delete(r *http.Request, args *LabelAddress, reply *SuccessResponse) error {
+	address, err := labels.StoredAddress(args.Address)
+	if err != nil {
+		return err
+	}
+	sess, err := service.conns.DB().NewSession("labels_delete", cfg.RequestTimeout)
+	if err != nil {
+		return err
+	}
+	if err := service.persist.DeleteAddressLabel(r.Context(), sess, address); err != nil {
+		return err
+	}
+	service.log.Info("Admin: label deleted", zap.String("address", address))
+	reply.Success = true
+	return nil
+}
+
+// List returns the labels of the addresses, or all labels without addresses
+func (service *LabelsAPI) List(r *http.Request, args *LabelAddresses, reply *LabelList) error {
+	addresses := make([]string, 0, len(args.Addresses))
+	for _, address := range args.Addresses {
+		stored, err := labels.StoredAddress(address)
+		if err != nil {
+			return err
+		}
+		addresses = append(addresses, stored)
+	}
+	sess, err := service.conns.DB().NewSession("labels_list", cfg.RequestTimeout)
+	if err != nil {
+		return err
+	}
+	stored, err := service.persist.QueryAddressLabels(r.Context(), sess, addresses)
+	if err != nil {
+		return err
+	}
+	reply.Labels = make([]*models.AddressLabel, 0, len(stored))
+	for _, v := range stored {
+		reply.Labels = append(reply.Labels, labels.Model(v))
+	}
+	return nil
+}
@@ -0,0 +1,2 @@
+drop table if exists `aggregate_rollups_hourly`;
+drop table if exists `aggregate_rollups_daily`;
@@ -0,0 +1,19 @@
+create table `aggregate_rollups_hourly`
+(
+    chain_id          varchar(50)     not null,
+    bucket_at         timestamp       not null,
+    transaction_count bigint unsigned not null default 0,
+    txfee             bigint unsigned not null default 0,
+    updated_at        timestamp(6)    not null default current_timestamp(6),
+    primary key (chain_id, bucket_at)
+);
+
+create table `aggregate_rollups_daily`
+(
+    chain_id          varchar(50)     not null,
+    bucket_at         timestamp       not null,
+    transaction_count bigint unsigned not null default 0,
+    txfee             bigint unsigned not null default 0,
+    updated_at        timestamp(6)    not null default current_timestamp(6),
+    primary key (chain_id, bucket_at)
+);
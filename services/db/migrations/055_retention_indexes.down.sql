@@ -0,0 +1 @@
+drop index transactions_epoch_vertex_id on transactions_epoch;
@@ -0,0 +1,4 @@
+drop index transactions_validator_start_end on transactions_validator;
+alter table `transactions_validator` drop column `removed_at`;
+alter table `transactions_validator` drop column `delegator`;
+alter table `transactions_validator` drop column `weight`;
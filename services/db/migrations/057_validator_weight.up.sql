@@ -0,0 +1,17 @@
+alter table `transactions_validator` add column `weight` bigint unsigned not null default 0;
+alter table `transactions_validator` add column `delegator` smallint unsigned not null default 0;
+alter table `transactions_validator` add column `removed_at` timestamp(6) null;
+update `transactions_validator` set `weight` = (
+    select coalesce(sum(`amount`), 0) from `avm_outputs`
+    where `avm_outputs`.`transaction_id` = `transactions_validator`.`id`
+    and (`avm_outputs`.`stake` = 1 or `avm_outputs`.`output_type` in (8193, 8194))
+);
+update `transactions_validator` set `removed_at` = (
+    select min(`avm_outputs_redeeming`.`redeemed_at`) from `avm_outputs_redeeming`
+    join `avm_transactions` on `avm_transactions`.`id` = `avm_outputs_redeeming`.`redeeming_transaction_id`
+    where `avm_outputs_redeeming`.`intx` = `transactions_validator`.`id` and `avm_transactions`.`type` = 'reward_validator'
+);
+update `transactions_validator` set `delegator` = 1 where `id` in (
+    select `id` from `avm_transactions` where `type` = 'add_permissionless_delegator'
+);
+create index transactions_validator_start_end on transactions_validator (start, `end`);
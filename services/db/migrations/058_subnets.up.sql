@@ -0,0 +1,42 @@
+create table `subnets`
+(
+    id         varchar(50)     not null primary key,
+    threshold  int unsigned    not null default 0,
+    locktime   bigint unsigned not null default 0,
+    asset_id   varchar(50)     not null default '',
+    created_at timestamp(6)    not null default current_timestamp(6)
+);
+
+create table `subnet_owners`
+(
+    subnet_id  varchar(50)  not null,
+    address    varchar(50)  not null,
+    created_at timestamp(6) not null default current_timestamp(6),
+    primary key (subnet_id, address)
+);
+create index subnet_owners_address on subnet_owners (address);
+
+create table `blockchains`
+(
+    id           varchar(50)   not null primary key,
+    subnet_id    varchar(50)   not null,
+    name         varchar(128)  not null default '',
+    vm_id        varchar(50)   not null,
+    fx_ids       varchar(1024) not null default '',
+    genesis_hash varchar(64)   not null default '',
+    created_at   timestamp(6)  not null default current_timestamp(6)
+);
+create index blockchains_subnet_id on blockchains (subnet_id);
+
+create table `subnet_validators`
+(
+    id         varchar(50)     not null primary key,
+    subnet_id  varchar(50)     not null,
+    node_id    varchar(50)     not null,
+    weight     bigint unsigned not null default 0,
+    start      bigint unsigned not null default 0,
+    `end`      bigint unsigned not null default 0,
+    removed_at timestamp(6)    null,
+    created_at timestamp(6)    not null default current_timestamp(6)
+);
+create index subnet_validators_subnet_id_node_id on subnet_validators (subnet_id, node_id);
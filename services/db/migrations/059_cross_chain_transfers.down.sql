@@ -0,0 +1 @@
+drop table if exists `cross_chain_transfers`;
@@ -0,0 +1,2 @@
+alter table `avm_outputs` add column `deposit_tx_id` varchar(50) not null default '';
+alter table `avm_outputs` add column `bond_tx_id` varchar(50) not null default '';
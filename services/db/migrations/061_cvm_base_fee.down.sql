@@ -0,0 +1 @@
+alter table `cvm_transactions_txdata` drop column `base_fee`;
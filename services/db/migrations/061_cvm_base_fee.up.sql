@@ -0,0 +1 @@
+alter table `cvm_transactions_txdata` add column `base_fee` bigint unsigned not null default 0;
@@ -0,0 +1 @@
+drop table if exists `supply_history`;
@@ -0,0 +1,13 @@
+create table `supply_history`
+(
+    asset_id   varchar(50)     not null,
+    bucket_at  timestamp       not null,
+    total      bigint unsigned not null default 0,
+    locked     bigint unsigned not null default 0,
+    deposited  bigint unsigned not null default 0,
+    bonded     bigint unsigned not null default 0,
+    burned     bigint unsigned not null default 0,
+    genesis    bigint unsigned not null default 0,
+    updated_at timestamp(6)    not null default current_timestamp(6),
+    primary key (asset_id, bucket_at)
+);
@@ -0,0 +1 @@
+alter table `supply_history` drop column `holders`;
@@ -0,0 +1 @@
+alter table `supply_history` add column `holders` bigint unsigned not null default 0;
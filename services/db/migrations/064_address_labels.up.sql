@@ -0,0 +1,10 @@
+create table `address_labels`
+(
+    address    varchar(100) not null primary key,
+    name       varchar(100) not null,
+    category   varchar(32)  not null default '',
+    notes      text         not null,
+    created_at timestamp(6) not null default current_timestamp(6),
+    updated_at timestamp(6) not null default current_timestamp(6)
+);
+create index `address_labels_name` on `address_labels` (name);
@@ -0,0 +1,37 @@
+drop table if exists aggregate_rollups_daily;
+drop table if exists aggregate_rollups_hourly;
+drop table if exists statistics;
+drop table if exists node_index;
+drop table if exists key_value_store;
+drop table if exists tx_pool;
+drop table if exists output_txs_accumulate;
+drop table if exists output_addresses_accumulate_in;
+drop table if exists output_addresses_accumulate_out;
+drop table if exists accumulate_balances_transactions;
+drop table if exists accumulate_balances_sent;
+drop table if exists accumulate_balances_received;
+drop table if exists cam_last_block_cache;
+drop table if exists cvm_transactions_txdata;
+drop table if exists cvm_accounts;
+drop table if exists cvm_addresses;
+drop table if exists cvm_transactions_atomic;
+drop table if exists cvm_blocks;
+drop table if exists reward;
+drop table if exists reward_owner;
+drop table if exists multisig_aliases;
+drop table if exists transactions_block;
+drop table if exists transactions_validator;
+drop table if exists pvm_blocks;
+drop table if exists transactions_epoch;
+drop table if exists addresses_bech32;
+drop table if exists address_chain;
+drop table if exists addresses;
+drop table if exists avm_transactions;
+drop table if exists avm_outputs_redeeming;
+drop table if exists avm_output_addresses;
+drop table if exists avm_outputs;
+drop table if exists avm_assets;
+
+drop function if exists date_format(timestamp, text);
+drop function if exists unix_timestamp(timestamp);
+drop domain if exists unsigned;
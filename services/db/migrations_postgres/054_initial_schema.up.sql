@@ -0,0 +1,435 @@
+--
+-- Postgres schema, equivalent to the mysql migrations up to and including 054.
+-- New migrations have to be added to both migration sets with the same version.
+--
+
+--
+-- Compatibility functions for the mysql syntax used by shared queries
+--
+
+create domain unsigned as numeric(20, 0);
+
+create function unix_timestamp(timestamp) returns bigint as $$
+    select extract(epoch from $1)::bigint
+$$ language sql immutable;
+
+create function date_format(timestamp, text) returns text as $$
+    select to_char($1, replace(replace(replace($2, '%Y', 'YYYY'), '%m', 'MM'), '%d', 'DD'))
+$$ language sql immutable;
+
+--
+-- X-Chain
+--
+
+create table avm_assets
+(
+    id             varchar(50)   not null primary key,
+    chain_id       varchar(50)   not null,
+    name           varchar(255)  not null,
+    symbol         varchar(255)  not null,
+    alias          varchar(255)  not null,
+    denomination   integer       not null,
+    created_at     timestamp(6)  not null default current_timestamp(6),
+    current_supply numeric(20)   not null
+);
+
+create table avm_outputs
+(
+    id             varchar(50)   not null primary key,
+    chain_id       varchar(50)   not null,
+    transaction_id varchar(50)   not null,
+    output_index   integer       not null,
+    asset_id       varchar(50)   not null,
+    output_type    bigint        not null,
+    amount         numeric(20)   not null,
+    locktime       numeric(20)   not null,
+    threshold      bigint        not null,
+    group_id       numeric(20)   default 0,
+    payload        bytea,
+    stake_locktime numeric(20)   not null default 0,
+    stake          boolean       default false,
+    frozen         boolean       default false,
+    stakeableout   boolean       default false,
+    genesisutxo    boolean       default false,
+    created_at     timestamp(6)  not null default current_timestamp(6)
+);
+create index avm_outputs_chain_id_id on avm_outputs (chain_id, id);
+create unique index avm_outputs_tx_id_output_idx on avm_outputs (transaction_id, output_index);
+create index avm_outputs_asset_id_output_type on avm_outputs (asset_id, output_type);
+create index avm_outputs_chain_id_created_at on avm_outputs (chain_id, created_at desc);
+create index avm_outputs_chain_id_asset_id_created_at on avm_outputs (chain_id, asset_id, created_at desc);
+create index avm_outputs_transaction_id_asset_id_output_type on avm_outputs (transaction_id, asset_id, output_type);
+
+create table avm_output_addresses
+(
+    output_id           varchar(50)  not null,
+    address             varchar(50)  not null,
+    redeeming_signature bytea        null,
+    created_at          timestamp(6) not null default current_timestamp(6),
+    updated_at          timestamp(6) not null default current_timestamp(6)
+);
+create index avm_output_addresses_output_id on avm_output_addresses (output_id);
+create unique index avm_output_addresses_output_id_addr on avm_output_addresses (output_id, address);
+create index avm_output_addresses_address on avm_output_addresses (address);
+create index avm_output_addresses_created_at on avm_output_addresses (created_at);
+
+create table avm_outputs_redeeming
+(
+    id                       varchar(50)  not null primary key,
+    redeemed_at              timestamp(6) not null default current_timestamp(6),
+    redeeming_transaction_id varchar(50)  not null,
+    amount                   numeric(20)  not null,
+    output_index             bigint       not null,
+    intx                     varchar(50)  default null,
+    asset_id                 varchar(50)  default null,
+    chain_id                 varchar(50)  default '',
+    created_at               timestamp(6) not null default current_timestamp(6)
+);
+create index avm_outputs_redeeming_redeeming_transaction_id on avm_outputs_redeeming (redeeming_transaction_id);
+
+create table avm_transactions
+(
+    id                      varchar(50)  not null primary key,
+    chain_id                varchar(50)  not null,
+    type                    varchar(255) not null,
+    canonical_serialization bytea,
+    memo                    bytea,
+    txfee                   numeric(20)  default 0,
+    genesis                 boolean      default false,
+    network_id              bigint       default 0,
+    status                  smallint     not null default 0,
+    created_at              timestamp(6) not null default current_timestamp(6)
+);
+create index avm_transactions_chain_id_created_at on avm_transactions (chain_id, created_at desc);
+create index avm_transactions_created_at_chain_id_idx on avm_transactions (created_at, chain_id);
+
+create table addresses
+(
+    address    varchar(50)  not null primary key,
+    public_key bytea        null,
+    created_at timestamp(6) not null default current_timestamp(6),
+    updated_at timestamp(6) not null default current_timestamp(6)
+);
+
+create table address_chain
+(
+    address    varchar(50)  not null,
+    chain_id   varchar(50)  not null,
+    created_at timestamp(6) not null default current_timestamp(6),
+    updated_at timestamp(6) not null default current_timestamp(6),
+    primary key (address, chain_id)
+);
+
+create table addresses_bech32
+(
+    address        varchar(50)  not null primary key,
+    bech32_address varchar(100),
+    updated_at     timestamp(6) not null default current_timestamp(6)
+);
+create index addresses_bech32_bech32_address on addresses_bech32 (bech32_address);
+
+create table transactions_epoch
+(
+    id         varchar(50)  not null primary key,
+    epoch      numeric(20)  not null default 0,
+    vertex_id  varchar(50)  default null,
+    created_at timestamp(6) not null default current_timestamp(6)
+);
+
+--
+-- P-Chain
+--
+
+create table pvm_blocks
+(
+    id            varchar(50)  not null primary key,
+    type          smallint     not null,
+    parent_id     varchar(50)  not null,
+    chain_id      varchar(50)  not null,
+    serialization bytea        not null,
+    height        numeric(20)  not null default 0,
+    proposer      varchar(50),
+    proposer_time timestamp(6) null,
+    created_at    timestamp(6) not null default current_timestamp(6)
+);
+create index pvm_blocks_parent_id on pvm_blocks (parent_id);
+create index pvm_blocks_height on pvm_blocks (height);
+
+create table transactions_validator
+(
+    id         varchar(50)  not null primary key,
+    node_id    varchar(50)  default '',
+    start      numeric(20)  default 0,
+    "end"      numeric(20)  default 0,
+    created_at timestamp(6) not null default current_timestamp(6)
+);
+
+create table transactions_block
+(
+    id          varchar(50)  not null primary key,
+    tx_block_id varchar(50)  default '',
+    created_at  timestamp(6) not null default current_timestamp(6)
+);
+
+create table multisig_aliases
+(
+    alias          varchar(35)  not null,
+    memo           varchar(256) not null,
+    owner          varchar(35)  not null,
+    transaction_id varchar(56)  not null,
+    created_at     timestamp    not null default current_timestamp,
+    primary key (alias, owner)
+);
+create index multisig_aliases_alias on multisig_aliases (alias);
+create index multisig_aliases_owner on multisig_aliases (owner);
+
+create table reward_owner
+(
+    address    varchar(50) not null,
+    hash       varchar(50) not null,
+    created_at timestamp   not null default current_timestamp,
+    primary key (address, hash)
+);
+create index reward_owner_address on reward_owner (address);
+create index reward_owner_hash on reward_owner (hash);
+
+create table reward
+(
+    reward_owner_bytes bytea,
+    reward_owner_hash  varchar(50) not null,
+    tx_id              varchar(50) not null,
+    type               smallint    not null default 0,
+    updated_at         timestamp   not null default current_timestamp,
+    primary key (reward_owner_hash, tx_id, type)
+);
+create index reward_reward_owner_hash on reward (reward_owner_hash);
+
+--
+-- C-Chain
+--
+
+create table cvm_blocks
+(
+    block         numeric(65)  not null primary key,
+    hash          varchar(100) not null,
+    chain_id      varchar(50)  not null,
+    evm_tx        smallint     not null default 0,
+    atomic_tx     smallint     not null default 0,
+    serialization bytea,
+    proposer      varchar(50),
+    proposer_time timestamp(6) null,
+    size          real         default 0,
+    created_at    timestamp(6) not null default current_timestamp(6)
+);
+create index cvm_blocks_hash on cvm_blocks (hash);
+create index cvm_blocks_chain_id on cvm_blocks (chain_id);
+create index cvm_blocks_created_at on cvm_blocks (created_at);
+
+create table cvm_transactions_atomic
+(
+    transaction_id varchar(50)  not null primary key,
+    block          numeric(65)  not null,
+    chain_id       varchar(50)  not null,
+    type           smallint     not null,
+    created_at     timestamp(6) not null default current_timestamp(6)
+);
+create index cvm_transactions_atomic_block on cvm_transactions_atomic (block);
+
+create table cvm_addresses
+(
+    id             varchar(50)  not null primary key,
+    type           smallint     not null,
+    idx            bigint       not null,
+    transaction_id varchar(50)  not null,
+    address        varchar(50)  not null,
+    asset_id       varchar(50)  not null,
+    amount         numeric(20)  not null default 0,
+    nonce          numeric(20)  not null default 0,
+    created_at     timestamp(6) not null default current_timestamp(6)
+);
+create index cvm_address_transaction_id on cvm_addresses (transaction_id);
+
+create table cvm_accounts
+(
+    id          bigserial   not null primary key,
+    address     char(42),
+    tx_count    numeric(20) not null default 0,
+    creation_tx char(66)
+);
+create unique index cvm_accounts_address on cvm_accounts (address);
+
+create table cvm_transactions_txdata
+(
+    hash          varchar(100) not null primary key,
+    block         numeric(20)  not null,
+    idx           integer      not null,
+    id_from_addr  bigint       not null default 0,
+    id_to_addr    bigint       not null default 0,
+    nonce         numeric(20)  not null,
+    amount        numeric(20)  not null default 0,
+    status        integer      not null default 0,
+    gas_price     numeric(20)  not null default 0,
+    gas_used      bigint       not null default 0,
+    serialization bytea,
+    receipt       bytea,
+    block_idx     numeric(24) generated always as (block * 1000 + (999 - idx)) stored,
+    created_at    timestamp(6) not null default current_timestamp(6)
+);
+create unique index cvm_transactions_txdata_block_idx on cvm_transactions_txdata (block_idx);
+create index cvm_transactions_txdata_created_at on cvm_transactions_txdata (created_at);
+create index ctx_id_from_addr on cvm_transactions_txdata (id_from_addr);
+create index ctx_id_to_addr on cvm_transactions_txdata (id_to_addr);
+
+create table cam_last_block_cache
+(
+    current_block numeric(65) not null,
+    chainid       varchar(50) not null
+);
+
+--
+-- Balances
+--
+
+create table accumulate_balances_received
+(
+    id           varchar(50)  not null primary key,
+    chain_id     varchar(50)  not null,
+    asset_id     varchar(50)  not null,
+    address      varchar(50)  not null,
+    total_amount numeric(65)  not null default 0,
+    utxo_count   numeric(65)  not null default 0,
+    updated_at   timestamp(6) not null default current_timestamp(6)
+);
+create index accumulate_balances_received_address_chain_id on accumulate_balances_received (address, chain_id);
+create index accumulate_balances_received_chain_id on accumulate_balances_received (chain_id);
+
+create table accumulate_balances_sent
+(
+    id           varchar(50)  not null primary key,
+    chain_id     varchar(50)  not null,
+    asset_id     varchar(50)  not null,
+    address      varchar(50)  not null,
+    total_amount numeric(65)  not null default 0,
+    utxo_count   numeric(65)  not null default 0,
+    updated_at   timestamp(6) not null default current_timestamp(6)
+);
+
+create table accumulate_balances_transactions
+(
+    id                varchar(50)  not null primary key,
+    chain_id          varchar(50)  not null,
+    asset_id          varchar(50)  not null,
+    address           varchar(50)  not null,
+    transaction_count numeric(65)  not null default 0,
+    updated_at        timestamp(6) not null default current_timestamp(6)
+);
+
+create table output_addresses_accumulate_out
+(
+    id             varchar(50)  not null primary key,
+    output_id      varchar(50)  not null,
+    address        varchar(50)  not null,
+    processed      integer      not null default 0,
+    transaction_id varchar(50)  not null default '',
+    output_index   integer      not null default 0,
+    created_at     timestamp(6) not null default current_timestamp(6)
+);
+create index output_addresses_accumulate_out_processed on output_addresses_accumulate_out (processed asc, created_at asc);
+create index output_addresses_accumulate_out_output_id on output_addresses_accumulate_out (output_id asc);
+
+create table output_addresses_accumulate_in
+(
+    id               varchar(50)  not null primary key,
+    output_id        varchar(50)  not null,
+    address          varchar(50)  not null,
+    processed        integer      not null default 0,
+    output_processed integer      not null default 0,
+    transaction_id   varchar(50)  not null default '',
+    output_index     integer      not null default 0,
+    created_at       timestamp(6) not null default current_timestamp(6)
+);
+create index output_addresses_accumulate_in_processed on output_addresses_accumulate_in (processed asc, created_at asc);
+create index output_addresses_accumulate_in_output_processed on output_addresses_accumulate_in (output_processed asc, processed asc, created_at asc);
+create index output_addresses_accumulate_in_output_id on output_addresses_accumulate_in (output_id asc);
+
+create table output_txs_accumulate
+(
+    id             varchar(50)  not null primary key,
+    chain_id       varchar(50)  not null,
+    asset_id       varchar(50)  not null,
+    address        varchar(50)  not null,
+    transaction_id varchar(50)  not null,
+    processed      integer      not null default 0,
+    created_at     timestamp(6) not null default current_timestamp(6)
+);
+create index output_txs_accumulate_processed on output_txs_accumulate (processed asc, created_at asc);
+
+--
+-- Infrastructure
+--
+
+create table tx_pool
+(
+    id            varchar(50)  not null primary key,
+    network_id    bigint       default 0,
+    chain_id      varchar(50)  not null,
+    msg_key       varchar(50)  not null,
+    serialization bytea,
+    topic         varchar(100) not null,
+    created_at    timestamp(6) not null default current_timestamp(6)
+);
+create index tx_pool_created_at on tx_pool (created_at asc);
+create index tx_pool_topic on tx_pool (topic);
+
+create table key_value_store
+(
+    k varchar(256)  not null primary key,
+    v varchar(1000) not null
+);
+
+create table node_index
+(
+    instance varchar(100) not null,
+    topic    varchar(100) not null,
+    idx      numeric(20)  default 0,
+    primary key (instance, topic)
+);
+
+--
+-- Statistics and aggregates
+--
+
+create table statistics
+(
+    date_at         date    not null primary key,
+    cvm_tx          integer default null,
+    avm_tx          integer default null,
+    token_transfer  real    default null,
+    gas_used        real    default null,
+    receive_count   integer default null,
+    send_count      integer default null,
+    active_accounts integer default null,
+    gas_price       real    default null,
+    blocks          integer default null,
+    avg_block_size  real    default null
+);
+
+create table aggregate_rollups_hourly
+(
+    chain_id          varchar(50)  not null,
+    bucket_at         timestamp    not null,
+    transaction_count numeric(20)  not null default 0,
+    txfee             numeric(20)  not null default 0,
+    updated_at        timestamp(6) not null default current_timestamp(6),
+    primary key (chain_id, bucket_at)
+);
+
+create table aggregate_rollups_daily
+(
+    chain_id          varchar(50)  not null,
+    bucket_at         timestamp    not null,
+    transaction_count numeric(20)  not null default 0,
+    txfee             numeric(20)  not null default 0,
+    updated_at        timestamp(6) not null default current_timestamp(6),
+    primary key (chain_id, bucket_at)
+);
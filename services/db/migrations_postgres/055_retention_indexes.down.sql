@@ -0,0 +1 @@
+drop index if exists transactions_epoch_vertex_id;
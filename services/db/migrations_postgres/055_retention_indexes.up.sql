@@ -0,0 +1 @@
+create index transactions_epoch_vertex_id on transactions_epoch (vertex_id);
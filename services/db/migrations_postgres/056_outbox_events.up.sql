@@ -0,0 +1,10 @@
+create table outbox_events
+(
+    id         varchar(50)  not null primary key,
+    chain_id   varchar(50)  not null,
+    tx_id      varchar(100) not null,
+    type       varchar(50)  not null,
+    payload    bytea,
+    created_at timestamp(6) not null default current_timestamp(6)
+);
+create index outbox_events_created_at on outbox_events (created_at asc);
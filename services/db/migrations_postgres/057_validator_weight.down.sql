@@ -0,0 +1,4 @@
+drop index if exists transactions_validator_start_end;
+alter table transactions_validator drop column removed_at;
+alter table transactions_validator drop column delegator;
+alter table transactions_validator drop column weight;
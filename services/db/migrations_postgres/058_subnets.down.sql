@@ -0,0 +1,4 @@
+drop table if exists subnet_validators;
+drop table if exists blockchains;
+drop table if exists subnet_owners;
+drop table if exists subnets;
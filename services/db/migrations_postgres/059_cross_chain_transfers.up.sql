@@ -0,0 +1,40 @@
+create table cross_chain_transfers
+(
+    id                   varchar(50)  not null primary key,
+    export_tx_id         varchar(50)  not null default '',
+    import_tx_id         varchar(50)  not null default '',
+    source_chain_id      varchar(50)  not null,
+    destination_chain_id varchar(50)  not null,
+    asset_id             varchar(50)  not null,
+    amount               numeric(20)  not null default 0,
+    exported_at          timestamp(6) null,
+    imported_at          timestamp(6) null,
+    created_at           timestamp(6) not null default current_timestamp(6)
+);
+create index cross_chain_transfers_export_tx_id on cross_chain_transfers (export_tx_id);
+create index cross_chain_transfers_import_tx_id on cross_chain_transfers (import_tx_id);
+create index cross_chain_transfers_exported_at on cross_chain_transfers (exported_at);
+
+insert into cross_chain_transfers
+    (id, export_tx_id, source_chain_id, destination_chain_id, asset_id, amount, exported_at, created_at)
+select avm_outputs.id,
+       avm_outputs.transaction_id,
+       avm_transactions.chain_id,
+       avm_outputs.chain_id,
+       avm_outputs.asset_id,
+       avm_outputs.amount,
+       avm_transactions.created_at,
+       avm_outputs.created_at
+from avm_outputs
+         join avm_transactions on avm_transactions.id = avm_outputs.transaction_id
+where avm_outputs.chain_id <> avm_transactions.chain_id
+on conflict do nothing;
+
+update cross_chain_transfers
+set import_tx_id = avm_outputs_redeeming.redeeming_transaction_id,
+    imported_at  = avm_outputs_redeeming.redeemed_at
+from avm_outputs_redeeming,
+     avm_transactions
+where avm_outputs_redeeming.id = cross_chain_transfers.id
+  and avm_transactions.id = avm_outputs_redeeming.redeeming_transaction_id
+  and avm_outputs_redeeming.chain_id <> avm_transactions.chain_id;
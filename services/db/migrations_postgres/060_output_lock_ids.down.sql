@@ -0,0 +1,2 @@
+alter table avm_outputs drop column bond_tx_id;
+alter table avm_outputs drop column deposit_tx_id;
@@ -0,0 +1 @@
+alter table cvm_transactions_txdata add column base_fee numeric(20) not null default 0;
@@ -0,0 +1,13 @@
+create table supply_history
+(
+    asset_id   varchar(50)  not null,
+    bucket_at  timestamp    not null,
+    total      numeric(20)  not null default 0,
+    locked     numeric(20)  not null default 0,
+    deposited  numeric(20)  not null default 0,
+    bonded     numeric(20)  not null default 0,
+    burned     numeric(20)  not null default 0,
+    genesis    numeric(20)  not null default 0,
+    updated_at timestamp(6) not null default current_timestamp(6),
+    primary key (asset_id, bucket_at)
+);
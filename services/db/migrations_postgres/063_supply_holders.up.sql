@@ -0,0 +1 @@
+alter table supply_history add column holders numeric(20) not null default 0;
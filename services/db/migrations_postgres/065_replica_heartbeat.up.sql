@@ -0,0 +1,5 @@
+create table replica_heartbeat
+(
+    id         smallint     not null primary key,
+    updated_at timestamp(6) not null default current_timestamp(6)
+);
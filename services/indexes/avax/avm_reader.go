@@ -31,7 +31,7 @@ func (r *Reader) ListAssets(ctx context.Context, p *params.ListAssetsParams, con
 	if conns != nil {
 		dbRunner = conns.DB().NewSessionForEventReceiver(conns.Stream().NewJob("list_assets"))
 	} else {
-		dbRunner, err = r.conns.DB().NewSession("list_assets", cfg.RequestTimeout)
+		dbRunner, err = r.db(ctx).NewSession("list_assets", cfg.RequestTimeout)
 		if err != nil {
 			return nil, err
 		}
@@ -78,7 +78,12 @@ func (r *Reader) GetAsset(ctx context.Context, p *params.ListAssetsParams, idStr
 	}
 	p.ListParams.DisableCounting = true
 
-	assetList, err := r.ListAssets(ctx, p, nil)
+	var assetList *models.AssetList
+	err = r.lookup(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		assetList, err = r.ListAssets(ctx, p, nil)
+		return err == nil && len(assetList.Assets) > 0, err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,108 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/platformvm/locked"
+	"github.com/ava-labs/avalanchego/vms/platformvm/stakeable"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services"
+)
+
+// InsertTransactionIndexedEvent writes the event to the outbox within the
+// session of the consumer, so it is only visible once the transaction is
+// committed. It does nothing if the outbox isn't enabled.
+func (w *Writer) InsertTransactionIndexedEvent(ctx services.ConsumerCtx, event *models.TransactionIndexedEvent) error {
+	if !w.outbox {
+		return nil
+	}
+
+	event.Type = models.EventTypeTransactionIndexed
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	outboxEvent := &db.OutboxEvent{
+		ChainID:   event.ChainID,
+		TxID:      event.TxID,
+		Type:      event.Type,
+		Payload:   payload,
+		CreatedAt: ctx.Time(),
+	}
+	outboxEvent.ComputeID()
+	return ctx.Persist().InsertOutboxEvent(ctx.Ctx(), ctx.DB(), outboxEvent)
+}
+
+// EventInputs returns the amounts of the inputs for a TransactionIndexedEvent
+func EventInputs(ins []*avax.TransferableInput) []*models.EventAmount {
+	amounts := make([]*models.EventAmount, 0, len(ins))
+	for _, in := range ins {
+		amounts = append(amounts, &models.EventAmount{
+			AssetID: in.AssetID().String(),
+			Amount:  strconv.FormatUint(in.Input().Amount(), 10),
+		})
+	}
+	return amounts
+}
+
+// EventOutputs returns the owners and amounts of the outputs for a
+// TransactionIndexedEvent
+func EventOutputs(outs []*avax.TransferableOutput) []*models.EventAmount {
+	amounts := make([]*models.EventAmount, 0, len(outs))
+	for _, out := range outs {
+		amount := &models.EventAmount{
+			AssetID:   out.AssetID().String(),
+			Amount:    strconv.FormatUint(out.Output().Amount(), 10),
+			Addresses: outputAddresses(out.Out),
+		}
+		amounts = append(amounts, amount)
+	}
+	return amounts
+}
+
+// outputAddresses returns the owners of an output, looking through the lock
+// wrappers of the platform chain
+func outputAddresses(out verify.State) []models.Address {
+	for {
+		switch typedOut := out.(type) {
+		case *stakeable.LockOut:
+			out = typedOut.TransferableOut
+			continue
+		case *locked.Out:
+			out = typedOut.TransferableOut
+			continue
+		}
+		break
+	}
+
+	owned, ok := out.(interface{ Addresses() [][]byte })
+	if !ok {
+		return nil
+	}
+	var addresses []models.Address
+	for _, addr := range owned.Addresses() {
+		addrBytes := [20]byte{}
+		copy(addrBytes[:], addr)
+		addresses = append(addresses, models.ToAddress(ids.ShortID(addrBytes)))
+	}
+	return addresses
+}
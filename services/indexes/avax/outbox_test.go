@@ -0,0 +1,53 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/stakeable"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/chain4travel/magellan/models"
+)
+
+func TestEventOutputsAddresses(t *testing.T) {
+	owner := ids.ShortID{1}
+	amounts := EventOutputs([]*avax.TransferableOutput{{
+		Asset: avax.Asset{ID: ids.ID{2}},
+		Out: &stakeable.LockOut{
+			Locktime: 1,
+			TransferableOut: &secp256k1fx.TransferOutput{
+				Amt:          10,
+				OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{owner}},
+			},
+		},
+	}})
+
+	b, err := json.Marshal(amounts)
+	if err != nil {
+		t.Fatal("marshal fail", err)
+	}
+	bech32, err := address.FormatBech32(models.Bech32HRP, owner.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"addresses":["` + bech32 + `"],"assetID":"` + ids.ID{2}.String() + `","amount":"10"}]`
+	if string(b) != expected {
+		t.Fatal("unexpected event outputs", string(b))
+	}
+}
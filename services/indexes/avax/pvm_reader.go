@@ -15,15 +15,20 @@ package avax
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
 	"github.com/chain4travel/magellan/models"
 	"github.com/chain4travel/magellan/services/indexes/params"
 )
 
 func (r *Reader) ListBlocks(ctx context.Context, params *params.ListBlocksParams) (*models.BlockList, error) {
-	dbRunner, err := r.conns.DB().NewSession("list_blocks", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("list_blocks", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -42,9 +47,245 @@ func (r *Reader) ListBlocks(ctx context.Context, params *params.ListBlocksParams
 }
 
 func (r *Reader) GetBlock(ctx context.Context, id ids.ID) (*models.Block, error) {
-	list, err := r.ListBlocks(ctx, &params.ListBlocksParams{ListParams: params.ListParams{ID: &id}})
+	var list *models.BlockList
+	err := r.lookup(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		list, err = r.ListBlocks(ctx, &params.ListBlocksParams{ListParams: params.ListParams{ID: &id}})
+		return err == nil && len(list.Blocks) > 0, err
+	})
 	if err != nil || len(list.Blocks) == 0 {
 		return nil, err
 	}
 	return list.Blocks[0], nil
 }
+
+// ValidatorsAt reconstructs the primary network validator set at the time or
+// P-chain height of p. A staker is active while the time is within its
+// staking period, unless a reward transaction removed it before.
+func (r *Reader) ValidatorsAt(ctx context.Context, p *params.ValidatorsAtParams) (*models.ValidatorSet, error) {
+	dbRunner, err := r.db(ctx).NewSession("validators_at", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &models.ValidatorSet{Time: p.Time, Validators: []*models.ValidatorStake{}}
+	if set.Time.IsZero() {
+		set.Time = time.Now().UTC()
+	}
+	if p.Height != nil {
+		var blocks []time.Time
+		_, err = dbRunner.
+			Select("created_at").
+			From(db.TablePvmBlocks).
+			Where("height = ?", *p.Height).
+			Limit(1).
+			LoadContext(ctx, &blocks)
+		if err != nil {
+			return nil, err
+		}
+		if len(blocks) == 0 {
+			return nil, fmt.Errorf("no P-chain block at height %d", *p.Height)
+		}
+		set.Time, set.Height = blocks[0].UTC(), p.Height
+	}
+	unix := set.Time.Unix()
+
+	end := dbRunner.Dialect.QuoteIdent("end")
+	builder := dbRunner.
+		Select("id", "node_id", "start", end, "weight", "delegator").
+		From(db.TableTransactionsValidator).
+		Where("start <= ? AND "+end+" > ?", unix, unix).
+		Where("created_at <= ?", set.Time).
+		Where("(removed_at IS NULL OR removed_at > ?)", set.Time)
+	if len(p.NodeIDs) != 0 {
+		builder.Where("node_id IN ?", p.NodeIDs)
+	}
+	var stakers []*db.TransactionsValidator
+	if _, err = builder.LoadContext(ctx, &stakers); err != nil {
+		return nil, err
+	}
+
+	type nodeStake struct {
+		validator  *db.TransactionsValidator
+		delegated  uint64
+		delegators uint64
+	}
+	nodes := make(map[string]*nodeStake)
+	for _, staker := range stakers {
+		node, ok := nodes[staker.NodeID]
+		if !ok {
+			node = &nodeStake{}
+			nodes[staker.NodeID] = node
+		}
+		switch {
+		case staker.Delegator:
+			node.delegated += staker.Weight
+			node.delegators++
+		case node.validator == nil || staker.Start > node.validator.Start:
+			node.validator = staker
+		}
+	}
+
+	var totalStake uint64
+	for nodeID, node := range nodes {
+		// delegations are only active while their validator is
+		if node.validator == nil {
+			continue
+		}
+		total := node.validator.Weight + node.delegated
+		totalStake += total
+		set.Validators = append(set.Validators, &models.ValidatorStake{
+			NodeID:          nodeID,
+			TxID:            models.StringID(node.validator.ID),
+			StartTime:       time.Unix(int64(node.validator.Start), 0).UTC(),
+			EndTime:         time.Unix(int64(node.validator.End), 0).UTC(),
+			Weight:          models.TokenAmountForUint64(node.validator.Weight),
+			DelegatedWeight: models.TokenAmountForUint64(node.delegated),
+			Delegators:      node.delegators,
+			TotalWeight:     models.TokenAmountForUint64(total),
+		})
+	}
+	sort.Slice(set.Validators, func(i, j int) bool {
+		wi, wj := nodes[set.Validators[i].NodeID], nodes[set.Validators[j].NodeID]
+		ti, tj := wi.validator.Weight+wi.delegated, wj.validator.Weight+wj.delegated
+		if ti != tj {
+			return ti > tj
+		}
+		return set.Validators[i].NodeID < set.Validators[j].NodeID
+	})
+	set.TotalStake = models.TokenAmountForUint64(totalStake)
+	return set, nil
+}
+
+// ListSubnets returns the subnets with their owners, chains and the history of
+// their validators
+func (r *Reader) ListSubnets(ctx context.Context, p *params.ListSubnetsParams) (*models.SubnetList, error) {
+	dbRunner, err := r.db(ctx).NewSession("list_subnets", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*db.Subnet
+	_, err = p.Apply(dbRunner.
+		Select("id", "threshold", "locktime", "asset_id", "created_at").
+		From(db.TableSubnets).
+		OrderAsc("created_at").
+		OrderAsc("id")).
+		LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.SubnetList{Subnets: make([]*models.Subnet, 0, len(rows))}
+	if len(rows) == 0 {
+		return list, nil
+	}
+	subnets := make(map[string]*models.Subnet, len(rows))
+	subnetIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		subnet := &models.Subnet{
+			ID:          models.StringID(row.ID),
+			Owners:      []models.Address{},
+			Threshold:   row.Threshold,
+			Locktime:    row.Locktime,
+			AssetID:     models.StringID(row.AssetID),
+			Blockchains: []models.StringID{},
+			Validators:  []*models.SubnetValidator{},
+			CreatedAt:   row.CreatedAt,
+		}
+		subnets[row.ID] = subnet
+		subnetIDs = append(subnetIDs, row.ID)
+		list.Subnets = append(list.Subnets, subnet)
+	}
+
+	var owners []*db.SubnetOwner
+	_, err = dbRunner.
+		Select("subnet_id", "address").
+		From(db.TableSubnetOwners).
+		Where("subnet_id IN ?", subnetIDs).
+		OrderAsc("address").
+		LoadContext(ctx, &owners)
+	if err != nil {
+		return nil, err
+	}
+	for _, owner := range owners {
+		subnet := subnets[owner.SubnetID]
+		subnet.Owners = append(subnet.Owners, models.Address(owner.Address))
+	}
+
+	var chains []*db.Blockchain
+	_, err = dbRunner.
+		Select("id", "subnet_id").
+		From(db.TableBlockchains).
+		Where("subnet_id IN ?", subnetIDs).
+		OrderAsc("created_at").
+		LoadContext(ctx, &chains)
+	if err != nil {
+		return nil, err
+	}
+	for _, chain := range chains {
+		subnet := subnets[chain.SubnetID]
+		subnet.Blockchains = append(subnet.Blockchains, models.StringID(chain.ID))
+	}
+
+	var validators []*db.SubnetValidator
+	_, err = dbRunner.
+		Select("id", "subnet_id", "node_id", "weight", "start", dbRunner.Dialect.QuoteIdent("end"), "removed_at").
+		From(db.TableSubnetValidators).
+		Where("subnet_id IN ?", subnetIDs).
+		OrderAsc("start").
+		LoadContext(ctx, &validators)
+	if err != nil {
+		return nil, err
+	}
+	for _, validator := range validators {
+		subnet := subnets[validator.SubnetID]
+		subnet.Validators = append(subnet.Validators, &models.SubnetValidator{
+			TxID:      models.StringID(validator.ID),
+			NodeID:    validator.NodeID,
+			Weight:    models.TokenAmountForUint64(validator.Weight),
+			StartTime: time.Unix(int64(validator.Start), 0).UTC(),
+			EndTime:   time.Unix(int64(validator.End), 0).UTC(),
+			RemovedAt: validator.RemovedAt,
+		})
+	}
+	return list, nil
+}
+
+func (r *Reader) ListBlockchains(ctx context.Context, p *params.ListBlockchainsParams) (*models.BlockchainList, error) {
+	dbRunner, err := r.db(ctx).NewSession("list_blockchains", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*db.Blockchain
+	_, err = p.Apply(dbRunner.
+		Select("id", "subnet_id", "name", "vm_id", "fx_ids", "genesis_hash", "created_at").
+		From(db.TableBlockchains).
+		OrderAsc("created_at").
+		OrderAsc("id")).
+		LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.BlockchainList{Blockchains: make([]*models.Blockchain, 0, len(rows))}
+	for _, row := range rows {
+		fxIDs := []models.StringID{}
+		if row.FxIDs != "" {
+			for _, fxID := range strings.Split(row.FxIDs, ",") {
+				fxIDs = append(fxIDs, models.StringID(fxID))
+			}
+		}
+		list.Blockchains = append(list.Blockchains, &models.Blockchain{
+			ID:          models.StringID(row.ID),
+			SubnetID:    models.StringID(row.SubnetID),
+			Name:        row.Name,
+			VMID:        models.StringID(row.VMID),
+			FxIDs:       fxIDs,
+			GenesisHash: row.GenesisHash,
+			CreatedAt:   row.CreatedAt,
+		})
+	}
+	return list, nil
+}
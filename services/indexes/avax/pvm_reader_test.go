@@ -0,0 +1,143 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+func TestValidatorsAt(t *testing.T) {
+	conns, sess := sqlitetest.New(t)
+
+	ctx := context.Background()
+	persist := db.NewPersist()
+	base := time.Unix(1000, 0).UTC()
+	at := func(s int64) time.Time { return base.Add(time.Duration(s) * time.Second) }
+
+	// node1 validates from 1000 to 2000 with a delegation from 1100 to 1500,
+	// node2 from 1000 to 3000 until a reward transaction removes it at 1800
+	stakers := []*db.TransactionsValidator{
+		{ID: "v1", NodeID: "node1", Start: 1000, End: 2000, Weight: 100, CreatedAt: at(-10)},
+		{ID: "d1", NodeID: "node1", Start: 1100, End: 1500, Weight: 30, Delegator: true, CreatedAt: at(50)},
+		{ID: "v2", NodeID: "node2", Start: 1000, End: 3000, Weight: 200, CreatedAt: at(-10)},
+	}
+	for _, staker := range stakers {
+		if err := persist.InsertTransactionsValidator(ctx, sess, staker, false); err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+	removedAt := at(800)
+	if err := persist.UpdateTransactionsValidatorRemoved(ctx, sess, &db.TransactionsValidator{ID: "v2", RemovedAt: &removedAt}); err != nil {
+		t.Fatal("update fail", err)
+	}
+	err := persist.InsertPvmBlocks(ctx, sess, &db.PvmBlocks{
+		ID:            "blk",
+		ChainID:       "pchain",
+		ParentID:      "parent",
+		Serialization: []byte{},
+		Height:        7,
+		CreatedAt:     at(200),
+	}, false)
+	if err != nil {
+		t.Fatal("insert fail", err)
+	}
+
+	reader := &Reader{conns: conns}
+	height, unknownHeight := uint64(7), uint64(8)
+	tests := []struct {
+		name       string
+		p          *params.ValidatorsAtParams
+		totalStake models.TokenAmount
+		weights    []models.TokenAmount
+	}{
+		{"before", &params.ValidatorsAtParams{Time: at(-1)}, "0", nil},
+		{"delegated", &params.ValidatorsAtParams{Time: at(200)}, "330", []models.TokenAmount{"200", "130"}},
+		{"height", &params.ValidatorsAtParams{Height: &height}, "330", []models.TokenAmount{"200", "130"}},
+		{"removed", &params.ValidatorsAtParams{Time: at(900)}, "100", []models.TokenAmount{"100"}},
+		{"node", &params.ValidatorsAtParams{Time: at(200), NodeIDs: []string{"node1"}}, "130", []models.TokenAmount{"130"}},
+	}
+
+	for _, test := range tests {
+		set, err := reader.ValidatorsAt(ctx, test.p)
+		if err != nil {
+			t.Fatal(test.name, "read fail", err)
+		}
+		if set.TotalStake != test.totalStake || len(set.Validators) != len(test.weights) {
+			t.Fatal(test.name, "unexpected set", set.TotalStake, len(set.Validators))
+		}
+		for i, weight := range test.weights {
+			if set.Validators[i].TotalWeight != weight {
+				t.Fatal(test.name, "unexpected weight", i, set.Validators[i].TotalWeight)
+			}
+		}
+	}
+
+	if _, err := reader.ValidatorsAt(ctx, &params.ValidatorsAtParams{Height: &unknownHeight}); err == nil {
+		t.Fatal("expected an error for an unknown height")
+	}
+}
+
+func TestListSubnets(t *testing.T) {
+	conns, sess := sqlitetest.New(t)
+
+	ctx := context.Background()
+	persist := db.NewPersist()
+	tm := time.Unix(1000, 0).UTC()
+	owner := ids.ShortID{1}.String()
+	for _, err := range []error{
+		persist.InsertSubnet(ctx, sess, &db.Subnet{ID: "subnet1", Threshold: 1, CreatedAt: tm}, false),
+		persist.InsertSubnetOwner(ctx, sess, &db.SubnetOwner{SubnetID: "subnet1", Address: owner, CreatedAt: tm}),
+		persist.InsertBlockchain(ctx, sess, &db.Blockchain{ID: "chain1", SubnetID: "subnet1", Name: "chain", VMID: "vm", FxIDs: "fx1,fx2", CreatedAt: tm}, false),
+		persist.InsertBlockchain(ctx, sess, &db.Blockchain{ID: "xchain", SubnetID: "primary", VMID: "avm", CreatedAt: tm}, false),
+		persist.InsertSubnetValidator(ctx, sess, &db.SubnetValidator{ID: "v1", SubnetID: "subnet1", NodeID: "node1", Weight: 10, Start: 1000, End: 2000, CreatedAt: tm}, false),
+		persist.UpdateSubnetValidatorRemoved(ctx, sess, &db.SubnetValidator{SubnetID: "subnet1", NodeID: "node1", RemovedAt: &tm}),
+	} {
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	reader := &Reader{conns: conns}
+	subnets, err := reader.ListSubnets(ctx, &params.ListSubnetsParams{})
+	if err != nil {
+		t.Fatal("list fail", err)
+	}
+	if len(subnets.Subnets) != 1 {
+		t.Fatal("unexpected subnets", len(subnets.Subnets))
+	}
+	subnet := subnets.Subnets[0]
+	if len(subnet.Owners) != 1 || string(subnet.Owners[0]) != owner ||
+		len(subnet.Blockchains) != 1 || subnet.Blockchains[0] != "chain1" {
+		t.Fatal("unexpected subnet", subnet)
+	}
+	if len(subnet.Validators) != 1 || subnet.Validators[0].Weight != "10" || subnet.Validators[0].RemovedAt == nil {
+		t.Fatal("unexpected subnet validators", subnet.Validators)
+	}
+
+	chains, err := reader.ListBlockchains(ctx, &params.ListBlockchainsParams{SubnetIDs: []string{"subnet1"}})
+	if err != nil {
+		t.Fatal("list fail", err)
+	}
+	if len(chains.Blockchains) != 1 || len(chains.Blockchains[0].FxIDs) != 2 || chains.Blockchains[0].VMID != "vm" {
+		t.Fatal("unexpected blockchains", chains.Blockchains)
+	}
+}
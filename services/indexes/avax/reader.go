@@ -28,6 +28,7 @@ import (
 	"github.com/chain4travel/magellan/caching"
 	"github.com/chain4travel/magellan/cfg"
 	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/labels"
 	"github.com/chain4travel/magellan/models"
 	"github.com/chain4travel/magellan/services"
 	"github.com/chain4travel/magellan/services/indexes/params"
@@ -74,6 +75,31 @@ type Reader struct {
 	doneCh chan struct{}
 }
 
+// primaryKey marks the contexts of reads which fall back to the primary
+type primaryKey struct{}
+
+// db returns the database to read from, a read replica unless the read falls
+// back to the primary
+func (r *Reader) db(ctx context.Context) *utils.Conn {
+	if ctx.Value(primaryKey{}) != nil {
+		return r.conns.Primary()
+	}
+	return r.conns.DB()
+}
+
+// lookup runs the lookup of an item, found reports whether the database had
+// it. An item which was just indexed may not have reached the read replicas
+// yet, so the lookup is repeated on the primary which has it right away.
+func (r *Reader) lookup(ctx context.Context, fn func(ctx context.Context) (found bool, err error)) error {
+	found, err := fn(ctx)
+	if found || (err != nil && !errors.Is(err, dbr.ErrNotFound)) ||
+		!r.conns.HasReplicas() || ctx.Value(primaryKey{}) != nil {
+		return err
+	}
+	_, err = fn(context.WithValue(ctx, primaryKey{}, true))
+	return err
+}
+
 func NewReader(networkID uint32, conns *utils.Connections, chainConsumers map[string]services.Consumer, sc *servicesctrl.Control) (*Reader, error) {
 	reader := &Reader{
 		conns:          conns,
@@ -91,7 +117,65 @@ func NewReader(networkID uint32, conns *utils.Connections, chainConsumers map[st
 	return reader, nil
 }
 
+// Search returns the results of the query with the labels of their addresses.
+// Labels whose name starts with the query are results of their own.
 func (r *Reader) Search(ctx context.Context, p *params.SearchParams, avaxAssetID ids.ID) (*models.SearchResults, error) {
+	results, err := r.search(ctx, p, avaxAssetID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbRunner, err := r.db(ctx).NewSession("search_labels", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var named []*db.AddressLabel
+	if len(p.ListParams.Query) >= MinSearchQueryLength {
+		builder := labelColumns(dbRunner).
+			Where(dbr.Like("name", p.ListParams.Query+"%")).
+			OrderAsc("name")
+		if p.ListParams.Limit != 0 {
+			builder.Limit(uint64(p.ListParams.Limit))
+		}
+		if _, err := builder.LoadContext(ctx, &named); err != nil {
+			return nil, err
+		}
+	}
+	for _, row := range named {
+		results.Results = append(results.Results, models.SearchResult{
+			SearchResultType: models.ResultTypeLabel,
+			Data:             labels.Model(row),
+		})
+		results.Count++
+	}
+
+	var addresses []string
+	for _, result := range results.Results {
+		switch data := result.Data.(type) {
+		case *models.AddressInfo:
+			addresses = append(addresses, string(data.Address))
+		case *models.Transaction:
+			addresses = append(addresses, transactionAddresses([]*models.Transaction{data})...)
+		case models.CResult:
+			if result.SearchResultType == models.ResultTypeCAddress {
+				addresses = append(addresses, strings.ToLower(data.Hash))
+			}
+		}
+	}
+	if results.Labels, err = addressLabels(ctx, dbRunner, addresses); err != nil {
+		return nil, err
+	}
+	for label, model := range labelMap(named) {
+		if results.Labels == nil {
+			results.Labels = make(models.AddressLabels)
+		}
+		results.Labels[label] = model
+	}
+	return results, nil
+}
+
+func (r *Reader) search(ctx context.Context, p *params.SearchParams, avaxAssetID ids.ID) (*models.SearchResults, error) {
 	p.ListParams.DisableCounting = true
 
 	var cblocks []models.CResult
@@ -141,7 +225,7 @@ func (r *Reader) Search(ctx context.Context, p *params.SearchParams, avaxAssetID
 		return collateSearchResults(assets, addresses, txs, cblocks, ctrans, caddr)
 	}
 
-	dbRunner, err := r.conns.DB().NewSession("search", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("search", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -160,66 +244,181 @@ func (r *Reader) Search(ctx context.Context, p *params.SearchParams, avaxAssetID
 	return collateSearchResults(assets, addresses, txs, cblocks, ctrans, caddr)
 }
 
-func (r *Reader) TxfeeAggregate(aggregateCache caching.AggregatesCache, params *params.TxfeeAggregateParams) (*models.TxfeeAggregatesHistogram, error) {
-	// if the request is not coming from the caching mechanism then return the values of the cache and do NOT probe the database
-	aggregateFeesMap := aggregateCache.GetAggregateFeesMap()
-	if len(aggregateFeesMap) != 0 {
-		cache := models.TxfeeAggregatesList{}
-		temp := models.TxfeeAggregates{}
+func (r *Reader) TxfeeAggregate(ctx context.Context, params *params.TxfeeAggregateParams) (*models.TxfeeAggregatesHistogram, error) {
+	if len(params.ChainIDs) == 0 {
+		return nil, fmt.Errorf("aggregate without chainID not allowed")
+	}
 
-		// based on the date interval we are going to retrieve the relevant part from our cache
-		keyDatePartValue := cfg.GetDatepartBasedOnDateParams(params.ListParams.StartTime, params.ListParams.EndTime)
-		temp.Txfee = aggregateFeesMap[params.ChainIDs[0]][keyDatePartValue]
+	dbRunner, err := r.db(ctx).NewSession("get_txfee_aggregates", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
 
-		cache = append(cache, temp)
-		cache[0].StartTime = params.ListParams.StartTime
-		cache[0].EndTime = params.ListParams.EndTime
-		return &models.TxfeeAggregatesHistogram{
-			TxfeeAggregates: cache[0],
-			StartTime:       params.ListParams.StartTime,
-			EndTime:         params.ListParams.EndTime,
-		}, nil
+	totals, err := r.aggregateTotals(ctx, dbRunner, params.ChainIDs, params.ListParams.StartTime, params.ListParams.EndTime)
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.TxfeeAggregatesHistogram{
-		TxfeeAggregates: models.TxfeeAggregates{},
-		StartTime:       params.ListParams.StartTime,
-		EndTime:         params.ListParams.EndTime,
+		TxfeeAggregates: models.TxfeeAggregates{
+			StartTime: params.ListParams.StartTime,
+			EndTime:   params.ListParams.EndTime,
+			Txfee:     totals.Txfee,
+		},
+		StartTime: params.ListParams.StartTime,
+		EndTime:   params.ListParams.EndTime,
 	}, nil
 }
 
-//gocyclo:ignore
-func (r *Reader) Aggregate(aggregateCache caching.AggregatesCache, params *params.AggregateParams) (*models.AggregatesHistogram, error) {
-	aggregateTransactionMap := aggregateCache.GetAggregateTransactionsMap()
-	if len(aggregateTransactionMap) != 0 {
-		if len(params.ChainIDs) == 0 {
-			return nil, fmt.Errorf("aggregate without chainID not allowed")
-		}
-		// if the request is not coming from the caching mechanism then return the values of the cache and do NOT probe the database
-		cache := models.AggregatesList{}
-		temp := models.Aggregates{}
-		// based on the date interval we are going to retrieve the relevant part from our cache
-		keyDatePartValue := cfg.GetDatepartBasedOnDateParams(params.ListParams.StartTime, params.ListParams.EndTime)
-		temp.TransactionCount = aggregateTransactionMap[params.ChainIDs[0]][keyDatePartValue]
-
-		cache = append(cache, temp)
-		cache[0].StartTime = params.ListParams.StartTime
-		cache[0].EndTime = params.ListParams.EndTime
-		return &models.AggregatesHistogram{
-			Aggregates: cache[0],
-			StartTime:  params.ListParams.StartTime,
-			EndTime:    params.ListParams.EndTime,
-		}, nil
+func (r *Reader) Aggregate(ctx context.Context, params *params.AggregateParams) (*models.AggregatesHistogram, error) {
+	if len(params.ChainIDs) == 0 {
+		return nil, fmt.Errorf("aggregate without chainID not allowed")
+	}
+
+	dbRunner, err := r.db(ctx).NewSession("get_transaction_aggregates", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var totals *db.AggregateRollup
+	if params.AssetID != nil {
+		// the rollups don't tell the assets apart
+		totals, err = countAssetTransactions(ctx, dbRunner, params.ChainIDs, *params.AssetID, params.ListParams.StartTime, params.ListParams.EndTime)
+	} else {
+		totals, err = r.aggregateTotals(ctx, dbRunner, params.ChainIDs, params.ListParams.StartTime, params.ListParams.EndTime)
 	}
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.AggregatesHistogram{
-		Aggregates: models.Aggregates{},
-		StartTime:  params.ListParams.StartTime,
-		EndTime:    params.ListParams.EndTime,
+		Aggregates: models.Aggregates{
+			StartTime:        params.ListParams.StartTime,
+			EndTime:          params.ListParams.EndTime,
+			TransactionCount: totals.TransactionCount,
+		},
+		StartTime: params.ListParams.StartTime,
+		EndTime:   params.ListParams.EndTime,
 	}, nil
 }
 
+// aggregateTotals sums the transactions of the given chains over the range.
+// The whole hours are read from the rollups, the partial hours at the edges
+// of the range are counted from the indexed rows.
+func (r *Reader) aggregateTotals(ctx context.Context, dbRunner *dbr.Session, chainIDs []string, startTime time.Time, endTime time.Time) (*db.AggregateRollup, error) {
+	startTime = startTime.UTC()
+	endTime = endTime.UTC()
+
+	hourStart := startTime.Truncate(time.Hour)
+	if hourStart.Before(startTime) {
+		hourStart = hourStart.Add(time.Hour)
+	}
+	hourEnd := endTime.Truncate(time.Hour)
+
+	totals := &db.AggregateRollup{}
+	edges := [][2]time.Time{{startTime, endTime}}
+	if hourStart.Before(hourEnd) {
+		edges = [][2]time.Time{{startTime, hourStart}, {hourEnd, endTime}}
+		rollups, err := sumAggregateRollups(ctx, dbRunner, chainIDs, hourStart, hourEnd)
+		if err != nil {
+			return nil, err
+		}
+		totals.TransactionCount += rollups.TransactionCount
+		totals.Txfee += rollups.Txfee
+	}
+
+	chains := r.sc.IndexedChains()
+	for _, edge := range edges {
+		if !edge[0].Before(edge[1]) {
+			continue
+		}
+		for _, chainID := range chainIDs {
+			vmType := chains[chainID].VMType
+			// the cvm tables hold the transactions of the C-chain only, like
+			// for the rollups
+			if cchainID := r.sc.ServicesCfg.CchainID; vmType == models.CVMName && cchainID != "" && chainID != cchainID {
+				continue
+			}
+			count, err := caching.CountTransactions(ctx, dbRunner, chainID, vmType, edge[0], edge[1])
+			if err != nil {
+				return nil, err
+			}
+			totals.TransactionCount += count.TransactionCount
+			totals.Txfee += count.Txfee
+		}
+	}
+	return totals, nil
+}
+
+// countAssetTransactions counts the transactions of the given chains within
+// the range which have an output of the asset
+func countAssetTransactions(ctx context.Context, dbRunner *dbr.Session, chainIDs []string, assetID ids.ID, startTime time.Time, endTime time.Time) (*db.AggregateRollup, error) {
+	totals := &db.AggregateRollup{}
+	err := dbRunner.
+		Select("COUNT(DISTINCT transaction_id) AS transaction_count").
+		From(db.TableOutputs).
+		Where("chain_id IN ?", chainIDs).
+		Where("asset_id = ?", assetID.String()).
+		Where("created_at >= ?", startTime).
+		Where("created_at < ?", endTime).
+		LoadOneContext(ctx, totals)
+	return totals, err
+}
+
+// sumAggregateRollups sums the aggregate rollups of the given chains over the
+// requested range. Whole days are read from the daily rollups and the edges
+// from the hourly ones, the range is therefore answered with hour resolution.
+func sumAggregateRollups(ctx context.Context, dbRunner *dbr.Session, chainIDs []string, startTime time.Time, endTime time.Time) (*db.AggregateRollup, error) {
+	startTime = startTime.UTC().Truncate(time.Hour)
+	endTime = endTime.UTC()
+
+	dayStart := startTime.Truncate(24 * time.Hour)
+	if dayStart.Before(startTime) {
+		dayStart = dayStart.Add(24 * time.Hour)
+	}
+	dayEnd := endTime.Truncate(24 * time.Hour)
+
+	type rollupRange struct {
+		table     string
+		startTime time.Time
+		endTime   time.Time
+	}
+	ranges := []rollupRange{{db.TableAggregateRollupsHourly, startTime, endTime}}
+	if dayStart.Before(dayEnd) {
+		ranges = []rollupRange{
+			{db.TableAggregateRollupsHourly, startTime, dayStart},
+			{db.TableAggregateRollupsDaily, dayStart, dayEnd},
+			{db.TableAggregateRollupsHourly, dayEnd, endTime},
+		}
+	}
+
+	totals := &db.AggregateRollup{}
+	for _, rr := range ranges {
+		if !rr.startTime.Before(rr.endTime) {
+			continue
+		}
+		sum := &db.AggregateRollup{}
+		err := dbRunner.
+			Select(
+				db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM(transaction_count), 0)")+" AS transaction_count",
+				db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM(txfee), 0)")+" AS txfee",
+			).
+			From(rr.table).
+			Where("chain_id IN ?", chainIDs).
+			Where("bucket_at >= ?", rr.startTime).
+			Where("bucket_at < ?", rr.endTime).
+			LoadOneContext(ctx, sum)
+		if err != nil {
+			return nil, err
+		}
+		totals.TransactionCount += sum.TransactionCount
+		totals.Txfee += sum.Txfee
+	}
+	return totals, nil
+}
+
 func (r *Reader) GetMultisigAlias(ctx context.Context, ownersAddresses []string) (*models.MultisigAliasList, error) {
-	dbRunner, err := r.conns.DB().NewSession("multisig_alias", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("multisig_alias", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -237,7 +436,7 @@ func (r *Reader) GetMultisigAlias(ctx context.Context, ownersAddresses []string)
 }
 
 func (r *Reader) GetReward(ctx context.Context, addresses []string) (*[]models.Reward, error) {
-	dbRunner, err := r.conns.DB().NewSession("reward", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("reward", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -255,7 +454,7 @@ func (r *Reader) GetReward(ctx context.Context, addresses []string) (*[]models.R
 }
 
 func (r *Reader) ListAddresses(ctx context.Context, p *params.ListAddressesParams) (*models.AddressList, error) {
-	dbRunner, err := r.conns.DB().NewSession("list_addresses", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("list_addresses", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -270,7 +469,7 @@ func (r *Reader) ListAddresses(ctx context.Context, p *params.ListAddressesParam
 	var ua *dbr.SelectStmt
 	var baseq *dbr.SelectStmt
 
-	if r.sc.IsAccumulateBalanceReader {
+	if r.sc.IsAccumulateBalanceReader() {
 		ua = dbRunner.Select("avm_outputs.chain_id", "avm_output_addresses.address").
 			Distinct().
 			From("avm_outputs").
@@ -392,7 +591,7 @@ func (r *Reader) ListAddresses(ctx context.Context, p *params.ListAddressesParam
 }
 
 func (r *Reader) ListOutputs(ctx context.Context, p *params.ListOutputsParams) (*models.OutputList, error) {
-	dbRunner, err := r.conns.DB().NewSession("list_transaction_outputs", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("list_transaction_outputs", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -461,9 +660,15 @@ func (r *Reader) ListOutputs(ctx context.Context, p *params.ListOutputsParams) (
 }
 
 func (r *Reader) GetTransaction(ctx context.Context, id ids.ID, avaxAssetID ids.ID) (*models.Transaction, error) {
-	txList, err := r.ListTransactions(ctx, &params.ListTransactionsParams{
+	p := &params.ListTransactionsParams{
 		ListParams: params.ListParams{ID: &id, DisableCounting: true},
-	}, avaxAssetID)
+	}
+	var txList *models.TransactionList
+	err := r.lookup(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		txList, err = r.ListTransactions(ctx, p, avaxAssetID)
+		return err == nil && len(txList.Transactions) > 0, err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -498,16 +703,33 @@ func (r *Reader) GetAddress(ctx context.Context, p *params.ListAddressesParams)
 		}
 	}
 	if len(addressList.Addresses) > 0 {
-		return addressList.Addresses[0], nil
+		addressInfo := addressList.Addresses[0]
+		dbRunner, err := r.db(ctx).NewSession("get_address_label", cfg.RequestTimeout)
+		if err != nil {
+			return nil, err
+		}
+		found, err := addressLabels(ctx, dbRunner, []string{string(addressInfo.Address)})
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range found {
+			addressInfo.Label = label
+		}
+		return addressInfo, nil
 	}
 	return nil, err
 }
 
 func (r *Reader) GetOutput(ctx context.Context, id ids.ID) (*models.Output, error) {
-	outputList, err := r.ListOutputs(ctx,
-		&params.ListOutputsParams{
-			ListParams: params.ListParams{ID: &id, DisableCounting: true},
-		})
+	var outputList *models.OutputList
+	err := r.lookup(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		outputList, err = r.ListOutputs(ctx,
+			&params.ListOutputsParams{
+				ListParams: params.ListParams{ID: &id, DisableCounting: true},
+			})
+		return err == nil && len(outputList.Outputs) > 0, err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -518,7 +740,7 @@ func (r *Reader) GetOutput(ctx context.Context, id ids.ID) (*models.Output, erro
 }
 
 func (r *Reader) AddressChains(ctx context.Context, p *params.AddressChainsParams) (*models.AddressChains, error) {
-	dbRunner, err := r.conns.DB().NewSession("addressChains", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("addressChains", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -556,7 +778,7 @@ func (r *Reader) AddressChains(ctx context.Context, p *params.AddressChainsParam
 }
 
 func (r *Reader) searchByID(ctx context.Context, id ids.ID, avaxAssetID ids.ID) (*models.SearchResults, error) {
-	dbRunner, err := r.conns.DB().NewSession("search_by_id", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("search_by_id", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -601,7 +823,7 @@ func (r *Reader) searchByShortID(ctx context.Context, id ids.ShortID) (*models.S
 }
 
 func (r *Reader) searchCBlockHeight(ctx context.Context, height uint64) ([]models.CResult, error) {
-	dbRunner, err := r.conns.DB().NewSession("search_cblock_height", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("search_cblock_height", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -618,7 +840,7 @@ func (r *Reader) searchCBlockHeight(ctx context.Context, height uint64) ([]model
 }
 
 func (r *Reader) searchCBlockHash(ctx context.Context, hash string) ([]models.CResult, error) {
-	dbRunner, err := r.conns.DB().NewSession("search_cblock_hash", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("search_cblock_hash", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -635,7 +857,7 @@ func (r *Reader) searchCBlockHash(ctx context.Context, hash string) ([]models.CR
 }
 
 func (r *Reader) searchCTransHash(ctx context.Context, hash string) ([]models.CResult, error) {
-	dbRunner, err := r.conns.DB().NewSession("search_ctrans_hash", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("search_ctrans_hash", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -652,7 +874,7 @@ func (r *Reader) searchCTransHash(ctx context.Context, hash string) ([]models.CR
 }
 
 func (r *Reader) searchCAddress(ctx context.Context, address string) ([]models.CResult, error) {
-	dbRunner, err := r.conns.DB().NewSession("search_cblock_hash", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("search_cblock_hash", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -772,6 +994,14 @@ func rawTransactionQuery(dbRunner *dbr.Session) *dbr.SelectStmt {
 		From("avm_transactions")
 }
 
+// AddChain serves the transactions of a chain found by the chain discovery
+// with its consumer
+func (r *Reader) AddChain(chainID string, consumer services.Consumer) {
+	r.avmLock.Lock()
+	defer r.avmLock.Unlock()
+	r.chainConsumers[chainID] = consumer
+}
+
 func (r *Reader) chainWriter(chainID string) (services.Consumer, error) {
 	r.avmLock.RLock()
 	w, ok := r.chainConsumers[chainID]
@@ -783,22 +1013,24 @@ func (r *Reader) chainWriter(chainID string) (services.Consumer, error) {
 }
 
 func (r *Reader) ATxDATA(ctx context.Context, p *params.TxDataParam) ([]byte, error) {
-	dbRunner, err := r.conns.DB().NewSession("atx_data", cfg.RequestTimeout)
-	if err != nil {
-		return nil, err
-	}
-
 	type Row struct {
 		Serialization []byte
 		ChainID       string
 	}
 	rows := []Row{}
 
-	_, err = dbRunner.
-		Select("canonical_serialization as serialization", "chain_id").
-		From("avm_transactions").
-		Where("id=?", p.ID).
-		LoadContext(ctx, &rows)
+	err := r.lookup(ctx, func(ctx context.Context) (bool, error) {
+		dbRunner, err := r.db(ctx).NewSession("atx_data", cfg.RequestTimeout)
+		if err != nil {
+			return false, err
+		}
+		_, err = dbRunner.
+			Select("canonical_serialization as serialization", "chain_id").
+			From("avm_transactions").
+			Where("id=?", p.ID).
+			LoadContext(ctx, &rows)
+		return len(rows) > 0, err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -822,11 +1054,6 @@ func (r *Reader) ATxDATA(ctx context.Context, p *params.TxDataParam) ([]byte, er
 }
 
 func (r *Reader) PTxDATA(ctx context.Context, p *params.TxDataParam) ([]byte, error) {
-	dbRunner, err := r.conns.DB().NewSession("ptx_data", cfg.RequestTimeout)
-	if err != nil {
-		return nil, err
-	}
-
 	type Row struct {
 		ID            string
 		Serialization []byte
@@ -836,25 +1063,26 @@ func (r *Reader) PTxDATA(ctx context.Context, p *params.TxDataParam) ([]byte, er
 	}
 	rows := []Row{}
 
-	idInt, ok := big.NewInt(0).SetString(p.ID, 10)
-	if idInt != nil && ok {
-		_, err = dbRunner.
-			Select("id", "serialization", "chain_id", "proposer", "proposer_time").
-			From(db.TablePvmBlocks).
-			Where("height="+idInt.String()).
-			LoadContext(ctx, &rows)
+	err := r.lookup(ctx, func(ctx context.Context) (bool, error) {
+		dbRunner, err := r.db(ctx).NewSession("ptx_data", cfg.RequestTimeout)
 		if err != nil {
-			return nil, err
+			return false, err
 		}
-	} else {
-		_, err = dbRunner.
+
+		sq := dbRunner.
 			Select("id", "serialization", "chain_id", "proposer", "proposer_time").
-			From(db.TablePvmBlocks).
-			Where("id=?", p.ID).
-			LoadContext(ctx, &rows)
-		if err != nil {
-			return nil, err
+			From(db.TablePvmBlocks)
+		idInt, ok := big.NewInt(0).SetString(p.ID, 10)
+		if idInt != nil && ok {
+			sq.Where("height=" + idInt.String())
+		} else {
+			sq.Where("id=?", p.ID)
 		}
+		_, err = sq.LoadContext(ctx, &rows)
+		return len(rows) > 0, err
+	})
+	if err != nil {
+		return nil, err
 	}
 	if len(rows) == 0 {
 		return []byte(""), nil
@@ -876,7 +1104,17 @@ func (r *Reader) PTxDATA(ctx context.Context, p *params.TxDataParam) ([]byte, er
 }
 
 func (r *Reader) CTxDATA(ctx context.Context, p *params.TxDataParam) ([]byte, error) {
-	dbRunner, err := r.conns.DB().NewSession("ctx_data", cfg.RequestTimeout)
+	var j []byte
+	err := r.lookup(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		j, err = r.cTxDATA(ctx, p)
+		return err == nil, err
+	})
+	return j, err
+}
+
+func (r *Reader) cTxDATA(ctx context.Context, p *params.TxDataParam) ([]byte, error) {
+	dbRunner, err := r.db(ctx).NewSession("ctx_data", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -909,13 +1147,16 @@ func (r *Reader) CTxDATA(ctx context.Context, p *params.TxDataParam) ([]byte, er
 
 	block := BlockExport{Hash: cvmBlock.Hash}
 
-	err = block.Header.UnmarshalJSON(cvmBlock.Serialization)
-	if err != nil {
+	if len(cvmBlock.Serialization) == 0 {
+		// the serialization has been pruned, only the indexed fields are left
+		block.Header.Number = idInt
+		block.Header.Time = uint64(cvmBlock.CreatedAt.Unix())
+	} else if err = block.Header.UnmarshalJSON(cvmBlock.Serialization); err != nil {
 		return nil, err
 	}
 
 	// Load Transactions and signatures
-	cTransactionList, err := r.ListCTransactions(ctx, &params.ListCTransactionsParams{BlockStart: idInt, BlockEnd: idInt})
+	cTransactionList, err := r.listCTransactions(ctx, &params.ListCTransactionsParams{BlockStart: idInt, BlockEnd: idInt})
 	if err != nil {
 		return nil, err
 	}
@@ -932,7 +1173,7 @@ func (r *Reader) UniqueAddresses(ctx context.Context, p *params.ListParams) (*mo
 	var UniqueAddresses []*models.UniqueAddresses
 	var baseq *dbr.SelectStmt
 	var dateFormat string
-	dbRunner, err := r.conns.DB().NewSession("unique_adresses", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("unique_adresses", cfg.RequestTimeout)
 	if err != nil {
 		return &models.AddressStruct{
 			AddressInfo: []*models.UniqueAddresses{},
@@ -992,7 +1233,7 @@ func (r *Reader) DailyIncreaseInfo(uniquea []*models.UniqueAddresses) *models.Ad
 }
 
 func (r *Reader) ActiveAddresses(ctx context.Context, p *params.ListParams) (*models.AddressStruct, error) {
-	dbRunner, err := r.conns.DB().NewSession("active_addresses", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("active_addresses", cfg.RequestTimeout)
 	var addressStatistics *models.AddressStruct
 	var ActiveAddresses []*models.ActiveAddresses
 	var Active *dbr.SelectStmt
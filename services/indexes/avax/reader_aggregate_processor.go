@@ -187,7 +187,7 @@ func (r *Reader) CacheAggregates(tag string) *models.AggregatesHistogram {
 }
 
 func (r *Reader) aggregateProcessor() error {
-	if !r.sc.IsAggregateCache {
+	if !r.sc.IsAggregateCache() {
 		return nil
 	}
 
@@ -335,9 +335,10 @@ func (r *Reader) addressCounts(ctx context.Context, sess *dbr.Session) {
 	}
 
 	// counts for the chains only..
+	chains := r.sc.IndexedChains()
 	var addressCountlpruned []*models.ChainCounts
 	for _, aCount := range addressCountl {
-		if _, ok := r.sc.Chains[string(aCount.ChainID)]; ok {
+		if _, ok := chains[string(aCount.ChainID)]; ok {
 			addressCountlpruned = append(addressCountlpruned, aCount)
 		}
 	}
@@ -364,9 +365,10 @@ func (r *Reader) txCounts(ctx context.Context, sess *dbr.Session) {
 	}
 
 	// counts for the chains only..
+	chains := r.sc.IndexedChains()
 	var txCountlpruned []*models.ChainCounts
 	for _, aCount := range txCountl {
-		if _, ok := r.sc.Chains[string(aCount.ChainID)]; ok {
+		if _, ok := chains[string(aCount.ChainID)]; ok {
 			txCountlpruned = append(txCountlpruned, aCount)
 		}
 	}
@@ -475,7 +477,7 @@ func (r *Reader) aggregateProcessorAssetAggr(conns *utils.Connections) {
 				zap.Time("endTime", p.ListParams.EndTime),
 			)
 			p.AssetID = &id
-			aggr, err := r.Aggregate(r.sc.AggregatesCache, p)
+			aggr, err := r.Aggregate(ctx, p)
 			if err != nil {
 				r.sc.Log.Warn("aggregation failed",
 					zap.String("operation", "running aggregation"),
@@ -584,7 +586,7 @@ func (r *Reader) processAggregate(runTm time.Time, tag string, intervalSize stri
 		zap.Time("startTime", p.ListParams.StartTime),
 		zap.Time("endTime", p.ListParams.EndTime),
 	)
-	return r.Aggregate(r.sc.AggregatesCache, p)
+	return r.Aggregate(context.Background(), p)
 }
 
 func (r *Reader) aggregateProcessor1m(conns *utils.Connections) {
@@ -0,0 +1,289 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+	"github.com/gocraft/dbr/v2"
+)
+
+// FeeStats returns the fee statistics of the transactions per chain and
+// transaction type within the time range
+func (r *Reader) FeeStats(ctx context.Context, p *params.FeeStatsParams) (*models.FeeStatsList, error) {
+	dbRunner, err := r.db(ctx).NewSession("fee_stats", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*struct {
+		ChainID          string
+		Type             string
+		TransactionCount uint64
+		Total            uint64
+		MinFee           uint64
+		MaxFee           uint64
+	}
+	_, err = p.Apply(dbRunner.
+		Select(
+			"chain_id",
+			"type",
+			"COUNT(*) AS transaction_count",
+			db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM(txfee), 0)")+" AS total",
+			db.CastUnsigned(dbRunner.Dialect, "COALESCE(MIN(txfee), 0)")+" AS min_fee",
+			db.CastUnsigned(dbRunner.Dialect, "COALESCE(MAX(txfee), 0)")+" AS max_fee",
+		).
+		From(db.TableTransactions).
+		GroupBy("chain_id", "type").
+		OrderAsc("chain_id").
+		OrderAsc("type")).
+		LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// the 95th percentile is the fee at its nearest rank, the smallest rank
+	// which covers 95% of the transactions of the chain and type
+	feeRanks := dbRunner.
+		Select(
+			"chain_id",
+			"type",
+			"COALESCE(txfee, 0) AS txfee",
+			"ROW_NUMBER() OVER (PARTITION BY chain_id, type ORDER BY COALESCE(txfee, 0)) AS fee_rank",
+			"COUNT(*) OVER (PARTITION BY chain_id, type) AS fee_count",
+		).
+		From(db.TableTransactions)
+	var p95s []*struct {
+		ChainID string
+		Type    string
+		P95     uint64
+	}
+	_, err = dbRunner.
+		Select(
+			"chain_id",
+			"type",
+			db.CastUnsigned(dbRunner.Dialect, "txfee")+" AS p95",
+		).
+		From(p.Apply(feeRanks).As("fee_ranks")).
+		Where("fee_rank * 100 >= fee_count * 95").
+		Where("(fee_rank - 1) * 100 < fee_count * 95").
+		LoadContext(ctx, &p95s)
+	if err != nil {
+		return nil, err
+	}
+	p95ByType := make(map[[2]string]uint64, len(p95s))
+	for _, row := range p95s {
+		p95ByType[[2]string{row.ChainID, row.Type}] = row.P95
+	}
+
+	list := &models.FeeStatsList{
+		StartTime: p.ListParams.StartTime,
+		EndTime:   p.ListParams.EndTime,
+		Stats:     make([]*models.FeeStats, 0, len(rows)),
+	}
+	for _, row := range rows {
+		stats := &models.FeeStats{
+			ChainID:          models.StringID(row.ChainID),
+			Type:             row.Type,
+			TransactionCount: row.TransactionCount,
+			Total:            row.Total,
+			Min:              row.MinFee,
+			Max:              row.MaxFee,
+			P95:              p95ByType[[2]string{row.ChainID, row.Type}],
+		}
+		if row.TransactionCount != 0 {
+			stats.Avg = row.Total / row.TransactionCount
+		}
+		list.Stats = append(list.Stats, stats)
+	}
+	return list, nil
+}
+
+// BurnedFees returns the fees burned on the chains within the time range and
+// the cumulated fees burned since genesis. The fees are read from the hourly
+// aggregate rollups, the range and the intervals have therefore hour
+// resolution.
+func (r *Reader) BurnedFees(ctx context.Context, p *params.BurnedFeesParams) (*models.BurnedFeesHistogram, error) {
+	if len(p.ChainIDs) == 0 {
+		return nil, fmt.Errorf("burned fees without chainID not allowed")
+	}
+
+	dbRunner, err := r.db(ctx).NewSession("burned_fees", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := p.FeeHistoryParams.ListParams.StartTime.UTC().Truncate(time.Hour)
+	endTime := p.FeeHistoryParams.ListParams.EndTime.UTC()
+	intervalSize := p.FeeHistoryParams.IntervalSize
+	if intervalSize != 0 && intervalSize < time.Hour {
+		intervalSize = time.Hour
+	}
+
+	before, err := sumAggregateRollups(ctx, dbRunner, p.ChainIDs, time.Unix(0, 0), startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []*struct {
+		IntervalID int64
+		Txfee      uint64
+	}
+	_, err = dbRunner.
+		Select(
+			intervalColumn(dbRunner.Dialect, "bucket_at", startTime, endTime, intervalSize),
+			db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM(txfee), 0)")+" AS txfee",
+		).
+		From(db.TableAggregateRollupsHourly).
+		Where("chain_id IN ?", p.ChainIDs).
+		Where("bucket_at >= ?", startTime).
+		Where("bucket_at < ?", endTime).
+		GroupBy("interval_id").
+		LoadContext(ctx, &buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := make([]models.BurnedFees, 0)
+	for _, tr := range feeIntervals(startTime, endTime, intervalSize) {
+		intervals = append(intervals, models.BurnedFees{StartTime: tr[0], EndTime: tr[1]})
+	}
+	for _, bucket := range buckets {
+		if bucket.IntervalID >= 0 && bucket.IntervalID < int64(len(intervals)) {
+			intervals[bucket.IntervalID].Burned += bucket.Txfee
+		}
+	}
+
+	histogram := &models.BurnedFeesHistogram{
+		BurnedFees: models.BurnedFees{StartTime: startTime, EndTime: endTime, Cumulative: before.Txfee},
+	}
+	for i := range intervals {
+		histogram.BurnedFees.Burned += intervals[i].Burned
+		histogram.BurnedFees.Cumulative += intervals[i].Burned
+		intervals[i].Cumulative = histogram.BurnedFees.Cumulative
+	}
+	if intervalSize != 0 {
+		histogram.IntervalSize = intervalSize
+		histogram.Intervals = intervals
+	}
+	return histogram, nil
+}
+
+// CChainFees splits the fees of the C-chain transactions within the time range
+// into the base fee of their blocks and the tip paid above it. Like the
+// aggregates, the fees are converted from wei to nAVAX per transaction.
+func (r *Reader) CChainFees(ctx context.Context, p *params.FeeHistoryParams) (*models.CChainFeesHistogram, error) {
+	dbRunner, err := r.db(ctx).NewSession("cchain_fees", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := p.ListParams.StartTime.UTC()
+	endTime := p.ListParams.EndTime.UTC()
+
+	var buckets []*struct {
+		IntervalID       int64
+		TransactionCount uint64
+		GasUsed          uint64
+		Total            uint64
+		BaseFee          uint64
+	}
+	total := db.CastUnsigned(dbRunner.Dialect, "(gas_price / 1000000000) * gas_used")
+	baseFee := db.CastUnsigned(dbRunner.Dialect, "(base_fee / 1000000000) * gas_used")
+	_, err = dbRunner.
+		Select(
+			intervalColumn(dbRunner.Dialect, "created_at", startTime, endTime, p.IntervalSize),
+			"COUNT(*) AS transaction_count",
+			db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM(gas_used), 0)")+" AS gas_used",
+			db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM("+total+"), 0)")+" AS total",
+			db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM("+baseFee+"), 0)")+" AS base_fee",
+		).
+		From(db.TableCvmTransactionsTxdata).
+		Where("created_at >= ?", startTime).
+		Where("created_at < ?", endTime).
+		GroupBy("interval_id").
+		LoadContext(ctx, &buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := make([]models.CChainFees, 0)
+	for _, tr := range feeIntervals(startTime, endTime, p.IntervalSize) {
+		intervals = append(intervals, models.CChainFees{StartTime: tr[0], EndTime: tr[1]})
+	}
+	for _, bucket := range buckets {
+		if bucket.IntervalID < 0 || bucket.IntervalID >= int64(len(intervals)) {
+			continue
+		}
+		interval := &intervals[bucket.IntervalID]
+		interval.TransactionCount += bucket.TransactionCount
+		interval.GasUsed += bucket.GasUsed
+		interval.Total += bucket.Total
+		interval.BaseFee += bucket.BaseFee
+	}
+
+	histogram := &models.CChainFeesHistogram{
+		Fees: models.CChainFees{StartTime: startTime, EndTime: endTime},
+	}
+	for i := range intervals {
+		interval := &intervals[i]
+		// both are rounded per transaction, the base fee may round above the total
+		if interval.BaseFee < interval.Total {
+			interval.Tip = interval.Total - interval.BaseFee
+		}
+		histogram.Fees.TransactionCount += interval.TransactionCount
+		histogram.Fees.GasUsed += interval.GasUsed
+		histogram.Fees.Total += interval.Total
+		histogram.Fees.BaseFee += interval.BaseFee
+		histogram.Fees.Tip += interval.Tip
+	}
+	if p.IntervalSize != 0 {
+		histogram.IntervalSize = p.IntervalSize
+		histogram.Intervals = intervals
+	}
+	return histogram, nil
+}
+
+// intervalColumn selects the index of the interval a row falls into as
+// interval_id, without an interval size the whole range is one interval
+func intervalColumn(d dbr.Dialect, column string, startTime time.Time, endTime time.Time, intervalSize time.Duration) string {
+	seconds := int64(intervalSize.Seconds())
+	if seconds == 0 {
+		seconds = int64(endTime.Sub(startTime).Seconds()) + 1
+	}
+	return fmt.Sprintf("FLOOR((%s - %d) / %d) AS interval_id", db.UnixTimestamp(d, column), startTime.Unix(), seconds)
+}
+
+// feeIntervals splits the range into the start and end times of its intervals,
+// the last one ends at the end of the range
+func feeIntervals(startTime time.Time, endTime time.Time, intervalSize time.Duration) [][2]time.Time {
+	if intervalSize == 0 {
+		return [][2]time.Time{{startTime, endTime}}
+	}
+	var intervals [][2]time.Time
+	for t := startTime; t.Before(endTime); t = t.Add(intervalSize) {
+		end := t.Add(intervalSize)
+		if end.After(endTime) {
+			end = endTime
+		}
+		intervals = append(intervals, [2]time.Time{t, end})
+	}
+	return intervals
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/services/indexes/params"
+	"github.com/chain4travel/magellan/utils"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+	"github.com/gocraft/dbr/v2"
+)
+
+func TestFees(t *testing.T) {
+	conns, sess := sqlitetest.New(t)
+	testFees(t, conns, sess)
+}
+
+func TestFeesPostgres(t *testing.T) {
+	conns, sess := newTestPostgres(t)
+
+	// the database outlives the test, the rows of earlier runs are removed
+	tm := feesTestTime
+	for _, stmt := range []*dbr.DeleteStmt{
+		sess.DeleteFrom(db.TableTransactions).Where("created_at >= ? AND created_at < ?", tm.Add(-time.Hour), tm.Add(3*time.Hour)),
+		sess.DeleteFrom(db.TableAggregateRollupsHourly).Where("chain_id = ?", "x"),
+		sess.DeleteFrom(db.TableCvmTransactionsTxdata).Where("hash = ?", "0x1"),
+		sess.DeleteFrom(db.TableCvmAccounts).Where("address IN ?", []string{"from", "to"}),
+	} {
+		if _, err := stmt.ExecContext(context.Background()); err != nil {
+			t.Fatal("delete fail", err)
+		}
+	}
+	testFees(t, conns, sess)
+}
+
+var feesTestTime = time.Unix(3600*1000, 0).UTC()
+
+func testFees(t *testing.T, conns *utils.Connections, sess *dbr.Session) {
+	ctx := context.Background()
+	persist := db.NewPersist()
+	tm := feesTestTime
+
+	// twenty base transactions paying 1 to 20 and one export
+	for i := 1; i <= 20; i++ {
+		err := persist.InsertTransactions(ctx, sess, &db.Transactions{
+			ID: fmt.Sprintf("base%d", i), ChainID: "x", Type: "base", Txfee: uint64(i), CreatedAt: tm,
+		}, false)
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+	for _, err := range []error{
+		persist.InsertTransactions(ctx, sess, &db.Transactions{
+			ID: "export", ChainID: "x", Type: "export", Txfee: 7, CreatedAt: tm,
+		}, false),
+		persist.InsertAggregateRollupHourly(ctx, sess, &db.AggregateRollup{
+			ChainID: "x", BucketAt: tm.Add(-time.Hour), Txfee: 100, UpdatedAt: tm,
+		}, false),
+		persist.InsertAggregateRollupHourly(ctx, sess, &db.AggregateRollup{
+			ChainID: "x", BucketAt: tm, Txfee: 10, UpdatedAt: tm,
+		}, false),
+		persist.InsertAggregateRollupHourly(ctx, sess, &db.AggregateRollup{
+			ChainID: "x", BucketAt: tm.Add(2 * time.Hour), Txfee: 20, UpdatedAt: tm,
+		}, false),
+		persist.InsertCvmAccount(ctx, sess, &db.CvmAccount{Address: "from"}, false),
+		persist.InsertCvmAccount(ctx, sess, &db.CvmAccount{Address: "to"}, false),
+		persist.InsertCvmTransactionsTxdata(ctx, sess, &db.CvmTransactionsTxdata{
+			Hash: "0x1", Block: "1", FromAddr: "from", ToAddr: "to", GasUsed: 21000,
+			GasPrice: 30000000000, BaseFee: 25000000000, CreatedAt: tm,
+		}, false),
+	} {
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	reader := &Reader{conns: conns}
+	stats, err := reader.FeeStats(ctx, &params.FeeStatsParams{
+		ListParams: params.ListParams{EndTime: tm.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatal("fee stats fail", err)
+	}
+	if len(stats.Stats) != 2 {
+		t.Fatal("unexpected fee stats", len(stats.Stats))
+	}
+	base := stats.Stats[0]
+	if base.Type != "base" || base.TransactionCount != 20 || base.Total != 210 ||
+		base.Min != 1 || base.Avg != 10 || base.P95 != 19 || base.Max != 20 {
+		t.Fatal("unexpected base fee stats", base)
+	}
+	if export := stats.Stats[1]; export.Type != "export" || export.P95 != 7 {
+		t.Fatal("unexpected export fee stats", export)
+	}
+
+	burned, err := reader.BurnedFees(ctx, &params.BurnedFeesParams{
+		FeeHistoryParams: params.FeeHistoryParams{
+			ListParams:   params.ListParams{StartTime: tm, EndTime: tm.Add(3 * time.Hour)},
+			IntervalSize: time.Hour,
+		},
+		ChainIDs: []string{"x"},
+	})
+	if err != nil {
+		t.Fatal("burned fees fail", err)
+	}
+	if burned.BurnedFees.Burned != 30 || burned.BurnedFees.Cumulative != 130 || len(burned.Intervals) != 3 {
+		t.Fatal("unexpected burned fees", burned)
+	}
+	if burned.Intervals[0].Cumulative != 110 || burned.Intervals[1].Burned != 0 || burned.Intervals[2].Cumulative != 130 {
+		t.Fatal("unexpected burned fee intervals", burned.Intervals)
+	}
+
+	cfees, err := reader.CChainFees(ctx, &params.FeeHistoryParams{
+		ListParams: params.ListParams{StartTime: tm, EndTime: tm.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatal("cchain fees fail", err)
+	}
+	if cfees.Fees.TransactionCount != 1 || cfees.Fees.GasUsed != 21000 || cfees.Fees.Total != 630000 ||
+		cfees.Fees.BaseFee != 525000 || cfees.Fees.Tip != 105000 || cfees.Intervals != nil {
+		t.Fatal("unexpected cchain fees", cfees.Fees)
+	}
+}
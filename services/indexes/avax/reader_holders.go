@@ -0,0 +1,136 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+	"github.com/gocraft/dbr/v2"
+)
+
+// holderBalances selects the addresses holding the asset of the params from the
+// balances kept by the balance manager
+func holderBalances(dbRunner *dbr.Session, p *params.AssetHoldersParams) *dbr.SelectStmt {
+	return p.ApplyBalances(db.HolderBalances(dbRunner))
+}
+
+// AssetHolders returns the addresses holding the asset, largest balance first,
+// and their share of its supply. The balances are read from the tables of the
+// balance manager, which are only kept with the accumulate_balance_reader
+// feature.
+func (r *Reader) AssetHolders(ctx context.Context, p *params.AssetHoldersParams) (*models.AssetHolderList, error) {
+	dbRunner, err := r.db(ctx).NewSession("asset_holders", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*struct {
+		Address models.Address
+		Balance string
+	}
+	_, err = p.Apply(holderBalances(dbRunner, p).
+		OrderDesc("balance").
+		OrderAsc("accumulate_balances_received.address")).
+		LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.AssetHolderList{
+		AssetID: models.StringID(p.AssetID.String()),
+		Holders: make([]*models.AssetHolder, 0, len(rows)),
+	}
+	err = dbRunner.
+		Select("COUNT(*)").
+		From(holderBalances(dbRunner, p).As("holders")).
+		LoadOneContext(ctx, &list.HolderCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// the share is of the whole supply, without a supply of the asset yet it
+	// is the sum of the balances. The sums are scanned as strings, the drivers
+	// return decimals as bytes and integers as int64.
+	var total string
+	err = dbRunner.
+		Select("total").
+		From(db.TableSupplyHistory).
+		Where("asset_id = ?", p.AssetID.String()).
+		OrderDesc("bucket_at").
+		Limit(1).
+		LoadOneContext(ctx, &total)
+	if errors.Is(err, dbr.ErrNotFound) {
+		err = dbRunner.
+			Select("COALESCE(SUM(holders.balance), 0)").
+			From(holderBalances(dbRunner, p).As("holders")).
+			LoadOneContext(ctx, &total)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list.Supply = models.TokenAmount(total)
+
+	supply, _ := new(big.Float).SetString(total)
+	for _, row := range rows {
+		holder := &models.AssetHolder{Address: row.Address, Balance: models.TokenAmount(row.Balance)}
+		balance, ok := new(big.Float).SetString(row.Balance)
+		if ok && supply != nil && supply.Sign() > 0 {
+			holder.Percentage, _ = balance.Quo(balance, supply).Mul(balance, big.NewFloat(100)).Float64()
+		}
+		list.Holders = append(list.Holders, holder)
+	}
+	return list, nil
+}
+
+// HolderCountHistory returns the daily number of addresses holding the asset,
+// oldest first. The counts are kept with the supply history.
+func (r *Reader) HolderCountHistory(ctx context.Context, p *params.HolderCountHistoryParams) (*models.HolderCountHistory, error) {
+	dbRunner, err := r.db(ctx).NewSession("holder_count_history", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*struct {
+		BucketAt time.Time
+		Holders  uint64
+	}
+	_, err = p.Apply(dbRunner.
+		Select("supply_history.bucket_at", "supply_history.holders").
+		From(db.TableSupplyHistory).
+		Where("supply_history.asset_id = ?", p.AssetID.String()).
+		OrderAsc("supply_history.bucket_at")).
+		LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &models.HolderCountHistory{
+		AssetID:      models.StringID(p.AssetID.String()),
+		StartTime:    p.ListParams.StartTime,
+		EndTime:      p.ListParams.EndTime,
+		HolderCounts: make([]*models.HolderCount, 0, len(rows)),
+	}
+	for _, row := range rows {
+		history.HolderCounts = append(history.HolderCounts, &models.HolderCount{Date: row.BucketAt, HolderCount: row.Holders})
+	}
+	return history, nil
+}
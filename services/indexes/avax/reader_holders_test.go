@@ -0,0 +1,111 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/services/indexes/params"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+func TestAssetHolders(t *testing.T) {
+	conns, sess := sqlitetest.New(t)
+
+	ctx := context.Background()
+	persist := db.NewPersist()
+	assetID := ids.ID{1}
+	tm := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	// a holds 60 on two chains, b 30, c spent all it received
+	balances := []struct {
+		table   string
+		chainID string
+		address string
+		amount  uint64
+	}{
+		{db.TableAccumulateBalancesReceived, "x", "a", 50},
+		{db.TableAccumulateBalancesReceived, "p", "a", 10},
+		{db.TableAccumulateBalancesReceived, "x", "b", 40},
+		{db.TableAccumulateBalancesSent, "x", "b", 10},
+		{db.TableAccumulateBalancesReceived, "x", "c", 5},
+		{db.TableAccumulateBalancesSent, "x", "c", 5},
+	}
+	for _, b := range balances {
+		v := &db.AccumulateBalancesAmount{ChainID: b.chainID, AssetID: assetID.String(), Address: b.address}
+		v.ComputeID()
+		_, err := sess.InsertInto(b.table).
+			Pair("id", v.ID).
+			Pair("chain_id", v.ChainID).
+			Pair("asset_id", v.AssetID).
+			Pair("address", v.Address).
+			Pair("total_amount", b.amount).
+			Pair("updated_at", tm).
+			ExecContext(ctx)
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	reader := &Reader{conns: conns}
+	holders, err := reader.AssetHolders(ctx, &params.AssetHoldersParams{AssetID: assetID})
+	if err != nil {
+		t.Fatal("holders fail", err)
+	}
+	if holders.HolderCount != 2 || holders.Supply != "90" || len(holders.Holders) != 2 {
+		t.Fatal("unexpected holders", holders)
+	}
+	if a := holders.Holders[0]; a.Address != "a" || a.Balance != "60" || a.Percentage < 66.6 || a.Percentage > 66.7 {
+		t.Fatal("unexpected holder", a)
+	}
+
+	// the share is of the supply once it is computed
+	for _, supply := range []*db.Supply{
+		{AssetID: assetID.String(), BucketAt: tm.Add(-24 * time.Hour), Total: 100, Holders: 1, UpdatedAt: tm},
+		{AssetID: assetID.String(), BucketAt: tm, Total: 120, Holders: 2, UpdatedAt: tm},
+	} {
+		if err := persist.InsertSupply(ctx, sess, supply); err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+	holders, err = reader.AssetHolders(ctx, &params.AssetHoldersParams{
+		ListParams: params.ListParams{Limit: 1, Offset: 1},
+		ChainIDs:   []string{"x"},
+		AssetID:    assetID,
+	})
+	if err != nil {
+		t.Fatal("holders fail", err)
+	}
+	if holders.HolderCount != 2 || holders.Supply != "120" || len(holders.Holders) != 1 {
+		t.Fatal("unexpected holders", holders)
+	}
+	if b := holders.Holders[0]; b.Address != "b" || b.Balance != "30" || b.Percentage != 25 {
+		t.Fatal("unexpected holder", b)
+	}
+
+	history, err := reader.HolderCountHistory(ctx, &params.HolderCountHistoryParams{
+		ListParams: params.ListParams{EndTime: tm.Add(time.Hour)},
+		AssetID:    assetID,
+	})
+	if err != nil {
+		t.Fatal("history fail", err)
+	}
+	if len(history.HolderCounts) != 2 || history.HolderCounts[0].HolderCount != 1 || !history.HolderCounts[1].Date.Equal(tm) {
+		t.Fatal("unexpected holder count history", history.HolderCounts)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/labels"
+	"github.com/chain4travel/magellan/models"
+	"github.com/gocraft/dbr/v2"
+)
+
+func labelColumns(dbRunner dbr.SessionRunner) *dbr.SelectStmt {
+	return dbRunner.
+		Select("address", "name", "category", "notes", "created_at", "updated_at").
+		From(db.TableAddressLabels)
+}
+
+// addressLabels returns the labels of the addresses as stored, keyed by the
+// displayed address. Addresses without a label are left out.
+func addressLabels(ctx context.Context, dbRunner dbr.SessionRunner, addresses []string) (models.AddressLabels, error) {
+	unique := make([]string, 0, len(addresses))
+	seen := make(map[string]struct{}, len(addresses))
+	for _, address := range addresses {
+		if _, ok := seen[address]; ok || address == "" {
+			continue
+		}
+		seen[address] = struct{}{}
+		unique = append(unique, address)
+	}
+	if len(unique) == 0 {
+		return nil, nil
+	}
+
+	var rows []*db.AddressLabel
+	if _, err := labelColumns(dbRunner).Where("address IN ?", unique).LoadContext(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return labelMap(rows), nil
+}
+
+func labelMap(rows []*db.AddressLabel) models.AddressLabels {
+	if len(rows) == 0 {
+		return nil
+	}
+	res := make(models.AddressLabels, len(rows))
+	for _, row := range rows {
+		label := labels.Model(row)
+		res[label.Address] = label
+	}
+	return res
+}
+
+// transactionAddresses returns the addresses of the inputs and outputs of the
+// transactions as stored with their labels
+func transactionAddresses(txs []*models.Transaction) []string {
+	var addresses []string
+	addOutput := func(output *models.Output) {
+		if output == nil {
+			return
+		}
+		for _, address := range output.Addresses {
+			addresses = append(addresses, string(address))
+		}
+		for _, address := range output.CAddresses {
+			addresses = append(addresses, strings.ToLower(address))
+		}
+	}
+	for _, tx := range txs {
+		for _, input := range tx.Inputs {
+			addOutput(input.Output)
+		}
+		for _, output := range tx.Outputs {
+			addOutput(output)
+		}
+	}
+	return addresses
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/labels"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+func TestAddressLabels(t *testing.T) {
+	_, sess := sqlitetest.New(t)
+
+	ctx := context.Background()
+	persist := db.NewPersist()
+	tm := time.Unix(1000, 0).UTC()
+	exchange := models.ToAddress(ids.ShortID{1})
+	bridge := "0x00000000000000000000000000000000000000ab"
+
+	for _, label := range []*db.AddressLabel{
+		{Address: string(exchange), Name: "exchange", Category: models.LabelCategoryExchange, CreatedAt: tm, UpdatedAt: tm},
+		{Address: bridge, Name: "bridge", Category: models.LabelCategoryBridge, CreatedAt: tm, UpdatedAt: tm},
+	} {
+		if err := persist.InsertAddressLabel(ctx, sess, label); err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	txs := []*models.Transaction{{
+		Inputs: []*models.Input{{Output: &models.Output{Addresses: []models.Address{exchange}}}},
+		Outputs: []*models.Output{
+			{Addresses: []models.Address{models.ToAddress(ids.ShortID{2})}},
+			{CAddresses: []string{"0x00000000000000000000000000000000000000AB"}},
+		},
+	}}
+	found, err := addressLabels(ctx, sess, transactionAddresses(txs))
+	if err != nil {
+		t.Fatal("labels fail", err)
+	}
+	if len(found) != 2 || found[bridge] == nil || found[bridge].Name != "bridge" {
+		t.Fatal("unexpected labels", found)
+	}
+	if label := found[labels.DisplayAddress(string(exchange))]; label == nil || label.Category != models.LabelCategoryExchange {
+		t.Fatal("unexpected exchange label", label)
+	}
+
+	if found, err = addressLabels(ctx, sess, nil); err != nil || found != nil {
+		t.Fatal("unexpected labels", found, err)
+	}
+}
@@ -26,9 +26,19 @@ import (
 )
 
 func (r *Reader) ListCBlocks(ctx context.Context, p *params.ListCBlocksParams) (*models.CBlockList, error) {
+	var list *models.CBlockList
+	err := r.lookup(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		list, err = r.listCBlocks(ctx, p)
+		return err == nil && (len(list.Blocks) > 0 || len(list.Transactions) > 0), err
+	})
+	return list, err
+}
+
+func (r *Reader) listCBlocks(ctx context.Context, p *params.ListCBlocksParams) (*models.CBlockList, error) {
 	fmtHex := func(n uint64) string { return "0x" + strconv.FormatUint(n, 16) }
 
-	dbRunner, err := r.conns.DB().NewSession("list_cblocks", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("list_cblocks", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -40,9 +50,12 @@ func (r *Reader) ListCBlocks(ctx context.Context, p *params.ListCBlocksParams) (
 		var blockList []*db.CvmBlocks
 
 		sq := dbRunner.Select(
+			"block",
+			"hash",
 			"evm_tx",
 			"atomic_tx",
 			"serialization",
+			"created_at",
 		).
 			From(db.TableCvmBlocks)
 
@@ -72,8 +85,15 @@ func (r *Reader) ListCBlocks(ctx context.Context, p *params.ListCBlocksParams) (
 
 		result.Blocks = make([]*models.CBlockHeaderBase, len(blockList))
 		for i, block := range blockList {
-			err = json.Unmarshal(block.Serialization, &result.Blocks[i])
-			if err != nil {
+			if len(block.Serialization) == 0 {
+				// the serialization has been pruned, only the indexed fields are left
+				number, _ := strconv.ParseUint(block.Block, 10, 64)
+				result.Blocks[i] = &models.CBlockHeaderBase{
+					Hash:   block.Hash,
+					Number: fmtHex(number),
+					Time:   fmtHex(uint64(block.CreatedAt.Unix())),
+				}
+			} else if err = json.Unmarshal(block.Serialization, &result.Blocks[i]); err != nil {
 				return nil, err
 			}
 			result.Blocks[i].EvmTx = block.EvmTx
@@ -86,7 +106,11 @@ func (r *Reader) ListCBlocks(ctx context.Context, p *params.ListCBlocksParams) (
 		var txList []*struct {
 			Serialization []byte
 			CreatedAt     time.Time
+			Hash          string
+			Nonce         uint64
+			Amount        uint64
 			FromAddr      string
+			ToAddr        string
 			Block         uint64
 			Idx           uint64
 			Status        uint16
@@ -97,7 +121,11 @@ func (r *Reader) ListCBlocks(ctx context.Context, p *params.ListCBlocksParams) (
 		sq := dbRunner.Select(
 			"serialization",
 			"created_at",
+			"hash",
+			"nonce",
+			"amount",
 			"F.address AS from_addr",
+			"COALESCE(T.address, '') AS to_addr",
 			"block",
 			"idx",
 			"status",
@@ -106,7 +134,8 @@ func (r *Reader) ListCBlocks(ctx context.Context, p *params.ListCBlocksParams) (
 			"block_idx",
 		).
 			From(db.TableCvmTransactionsTxdata).
-			LeftJoin(dbr.I(db.TableCvmAccounts).As("F"), "id_from_addr = F.id")
+			LeftJoin(dbr.I(db.TableCvmAccounts).As("F"), "id_from_addr = F.id").
+			LeftJoin(dbr.I(db.TableCvmAccounts).As("T"), "id_to_addr = T.id")
 
 		if p.ListParams.StartTimeProvided {
 			sq = sq.Where("created_at >= ?", p.ListParams.StartTime)
@@ -144,8 +173,15 @@ func (r *Reader) ListCBlocks(ctx context.Context, p *params.ListCBlocksParams) (
 		result.Transactions = make([]*models.CTransactionDataBase, len(txList))
 		for i, tx := range txList {
 			dest := &result.Transactions[i]
-			err = json.Unmarshal(tx.Serialization, dest)
-			if err != nil {
+			if len(tx.Serialization) == 0 {
+				// the serialization has been pruned, only the indexed fields are left
+				*dest = &models.CTransactionDataBase{
+					Hash:   tx.Hash,
+					Nonce:  fmtHex(tx.Nonce),
+					Amount: fmtHex(tx.Amount),
+					To:     tx.ToAddr,
+				}
+			} else if err = json.Unmarshal(tx.Serialization, dest); err != nil {
 				return nil, err
 			}
 			(*dest).Block = fmtHex(tx.Block)
@@ -162,7 +198,7 @@ func (r *Reader) ListCBlocks(ctx context.Context, p *params.ListCBlocksParams) (
 }
 
 func (r *Reader) AverageBlockSizeReader(ctx context.Context, p *params.ListParams) ([]*models.AverageBlockSize, error) {
-	dbRunner, err := r.conns.DB().NewSession("average_block_size", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("average_block_size", cfg.RequestTimeout)
 	var averageBlockSizeData []*models.AverageBlockSize
 	var baseq *dbr.SelectStmt
 	var dateFormat string
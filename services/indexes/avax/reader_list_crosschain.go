@@ -0,0 +1,106 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+)
+
+// ListCrossChainTransfers lists the outputs exported to another chain, newest
+// first, with the import which consumed them if there is one
+func (r *Reader) ListCrossChainTransfers(ctx context.Context, p *params.ListCrossChainTransfersParams) (*models.CrossChainTransferList, error) {
+	dbRunner, err := r.db(ctx).NewSession("list_cross_chain_transfers", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*db.CrossChainTransfer
+	_, err = p.Apply(dbRunner.
+		Select(
+			"id",
+			"export_tx_id",
+			"import_tx_id",
+			"source_chain_id",
+			"destination_chain_id",
+			"asset_id",
+			"amount",
+			"exported_at",
+			"imported_at",
+			"created_at",
+		).
+		From(db.TableCrossChainTransfers).
+		OrderDesc("created_at").
+		OrderAsc("id")).
+		LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.CrossChainTransferList{Transfers: make([]*models.CrossChainTransfer, 0, len(rows))}
+	if len(rows) == 0 {
+		return list, nil
+	}
+	transfers := make(map[string]*models.CrossChainTransfer, len(rows))
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		transfer := &models.CrossChainTransfer{
+			UTXOID:             models.StringID(row.ID),
+			ExportTxID:         models.StringID(row.ExportTxID),
+			ImportTxID:         models.StringID(row.ImportTxID),
+			SourceChainID:      models.StringID(row.SourceChainID),
+			DestinationChainID: models.StringID(row.DestinationChainID),
+			AssetID:            models.StringID(row.AssetID),
+			Amount:             models.TokenAmountForUint64(row.Amount),
+			Addresses:          []models.Address{},
+			Status:             models.CrossChainTransferPending,
+			ExportedAt:         row.ExportedAt,
+			ImportedAt:         row.ImportedAt,
+		}
+		if row.ImportTxID != "" {
+			transfer.Status = models.CrossChainTransferCompleted
+		}
+		if row.ExportedAt != nil && row.ImportedAt != nil {
+			latency := row.ImportedAt.Sub(*row.ExportedAt).Seconds()
+			transfer.Latency = &latency
+		}
+		transfers[row.ID] = transfer
+		ids = append(ids, row.ID)
+		list.Transfers = append(list.Transfers, transfer)
+	}
+
+	var addresses []*struct {
+		OutputID string
+		Address  string
+	}
+	_, err = dbRunner.
+		Select("output_id", "address").
+		From("avm_output_addresses").
+		Where("output_id IN ?", ids).
+		OrderAsc("address").
+		LoadContext(ctx, &addresses)
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range addresses {
+		transfer := transfers[address.OutputID]
+		transfer.Addresses = append(transfer.Addresses, models.Address(address.Address))
+	}
+
+	return list, nil
+}
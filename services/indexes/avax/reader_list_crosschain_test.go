@@ -0,0 +1,86 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+func TestListCrossChainTransfers(t *testing.T) {
+	conns, sess := sqlitetest.New(t)
+
+	ctx := context.Background()
+	persist := db.NewPersist()
+	exportedAt := time.Unix(1000, 0).UTC()
+	importedAt := exportedAt.Add(90 * time.Second)
+	owner := ids.ShortID{1}
+
+	// the import of utxo1 is indexed before its export, utxo2 is never
+	// imported
+	for _, err := range []error{
+		persist.InsertCrossChainTransferImport(ctx, sess, &db.CrossChainTransfer{
+			ID: "utxo1", ImportTxID: "import", SourceChainID: "x", DestinationChainID: "p",
+			AssetID: "asset", Amount: 10, ImportedAt: &importedAt, CreatedAt: importedAt,
+		}),
+		persist.InsertCrossChainTransferExport(ctx, sess, &db.CrossChainTransfer{
+			ID: "utxo1", ExportTxID: "export", SourceChainID: "x", DestinationChainID: "p",
+			AssetID: "asset", Amount: 10, ExportedAt: &exportedAt, CreatedAt: exportedAt,
+		}),
+		persist.InsertCrossChainTransferExport(ctx, sess, &db.CrossChainTransfer{
+			ID: "utxo2", ExportTxID: "export", SourceChainID: "x", DestinationChainID: "p",
+			AssetID: "asset", Amount: 20, ExportedAt: &exportedAt, CreatedAt: exportedAt,
+		}),
+		persist.InsertOutputAddresses(ctx, sess, &db.OutputAddresses{
+			OutputID: "utxo2", Address: owner.String(), CreatedAt: exportedAt,
+		}, false),
+	} {
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	reader := &Reader{conns: conns}
+	list, err := reader.ListCrossChainTransfers(ctx, &params.ListCrossChainTransfersParams{})
+	if err != nil {
+		t.Fatal("list fail", err)
+	}
+	if len(list.Transfers) != 2 {
+		t.Fatal("unexpected transfers", len(list.Transfers))
+	}
+	completed := list.Transfers[0]
+	if completed.UTXOID != "utxo1" || completed.ExportTxID != "export" || completed.ImportTxID != "import" ||
+		completed.Status != models.CrossChainTransferCompleted || completed.Latency == nil || *completed.Latency != 90 {
+		t.Fatal("unexpected completed transfer", completed)
+	}
+
+	pending, err := reader.ListCrossChainTransfers(ctx, &params.ListCrossChainTransfersParams{
+		Addresses: []ids.ShortID{owner},
+		Status:    models.CrossChainTransferPending,
+	})
+	if err != nil {
+		t.Fatal("list fail", err)
+	}
+	if len(pending.Transfers) != 1 || pending.Transfers[0].UTXOID != "utxo2" ||
+		len(pending.Transfers[0].Addresses) != 1 || pending.Transfers[0].Latency != nil {
+		t.Fatal("unexpected pending transfers", pending.Transfers)
+	}
+}
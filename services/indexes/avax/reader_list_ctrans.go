@@ -14,6 +14,7 @@ import (
 	"context"
 	"encoding/hex"
 	"math/big"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +29,16 @@ import (
 )
 
 func (r *Reader) ListCTransactions(ctx context.Context, p *params.ListCTransactionsParams) (*models.CTransactionList, error) {
+	var list *models.CTransactionList
+	err := r.lookup(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		list, err = r.listCTransactions(ctx, p)
+		return err == nil && len(list.Transactions) > 0, err
+	})
+	return list, err
+}
+
+func (r *Reader) listCTransactions(ctx context.Context, p *params.ListCTransactionsParams) (*models.CTransactionList, error) {
 	toCTransactionData := func(t *types.Transaction) *models.CTransactionData {
 		res := &models.CTransactionData{}
 		res.Type = int(t.Type())
@@ -79,14 +90,19 @@ func (r *Reader) ListCTransactions(ctx context.Context, p *params.ListCTransacti
 		return res
 	}
 
-	dbRunner, err := r.conns.DB().NewSession("list_ctransactions", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("list_ctransactions", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
 
 	type TxData struct {
 		Block         string
+		Hash          string
+		Nonce         uint64
+		Amount        uint64
+		GasPrice      uint64
 		FromAddr      string
+		ToAddr        string
 		Serialization []byte
 		Receipt       []byte
 		CreatedAt     time.Time
@@ -96,12 +112,18 @@ func (r *Reader) ListCTransactions(ctx context.Context, p *params.ListCTransacti
 
 	sq := dbRunner.Select(
 		"block",
+		db.TableCvmTransactionsTxdata+".hash",
+		"nonce",
+		"amount",
+		"gas_price",
 		"F.address AS from_addr",
+		"COALESCE(T.address, '') AS to_addr",
 		"serialization",
 		"receipt",
 		"created_at",
 	).From(db.TableCvmTransactionsTxdata).
-		LeftJoin(dbr.I(db.TableCvmAccounts).As("F"), "id_from_addr=id")
+		LeftJoin(dbr.I(db.TableCvmAccounts).As("F"), "id_from_addr=F.id").
+		LeftJoin(dbr.I(db.TableCvmAccounts).As("T"), "id_to_addr=T.id")
 
 	r.listCTransFilter(p, dbRunner, sq)
 	if len(p.Hashes) > 0 {
@@ -118,17 +140,31 @@ func (r *Reader) ListCTransactions(ctx context.Context, p *params.ListCTransacti
 
 	trItems := make([]*models.CTransactionData, 0, len(dataList))
 	for _, txdata := range dataList {
-		var tr types.Transaction
-		err := tr.UnmarshalJSON(txdata.Serialization)
-		if err != nil {
-			return nil, err
+		var ctr *models.CTransactionData
+		if len(txdata.Serialization) == 0 {
+			// the serialization has been pruned, only the indexed fields are left
+			amount := strconv.FormatUint(txdata.Amount, 10)
+			gasPrice := strconv.FormatUint(txdata.GasPrice, 10)
+			ctr = &models.CTransactionData{
+				Hash:     txdata.Hash,
+				Nonce:    txdata.Nonce,
+				GasPrice: &gasPrice,
+				Amount:   &amount,
+				ToAddr:   txdata.ToAddr,
+			}
+		} else {
+			var tr types.Transaction
+			err := tr.UnmarshalJSON(txdata.Serialization)
+			if err != nil {
+				return nil, err
+			}
+			ctr = toCTransactionData(&tr)
 		}
 		receipt := &modelsc.ExtendedReceipt{}
 		err = receipt.UnmarshalJSON(txdata.Receipt)
 		if err != nil {
 			return nil, err
 		}
-		ctr := toCTransactionData(&tr)
 		ctr.Block = txdata.Block
 		ctr.CreatedAt = txdata.CreatedAt
 		ctr.FromAddr = txdata.FromAddr
@@ -137,10 +173,20 @@ func (r *Reader) ListCTransactions(ctx context.Context, p *params.ListCTransacti
 	}
 	listParamsOriginal := p.ListParams
 
+	addresses := make([]string, 0, 2*len(trItems))
+	for _, ctr := range trItems {
+		addresses = append(addresses, strings.ToLower(ctr.FromAddr), strings.ToLower(ctr.ToAddr))
+	}
+	labels, err := addressLabels(ctx, dbRunner, addresses)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.CTransactionList{
 		Transactions: trItems,
 		StartTime:    listParamsOriginal.StartTime,
 		EndTime:      listParamsOriginal.EndTime,
+		Labels:       labels,
 	}, nil
 }
 
@@ -92,7 +92,7 @@ func (r *Reader) listTxsQuery(baseStmt *dbr.SelectStmt, p *params.ListTransactio
 }
 
 func (r *Reader) listTxFromCache(p *params.ListTransactionsParams) *models.Transaction {
-	if !r.sc.IsAggregateCache {
+	if !r.sc.IsAggregateCache() {
 		return nil
 	}
 	if len(p.ListParams.Values) != 0 {
@@ -112,7 +112,7 @@ func (r *Reader) listTxFromCache(p *params.ListTransactionsParams) *models.Trans
 }
 
 func (r *Reader) listTxsFromCache(p *params.ListTransactionsParams) ([]*models.Transaction, bool) {
-	if !r.sc.IsAggregateCache || p.ListParams.Limit == 0 || p.ListParams.Offset != 0 {
+	if !r.sc.IsAggregateCache() || p.ListParams.Limit == 0 || p.ListParams.Offset != 0 {
 		return nil, false
 	}
 
@@ -259,7 +259,7 @@ func (r *Reader) listTxs(
 }
 
 func (r *Reader) ListTransactions(ctx context.Context, p *params.ListTransactionsParams, avaxAssetID ids.ID) (*models.TransactionList, error) {
-	dbRunner, err := r.conns.DB().NewSession("get_transactions", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("get_transactions", cfg.RequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -287,6 +287,11 @@ func (r *Reader) ListTransactions(ctx context.Context, p *params.ListTransaction
 
 	next := r.transactionProcessNext(txs, listParamsOriginal, p)
 
+	labels, err := addressLabels(ctx, dbRunner, transactionAddresses(txs))
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.TransactionList{ListMetadata: models.ListMetadata{
 		Count: count,
 	},
@@ -294,6 +299,7 @@ func (r *Reader) ListTransactions(ctx context.Context, p *params.ListTransaction
 		StartTime:    listParamsOriginal.StartTime,
 		EndTime:      listParamsOriginal.EndTime,
 		Next:         next,
+		Labels:       labels,
 	}, nil
 }
 
@@ -716,7 +722,7 @@ func selectOutputs(dbRunner dbr.SessionRunner, redeem bool) *dbr.SelectBuilder {
 }
 
 func (r *Reader) DailyTransactions(ctx context.Context, p *params.ListParams) (*models.StatisticsStruct, error) {
-	dbRunner, err := r.conns.DB().NewSession("daily_transactions", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("daily_transactions", cfg.RequestTimeout)
 	var transactionData []*models.TransactionsInfo
 	var statistics *models.StatisticsStruct
 	var baseq *dbr.SelectStmt
@@ -765,7 +771,7 @@ func (r *Reader) DailyTransactions(ctx context.Context, p *params.ListParams) (*
 }
 
 func (r *Reader) GasUsedPerDay(ctx context.Context, p *params.ListParams) (models.StatisticsStruct, error) {
-	dbRunner, err := r.conns.DB().NewSession("gas_used_per_day", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("gas_used_per_day", cfg.RequestTimeout)
 	var baseq *dbr.SelectStmt
 	var dateFormat string
 	if err != nil {
@@ -823,7 +829,7 @@ func (r *Reader) GasUsedPerDay(ctx context.Context, p *params.ListParams) (model
 }
 
 func (r *Reader) AvgGasPriceUsed(ctx context.Context, p *params.ListParams) (models.StatisticsStruct, error) {
-	dbRunner, err := r.conns.DB().NewSession("avg_gas_price", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("avg_gas_price", cfg.RequestTimeout)
 	var baseq *dbr.SelectStmt
 	var dateFormat string
 	if err != nil {
@@ -874,7 +880,7 @@ func (r *Reader) AvgGasPriceUsed(ctx context.Context, p *params.ListParams) (mod
 }
 
 func (r *Reader) DailyTokenTransfer(ctx context.Context, p *params.ListParams) ([]*models.TransactionsPerDate, error) {
-	dbRunner, err := r.conns.DB().NewSession("daily_token", cfg.RequestTimeout)
+	dbRunner, err := r.db(ctx).NewSession("daily_token", cfg.RequestTimeout)
 	var baseq *dbr.SelectStmt
 	var dateFormat string
 	ether := 1e18
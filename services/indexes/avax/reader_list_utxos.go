@@ -0,0 +1,125 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+)
+
+// ListUTXOs lists the unspent outputs of the addresses with their lock state,
+// oldest first, so wallets can select the coins to spend
+func (r *Reader) ListUTXOs(ctx context.Context, p *params.ListUTXOsParams) (*models.UTXOList, error) {
+	dbRunner, err := r.db(ctx).NewSession("list_utxos", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	var rows []*struct {
+		db.Outputs
+		Atomic bool
+	}
+	_, err = p.Apply(dbRunner.
+		Select(
+			"avm_outputs.id",
+			"avm_outputs.transaction_id",
+			"avm_outputs.output_index",
+			"avm_outputs.chain_id",
+			"avm_outputs.asset_id",
+			"avm_outputs.output_type",
+			"avm_outputs.amount",
+			"avm_outputs.threshold",
+			"avm_outputs.locktime",
+			"avm_outputs.stake_locktime",
+			"avm_outputs.stake",
+			"avm_outputs.frozen",
+			"avm_outputs.deposit_tx_id",
+			"avm_outputs.bond_tx_id",
+			"avm_outputs.created_at",
+			"CASE WHEN cross_chain_transfers.id IS NULL THEN 0 ELSE 1 END AS atomic",
+		).
+		From(db.TableOutputs).
+		LeftJoin(db.TableCrossChainTransfers, "cross_chain_transfers.id = avm_outputs.id").
+		OrderAsc("avm_outputs.created_at").
+		OrderAsc("avm_outputs.id"), now).
+		LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.UTXOList{UTXOs: make([]*models.UTXO, 0, len(rows))}
+	if len(rows) == 0 {
+		return list, nil
+	}
+	utxos := make(map[string]*models.UTXO, len(rows))
+	outputIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		utxo := &models.UTXO{
+			ID:            models.StringID(row.ID),
+			TransactionID: models.StringID(row.TransactionID),
+			OutputIndex:   uint64(row.OutputIndex),
+			ChainID:       models.StringID(row.ChainID),
+			AssetID:       models.StringID(row.AssetID),
+			OutputType:    row.OutputType,
+			Amount:        models.TokenAmountForUint64(row.Amount),
+			Addresses:     []models.Address{},
+			Threshold:     uint64(row.Threshold),
+			Locktime:      row.Locktime,
+			StakeLocktime: row.StakeLocktime,
+			Stake:         row.Stake,
+			Frozen:        row.Frozen,
+			DepositTxID:   models.StringID(row.DepositTxID),
+			BondTxID:      models.StringID(row.BondTxID),
+			Atomic:        row.Atomic,
+			CreatedAt:     row.CreatedAt,
+		}
+		switch row.OutputType {
+		case models.OutputTypesLockedOutD:
+			utxo.LockType = models.LockTypeDeposited
+		case models.OutputTypesLockedOutB:
+			utxo.LockType = models.LockTypeBonded
+		case models.OutputTypesLockedOutDB:
+			utxo.LockType = models.LockTypeDepositedBonded
+		}
+		unix := uint64(now.Unix())
+		utxo.Spendable = utxo.LockType == "" && !row.Stake && !row.Frozen && !row.Atomic &&
+			row.Locktime <= unix && row.StakeLocktime <= unix
+		utxos[row.ID] = utxo
+		outputIDs = append(outputIDs, row.ID)
+		list.UTXOs = append(list.UTXOs, utxo)
+	}
+
+	var addresses []*db.OutputAddresses
+	_, err = dbRunner.
+		Select("output_id", "address").
+		From("avm_output_addresses").
+		Where("output_id IN ?", outputIDs).
+		OrderAsc("address").
+		LoadContext(ctx, &addresses)
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range addresses {
+		utxo := utxos[address.OutputID]
+		utxo.Addresses = append(utxo.Addresses, models.Address(address.Address))
+	}
+
+	return list, nil
+}
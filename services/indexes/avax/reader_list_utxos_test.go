@@ -0,0 +1,96 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+func TestListUTXOs(t *testing.T) {
+	conns, sess := sqlitetest.New(t)
+
+	ctx := context.Background()
+	persist := db.NewPersist()
+	owner := ids.ShortID{1}
+	tm := time.Unix(1000, 0).UTC()
+	future := uint64(time.Now().Add(time.Hour).Unix())
+
+	outputs := []*db.Outputs{
+		{ID: "free", TransactionID: "tx", OutputIndex: 0, Amount: 1, Threshold: 1},
+		{ID: "spent", TransactionID: "tx", OutputIndex: 1, Amount: 2, Threshold: 1},
+		{ID: "deposited", TransactionID: "tx", OutputIndex: 2, Amount: 3, Threshold: 1, OutputType: models.OutputTypesLockedOutD, DepositTxID: "deposit"},
+		{ID: "timelocked", TransactionID: "tx", OutputIndex: 3, Amount: 4, Threshold: 1, Locktime: future},
+		{ID: "exported", TransactionID: "tx", OutputIndex: 4, Amount: 5, Threshold: 1},
+	}
+	for i, output := range outputs {
+		output.ChainID = "p"
+		output.AssetID = "asset"
+		output.CreatedAt = tm.Add(time.Duration(i) * time.Second)
+		if output.OutputType == 0 {
+			output.OutputType = models.OutputTypesSECP2556K1Transfer
+		}
+		if err := persist.InsertOutputs(ctx, sess, output, false); err != nil {
+			t.Fatal("insert fail", err)
+		}
+		err := persist.InsertOutputAddresses(ctx, sess, &db.OutputAddresses{OutputID: output.ID, Address: owner.String(), CreatedAt: tm}, false)
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+	for _, err := range []error{
+		persist.InsertOutputsRedeeming(ctx, sess, &db.OutputsRedeeming{
+			ID: "spent", RedeemedAt: tm, RedeemingTransactionID: "tx2", Intx: "tx", AssetID: "asset", ChainID: "p", CreatedAt: tm,
+		}, false),
+		persist.InsertCrossChainTransferExport(ctx, sess, &db.CrossChainTransfer{
+			ID: "exported", ExportTxID: "tx", SourceChainID: "x", DestinationChainID: "p", AssetID: "asset", Amount: 5, ExportedAt: &tm, CreatedAt: tm,
+		}),
+	} {
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	reader := &Reader{conns: conns}
+	list, err := reader.ListUTXOs(ctx, &params.ListUTXOsParams{Addresses: []ids.ShortID{owner}})
+	if err != nil {
+		t.Fatal("list fail", err)
+	}
+	if len(list.UTXOs) != 4 {
+		t.Fatal("unexpected utxos", len(list.UTXOs))
+	}
+	deposited := list.UTXOs[1]
+	if deposited.ID != "deposited" || deposited.LockType != models.LockTypeDeposited ||
+		deposited.DepositTxID != "deposit" || deposited.Spendable {
+		t.Fatal("unexpected deposited utxo", deposited)
+	}
+	if !list.UTXOs[0].Spendable || list.UTXOs[2].Spendable || !list.UTXOs[3].Atomic || list.UTXOs[3].Spendable {
+		t.Fatal("unexpected spendability", list.UTXOs[0], list.UTXOs[2], list.UTXOs[3])
+	}
+
+	spendable, err := reader.ListUTXOs(ctx, &params.ListUTXOsParams{Addresses: []ids.ShortID{owner}, Spendable: true})
+	if err != nil {
+		t.Fatal("list fail", err)
+	}
+	if len(spendable.UTXOs) != 1 || spendable.UTXOs[0].ID != "free" || len(spendable.UTXOs[0].Addresses) != 1 {
+		t.Fatal("unexpected spendable utxos", spendable.UTXOs)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/utils"
+	"github.com/gocraft/dbr/v2"
+)
+
+// testPostgresDSNEnv names the dsn of a migrated postgres database, the
+// postgres tests are skipped without it
+const testPostgresDSNEnv = "MAGELLAN_TEST_POSTGRES_DSN"
+
+func newTestPostgres(t *testing.T) (*utils.Connections, *dbr.Session) {
+	dsn := os.Getenv(testPostgresDSNEnv)
+	if dsn == "" {
+		t.Skip(testPostgresDSNEnv + " not set")
+	}
+	conns, err := utils.NewDBFromConfig(cfg.Services{
+		DB: &cfg.DB{
+			Driver: utils.DriverPostgres,
+			DSN:    dsn,
+		},
+	}, false)
+	if err != nil {
+		t.Fatal("db fail", err)
+	}
+	t.Cleanup(func() { _ = conns.Close() })
+
+	sess, err := conns.DB().NewSession("test", time.Minute)
+	if err != nil {
+		t.Fatal("session fail", err)
+	}
+	return conns, sess
+}
+
+func TestSumAggregateRollupsPostgres(t *testing.T) {
+	_, sess := newTestPostgres(t)
+
+	ctx := context.Background()
+	persist := db.NewPersist()
+	chainID := "postgres_rollups"
+	for _, table := range []string{db.TableAggregateRollupsHourly, db.TableAggregateRollupsDaily} {
+		if _, err := sess.DeleteFrom(table).Where("chain_id = ?", chainID).ExecContext(ctx); err != nil {
+			t.Fatal("delete fail", err)
+		}
+	}
+
+	day := time.Unix(24*3600*1000, 0).UTC()
+	for _, err := range []error{
+		persist.InsertAggregateRollupHourly(ctx, sess, &db.AggregateRollup{
+			ChainID: chainID, BucketAt: day.Add(-time.Hour), TransactionCount: 1, Txfee: 10, UpdatedAt: day,
+		}, false),
+		persist.InsertAggregateRollupDaily(ctx, sess, &db.AggregateRollup{
+			ChainID: chainID, BucketAt: day, TransactionCount: 20, Txfee: 200, UpdatedAt: day,
+		}, false),
+		persist.InsertAggregateRollupHourly(ctx, sess, &db.AggregateRollup{
+			ChainID: chainID, BucketAt: day.Add(24 * time.Hour), TransactionCount: 3, Txfee: 30, UpdatedAt: day,
+		}, false),
+	} {
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	totals, err := sumAggregateRollups(ctx, sess, []string{chainID}, day.Add(-time.Hour), day.Add(25*time.Hour))
+	if err != nil {
+		t.Fatal("sum fail", err)
+	}
+	if totals.TransactionCount != 24 || totals.Txfee != 240 {
+		t.Fatal("unexpected totals", totals)
+	}
+
+	var ts int64
+	err = sess.
+		Select(db.UnixTimestamp(sess.Dialect, "MAX(bucket_at)")).
+		From(db.TableAggregateRollupsHourly).
+		Where("chain_id = ?", chainID).
+		LoadOneContext(ctx, &ts)
+	if err != nil {
+		t.Fatal("select fail", err)
+	}
+	if ts != day.Add(24*time.Hour).Unix() {
+		t.Fatal("unexpected timestamp", ts)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/services/indexes/params"
+	"github.com/gocraft/dbr/v2"
+)
+
+type supplyRow struct {
+	db.Supply
+	Denomination uint8
+}
+
+func supplyColumns(b *dbr.Session) *dbr.SelectStmt {
+	return b.
+		Select(
+			"supply_history.asset_id",
+			"supply_history.bucket_at",
+			"supply_history.total",
+			"supply_history.locked",
+			"supply_history.deposited",
+			"supply_history.bonded",
+			"supply_history.burned",
+			"supply_history.genesis",
+			"supply_history.holders",
+			"supply_history.updated_at",
+			"COALESCE(avm_assets.denomination, 0) AS denomination",
+		).
+		From(db.TableSupplyHistory).
+		LeftJoin(db.TableAssets, "avm_assets.id = supply_history.asset_id")
+}
+
+func supplyModel(row *supplyRow) *models.Supply {
+	unlocked := uint64(0)
+	if row.Locked < row.Total {
+		unlocked = row.Total - row.Locked
+	}
+	return &models.Supply{
+		AssetID:      models.StringID(row.AssetID),
+		Denomination: row.Denomination,
+		Date:         row.BucketAt,
+		Total:        models.TokenAmountForUint64(row.Total),
+		Locked:       models.TokenAmountForUint64(row.Locked),
+		Deposited:    models.TokenAmountForUint64(row.Deposited),
+		Bonded:       models.TokenAmountForUint64(row.Bonded),
+		Unlocked:     models.TokenAmountForUint64(unlocked),
+		Burned:       models.TokenAmountForUint64(row.Burned),
+		Genesis:      models.TokenAmountForUint64(row.Genesis),
+		Holders:      row.Holders,
+		UpdatedAt:    row.UpdatedAt,
+	}
+}
+
+// Supply returns the latest supply of the asset, or of the native asset if
+// the params don't name one
+func (r *Reader) Supply(ctx context.Context, p *params.SupplyParams, avaxAssetID ids.ID) (*models.Supply, error) {
+	dbRunner, err := r.db(ctx).NewSession("supply", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	assetID := avaxAssetID
+	if p.AssetID != nil {
+		assetID = *p.AssetID
+	}
+	row := &supplyRow{}
+	err = supplyColumns(dbRunner).
+		Where("supply_history.asset_id = ?", assetID.String()).
+		OrderDesc("supply_history.bucket_at").
+		Limit(1).
+		LoadOneContext(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	return supplyModel(row), nil
+}
+
+// SupplyHistory returns the daily supplies of the asset, or of the native
+// asset if the params don't name one, oldest first
+func (r *Reader) SupplyHistory(ctx context.Context, p *params.SupplyHistoryParams, avaxAssetID ids.ID) (*models.SupplyHistory, error) {
+	dbRunner, err := r.db(ctx).NewSession("supply_history", cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	assetID := avaxAssetID
+	if p.AssetID != nil {
+		assetID = *p.AssetID
+	}
+	var rows []*supplyRow
+	_, err = p.Apply(supplyColumns(dbRunner).
+		Where("supply_history.asset_id = ?", assetID.String()).
+		OrderAsc("supply_history.bucket_at")).
+		LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &models.SupplyHistory{
+		AssetID:   models.StringID(assetID.String()),
+		StartTime: p.ListParams.StartTime,
+		EndTime:   p.ListParams.EndTime,
+		Supplies:  make([]*models.Supply, 0, len(rows)),
+	}
+	for _, row := range rows {
+		history.Supplies = append(history.Supplies, supplyModel(row))
+	}
+	return history, nil
+}
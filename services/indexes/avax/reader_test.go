@@ -15,10 +15,12 @@ package avax
 
 import (
 	"context"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/chain4travel/magellan/caching"
 	"github.com/chain4travel/magellan/cfg"
@@ -28,7 +30,11 @@ import (
 	"github.com/chain4travel/magellan/services/indexes/params"
 	"github.com/chain4travel/magellan/servicesctrl"
 	"github.com/chain4travel/magellan/utils"
+	"github.com/gocraft/dbr/v2"
 	"github.com/stretchr/testify/require"
+
+	// sqlite driver, registered as utils.SqliteDriverName
+	_ "github.com/chain4travel/magellan/utils/sqlite"
 )
 
 func TestCollectInsAndOuts(t *testing.T) {
@@ -152,9 +158,6 @@ func TestAggregates(t *testing.T) {
 	_, _ = sessOuts.DeleteFrom("avm_outputs").ExecContext(ctx)
 
 	timeNow := time.Now().UTC().Truncate(1 * time.Second)
-	yesterdayDateTime := time.Now().UTC().AddDate(0, 0, -1)
-	prevWeekDateTime := time.Now().UTC().AddDate(0, 0, -7)
-	prevMonthDateTime := time.Now().UTC().AddDate(0, -1, 0)
 
 	// Add transaction and output to test last days' aggregates
 	transaction := &db.Transactions{
@@ -165,35 +168,16 @@ func TestAggregates(t *testing.T) {
 		CreatedAt: timeNow.Add(-1 * time.Hour),
 	}
 	_ = persist.InsertTransactions(ctx, sessTx, transaction, false)
+	assetID := ids.ID{1}
 	output := &db.Outputs{
 		ID:            "id1",
 		TransactionID: "id1",
 		ChainID:       "cid",
+		AssetID:       assetID.String(),
 		CreatedAt:     timeNow.Add(-1 * time.Hour),
 	}
 	_ = persist.InsertOutputs(ctx, sessOuts, output, false)
 
-	err := reader.sc.AggregatesCache.GetAggregatesFeesAndUpdate(
-		reader.sc.Chains,
-		reader.conns,
-		"cid",
-		yesterdayDateTime,
-		timeNow.Add(1*time.Hour),
-		"day")
-	if err != nil {
-		t.Error("error", err)
-	}
-	err = reader.sc.AggregatesCache.GetAggregatesAndUpdate(
-		reader.sc.Chains,
-		reader.conns,
-		"cid",
-		yesterdayDateTime,
-		timeNow.Add(1*time.Hour),
-		"day")
-	if err != nil {
-		t.Error("error", err)
-	}
-
 	// Add transaction and output to test last week's aggregates
 	transaction = &db.Transactions{
 		ID:        "id2",
@@ -211,27 +195,6 @@ func TestAggregates(t *testing.T) {
 	}
 	_ = persist.InsertOutputs(ctx, sessOuts, output, false)
 
-	err = reader.sc.AggregatesCache.GetAggregatesFeesAndUpdate(
-		reader.sc.Chains,
-		reader.conns,
-		"cid",
-		prevWeekDateTime,
-		timeNow.Add(1*time.Hour),
-		"week")
-	if err != nil {
-		t.Error("error", err)
-	}
-	err = reader.sc.AggregatesCache.GetAggregatesAndUpdate(
-		reader.sc.Chains,
-		reader.conns,
-		"cid",
-		prevWeekDateTime,
-		timeNow.Add(1*time.Hour),
-		"week")
-	if err != nil {
-		t.Error("error", err)
-	}
-
 	// Add transaction and output to test last month's aggregates
 	transaction = &db.Transactions{
 		ID:        "id3",
@@ -249,23 +212,9 @@ func TestAggregates(t *testing.T) {
 	}
 	_ = persist.InsertOutputs(ctx, sessOuts, output, false)
 
-	err = reader.sc.AggregatesCache.GetAggregatesFeesAndUpdate(
-		reader.sc.Chains,
-		reader.conns,
-		"cid",
-		prevMonthDateTime,
-		timeNow.Add(1*time.Hour),
-		"month")
-	if err != nil {
-		t.Error("error", err)
-	}
-	err = reader.sc.AggregatesCache.GetAggregatesAndUpdate(
-		reader.sc.Chains,
-		reader.conns,
-		"cid",
-		prevMonthDateTime,
-		timeNow.Add(1*time.Hour),
-		"month")
+	_, _ = sessTx.DeleteFrom(db.TableAggregateRollupsHourly).ExecContext(ctx)
+	_, _ = sessTx.DeleteFrom(db.TableAggregateRollupsDaily).ExecContext(ctx)
+	err := reader.sc.AggregatesCache.UpdateRollups(ctx, reader.conns, reader.sc.Chains, timeNow)
 	if err != nil {
 		t.Error("error", err)
 	}
@@ -278,7 +227,7 @@ func TestAggregates(t *testing.T) {
 		ListParams: params.ListParams{StartTime: startTime, EndTime: endTime},
 		ChainIDs:   []string{"cid"},
 	}
-	aggFees, err := reader.TxfeeAggregate(reader.sc.AggregatesCache, &feeAggregateParams)
+	aggFees, err := reader.TxfeeAggregate(ctx, &feeAggregateParams)
 	if err != nil {
 		t.Error("error", err)
 	}
@@ -292,7 +241,7 @@ func TestAggregates(t *testing.T) {
 		ListParams: params.ListParams{StartTime: startTime, EndTime: endTime},
 		ChainIDs:   []string{"cid"},
 	}
-	agg, err := reader.Aggregate(reader.sc.AggregatesCache, &aggregateParams)
+	agg, err := reader.Aggregate(ctx, &aggregateParams)
 	if err != nil {
 		t.Error("error", err)
 	}
@@ -311,7 +260,7 @@ func TestAggregates(t *testing.T) {
 		ListParams: params.ListParams{StartTime: startTime, EndTime: endTime},
 		ChainIDs:   []string{"cid"},
 	}
-	aggFees, err = reader.TxfeeAggregate(reader.sc.AggregatesCache, &feeAggregateParams)
+	aggFees, err = reader.TxfeeAggregate(ctx, &feeAggregateParams)
 	if err != nil {
 		t.Error("error", err)
 	}
@@ -325,7 +274,7 @@ func TestAggregates(t *testing.T) {
 		ListParams: params.ListParams{StartTime: startTime, EndTime: endTime},
 		ChainIDs:   []string{"cid"},
 	}
-	agg, err = reader.Aggregate(reader.sc.AggregatesCache, &aggregateParams)
+	agg, err = reader.Aggregate(ctx, &aggregateParams)
 	if err != nil {
 		t.Error("error", err)
 	}
@@ -344,7 +293,7 @@ func TestAggregates(t *testing.T) {
 		ListParams: params.ListParams{StartTime: startTime, EndTime: endTime},
 		ChainIDs:   []string{"cid"},
 	}
-	aggFees, err = reader.TxfeeAggregate(reader.sc.AggregatesCache, &feeAggregateParams)
+	aggFees, err = reader.TxfeeAggregate(ctx, &feeAggregateParams)
 	if err != nil {
 		t.Error("error", err)
 	}
@@ -358,7 +307,7 @@ func TestAggregates(t *testing.T) {
 		ListParams: params.ListParams{StartTime: startTime, EndTime: endTime},
 		ChainIDs:   []string{"cid"},
 	}
-	agg, err = reader.Aggregate(reader.sc.AggregatesCache, &aggregateParams)
+	agg, err = reader.Aggregate(ctx, &aggregateParams)
 	if err != nil {
 		t.Error("error", err)
 	}
@@ -368,6 +317,42 @@ func TestAggregates(t *testing.T) {
 	if agg.StartTime != startTime || agg.EndTime != endTime {
 		t.Error("aggregate tx invalid")
 	}
+
+	// ranges within an hour are counted from the transactions
+	for _, tc := range []struct {
+		startTime time.Time
+		endTime   time.Time
+		count     uint64
+	}{
+		{timeNow.Add(-61 * time.Minute), timeNow.Add(-59 * time.Minute), 1},
+		{timeNow.Add(-59 * time.Minute), timeNow, 0},
+	} {
+		aggregateParams = params.AggregateParams{
+			ListParams: params.ListParams{StartTime: tc.startTime, EndTime: tc.endTime},
+			ChainIDs:   []string{"cid"},
+		}
+		agg, err = reader.Aggregate(ctx, &aggregateParams)
+		if err != nil {
+			t.Error("error", err)
+		}
+		if agg.Aggregates.TransactionCount != tc.count {
+			t.Errorf("Expected %d txs from %v", tc.count, tc.startTime)
+		}
+	}
+
+	// an asset only counts the transactions with outputs of the asset
+	aggregateParams = params.AggregateParams{
+		ListParams: params.ListParams{StartTime: timeNow.Add(-250 * time.Hour), EndTime: timeNow.Add(time.Hour)},
+		ChainIDs:   []string{"cid"},
+		AssetID:    &assetID,
+	}
+	agg, err = reader.Aggregate(ctx, &aggregateParams)
+	if err != nil {
+		t.Error("error", err)
+	}
+	if agg.Aggregates.TransactionCount != 1 {
+		t.Errorf("Expected %d asset txs", 1)
+	}
 }
 
 func TestDailyTransactionsStatistics(t *testing.T) {
@@ -1013,8 +998,7 @@ func newTestIndex(t *testing.T) (*Reader, func()) {
 	}
 
 	sc := &servicesctrl.Control{Log: logging.NoLog{}, Services: conf, Chains: chains}
-	sc.AggregatesCache = caching.NewAggregatesCache()
-	sc.AggregatesCache.InitCacheStorage(chains)
+	sc.AggregatesCache = caching.NewAggregatesCache(db.NewPersist(), "")
 	conns, err := sc.Database()
 	if err != nil {
 		t.Fatal("Failed to create connections:", err.Error())
@@ -1027,6 +1011,52 @@ func newTestIndex(t *testing.T) (*Reader, func()) {
 	}
 }
 
+func TestLookupFallsBackToPrimary(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	primaryDSN, replicaDSN := filepath.Join(dir, "primary.db"), filepath.Join(dir, "replica.db")
+	for _, dsn := range []string{primaryDSN, replicaDSN} {
+		conns, err := utils.NewDBFromConfig(cfg.Services{DB: &cfg.DB{Driver: utils.DriverSqlite, DSN: dsn}}, false)
+		require.NoError(err)
+		require.NoError(conns.Close())
+	}
+	conns, err := utils.NewDBFromConfig(cfg.Services{
+		DB: &cfg.DB{
+			Driver: utils.DriverSqlite,
+			DSN:    primaryDSN,
+			RODSNs: []string{replicaDSN},
+		},
+	}, true)
+	require.NoError(err)
+	t.Cleanup(func() { _ = conns.Close() })
+
+	reader := &Reader{conns: conns}
+	ctx := newTestContext()
+	var seen []*utils.Conn
+	lookup := func(found bool, err error) error {
+		seen = nil
+		return reader.lookup(ctx, func(ctx context.Context) (bool, error) {
+			seen = append(seen, reader.db(ctx))
+			return found, err
+		})
+	}
+
+	require.NoError(lookup(true, nil))
+	require.Len(seen, 1)
+	require.NotSame(conns.Primary(), seen[0])
+
+	require.NoError(lookup(false, nil))
+	require.Len(seen, 2)
+	require.Same(conns.Primary(), seen[1])
+
+	require.ErrorIs(lookup(false, dbr.ErrNotFound), dbr.ErrNotFound)
+	require.Len(seen, 2)
+
+	require.ErrorIs(lookup(false, context.Canceled), context.Canceled)
+	require.Len(seen, 1)
+}
+
 func newTestContext() context.Context {
 	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
 	time.AfterFunc(5*time.Second, cancelFn)
@@ -48,10 +48,13 @@ var ecdsaRecoveryFactory = secp256k1.Factory{}
 type Writer struct {
 	chainID     string
 	avaxAssetID ids.ID
+
+	// outbox enables the transaction indexed events
+	outbox bool
 }
 
-func NewWriter(chainID string, avaxAssetID ids.ID) *Writer {
-	return &Writer{chainID: chainID, avaxAssetID: avaxAssetID}
+func NewWriter(chainID string, avaxAssetID ids.ID, outbox bool) *Writer {
+	return &Writer{chainID: chainID, avaxAssetID: avaxAssetID, outbox: outbox}
 }
 
 type AddInsContainer struct {
@@ -130,7 +133,7 @@ func (w *Writer) InsertTransaction(
 	}
 
 	// Add baseTx to the table
-	return w.InsertTransactionBase(
+	err = w.InsertTransactionBase(
 		ctx,
 		txID,
 		w.chainID,
@@ -141,6 +144,26 @@ func (w *Writer) InsertTransaction(
 		genesis,
 		baseTx.NetworkID,
 	)
+	if err != nil || genesis {
+		return err
+	}
+
+	event := &models.TransactionIndexedEvent{
+		ChainID:   w.chainID,
+		TxID:      txID.String(),
+		TxType:    txType.String(),
+		Timestamp: ctx.Time(),
+		Txfee:     txfee,
+		Inputs:    EventInputs(baseTx.Ins),
+		Outputs:   EventOutputs(baseTx.Outs),
+	}
+	if addIns != nil {
+		event.Inputs = append(event.Inputs, EventInputs(addIns.Ins)...)
+	}
+	if addOuts != nil {
+		event.Outputs = append(event.Outputs, EventOutputs(addOuts.Outs)...)
+	}
+	return w.InsertTransactionIndexedEvent(ctx, event)
 }
 
 func (w *Writer) InsertTransactionBase(
@@ -235,6 +258,24 @@ func (w *Writer) InsertTransactionIns(
 		return 0, err
 	}
 
+	// an input of another chain is imported
+	if chainID != w.chainID {
+		importedAt := ctx.Time()
+		err = ctx.Persist().InsertCrossChainTransferImport(ctx.Ctx(), ctx.DB(), &db.CrossChainTransfer{
+			ID:                 inputID.String(),
+			ImportTxID:         txID.String(),
+			SourceChainID:      chainID,
+			DestinationChainID: w.chainID,
+			AssetID:            in.AssetID().String(),
+			Amount:             in.Input().Amount(),
+			ImportedAt:         &importedAt,
+			CreatedAt:          ctx.Time(),
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	return totalin, ctx.Persist().InsertOutputsRedeeming(ctx.Ctx(), ctx.DB(), outputsRedeeming, cfg.PerformUpdates)
 }
 
@@ -253,6 +294,24 @@ func (w *Writer) InsertTransactionOuts(
 	if err != nil {
 		return 0, err
 	}
+
+	// an output for another chain is exported
+	if chainID != w.chainID {
+		exportedAt := ctx.Time()
+		err = ctx.Persist().InsertCrossChainTransferExport(ctx.Ctx(), ctx.DB(), &db.CrossChainTransfer{
+			ID:                 txID.Prefix(uint64(idx)).String(),
+			ExportTxID:         txID.String(),
+			SourceChainID:      w.chainID,
+			DestinationChainID: chainID,
+			AssetID:            out.AssetID().String(),
+			Amount:             out.Output().Amount(),
+			ExportedAt:         &exportedAt,
+			CreatedAt:          ctx.Time(),
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
 	return totalout, nil
 }
 
@@ -271,6 +330,7 @@ func (w *Writer) InsertOutput(
 	frozen bool,
 	stakeableout bool,
 	genesisutxo bool,
+	lockIDs *locked.IDs,
 ) error {
 	outputID := txID.Prefix(uint64(idx))
 
@@ -319,6 +379,23 @@ func (w *Writer) InsertOutput(
 		Genesisutxo:   genesisutxo,
 		CreatedAt:     ctx.Time(),
 	}
+	if lockIDs != nil {
+		// outputs locked by the transaction creating them refer to it with
+		// the ThisTxID placeholder
+		depositTxID, bondTxID := lockIDs.DepositTxID, lockIDs.BondTxID
+		if depositTxID == locked.ThisTxID {
+			depositTxID = txID
+		}
+		if bondTxID == locked.ThisTxID {
+			bondTxID = txID
+		}
+		if depositTxID != ids.Empty {
+			output.DepositTxID = depositTxID.String()
+		}
+		if bondTxID != ids.Empty {
+			output.BondTxID = bondTxID.String()
+		}
+	}
 
 	// ensure that addresses are created before the outputs
 	return ctx.Persist().InsertOutputs(ctx.Ctx(), ctx.DB(), output, cfg.PerformUpdates)
@@ -454,6 +531,7 @@ func (w *Writer) ProcessStateOut(
 			false,
 			true,
 			genesisutxo,
+			nil,
 		)
 		if err != nil {
 			return 0, 0, err
@@ -474,6 +552,7 @@ func (w *Writer) ProcessStateOut(
 			false,
 			false,
 			genesisutxo,
+			nil,
 		)
 		if err != nil {
 			return 0, 0, err
@@ -494,6 +573,7 @@ func (w *Writer) ProcessStateOut(
 			false,
 			false,
 			genesisutxo,
+			nil,
 		)
 		if err != nil {
 			return 0, 0, err
@@ -514,6 +594,7 @@ func (w *Writer) ProcessStateOut(
 			false,
 			false,
 			genesisutxo,
+			nil,
 		)
 		if err != nil {
 			return 0, 0, err
@@ -540,6 +621,7 @@ func (w *Writer) ProcessStateOut(
 			false,
 			false,
 			genesisutxo,
+			nil,
 		)
 		if err != nil {
 			return 0, 0, err
@@ -570,6 +652,7 @@ func (w *Writer) ProcessStateOut(
 			false,
 			false,
 			genesisutxo,
+			nil,
 		)
 		if err != nil {
 			return 0, 0, err
@@ -619,6 +702,7 @@ func (w *Writer) ProcessStateOut(
 			false,
 			false,
 			false,
+			&typedOut.IDs,
 		)
 		if err != nil {
 			return 0, 0, err
@@ -165,7 +165,7 @@ func newTestIndex(t *testing.T, chainID ids.ID) (*utils.Connections, *Writer, *a
 	}
 
 	// Create index
-	writer, err := NewWriter(networkID, chainID.String())
+	writer, err := NewWriter(networkID, chainID.String(), nil)
 	if err != nil {
 		t.Fatal("Failed to create writer:", err.Error())
 	}
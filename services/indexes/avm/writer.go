@@ -62,7 +62,7 @@ type Writer struct {
 	ctx  *snow.Context
 }
 
-func NewWriter(networkID uint32, chainID string) (*Writer, error) {
+func NewWriter(networkID uint32, chainID string, conf *cfg.Config) (*Writer, error) {
 	_, avaxAssetID, err := genesis.FromConfig(genesis.GetConfig(networkID))
 	if err != nil {
 		return nil, err
@@ -90,7 +90,7 @@ func NewWriter(networkID uint32, chainID string) (*Writer, error) {
 		chainID:     chainID,
 		networkID:   networkID,
 		avaxAssetID: avaxAssetID,
-		avax:        avax.NewWriter(chainID, avaxAssetID),
+		avax:        avax.NewWriter(chainID, avaxAssetID, conf != nil && conf.Outbox.Sink != ""),
 		ctx:         ctx,
 	}, nil
 }
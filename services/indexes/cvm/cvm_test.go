@@ -146,3 +146,12 @@ func TestInsertTxInternalImport(t *testing.T) {
 		t.Fatal("insert failed")
 	}
 }
+
+func TestEvmTxfee(t *testing.T) {
+	if fee := evmTxfee(&db.CvmTransactionsTxdata{GasUsed: 21000, GasPrice: 25000000000}); fee != 525000 {
+		t.Fatal("unexpected fee", fee)
+	}
+	if fee := evmTxfee(&db.CvmTransactionsTxdata{GasUsed: 21000}); fee != 0 {
+		t.Fatal("fee without receipt", fee)
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/ava-labs/avalanchego/codec"
@@ -42,6 +44,9 @@ import (
 
 var ErrUnknownBlockType = errors.New("unknown block type")
 
+// evmTxType is the transaction type of the indexed events of evm transactions
+const evmTxType = "evm_tx"
+
 type Writer struct {
 	networkID   uint32
 	avaxAssetID ids.ID
@@ -49,7 +54,8 @@ type Writer struct {
 	codec           codec.Manager
 	avax            *avaxIndexer.Writer
 	ap5Activation   uint64
-	client          *modelsc.Client
+	clients         []*modelsc.Client
+	nodes           *utils.NodeSet
 	banffActivation uint64
 }
 
@@ -62,29 +68,60 @@ func NewWriter(networkID uint32, chainID string, conf *cfg.Config) (*Writer, err
 	ap5Activation := version.GetApricotPhase5Time(networkID).Unix()
 	banffActivation := version.GetBanffTime(networkID).Unix()
 
-	var client *modelsc.Client
-	if conf != nil { // check for test cases
-		if client, err = modelsc.NewClient(conf.CaminoNode + "/ext/bc/C/rpc"); err != nil {
-			return nil, err
-		}
-	}
-
-	return &Writer{
+	w := &Writer{
 		networkID:       networkID,
 		avaxAssetID:     avaxAssetID,
 		codec:           evm.Codec,
-		avax:            avaxIndexer.NewWriter(chainID, avaxAssetID),
+		avax:            avaxIndexer.NewWriter(chainID, avaxAssetID, conf != nil && conf.Outbox.Sink != ""),
 		ap5Activation:   uint64(ap5Activation),
-		client:          client,
 		banffActivation: uint64(banffActivation),
-	}, nil
+	}
+
+	if conf != nil { // check for test cases
+		// the primary network C-chain is served under its alias, the evm
+		// chains of a subnet under their ID
+		alias := "C"
+		if conf.CchainID != "" && chainID != conf.CchainID {
+			alias = chainID
+		}
+		urls := conf.NodeURLs()
+		for _, url := range urls {
+			client, err := modelsc.NewClient(url + "/ext/bc/" + alias + "/rpc")
+			if err != nil {
+				w.Close()
+				return nil, err
+			}
+			w.clients = append(w.clients, client)
+		}
+		w.nodes = utils.NewNodeSet(fmt.Sprintf("rpc_%s", chainID), urls, conf.NodeFailover, w.blockNumber)
+	}
+
+	return w, nil
 }
 
 // OPT: Not yet called!!
 func (w *Writer) Close() {
-	if w.client != nil {
-		w.client.Close()
+	if w.nodes != nil {
+		w.nodes.Close()
+	}
+	for _, client := range w.clients {
+		client.Close()
+	}
+}
+
+func (w *Writer) blockNumber(ctx context.Context, node int) (uint64, error) {
+	return w.clients[node].BlockNumber(ctx)
+}
+
+// readReceipt reads the receipt from the node in use, and fails over to the
+// next node if it can't be read
+func (w *Writer) readReceipt(hash string) (*modelsc.ExtendedReceipt, error) {
+	node := w.nodes.Active()
+	receipt, err := w.clients[node].ReadReceipt(hash, time.Second*1)
+	if err != nil {
+		w.nodes.Failed(node)
 	}
+	return receipt, err
 }
 
 func (*Writer) Name() string { return "cvm-index" }
@@ -237,14 +274,17 @@ func (w *Writer) indexBlockInternal(ctx services.ConsumerCtx, atomicTXs []*evm.T
 			CreatedAt:     ctx.Time(),
 		}
 
-		if w.client != nil {
-			receipt, err := w.client.ReadReceipt(hash, time.Second*1)
+		if len(w.clients) != 0 {
+			receipt, err := w.readReceipt(hash)
 			if err != nil {
 				return err
 			}
 
 			cvmTransactionTxdata.Status = uint16(receipt.Status)
 			cvmTransactionTxdata.GasPrice = receipt.EffectiveGasPrice
+			if baseFee := block.BaseFee(); baseFee != nil {
+				cvmTransactionTxdata.BaseFee = baseFee.Uint64()
+			}
 			cvmTransactionTxdata.GasUsed = receipt.GasUsed
 			cvmTransactionTxdata.Receipt = receipt.Raw
 
@@ -277,6 +317,20 @@ func (w *Writer) indexBlockInternal(ctx services.ConsumerCtx, atomicTXs []*evm.T
 		if err != nil {
 			return err
 		}
+
+		assetID := w.avaxAssetID.String()
+		err = w.avax.InsertTransactionIndexedEvent(ctx, &models.TransactionIndexedEvent{
+			ChainID:   ctx.ChainID(),
+			TxID:      hash,
+			TxType:    evmTxType,
+			Timestamp: ctx.Time(),
+			Txfee:     evmTxfee(cvmTransactionTxdata),
+			Inputs:    []*models.EventAmount{{CAddresses: []string{fromStr}, AssetID: assetID, Amount: rawtx.Value().String()}},
+			Outputs:   []*models.EventAmount{{CAddresses: []string{toStr}, AssetID: assetID, Amount: rawtx.Value().String()}},
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	for _, txIDString := range txIDs {
@@ -338,6 +392,14 @@ func (w *Writer) indexBlockInternal(ctx services.ConsumerCtx, atomicTXs []*evm.T
 	return nil
 }
 
+// evmTxfee returns the fee paid for an evm transaction in nAVAX, like the
+// fees of the other chains. It is 0 if the receipt wasn't read.
+func evmTxfee(txdata *db.CvmTransactionsTxdata) uint64 {
+	fee := new(big.Int).SetUint64(txdata.GasPrice)
+	fee.Mul(fee, new(big.Int).SetUint64(txdata.GasUsed))
+	return fee.Div(fee, big.NewInt(1000000000)).Uint64()
+}
+
 func (w *Writer) indexTransaction(
 	ctx services.ConsumerCtx,
 	id ids.ID,
@@ -345,6 +407,8 @@ func (w *Writer) indexTransaction(
 	blockChainID ids.ID,
 	txFee uint64,
 	unsignedBytes []byte,
+	inputs []*models.EventAmount,
+	outputs []*models.EventAmount,
 ) error {
 	avmTxtype := ""
 	switch typ {
@@ -354,7 +418,7 @@ func (w *Writer) indexTransaction(
 		avmTxtype = "atomic_export_tx"
 	}
 
-	return w.avax.InsertTransactionBase(
+	err := w.avax.InsertTransactionBase(
 		ctx,
 		id,
 		blockChainID.String(),
@@ -365,6 +429,19 @@ func (w *Writer) indexTransaction(
 		false,
 		w.networkID,
 	)
+	if err != nil {
+		return err
+	}
+
+	return w.avax.InsertTransactionIndexedEvent(ctx, &models.TransactionIndexedEvent{
+		ChainID:   blockChainID.String(),
+		TxID:      id.String(),
+		TxType:    avmTxtype,
+		Timestamp: ctx.Time(),
+		Txfee:     txFee,
+		Inputs:    inputs,
+		Outputs:   outputs,
+	})
 }
 
 func (w *Writer) insertAddress(
@@ -421,7 +498,17 @@ func (w *Writer) indexExportTx(ctx services.ConsumerCtx, txID ids.ID, tx *evm.Un
 		idx++
 	}
 
-	return w.indexTransaction(ctx, txID, models.CChainExport, tx.BlockchainID, totalin-totalout, blockBytes)
+	inputs := make([]*models.EventAmount, 0, len(tx.Ins))
+	for _, in := range tx.Ins {
+		inputs = append(inputs, &models.EventAmount{
+			CAddresses: []string{utils.CommonAddressHexRepair(&in.Address)},
+			AssetID:    in.AssetID.String(),
+			Amount:     strconv.FormatUint(in.Amount, 10),
+		})
+	}
+
+	return w.indexTransaction(ctx, txID, models.CChainExport, tx.BlockchainID, totalin-totalout, blockBytes,
+		inputs, avaxIndexer.EventOutputs(tx.ExportedOutputs))
 }
 
 func (w *Writer) indexImportTx(ctx services.ConsumerCtx, txID ids.ID, tx *evm.UnsignedImportTx, creds []verify.Verifiable, blockBytes []byte, unsignedBytes []byte) error {
@@ -450,5 +537,15 @@ func (w *Writer) indexImportTx(ctx services.ConsumerCtx, txID ids.ID, tx *evm.Un
 		}
 	}
 
-	return w.indexTransaction(ctx, txID, models.CChainImport, tx.BlockchainID, totalin-totalout, blockBytes)
+	outputs := make([]*models.EventAmount, 0, len(tx.Outs))
+	for _, out := range tx.Outs {
+		outputs = append(outputs, &models.EventAmount{
+			CAddresses: []string{utils.CommonAddressHexRepair(&out.Address)},
+			AssetID:    out.AssetID.String(),
+			Amount:     strconv.FormatUint(out.Amount, 10),
+		})
+	}
+
+	return w.indexTransaction(ctx, txID, models.CChainImport, tx.BlockchainID, totalin-totalout, blockBytes,
+		avaxIndexer.EventInputs(tx.ImportedInputs), outputs)
 }
@@ -45,6 +45,15 @@ var (
 	_ Param = &ListOutputsParams{}
 	_ Param = &ListCTransactionsParams{}
 	_ Param = &ListBlocksParams{}
+	_ Param = &ListCrossChainTransfersParams{}
+	_ Param = &ListUTXOsParams{}
+	_ Param = &FeeStatsParams{}
+	_ Param = &FeeHistoryParams{}
+	_ Param = &BurnedFeesParams{}
+	_ Param = &SupplyParams{}
+	_ Param = &SupplyHistoryParams{}
+	_ Param = &AssetHoldersParams{}
+	_ Param = &HolderCountHistoryParams{}
 )
 
 type SearchParams struct {
@@ -102,9 +111,9 @@ func (p *EmissionsParams) CacheKey() []string {
 type TxfeeAggregateParams struct {
 	ListParams ListParams
 
-	IntervalSize time.Duration
+	IntervalSize time.Duration `query:"intervalSize"`
 
-	ChainIDs []string
+	ChainIDs []string `query:"chainID"`
 }
 
 func (p *TxfeeAggregateParams) ForValues(version uint8, q url.Values) (err error) {
@@ -138,12 +147,206 @@ func (p *TxfeeAggregateParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 	return b
 }
 
+// FeeStatsParams select the chains and the time range of the fee statistics
+type FeeStatsParams struct {
+	ListParams ListParams
+	ChainIDs   []string `query:"chainID"`
+}
+
+func (p *FeeStatsParams) ForValues(v uint8, q url.Values) error {
+	if err := p.ListParams.ForValues(v, q); err != nil {
+		return err
+	}
+	p.ChainIDs = q[KeyChainID]
+	return nil
+}
+
+func (p *FeeStatsParams) CacheKey() []string {
+	return append(p.ListParams.CacheKey(), CacheKey(KeyChainID, strings.Join(p.ChainIDs, "|")))
+}
+
+// Apply restricts the transactions to the chains and the time range
+func (p *FeeStatsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
+	if len(p.ChainIDs) != 0 {
+		b.Where("avm_transactions.chain_id IN ?", p.ChainIDs)
+	}
+	if p.ListParams.StartTimeProvided {
+		b.Where("avm_transactions.created_at >= ?", p.ListParams.StartTime)
+	}
+	return b.Where("avm_transactions.created_at < ?", p.ListParams.EndTime)
+}
+
+// FeeHistoryParams select the time range of a fee history and the size of
+// its intervals, no intervals are returned without an interval size
+type FeeHistoryParams struct {
+	ListParams   ListParams
+	IntervalSize time.Duration `query:"intervalSize"`
+}
+
+func (p *FeeHistoryParams) ForValues(v uint8, q url.Values) (err error) {
+	if err = p.ListParams.ForValues(v, q); err != nil {
+		return err
+	}
+	if p.IntervalSize, err = GetQueryInterval(q, KeyIntervalSize); err != nil {
+		return err
+	}
+	if p.IntervalSize != 0 && p.ListParams.EndTime.Sub(p.ListParams.StartTime)/p.IntervalSize > MaxIntervals {
+		return ErrTooManyIntervals
+	}
+	return nil
+}
+
+func (p *FeeHistoryParams) CacheKey() []string {
+	return append(p.ListParams.CacheKey(), CacheKey(KeyIntervalSize, int64(p.IntervalSize.Seconds())))
+}
+
+// BurnedFeesParams select the chains of a fee history
+type BurnedFeesParams struct {
+	FeeHistoryParams FeeHistoryParams
+	ChainIDs         []string `query:"chainID"`
+}
+
+func (p *BurnedFeesParams) ForValues(v uint8, q url.Values) error {
+	if err := p.FeeHistoryParams.ForValues(v, q); err != nil {
+		return err
+	}
+	p.ChainIDs = q[KeyChainID]
+	return nil
+}
+
+func (p *BurnedFeesParams) CacheKey() []string {
+	return append(p.FeeHistoryParams.CacheKey(), CacheKey(KeyChainID, strings.Join(p.ChainIDs, "|")))
+}
+
+// SupplyParams select the asset of the current supply. With a field only
+// that amount is returned in whole tokens.
+type SupplyParams struct {
+	AssetID *ids.ID `query:"assetID"`
+	Field   string  `query:"field"`
+}
+
+func (p *SupplyParams) ForValues(v uint8, q url.Values) (err error) {
+	if p.AssetID, err = GetQueryID(q, KeyAssetID); err != nil {
+		return err
+	}
+	p.Field = GetQueryString(q, KeyField, "")
+	if p.Field != "" {
+		if _, ok := (&models.Supply{}).Field(p.Field); !ok {
+			return ErrUnknownField
+		}
+	}
+	return nil
+}
+
+func (p *SupplyParams) CacheKey() []string {
+	k := []string{CacheKey(KeyField, p.Field)}
+	if p.AssetID != nil {
+		k = append(k, CacheKey(KeyAssetID, p.AssetID.String()))
+	}
+	return k
+}
+
+// SupplyHistoryParams select the asset and the days of the supply history
+type SupplyHistoryParams struct {
+	ListParams ListParams
+	AssetID    *ids.ID `query:"assetID"`
+}
+
+func (p *SupplyHistoryParams) ForValues(v uint8, q url.Values) (err error) {
+	if err = p.ListParams.ForValues(v, q); err != nil {
+		return err
+	}
+	p.AssetID, err = GetQueryID(q, KeyAssetID)
+	return err
+}
+
+func (p *SupplyHistoryParams) CacheKey() []string {
+	k := p.ListParams.CacheKey()
+	if p.AssetID != nil {
+		k = append(k, CacheKey(KeyAssetID, p.AssetID.String()))
+	}
+	return k
+}
+
+// Apply restricts the history to the days within the time range
+func (p *SupplyHistoryParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
+	if p.ListParams.Limit != 0 {
+		b.Limit(uint64(p.ListParams.Limit))
+	}
+	if p.ListParams.StartTimeProvided {
+		b.Where("supply_history.bucket_at >= ?", p.ListParams.StartTime)
+	}
+	return b.Where("supply_history.bucket_at < ?", p.ListParams.EndTime)
+}
+
+// AssetHoldersParams page through the holders of the asset of the path,
+// largest balance first
+type AssetHoldersParams struct {
+	ListParams ListParams
+	ChainIDs   []string `query:"chainID"`
+	AssetID    ids.ID
+}
+
+func (p *AssetHoldersParams) ForValues(v uint8, q url.Values) error {
+	if err := p.ListParams.ForValuesAllowOffset(v, q); err != nil {
+		return err
+	}
+	p.ChainIDs = q[KeyChainID]
+	return nil
+}
+
+func (p *AssetHoldersParams) CacheKey() []string {
+	return append(p.ListParams.CacheKey(),
+		CacheKey(KeyChainID, strings.Join(p.ChainIDs, "|")),
+		CacheKey(KeyAssetID, p.AssetID.String()))
+}
+
+// Apply pages the holders
+func (p *AssetHoldersParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
+	if p.ListParams.Limit != 0 {
+		b.Limit(uint64(p.ListParams.Limit))
+	}
+	if p.ListParams.Offset != 0 {
+		b.Offset(uint64(p.ListParams.Offset))
+	}
+	return b
+}
+
+// ApplyBalances restricts the balances to the asset and the chains
+func (p *AssetHoldersParams) ApplyBalances(b *dbr.SelectBuilder) *dbr.SelectBuilder {
+	b.Where("accumulate_balances_received.asset_id = ?", p.AssetID.String())
+	if len(p.ChainIDs) != 0 {
+		b.Where("accumulate_balances_received.chain_id IN ?", p.ChainIDs)
+	}
+	return b
+}
+
+// HolderCountHistoryParams select the days of the holder count history of the
+// asset of the path
+type HolderCountHistoryParams struct {
+	ListParams ListParams
+	AssetID    ids.ID
+}
+
+func (p *HolderCountHistoryParams) ForValues(v uint8, q url.Values) error {
+	return p.ListParams.ForValues(v, q)
+}
+
+func (p *HolderCountHistoryParams) CacheKey() []string {
+	return append(p.ListParams.CacheKey(), CacheKey(KeyAssetID, p.AssetID.String()))
+}
+
+// Apply restricts the history to the days within the time range
+func (p *HolderCountHistoryParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
+	return (&SupplyHistoryParams{ListParams: p.ListParams}).Apply(b)
+}
+
 type AggregateParams struct {
 	ListParams ListParams
 
-	ChainIDs     []string
-	AssetID      *ids.ID
-	IntervalSize time.Duration
+	ChainIDs     []string      `query:"chainID"`
+	AssetID      *ids.ID       `query:"assetID"`
+	IntervalSize time.Duration `query:"intervalSize"`
 }
 
 func (p *AggregateParams) ForValues(version uint8, q url.Values) (err error) {
@@ -199,17 +402,17 @@ func (p *AggregateParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 
 type ListTransactionsParams struct {
 	ListParams ListParams
-	ChainIDs   []string
-	Addresses  []ids.ShortID
-	AssetID    *ids.ID
+	ChainIDs   []string      `query:"chainID"`
+	Addresses  []ids.ShortID `query:"address"`
+	AssetID    *ids.ID       `query:"assetID"`
 
-	OutputOutputTypes []uint64
-	OutputGroupIDs    []uint64
+	OutputOutputTypes []uint64 `query:"outputOutputType"`
+	OutputGroupIDs    []uint64 `query:"outputGroupId"`
 
-	DisableGenesis bool
-	Raw            bool
+	DisableGenesis bool `query:"disableGenesis"`
+	Raw            bool `query:"raw"`
 
-	Sort TransactionSort
+	Sort TransactionSort `query:"sort"`
 }
 
 func (p *ListTransactionsParams) ForValues(v uint8, q url.Values) error {
@@ -297,13 +500,13 @@ func (p *ListTransactionsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder
 
 type ListCTransactionsParams struct {
 	ListParams     ListParams
-	CAddresses     []string
-	CAddressesTo   []string
-	CAddressesFrom []string
-	Hashes         []string
-	Sort           TransactionSort
-	BlockStart     *big.Int
-	BlockEnd       *big.Int
+	CAddresses     []string        `query:"address"`
+	CAddressesTo   []string        `query:"toAddress"`
+	CAddressesFrom []string        `query:"fromAddress"`
+	Hashes         []string        `query:"hash"`
+	Sort           TransactionSort `query:"sort"`
+	BlockStart     *big.Int        `query:"blockStart"`
+	BlockEnd       *big.Int        `query:"blockEnd"`
 }
 
 func (p *ListCTransactionsParams) ForValues(v uint8, q url.Values) error {
@@ -391,11 +594,12 @@ func (p *ListCTransactionsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder
 
 type ListCBlocksParams struct {
 	ListParams ListParams
+	// TxLimit is the second value of the limit key
 	TxLimit    int
-	CAddresses []string
-	BlockStart *big.Int
-	BlockEnd   *big.Int
-	TxID       uint
+	CAddresses []string `query:"address"`
+	BlockStart *big.Int `query:"blockStart"`
+	BlockEnd   *big.Int `query:"blockEnd"`
+	TxID       uint     `query:"transactionId"`
 }
 
 func (p *ListCBlocksParams) ForValues(version uint8, q url.Values) (err error) {
@@ -464,7 +668,7 @@ func (p *ListCBlocksParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 
 type ListAssetsParams struct {
 	ListParams  ListParams
-	Alias       string
+	Alias       string `query:"alias"`
 	PathParamID string
 }
 
@@ -509,8 +713,8 @@ func (p *ListAssetsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 
 type ListAddressesParams struct {
 	ListParams ListParams
-	ChainIDs   []string
-	Address    *ids.ShortID
+	ChainIDs   []string     `query:"chainID"`
+	Address    *ids.ShortID `query:"address"`
 }
 
 func (p *ListAddressesParams) ForValues(v uint8, q url.Values) error {
@@ -574,7 +778,7 @@ func (p *ListAddressesParams) Apply(b *dbr.SelectBuilder, accumulateReader bool)
 
 type AddressChainsParams struct {
 	ListParams ListParams
-	Addresses  []ids.ShortID
+	Addresses  []ids.ShortID `query:"address"`
 }
 
 func (p *AddressChainsParams) ForValues(v uint8, q url.Values) error {
@@ -620,9 +824,9 @@ func (p *AddressChainsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 
 type ListOutputsParams struct {
 	ListParams ListParams
-	ChainIDs   []string
-	Addresses  []ids.ShortID
-	Spent      *bool
+	ChainIDs   []string      `query:"chainID"`
+	Addresses  []ids.ShortID `query:"address"`
+	Spent      *bool         `query:"spent"`
 }
 
 func (p *ListOutputsParams) ForValues(v uint8, q url.Values) error {
@@ -720,6 +924,215 @@ func (p *ListBlocksParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 	return p.ListParams.Apply("pvm_blocks", b)
 }
 
+type ListSubnetsParams struct {
+	ListParams ListParams
+}
+
+func (p *ListSubnetsParams) ForValues(v uint8, q url.Values) error {
+	return p.ListParams.ForValues(v, q)
+}
+
+func (p *ListSubnetsParams) CacheKey() []string {
+	return p.ListParams.CacheKey()
+}
+
+func (p *ListSubnetsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
+	return p.ListParams.Apply("subnets", b)
+}
+
+type ListBlockchainsParams struct {
+	ListParams ListParams
+	SubnetIDs  []string `query:"subnetID"`
+}
+
+func (p *ListBlockchainsParams) ForValues(v uint8, q url.Values) error {
+	if err := p.ListParams.ForValues(v, q); err != nil {
+		return err
+	}
+	p.SubnetIDs = q[KeySubnetID]
+	return nil
+}
+
+func (p *ListBlockchainsParams) CacheKey() []string {
+	return append(p.ListParams.CacheKey(),
+		CacheKey(KeySubnetID, strings.Join(p.SubnetIDs, ",")))
+}
+
+func (p *ListBlockchainsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
+	p.ListParams.Apply("blockchains", b)
+	if len(p.SubnetIDs) != 0 {
+		b.Where("blockchains.subnet_id IN ?", p.SubnetIDs)
+	}
+	return b
+}
+
+type ListCrossChainTransfersParams struct {
+	ListParams ListParams
+	Addresses  []ids.ShortID `query:"address"`
+	ChainIDs   []string      `query:"chainID"`
+	Status     string        `query:"status"`
+}
+
+func (p *ListCrossChainTransfersParams) ForValues(v uint8, q url.Values) error {
+	if err := p.ListParams.ForValuesAllowOffset(v, q); err != nil {
+		return err
+	}
+
+	for _, addressStr := range q[KeyAddress] {
+		addr, err := AddressFromString(addressStr)
+		if err != nil {
+			return err
+		}
+		p.Addresses = append(p.Addresses, addr)
+	}
+
+	p.ChainIDs = q[KeyChainID]
+
+	p.Status = GetQueryString(q, KeyStatus, "")
+	switch p.Status {
+	case "", models.CrossChainTransferPending, models.CrossChainTransferCompleted:
+	default:
+		return ErrUnknownStatus
+	}
+	return nil
+}
+
+func (p *ListCrossChainTransfersParams) CacheKey() []string {
+	k := p.ListParams.CacheKey()
+	for _, address := range p.Addresses {
+		k = append(k, CacheKey(KeyAddress, address.String()))
+	}
+	return append(k,
+		CacheKey(KeyChainID, strings.Join(p.ChainIDs, "|")),
+		CacheKey(KeyStatus, p.Status))
+}
+
+// Apply filters the transfers by the chain they leave or enter, their status,
+// and the addresses owning the transferred output
+func (p *ListCrossChainTransfersParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
+	p.ListParams.Apply("cross_chain_transfers", b)
+
+	if p.ListParams.StartTimeProvided {
+		b.Where("cross_chain_transfers.created_at >= ?", p.ListParams.StartTime)
+	}
+	if p.ListParams.EndTimeProvided {
+		b.Where("cross_chain_transfers.created_at < ?", p.ListParams.EndTime)
+	}
+	if len(p.ChainIDs) != 0 {
+		b.Where("(cross_chain_transfers.source_chain_id IN ? OR cross_chain_transfers.destination_chain_id IN ?)", p.ChainIDs, p.ChainIDs)
+	}
+	switch p.Status {
+	case models.CrossChainTransferPending:
+		b.Where("cross_chain_transfers.import_tx_id = ''")
+	case models.CrossChainTransferCompleted:
+		b.Where("cross_chain_transfers.import_tx_id <> ''")
+	}
+	if len(p.Addresses) != 0 {
+		addrs := make([]string, 0, len(p.Addresses))
+		for _, address := range p.Addresses {
+			addrs = append(addrs, address.String())
+		}
+		b.Where("cross_chain_transfers.id IN ?", dbr.Select("output_id").
+			From("avm_output_addresses").
+			Where("address IN ?", addrs))
+	}
+	return b
+}
+
+type ListUTXOsParams struct {
+	ListParams ListParams
+	Addresses  []ids.ShortID `query:"address"`
+	ChainIDs   []string      `query:"chainID"`
+	AssetID    *ids.ID       `query:"assetID"`
+	Spendable  bool          `query:"spendable"`
+}
+
+func (p *ListUTXOsParams) ForValues(v uint8, q url.Values) (err error) {
+	if err = p.ListParams.ForValuesAllowOffset(v, q); err != nil {
+		return err
+	}
+
+	for _, addressStr := range q[KeyAddress] {
+		addr, err := AddressFromString(addressStr)
+		if err != nil {
+			return err
+		}
+		p.Addresses = append(p.Addresses, addr)
+	}
+	if len(p.Addresses) == 0 {
+		return ErrNoAddress
+	}
+
+	p.ChainIDs = q[KeyChainID]
+
+	p.AssetID, err = GetQueryID(q, KeyAssetID)
+	if err != nil {
+		return err
+	}
+
+	p.Spendable, err = GetQueryBool(q, KeySpendable, false)
+	return err
+}
+
+func (p *ListUTXOsParams) CacheKey() []string {
+	k := p.ListParams.CacheKey()
+	for _, address := range p.Addresses {
+		k = append(k, CacheKey(KeyAddress, address.String()))
+	}
+	if p.AssetID != nil {
+		k = append(k, CacheKey(KeyAssetID, p.AssetID.String()))
+	}
+	return append(k,
+		CacheKey(KeyChainID, strings.Join(p.ChainIDs, "|")),
+		CacheKey(KeySpendable, p.Spendable))
+}
+
+// Apply filters the unspent outputs owned by the addresses. Spendable outputs
+// are neither locked nor staked, frozen or waiting for their import, and their
+// locktimes have passed at now.
+func (p *ListUTXOsParams) Apply(b *dbr.SelectBuilder, now time.Time) *dbr.SelectBuilder {
+	if p.ListParams.Limit != 0 {
+		b.Limit(uint64(p.ListParams.Limit))
+	}
+	if p.ListParams.Offset != 0 {
+		b.Offset(uint64(p.ListParams.Offset))
+	}
+
+	addrs := make([]string, 0, len(p.Addresses))
+	for _, address := range p.Addresses {
+		addrs = append(addrs, address.String())
+	}
+	b.Where("avm_outputs.id IN ?", dbr.Select("output_id").
+		From("avm_output_addresses").
+		Where("address IN ?", addrs))
+	b.Where("NOT EXISTS ?", dbr.Select("1").
+		From("avm_outputs_redeeming").
+		Where("avm_outputs_redeeming.id = avm_outputs.id"))
+
+	if len(p.ChainIDs) != 0 {
+		b.Where("avm_outputs.chain_id IN ?", p.ChainIDs)
+	}
+	if p.AssetID != nil {
+		b.Where("avm_outputs.asset_id = ?", p.AssetID.String())
+	}
+	if p.Spendable {
+		unix := uint64(now.Unix())
+		b.Where("avm_outputs.output_type NOT IN ?", []models.OutputType{
+			models.OutputTypesLockedOutD,
+			models.OutputTypesLockedOutB,
+			models.OutputTypesLockedOutDB,
+		}).
+			Where("avm_outputs.locktime <= ?", unix).
+			Where("avm_outputs.stake_locktime <= ?", unix).
+			Where("avm_outputs.stake = ?", false).
+			Where("avm_outputs.frozen = ?", false).
+			Where("NOT EXISTS ?", dbr.Select("1").
+				From("cross_chain_transfers").
+				Where("cross_chain_transfers.id = avm_outputs.id"))
+	}
+	return b
+}
+
 func ForValueChainID(chainID *ids.ID, chainIDs []string) []string {
 	if chainID == nil {
 		return chainIDs
@@ -801,3 +1214,41 @@ func (p *ValidatorParams) SetParamInfo(v uint8, rpc string) error {
 func (p *ValidatorParams) CacheKey() []string {
 	return p.ListParams.CacheKey()
 }
+
+// ValidatorsAtParams select the moment a validator set is read at, either by
+// time or by P-chain height. Without both the current set is read.
+type ValidatorsAtParams struct {
+	Time    time.Time `query:"time"`
+	Height  *uint64   `query:"height"`
+	NodeIDs []string  `query:"nodeID"`
+}
+
+func (p *ValidatorsAtParams) ForValues(v uint8, q url.Values) error {
+	ok, t, err := GetQueryTime(q, KeyTime)
+	if err != nil {
+		return err
+	}
+	if ok {
+		p.Time = t
+	}
+	if height := GetQueryString(q, KeyHeight, ""); height != "" {
+		if ok {
+			return ErrTimeAndHeight
+		}
+		h, err := strconv.ParseUint(height, 10, 64)
+		if err != nil {
+			return err
+		}
+		p.Height = &h
+	}
+	p.NodeIDs = q[KeyNodeID]
+	return nil
+}
+
+func (p *ValidatorsAtParams) CacheKey() []string {
+	k := []string{CacheKey(KeyTime, p.Time.Unix())}
+	if p.Height != nil {
+		k = append(k, CacheKey(KeyHeight, *p.Height))
+	}
+	return append(k, CacheKey(KeyNodeID, strings.Join(p.NodeIDs, ",")))
+}
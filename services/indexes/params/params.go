@@ -49,10 +49,19 @@ const (
 	KeyTransactionID    = "transactionId"
 	KeyRPC              = "rpc"
 	KeyRaw              = "raw"
+	KeyTime             = "time"
+	KeyHeight           = "height"
+	KeyNodeID           = "nodeID"
+	KeySubnetID         = "subnetID"
+	KeyStatus           = "status"
+	KeySpendable        = "spendable"
+	KeyField            = "field"
 
 	PaginationMaxLimit      = 5000
 	PaginationDefaultOffset = 0
 
+	MaxIntervals = 1000
+
 	VersionDefault = 0
 )
 
@@ -75,6 +84,12 @@ var (
 	}
 
 	ErrUndefinedSort = errors.New("undefined sort")
+	ErrTimeAndHeight = errors.New("time and height are exclusive")
+	ErrUnknownStatus = errors.New("unknown status")
+	ErrNoAddress     = errors.New("address is required")
+	ErrUnknownField  = errors.New("unknown field")
+
+	ErrTooManyIntervals = errors.New("too many intervals, narrow the time range or widen the interval size")
 
 	// Ensure params types satisfy the interface
 	_ Param = &ListParams{}
@@ -92,20 +107,23 @@ func CacheKey(name string, val interface{}) string {
 }
 
 // Global params
+//
+// The query tags name the query key a field is parsed from, they document the
+// params in the OpenAPI specification of the API.
 type ListParams struct {
 	Values url.Values
-	ID     *ids.ID
-	Query  string
+	ID     *ids.ID `query:"id"`
+	Query  string  `query:"query"`
 
-	Limit           int
-	Offset          int
-	DisableCounting bool
+	Limit           int  `query:"limit"`
+	Offset          int  `query:"offset"`
+	DisableCounting bool `query:"disableCount"`
 
 	StartTimeProvided bool
 	EndTimeProvided   bool
 
-	StartTime time.Time
-	EndTime   time.Time
+	StartTime time.Time `query:"startTime"`
+	EndTime   time.Time `query:"endTime"`
 }
 
 func (p *ListParams) ForValues(version uint8, q url.Values) (err error) {
@@ -21,6 +21,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	"github.com/chain4travel/magellan/cfg"
 	"github.com/chain4travel/magellan/db"
 	"github.com/chain4travel/magellan/models"
@@ -85,7 +86,7 @@ func newTestIndex(t *testing.T, networkID uint32, chainID ids.ID) (*utils.Connec
 	}
 
 	// Create index
-	writer, err := NewWriter(networkID, chainID.String())
+	writer, err := NewWriter(networkID, chainID.String(), nil)
 	if err != nil {
 		t.Fatal("Failed to create writer:", err.Error())
 	}
@@ -124,6 +125,62 @@ func TestInsertTxInternal(t *testing.T) {
 	}
 }
 
+func TestInsertSubnetTxs(t *testing.T) {
+	conns, writer, _, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+	ctx := context.Background()
+
+	persist := db.NewPersistMock()
+	session, _ := conns.DB().NewSession("pvm_test_subnet", cfg.RequestTimeout)
+	cCtx := services.NewConsumerContext(ctx, session, time.Now().Unix(), 0, persist, testXChainID.String())
+
+	owner := ids.ShortID{1}
+	createSubnet := &txs.Tx{Unsigned: &txs.CreateSubnetTx{
+		Owner: &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{owner}},
+	}}
+	subnetID := createSubnet.ID()
+	nodeID := ids.NodeID{2}
+	for _, unsigned := range []txs.UnsignedTx{
+		createSubnet.Unsigned,
+		&txs.CreateChainTx{SubnetID: subnetID, BlockchainName: "chain", VMID: ids.ID{3}, FxIDs: []ids.ID{{4}}},
+		&txs.AddSubnetValidatorTx{SubnetValidator: txs.SubnetValidator{
+			Validator: txs.Validator{NodeID: nodeID, Start: 1, End: 2, Wght: 10},
+			Subnet:    subnetID,
+		}},
+		&txs.RemoveSubnetValidatorTx{NodeID: nodeID, Subnet: subnetID},
+		&txs.TransformSubnetTx{Subnet: subnetID, AssetID: ids.ID{5}},
+	} {
+		tx := &txs.Tx{Unsigned: unsigned}
+		if err := writer.indexTransaction(cCtx, tx.ID(), tx, false); err != nil {
+			t.Fatal("insert failed", err)
+		}
+	}
+
+	subnet := persist.Subnets[subnetID.String()]
+	if subnet == nil || subnet.Threshold != 1 || subnet.AssetID != (ids.ID{5}).String() {
+		t.Fatal("subnet insert failed", subnet)
+	}
+	if persist.SubnetOwners[subnetID.String()+":"+owner.String()] == nil {
+		t.Fatal("subnet owner insert failed")
+	}
+	if len(persist.Blockchains) != 1 {
+		t.Fatal("blockchain insert failed")
+	}
+	for _, chain := range persist.Blockchains {
+		if chain.SubnetID != subnetID.String() || chain.Name != "chain" || chain.FxIDs != (ids.ID{4}).String() {
+			t.Fatal("unexpected blockchain", chain)
+		}
+	}
+	if len(persist.SubnetValidators) != 1 {
+		t.Fatal("subnet validator insert failed")
+	}
+	for _, validator := range persist.SubnetValidators {
+		if validator.Weight != 10 || validator.RemovedAt == nil {
+			t.Fatal("unexpected subnet validator", validator)
+		}
+	}
+}
+
 func TestCommonBlock(t *testing.T) {
 	conns, writer, _, closeFn := newTestIndex(t, 5, testXChainID)
 	defer closeFn()
@@ -5,10 +5,12 @@ package pvm
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/gocraft/dbr/v2"
@@ -20,6 +22,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/cb58"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
@@ -27,6 +30,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/components/multisig"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/proposervm/block"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -55,7 +59,7 @@ type Writer struct {
 	ctx  *snow.Context
 }
 
-func NewWriter(networkID uint32, chainID string) (*Writer, error) {
+func NewWriter(networkID uint32, chainID string, conf *cfg.Config) (*Writer, error) {
 	_, avaxAssetID, err := genesis.FromConfig(genesis.GetConfig(networkID))
 	if err != nil {
 		return nil, err
@@ -82,7 +86,7 @@ func NewWriter(networkID uint32, chainID string) (*Writer, error) {
 		chainID:     chainID,
 		networkID:   networkID,
 		avaxAssetID: avaxAssetID,
-		avax:        avaxIndexer.NewWriter(chainID, avaxAssetID),
+		avax:        avaxIndexer.NewWriter(chainID, avaxAssetID, conf != nil && conf.Outbox.Sink != ""),
 		ctx:         ctx,
 	}, nil
 }
@@ -460,19 +464,31 @@ func (w *Writer) indexTransaction(ctx services.ConsumerCtx, blkID ids.ID, tx *tx
 			ChainID: w.chainID,
 		}
 		typ = models.TransactionTypeAddValidator
-		err := w.InsertTransactionValidator(ctx, txID, castTx.Validator)
+		err := w.InsertTransactionValidator(ctx, txID, castTx.Validator, false)
 		if err != nil {
 			return err
 		}
 	case *txs.AddSubnetValidatorTx:
 		baseTx = castTx.BaseTx.BaseTx
 		typ = models.TransactionTypeAddSubnetValidator
+		err := w.InsertSubnetValidator(ctx, txID, castTx.SubnetValidator.Subnet, castTx.SubnetValidator.Validator)
+		if err != nil {
+			return err
+		}
 	case *txs.CreateSubnetTx:
 		baseTx = castTx.BaseTx.BaseTx
 		typ = models.TransactionTypeCreateSubnet
+		err := w.InsertSubnet(ctx, txID, castTx.Owner)
+		if err != nil {
+			return err
+		}
 	case *txs.CreateChainTx:
 		baseTx = castTx.BaseTx.BaseTx
 		typ = models.TransactionTypeCreateChain
+		err := w.InsertBlockchain(ctx, txID, castTx)
+		if err != nil {
+			return err
+		}
 	case *txs.ImportTx:
 		baseTx = castTx.BaseTx.BaseTx
 		ins = &avaxIndexer.AddInsContainer{
@@ -492,15 +508,30 @@ func (w *Writer) indexTransaction(ctx services.ConsumerCtx, blkID ids.ID, tx *tx
 	case *txs.RemoveSubnetValidatorTx:
 		baseTx = castTx.BaseTx.BaseTx
 		typ = models.TransactionTypeRemoveSubnetValidator
+		err := w.UpdateSubnetValidatorRemoved(ctx, castTx.Subnet, castTx.NodeID)
+		if err != nil {
+			return err
+		}
 	case *txs.TransformSubnetTx:
 		baseTx = castTx.BaseTx.BaseTx
 		typ = models.TransactionTypeTransformSubnet
+		err := ctx.Persist().UpdateSubnetAssetID(ctx.Ctx(), ctx.DB(), &db.Subnet{
+			ID:      castTx.Subnet.String(),
+			AssetID: castTx.AssetID.String(),
+		})
+		if err != nil {
+			return err
+		}
 	case *txs.AddPermissionlessValidatorTx:
 		baseTx = castTx.BaseTx.BaseTx
 		typ = models.TransactionTypeAddPermissionlessValidator
 
-		// TODO: Handle this for all subnetIDs
+		// TODO: Handle the stake of all subnetIDs
 		if castTx.Subnet != constants.PrimaryNetworkID {
+			err := w.InsertSubnetValidator(ctx, txID, castTx.Subnet, castTx.Validator)
+			if err != nil {
+				return err
+			}
 			break
 		}
 
@@ -509,7 +540,7 @@ func (w *Writer) indexTransaction(ctx services.ConsumerCtx, blkID ids.ID, tx *tx
 			Stake:   true,
 			ChainID: w.chainID,
 		}
-		err := w.InsertTransactionValidator(ctx, txID, castTx.Validator)
+		err := w.InsertTransactionValidator(ctx, txID, castTx.Validator, false)
 		if err != nil {
 			return err
 		}
@@ -527,7 +558,7 @@ func (w *Writer) indexTransaction(ctx services.ConsumerCtx, blkID ids.ID, tx *tx
 			Stake:   true,
 			ChainID: w.chainID,
 		}
-		err := w.InsertTransactionValidator(ctx, txID, castTx.Validator)
+		err := w.InsertTransactionValidator(ctx, txID, castTx.Validator, true)
 		if err != nil {
 			return err
 		}
@@ -535,7 +566,7 @@ func (w *Writer) indexTransaction(ctx services.ConsumerCtx, blkID ids.ID, tx *tx
 		innerTx := castTx.AddValidatorTx
 		baseTx = innerTx.BaseTx.BaseTx
 		typ = models.TransactionTypeAddValidator
-		err := w.InsertTransactionValidator(ctx, txID, innerTx.Validator)
+		err := w.InsertTransactionValidator(ctx, txID, innerTx.Validator, false)
 		if err != nil {
 			return err
 		}
@@ -587,6 +618,10 @@ func (w *Writer) indexTransaction(ctx services.ConsumerCtx, blkID ids.ID, tx *tx
 			Outs:         castTx.Outs,
 		}
 		typ = models.TransactionTypeCaminoRewardValidator
+		err := w.UpdateTransactionValidatorRemoved(ctx, castTx.TxID)
+		if err != nil {
+			return err
+		}
 	case *txs.AddDepositOfferTx:
 		baseTx = castTx.BaseTx.BaseTx
 		typ = models.TransactionTypeAddDepositOffer
@@ -659,17 +694,99 @@ func (w *Writer) insertReward(ctx services.ConsumerCtx, txID ids.ID, rewardOwner
 	return nil
 }
 
-func (w *Writer) InsertTransactionValidator(ctx services.ConsumerCtx, txID ids.ID, validator txs.Validator) error {
+func (w *Writer) InsertTransactionValidator(ctx services.ConsumerCtx, txID ids.ID, validator txs.Validator, delegator bool) error {
 	transactionsValidator := &db.TransactionsValidator{
 		ID:        txID.String(),
 		NodeID:    validator.NodeID.String(),
 		Start:     validator.Start,
 		End:       validator.End,
+		Weight:    validator.Wght,
+		Delegator: delegator,
 		CreatedAt: ctx.Time(),
 	}
 	return ctx.Persist().InsertTransactionsValidator(ctx.Ctx(), ctx.DB(), transactionsValidator, cfg.PerformUpdates)
 }
 
+// UpdateTransactionValidatorRemoved marks the staker added by txID as removed
+// from the validator set by the reward transaction being indexed
+func (w *Writer) UpdateTransactionValidatorRemoved(ctx services.ConsumerCtx, txID ids.ID) error {
+	removedAt := ctx.Time()
+	transactionsValidator := &db.TransactionsValidator{
+		ID:        txID.String(),
+		RemovedAt: &removedAt,
+	}
+	return ctx.Persist().UpdateTransactionsValidatorRemoved(ctx.Ctx(), ctx.DB(), transactionsValidator)
+}
+
+func (w *Writer) InsertSubnet(ctx services.ConsumerCtx, txID ids.ID, owner fx.Owner) error {
+	owners, ok := owner.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return fmt.Errorf("subnet owner %T", owner)
+	}
+	err := ctx.Persist().InsertSubnet(ctx.Ctx(), ctx.DB(), &db.Subnet{
+		ID:        txID.String(),
+		Threshold: owners.Threshold,
+		Locktime:  owners.Locktime,
+		CreatedAt: ctx.Time(),
+	}, cfg.PerformUpdates)
+	if err != nil {
+		return err
+	}
+	for _, addr := range owners.Addrs {
+		err = ctx.Persist().InsertSubnetOwner(ctx.Ctx(), ctx.DB(), &db.SubnetOwner{
+			SubnetID:  txID.String(),
+			Address:   addr.String(),
+			CreatedAt: ctx.Time(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Writer) InsertBlockchain(ctx services.ConsumerCtx, txID ids.ID, tx *txs.CreateChainTx) error {
+	fxIDs := make([]string, 0, len(tx.FxIDs))
+	for _, fxID := range tx.FxIDs {
+		fxIDs = append(fxIDs, fxID.String())
+	}
+	blockchain := &db.Blockchain{
+		ID:          txID.String(),
+		SubnetID:    tx.SubnetID.String(),
+		Name:        tx.BlockchainName,
+		VMID:        tx.VMID.String(),
+		FxIDs:       strings.Join(fxIDs, ","),
+		GenesisHash: hex.EncodeToString(hashing.ComputeHash256(tx.GenesisData)),
+		CreatedAt:   ctx.Time(),
+	}
+	return ctx.Persist().InsertBlockchain(ctx.Ctx(), ctx.DB(), blockchain, cfg.PerformUpdates)
+}
+
+func (w *Writer) InsertSubnetValidator(ctx services.ConsumerCtx, txID ids.ID, subnetID ids.ID, validator txs.Validator) error {
+	subnetValidator := &db.SubnetValidator{
+		ID:        txID.String(),
+		SubnetID:  subnetID.String(),
+		NodeID:    validator.NodeID.String(),
+		Weight:    validator.Wght,
+		Start:     validator.Start,
+		End:       validator.End,
+		CreatedAt: ctx.Time(),
+	}
+	return ctx.Persist().InsertSubnetValidator(ctx.Ctx(), ctx.DB(), subnetValidator, cfg.PerformUpdates)
+}
+
+// UpdateSubnetValidatorRemoved marks the node as removed from the validators
+// of the subnet by the RemoveSubnetValidatorTx being indexed
+func (w *Writer) UpdateSubnetValidatorRemoved(ctx services.ConsumerCtx, subnetID ids.ID, nodeID ids.NodeID) error {
+	removedAt := ctx.Time()
+	subnetValidator := &db.SubnetValidator{
+		SubnetID:  subnetID.String(),
+		NodeID:    nodeID.String(),
+		RemovedAt: &removedAt,
+	}
+	return ctx.Persist().UpdateSubnetValidatorRemoved(ctx.Ctx(), ctx.DB(), subnetValidator)
+}
+
 func (w *Writer) InsertTransactionBlock(ctx services.ConsumerCtx, txID ids.ID, blkTxID ids.ID) error {
 	transactionsBlock := &db.TransactionsBlock{
 		ID:        txID.String(),
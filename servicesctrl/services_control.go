@@ -14,13 +14,21 @@
 package servicesctrl
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/chain4travel/magellan/caching"
 	"github.com/chain4travel/magellan/cfg"
 	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/labels"
+	"github.com/chain4travel/magellan/retention"
+	"github.com/chain4travel/magellan/supply"
 	"github.com/chain4travel/magellan/utils"
+	"github.com/gocraft/dbr/v2"
+	"go.uber.org/zap"
 
 	avlancheGoUtils "github.com/ava-labs/avalanchego/utils"
 )
@@ -51,12 +59,30 @@ type Control struct {
 	BalanceManager             utils.ExecIface
 	GenesisContainer           *utils.GenesisContainer
 	IsAccumulateBalanceIndexer bool
-	IsAccumulateBalanceReader  bool
 	IsDisableBootstrap         bool
-	IsAggregateCache           bool
 	IndexedList                utils.IndexedList
 	LocalTxPool                chan *LocalTxPoolJob
 	AggregatesCache            caching.AggregatesCache
+	APICache                   caching.Cache
+	Pauses                     utils.Pauses
+
+	settingsLock sync.RWMutex
+	settings     settings
+
+	// discovered holds the chains started by the chain discovery
+	discoveredLock sync.RWMutex
+	discovered     map[string]cfg.Chain
+}
+
+// settings are the parts of the config which are applied again on a reload
+type settings struct {
+	accumulateBalanceReader bool
+	aggregateCache          bool
+	geoIP                   cfg.EndpointService
+	inmutableInsights       cfg.EndpointService
+	cacheUpdateInterval     time.Duration
+	cacheStatisticsInterval time.Duration
+	cacheEmissionsInterval  time.Duration
 }
 
 func (s *Control) Logger() logging.Logger {
@@ -64,26 +90,18 @@ func (s *Control) Logger() logging.Logger {
 }
 
 func (s *Control) Init(networkID uint32) error {
-	s.AggregatesCache.InitCacheStorage(s.Chains)
 	s.IndexedList = utils.NewIndexedList(cfg.MaxSizedList)
 	s.LocalTxPool = make(chan *LocalTxPoolJob, cfg.MaxTxPoolSize)
+	s.Pauses = utils.NewPauses()
 
 	if _, ok := s.Features["accumulate_balance_indexer"]; ok {
 		s.Log.Info("enable feature accumulate_balance_indexer")
 		s.IsAccumulateBalanceIndexer = true
-
-		// reader will work only if we enable indexer.
-		if _, ok := s.Features["accumulate_balance_reader"]; ok {
-			s.Log.Info("enable feature accumulate_balance_reader")
-			s.IsAccumulateBalanceReader = true
-		}
 	}
 	if _, ok := s.Features["disable_bootstrap"]; ok {
 		s.IsDisableBootstrap = true
 	}
-	if _, ok := s.Features["aggregate_cache"]; ok {
-		s.IsAggregateCache = true
-	}
+	s.Reload(&s.ServicesCfg)
 	var err error
 	s.GenesisContainer, err = utils.NewGenesisContainer(&s.ServicesCfg)
 	if err != nil {
@@ -93,55 +111,268 @@ func (s *Control) Init(networkID uint32) error {
 	return nil
 }
 
-func (s *Control) StartCacheScheduler(config *cfg.Config) error {
+// Reload applies the settings of the config which do not need a restart
+func (s *Control) Reload(config *cfg.Config) {
+	v := settings{
+		geoIP:                   config.Services.GeoIP,
+		inmutableInsights:       config.Services.InmutableInsights,
+		cacheUpdateInterval:     cacheInterval(config.CacheUpdateInterval, time.Second),
+		cacheStatisticsInterval: cacheInterval(config.CacheStatisticsInterval, time.Hour),
+		cacheEmissionsInterval:  cacheInterval(config.CacheEmissionsInterval, time.Hour),
+	}
+	// reader will work only if we enable indexer.
+	if _, ok := config.Features["accumulate_balance_reader"]; ok && s.IsAccumulateBalanceIndexer {
+		v.accumulateBalanceReader = true
+	}
+	if _, ok := config.Features["aggregate_cache"]; ok {
+		v.aggregateCache = true
+	}
+
+	s.settingsLock.Lock()
+	defer s.settingsLock.Unlock()
+	if v.accumulateBalanceReader != s.settings.accumulateBalanceReader {
+		s.Log.Info("feature accumulate_balance_reader", zap.Bool("enabled", v.accumulateBalanceReader))
+	}
+	if v.aggregateCache != s.settings.aggregateCache {
+		s.Log.Info("feature aggregate_cache", zap.Bool("enabled", v.aggregateCache))
+	}
+	s.settings = v
+}
+
+// cacheInterval converts an interval of the config to a duration of at least
+// one unit, the schedulers would run in a loop on an interval of 0
+func cacheInterval(value uint64, unit time.Duration) time.Duration {
+	if value == 0 {
+		return unit
+	}
+	return time.Duration(value) * unit
+}
+
+func (s *Control) IsAccumulateBalanceReader() bool {
+	s.settingsLock.RLock()
+	defer s.settingsLock.RUnlock()
+	return s.settings.accumulateBalanceReader
+}
+
+func (s *Control) IsAggregateCache() bool {
+	s.settingsLock.RLock()
+	defer s.settingsLock.RUnlock()
+	return s.settings.aggregateCache
+}
+
+func (s *Control) GeoIP() cfg.EndpointService {
+	s.settingsLock.RLock()
+	defer s.settingsLock.RUnlock()
+	return s.settings.geoIP
+}
+
+func (s *Control) InmutableInsights() cfg.EndpointService {
+	s.settingsLock.RLock()
+	defer s.settingsLock.RUnlock()
+	return s.settings.inmutableInsights
+}
+
+func (s *Control) CacheUpdateInterval() time.Duration {
+	s.settingsLock.RLock()
+	defer s.settingsLock.RUnlock()
+	return s.settings.cacheUpdateInterval
+}
+
+func (s *Control) CacheStatisticsInterval() time.Duration {
+	s.settingsLock.RLock()
+	defer s.settingsLock.RUnlock()
+	return s.settings.cacheStatisticsInterval
+}
+
+func (s *Control) CacheEmissionsInterval() time.Duration {
+	s.settingsLock.RLock()
+	defer s.settingsLock.RUnlock()
+	return s.settings.cacheEmissionsInterval
+}
+
+// AddChain registers a chain found by the chain discovery, the schedulers and
+// the readers handle it like the configured chains from then on
+func (s *Control) AddChain(chain cfg.Chain) {
+	s.discoveredLock.Lock()
+	defer s.discoveredLock.Unlock()
+	if s.discovered == nil {
+		s.discovered = make(map[string]cfg.Chain)
+	}
+	s.discovered[chain.ID] = chain
+}
+
+// IndexedChains returns the configured chains and the discovered ones
+func (s *Control) IndexedChains() map[string]cfg.Chain {
+	s.discoveredLock.RLock()
+	defer s.discoveredLock.RUnlock()
+	chains := make(map[string]cfg.Chain, len(s.Chains)+len(s.discovered))
+	for id, chain := range s.Chains {
+		chains[id] = chain
+	}
+	for id, chain := range s.discovered {
+		chains[id] = chain
+	}
+	return chains
+}
+
+// StartRollupScheduler keeps the aggregate rollups up to date. The rollups
+// are written, so this runs against the primary database.
+func (s *Control) StartRollupScheduler(config *cfg.Config) error {
 	// create new database connection
-	connections, err := s.DatabaseRO()
+	connections, err := s.Database()
 	if err != nil {
 		return err
 	}
 
-	MyTimer := time.NewTimer(time.Duration(config.CacheUpdateInterval) * time.Second)
+	MyTimer := time.NewTimer(0)
 
 	for range MyTimer.C {
 		MyTimer.Stop()
-		toDate := time.Now()
-		yesterdayDateTime := time.Now().AddDate(0, 0, -1)
-		prevWeekDateTime := time.Now().AddDate(0, 0, -7)
-		prevMonthDateTime := time.Now().AddDate(0, -1, 0)
-
-		// update cache for all chains
-		for id := range config.Chains {
-			// previous day aggregate number
-			err := s.AggregatesCache.GetAggregatesAndUpdate(s.Chains, connections, id, yesterdayDateTime, toDate, "day")
-			if err != nil {
-				return err
-			}
-			err = s.AggregatesCache.GetAggregatesFeesAndUpdate(s.Chains, connections, id, yesterdayDateTime, toDate, "day")
-			if err != nil {
-				return err
-			}
-			// previous week aggregate number
-			err = s.AggregatesCache.GetAggregatesAndUpdate(s.Chains, connections, id, prevWeekDateTime, toDate, "week")
-			if err != nil {
-				return err
-			}
-			err = s.AggregatesCache.GetAggregatesFeesAndUpdate(s.Chains, connections, id, prevWeekDateTime, toDate, "week")
-			if err != nil {
-				return err
-			}
-			// previous month aggregate number
-			err = s.AggregatesCache.GetAggregatesAndUpdate(s.Chains, connections, id, prevMonthDateTime, toDate, "month")
-			if err != nil {
-				return err
-			}
-			err = s.AggregatesCache.GetAggregatesFeesAndUpdate(s.Chains, connections, id, prevMonthDateTime, toDate, "month")
-			if err != nil {
-				return err
-			}
+		err := s.AggregatesCache.UpdateRollups(context.Background(), connections, s.IndexedChains(), time.Now())
+		if err != nil {
+			s.Log.Warn("aggregate rollup update failed", zap.Error(err))
 		}
+		MyTimer.Reset(caching.RollupInterval)
+	}
+	return nil
+}
+
+// StartRetentionScheduler periodically prunes the data which is past its
+// configured retention. It returns right away if no retention is configured.
+func (s *Control) StartRetentionScheduler(config *cfg.Config) error {
+	if !retention.NewPruner(config.Retention, nil).Enabled() {
+		return nil
+	}
 
-		MyTimer.Reset(time.Duration(config.CacheUpdateInterval) * time.Second)
+	// create new database connection
+	connections, err := s.Database()
+	if err != nil {
+		return err
+	}
+
+	MyTimer := time.NewTimer(0)
+
+	for range MyTimer.C {
+		MyTimer.Stop()
+		pruner := retention.NewPruner(config.Retention, s.IndexedChains())
+		report, err := pruner.Run(context.Background(), connections, time.Now().UTC())
+		if err != nil {
+			s.Log.Warn("retention run failed", zap.Error(err))
+		}
+		s.Log.Info("retention run finished",
+			zap.Uint64("txPoolRows", report.TxPoolRows),
+			zap.Uint64("cvmBlockRows", report.CvmBlockRows),
+			zap.Uint64("cvmTransactionRows", report.CvmTransactionRows),
+			zap.Uint64("reclaimedBytes", report.ReclaimedBytes()),
+		)
+		MyTimer.Reset(pruner.Interval())
+	}
+	return nil
+}
+
+// StartSupplyScheduler keeps the supply of the current day up to date, the
+// supplies of the past days are kept as the supply history
+func (s *Control) StartSupplyScheduler(config *cfg.Config) error {
+	calculator, err := supply.NewCalculator(*config, s.GenesisContainer, s.Persist)
+	if err != nil {
+		return err
+	}
+
+	// create new database connection
+	connections, err := s.Database()
+	if err != nil {
+		return err
+	}
+
+	MyTimer := time.NewTimer(0)
+
+	for range MyTimer.C {
+		MyTimer.Stop()
+		supplies, err := calculator.Run(context.Background(), connections, time.Now())
+		if err != nil {
+			s.Log.Warn("supply run failed", zap.Error(err))
+		} else {
+			s.Log.Info("supply run finished", zap.Int("assets", len(supplies)))
+		}
+		MyTimer.Reset(supply.Interval)
+	}
+	return nil
+}
+
+// StartHeartbeatScheduler updates the heartbeat row on the primary database.
+// The API compares the row of the read replicas to it to find lagging ones, so
+// nothing is written without read replicas. The row is updated every half of
+// the max replica lag and only if no other stream indexer updated it within
+// that time, so a single process writes it.
+func (s *Control) StartHeartbeatScheduler(config *cfg.Config) error {
+	if !utils.HasReplicas(*config.DB) {
+		return nil
+	}
+
+	// create new database connection
+	connections, err := s.Database()
+	if err != nil {
+		return err
+	}
+
+	interval := utils.ReplicaHeartbeatInterval(*config.DB)
+	MyTimer := time.NewTimer(0)
+
+	for range MyTimer.C {
+		MyTimer.Stop()
+		err := s.updateHeartbeat(connections, interval)
+		if err != nil {
+			s.Log.Warn("replica heartbeat failed", zap.Error(err))
+		}
+		MyTimer.Reset(interval)
+	}
+	return nil
+}
+
+func (s *Control) updateHeartbeat(connections *utils.Connections, interval time.Duration) error {
+	ctx := context.Background()
+	sess, err := connections.DB().NewSession("replica_heartbeat", cfg.DBTimeout)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	last, err := s.Persist.QueryReplicaHeartbeat(ctx, sess, &db.ReplicaHeartbeat{ID: utils.ReplicaHeartbeatID})
+	switch {
+	case err == nil && now.Sub(last.UpdatedAt) < interval:
+		// another stream indexer keeps the heartbeat
+		return nil
+	case err != nil && !errors.Is(err, dbr.ErrNotFound):
+		return err
+	}
+	return s.Persist.InsertReplicaHeartbeat(ctx, sess, &db.ReplicaHeartbeat{
+		ID:        utils.ReplicaHeartbeatID,
+		UpdatedAt: now,
+	})
+}
+
+// SeedLabels stores the labels of the seed file of the config, if there is
+// one
+func (s *Control) SeedLabels(config *cfg.Config) error {
+	if config.Labels.SeedFile == "" {
+		return nil
+	}
+
+	connections, err := s.Database()
+	if err != nil {
+		return err
+	}
+	defer connections.Close()
+
+	sess, err := connections.DB().NewSession("labels_seed", cfg.DBTimeout)
+	if err != nil {
+		return err
+	}
+	count, err := labels.Seed(context.Background(), s.Persist, sess, config.Labels.SeedFile, time.Now().UTC())
+	if err != nil {
+		return err
 	}
+	s.Log.Info("labels seeded", zap.String("file", config.Labels.SeedFile), zap.Int("labels", count))
 	return nil
 }
 
@@ -154,12 +385,12 @@ func (s *Control) StartStatisticsScheduler(config *cfg.Config) error {
 	if err != nil {
 		s.Logger().Info(err.Error())
 	}
-	MyTimer := time.NewTimer(time.Duration(config.CacheStatisticsInterval) * time.Hour)
+	MyTimer := time.NewTimer(s.CacheStatisticsInterval())
 
 	for range MyTimer.C {
 		MyTimer.Stop()
-		_ = s.AggregatesCache.UpdateStatistics(connections, config.Chains)
-		MyTimer.Reset(time.Duration(config.CacheStatisticsInterval) * time.Hour)
+		_ = s.AggregatesCache.UpdateStatistics(connections, s.IndexedChains())
+		MyTimer.Reset(s.CacheStatisticsInterval())
 	}
 	return nil
 }
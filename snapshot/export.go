@@ -0,0 +1,214 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/chain4travel/magellan/utils"
+	"github.com/gocraft/dbr/v2"
+)
+
+// Export writes all tables of the database to w. The tables are read in a
+// single read only transaction, so the archive holds the state of one point in
+// time even while the indexer keeps writing.
+func Export(ctx context.Context, conn *utils.Conn, networkID uint32, w io.Writer) (*Manifest, error) {
+	// statement timeouts are set per connection
+	conn.SetMaxOpenConns(1)
+	sess, err := conn.NewSession("snapshot_export", Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sess.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	version, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := listTables(ctx, tx, conn.Driver())
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		SchemaVersion: version,
+		NetworkID:     networkID,
+		Driver:        conn.Driver(),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	for _, name := range tables {
+		table := &Table{Name: name}
+		if err = exportTable(ctx, tx, table, tw); err != nil {
+			return nil, err
+		}
+		manifest.Tables = append(manifest.Tables, table)
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Name:    manifestName,
+		Mode:    0o644,
+		Size:    int64(len(manifestBytes)),
+		ModTime: manifest.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tw.Write(manifestBytes); err != nil {
+		return nil, err
+	}
+	if err = tw.Close(); err != nil {
+		return nil, err
+	}
+	if err = gzw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, tx.Commit()
+}
+
+// exportTable encodes the rows of the table into a temporary file first, as
+// the tar header needs the size up front
+func exportTable(ctx context.Context, tx *dbr.Tx, table *Table, tw *tar.Writer) error {
+	tmp, err := os.CreateTemp("", "magellan-snapshot-*.gob")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	rows, err := tx.QueryContext(ctx, "SELECT * FROM "+tx.Dialect.QuoteIdent(table.Name))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	enc := gob.NewEncoder(io.MultiWriter(tmp, hash))
+	if err = enc.Encode(&tableHeader{Table: table.Name, Columns: columns}); err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(columns))
+	scan := make([]interface{}, len(columns))
+	for i := range values {
+		scan[i] = &values[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(scan...); err != nil {
+			return err
+		}
+		if err = enc.Encode(values); err != nil {
+			return err
+		}
+		table.Rows++
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	info, err := tmp.Stat()
+	if err != nil {
+		return err
+	}
+	table.Size = uint64(info.Size())
+	table.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    table.fileName(),
+		Mode:    0o644,
+		Size:    info.Size(),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, tmp)
+	return err
+}
+
+// schemaVersion returns the migration version of the database
+func schemaVersion(ctx context.Context, runner dbr.SessionRunner) (int64, error) {
+	var migration struct {
+		Version int64
+		Dirty   bool
+	}
+	err := runner.Select("version", "dirty").
+		From(schemaMigrationsTable).
+		LoadOneContext(ctx, &migration)
+	if err != nil {
+		return 0, err
+	}
+	if migration.Dirty {
+		return 0, ErrDirtyMigrations
+	}
+	return migration.Version, nil
+}
+
+// listTables returns the tables of the database, without the migration
+// bookkeeping of golang-migrate
+func listTables(ctx context.Context, runner dbr.SessionRunner, driver string) ([]string, error) {
+	var query string
+	switch driver {
+	case utils.DriverSqlite:
+		query = "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name"
+	case utils.DriverPostgres:
+		query = "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' ORDER BY table_name"
+	default:
+		query = "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name"
+	}
+
+	var names []string
+	if _, err := runner.SelectBySql(query).LoadContext(ctx, &names); err != nil {
+		return nil, err
+	}
+	tables := make([]string, 0, len(names))
+	for _, name := range names {
+		if name != schemaMigrationsTable {
+			tables = append(tables, name)
+		}
+	}
+	return tables, nil
+}
@@ -0,0 +1,259 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/chain4travel/magellan/utils"
+	"github.com/gocraft/dbr/v2"
+)
+
+const (
+	maxBatchRows = 1000
+
+	// the number of placeholders a statement may have
+	maxPlaceholders       = 60000
+	maxSqlitePlaceholders = 999
+)
+
+// Verify checks the checksums of all tables in the archive at path and that
+// it can be imported into a database at requiredVersion of the network
+func Verify(path string, networkID uint32, requiredVersion int64) (*Manifest, error) {
+	var manifest *Manifest
+	sums := make(map[string]string)
+	err := walkArchive(path, func(hdr *tar.Header, r io.Reader) error {
+		if hdr.Name == manifestName {
+			manifest = &Manifest{}
+			return json.NewDecoder(r).Decode(manifest)
+		}
+		hash := sha256.New()
+		if _, err := io.Copy(hash, r); err != nil {
+			return err
+		}
+		sums[hdr.Name] = hex.EncodeToString(hash.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, ErrNoManifest
+	}
+	if err = manifest.validate(networkID, requiredVersion); err != nil {
+		return nil, err
+	}
+	for _, table := range manifest.Tables {
+		sum, ok := sums[table.fileName()]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingTable, table.Name)
+		}
+		if sum != table.SHA256 {
+			return nil, fmt.Errorf("%w: %s", ErrChecksum, table.Name)
+		}
+	}
+	return manifest, nil
+}
+
+// Import loads the archive at path into the database. The archive is verified
+// completely before anything is written and the checksums are verified again
+// while the tables are loaded, all in one transaction. The tables of the
+// archive must be empty, unless truncate is set, which deletes their rows
+// first.
+func Import(ctx context.Context, conn *utils.Conn, networkID uint32, path string, truncate bool) (*Manifest, error) {
+	// statement timeouts are set per connection
+	conn.SetMaxOpenConns(1)
+	sess, err := conn.NewSession("snapshot_import", Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := schemaVersion(ctx, sess)
+	if err != nil {
+		return nil, err
+	}
+	if version != utils.RequiredVersion {
+		return nil, fmt.Errorf("%w: database %d, required %d", ErrSchemaVersion, version, utils.RequiredVersion)
+	}
+
+	manifest, err := Verify(path, networkID, utils.RequiredVersion)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Driver != conn.Driver() {
+		return nil, fmt.Errorf("%w: snapshot %s, database %s", ErrDriver, manifest.Driver, conn.Driver())
+	}
+
+	tx, err := sess.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	tables := make(map[string]*Table, len(manifest.Tables))
+	for _, table := range manifest.Tables {
+		tables[table.fileName()] = table
+		if err = prepareTable(ctx, tx, table.Name, truncate); err != nil {
+			return nil, err
+		}
+	}
+
+	// the archive may have changed since it was verified
+	imported := make(map[string]struct{}, len(tables))
+	err = walkArchive(path, func(hdr *tar.Header, r io.Reader) error {
+		table, ok := tables[hdr.Name]
+		if !ok {
+			return nil
+		}
+		hash := sha256.New()
+		r = io.TeeReader(r, hash)
+		if err := importTable(ctx, tx, conn.Driver(), table, r); err != nil {
+			return err
+		}
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			return err
+		}
+		if hex.EncodeToString(hash.Sum(nil)) != table.SHA256 {
+			return fmt.Errorf("%w: %s", ErrChecksum, table.Name)
+		}
+		imported[hdr.Name] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name, table := range tables {
+		if _, ok := imported[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingTable, table.Name)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func prepareTable(ctx context.Context, sess dbr.SessionRunner, table string, truncate bool) error {
+	if truncate {
+		_, err := sess.DeleteFrom(table).ExecContext(ctx)
+		return err
+	}
+	var rows uint64
+	err := sess.Select("COUNT(*)").From(table).LoadOneContext(ctx, &rows)
+	if err != nil {
+		return err
+	}
+	if rows != 0 {
+		return fmt.Errorf("%w: %s", ErrTableNotEmpty, table)
+	}
+	return nil
+}
+
+func importTable(ctx context.Context, sess dbr.SessionRunner, driver string, table *Table, r io.Reader) error {
+	dec := gob.NewDecoder(r)
+	header := &tableHeader{}
+	if err := dec.Decode(header); err != nil {
+		return err
+	}
+
+	placeholders := maxPlaceholders
+	if driver == utils.DriverSqlite {
+		placeholders = maxSqlitePlaceholders
+	}
+	batchRows := placeholders / len(header.Columns)
+	if batchRows > maxBatchRows {
+		batchRows = maxBatchRows
+	}
+
+	var (
+		rows  uint64
+		batch *dbr.InsertStmt
+		size  int
+	)
+	flush := func() error {
+		if size == 0 {
+			return nil
+		}
+		_, err := batch.ExecContext(ctx)
+		batch, size = nil, 0
+		return err
+	}
+	for {
+		var values []interface{}
+		err := dec.Decode(&values)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if batch == nil {
+			batch = sess.InsertInto(table.Name).Columns(header.Columns...)
+		}
+		batch.Values(values...)
+		size++
+		rows++
+		if size >= batchRows {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if rows != table.Rows {
+		return fmt.Errorf("%w: %s has %d rows, expected %d", ErrRowCount, table.Name, rows, table.Rows)
+	}
+	return nil
+}
+
+// walkArchive calls fn with every file of the archive at path
+func walkArchive(path string, fn func(hdr *tar.Header, r io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gzr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = fn(hdr, tr); err != nil {
+			return err
+		}
+	}
+}
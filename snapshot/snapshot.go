@@ -0,0 +1,102 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package snapshot exports the index database into an archive and imports it
+// into another database, so new instances don't have to index from genesis.
+//
+// An archive is a gzip compressed tar file. It holds a gob encoded file per
+// table under tables/, made of a tableHeader followed by the rows, and a
+// manifest.json with the versions and the sha256 checksum of every table file.
+package snapshot
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+)
+
+const (
+	// FormatVersion is the version of the archive layout
+	FormatVersion = 1
+
+	manifestName = "manifest.json"
+	tablesDir    = "tables"
+
+	// Timeout bounds the statements of an export or import, which read and
+	// write whole tables
+	Timeout = 24 * time.Hour
+
+	schemaMigrationsTable = "schema_migrations"
+)
+
+var (
+	ErrFormatVersion   = errors.New("unsupported snapshot format version")
+	ErrSchemaVersion   = errors.New("snapshot schema version doesn't match")
+	ErrNetworkID       = errors.New("snapshot network id doesn't match")
+	ErrChecksum        = errors.New("snapshot checksum mismatch")
+	ErrNoManifest      = errors.New("snapshot has no manifest")
+	ErrTableNotEmpty   = errors.New("table is not empty")
+	ErrRowCount        = errors.New("snapshot row count mismatch")
+	ErrMissingTable    = errors.New("snapshot table missing")
+	ErrDirtyMigrations = errors.New("database migration is dirty")
+	ErrDriver          = errors.New("snapshot driver doesn't match")
+)
+
+func init() {
+	// the sql drivers return times besides the basic types gob knows
+	gob.Register(time.Time{})
+}
+
+// Manifest describes the content of an archive
+type Manifest struct {
+	FormatVersion uint32    `json:"formatVersion"`
+	SchemaVersion int64     `json:"schemaVersion"`
+	NetworkID     uint32    `json:"networkID"`
+	Driver        string    `json:"driver"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Tables        []*Table  `json:"tables"`
+}
+
+// Table is a table of an archive
+type Table struct {
+	Name   string `json:"name"`
+	Rows   uint64 `json:"rows"`
+	Size   uint64 `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+func (t *Table) fileName() string {
+	return path.Join(tablesDir, t.Name+".gob")
+}
+
+// tableHeader starts the file of a table, the rows follow as []interface{}
+// in the order of Columns
+type tableHeader struct {
+	Table   string
+	Columns []string
+}
+
+func (m *Manifest) validate(networkID uint32, requiredVersion int64) error {
+	if m.FormatVersion != FormatVersion {
+		return fmt.Errorf("%w: %d", ErrFormatVersion, m.FormatVersion)
+	}
+	if m.SchemaVersion != requiredVersion {
+		return fmt.Errorf("%w: snapshot %d, required %d", ErrSchemaVersion, m.SchemaVersion, requiredVersion)
+	}
+	if m.NetworkID != networkID {
+		return fmt.Errorf("%w: snapshot %d, configured %d", ErrNetworkID, m.NetworkID, networkID)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/utils"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	p := db.NewPersist()
+
+	source, sess := sqlitetest.New(t)
+	txPool := &db.TxPool{
+		NetworkID:     1,
+		ChainID:       "xchain",
+		MsgKey:        "key",
+		Serialization: []byte{0, 1, 2},
+		Topic:         "1-xchain-decisions",
+		CreatedAt:     time.Unix(1, 0).UTC(),
+	}
+	txPool.ComputeID()
+	if err := p.InsertTxPool(ctx, sess, txPool); err != nil {
+		t.Fatal("insert fail", err)
+	}
+	nodeIndex := &db.NodeIndex{Instance: "default", Topic: txPool.Topic, Idx: 42}
+	if err := p.InsertNodeIndex(ctx, sess, nodeIndex, false); err != nil {
+		t.Fatal("insert fail", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal("create fail", err)
+	}
+	manifest, err := Export(ctx, source.DB(), 1, f)
+	_ = f.Close()
+	if err != nil {
+		t.Fatal("export fail", err)
+	}
+	if manifest.SchemaVersion != utils.RequiredVersion || len(manifest.Tables) == 0 {
+		t.Fatal("unexpected manifest", manifest)
+	}
+
+	if _, err = Verify(archive, 2, utils.RequiredVersion); !errors.Is(err, ErrNetworkID) {
+		t.Fatal("expected network mismatch", err)
+	}
+	if _, err = Verify(archive, 1, utils.RequiredVersion+1); !errors.Is(err, ErrSchemaVersion) {
+		t.Fatal("expected schema mismatch", err)
+	}
+
+	target, tsess := sqlitetest.New(t)
+	if _, err = Import(ctx, target.DB(), 1, archive, false); err != nil {
+		t.Fatal("import fail", err)
+	}
+
+	imported, err := p.QueryTxPool(ctx, tsess, txPool)
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if string(imported.Serialization) != string(txPool.Serialization) || !imported.CreatedAt.Equal(txPool.CreatedAt) {
+		t.Fatal("compare fail", imported)
+	}
+	importedIndex, err := p.QueryNodeIndex(ctx, tsess, nodeIndex)
+	if err != nil || importedIndex.Idx != 42 {
+		t.Fatal("compare fail", err)
+	}
+
+	// the tables have rows now
+	if _, err = Import(ctx, target.DB(), 1, archive, false); !errors.Is(err, ErrTableNotEmpty) {
+		t.Fatal("expected not empty", err)
+	}
+	if _, err = Import(ctx, target.DB(), 1, archive, true); err != nil {
+		t.Fatal("import with truncate fail", err)
+	}
+
+	// a failed import leaves the tables as they were
+	broken := filepath.Join(t.TempDir(), "broken.tar.gz")
+	rewriteManifest(t, archive, broken, func(m *Manifest) {
+		for _, table := range m.Tables {
+			if table.Name == db.TableTxPool {
+				table.Rows++
+			}
+		}
+	})
+	if _, err = Import(ctx, target.DB(), 1, broken, true); !errors.Is(err, ErrRowCount) {
+		t.Fatal("expected row count mismatch", err)
+	}
+	if _, err = p.QueryTxPool(ctx, tsess, txPool); err != nil {
+		t.Fatal("truncate not rolled back", err)
+	}
+
+	mysql := filepath.Join(t.TempDir(), "mysql.tar.gz")
+	rewriteManifest(t, archive, mysql, func(m *Manifest) {
+		m.Driver = utils.DriverMysql
+	})
+	if _, err = Import(ctx, target.DB(), 1, mysql, true); !errors.Is(err, ErrDriver) {
+		t.Fatal("expected driver mismatch", err)
+	}
+}
+
+// rewriteManifest copies the archive at src to dst with the manifest changed
+// by fn
+func rewriteManifest(t *testing.T, src string, dst string, fn func(*Manifest)) {
+	f, err := os.Create(dst)
+	if err != nil {
+		t.Fatal("create fail", err)
+	}
+	defer f.Close()
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+
+	err = walkArchive(src, func(hdr *tar.Header, r io.Reader) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if hdr.Name == manifestName {
+			manifest := &Manifest{}
+			if err := json.Unmarshal(b, manifest); err != nil {
+				return err
+			}
+			fn(manifest)
+			if b, err = json.Marshal(manifest); err != nil {
+				return err
+			}
+			hdr.Size = int64(len(b))
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = tw.Write(b)
+		return err
+	})
+	if err != nil {
+		t.Fatal("rewrite fail", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal("rewrite fail", err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal("rewrite fail", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+)
+
+const (
+	BrokerDriverKafka  = "kafka"
+	BrokerDriverNATS   = "nats"
+	BrokerDriverMemory = "memory"
+
+	// DefaultBrokerGroup is the consumer group of the indexer if none is
+	// configured
+	DefaultBrokerGroup = "magellan-indexer"
+
+	// message headers, for brokers which don't carry the fields natively
+	brokerHeaderKey       = "key"
+	brokerHeaderChainID   = "chainID"
+	brokerHeaderTimestamp = "timestamp"
+)
+
+var (
+	ErrUnknownBrokerDriver = errors.New("unknown broker driver")
+	ErrBrokerClosed        = errors.New("broker closed")
+	ErrNoBrokerURLs        = errors.New("no broker urls")
+	ErrNoBroker            = errors.New("no broker configured")
+)
+
+// Broker carries the containers of the producers to topics named by
+// GetTopicName, so they can be consumed without reading the tx_pool table
+type Broker interface {
+	// Publish sends the messages to the topic as one batch
+	Publish(ctx context.Context, topic string, msgs ...*BrokerMessage) error
+
+	// Subscribe joins the consumer group on the topic. Every message of the
+	// topic is delivered to one subscriber of each group.
+	Subscribe(topic string, group string) (BrokerSubscription, error)
+	Close() error
+}
+
+type BrokerSubscription interface {
+	// Next blocks until the next message of the topic arrives or ctx is done
+	Next(ctx context.Context) (*BrokerMessage, error)
+
+	// Ack marks a message returned by Next as processed
+	Ack(ctx context.Context, msg *BrokerMessage) error
+	Close() error
+}
+
+// BrokerMessage is a container on a broker topic
+type BrokerMessage struct {
+	Key       string
+	ChainID   string
+	Body      []byte
+	Timestamp time.Time
+
+	// native is the message of the broker client, needed to acknowledge it
+	native interface{}
+}
+
+// DeadLetterTopic returns the topic the messages of topic are published to
+// which the indexer failed to process
+func DeadLetterTopic(topic string) string {
+	return topic + "-deadletter"
+}
+
+func NewBrokerMessage(txPool *db.TxPool) *BrokerMessage {
+	return &BrokerMessage{
+		Key:       txPool.MsgKey,
+		ChainID:   txPool.ChainID,
+		Body:      txPool.Serialization,
+		Timestamp: txPool.CreatedAt,
+	}
+}
+
+// TxPool returns the message as the tx_pool row the producer would have
+// written for it, so it can be handed to the same processors
+func (m *BrokerMessage) TxPool(networkID uint32, topic string) *db.TxPool {
+	txPool := &db.TxPool{
+		NetworkID:     networkID,
+		ChainID:       m.ChainID,
+		MsgKey:        m.Key,
+		Serialization: m.Body,
+		Topic:         topic,
+		CreatedAt:     m.Timestamp,
+	}
+	txPool.ComputeID()
+	return txPool
+}
+
+// NewBroker connects to the configured broker, it returns nil if none is
+// configured
+func NewBroker(conf cfg.Broker) (Broker, error) {
+	switch conf.Driver {
+	case "":
+		return nil, nil
+	case BrokerDriverKafka:
+		return newKafkaBroker(conf.URLs)
+	case BrokerDriverNATS:
+		return newNATSBroker(conf.URLs)
+	case BrokerDriverMemory:
+		return sharedMemoryBroker(conf.URLs), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBrokerDriver, conf.Driver)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"context"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// kafkaBatchTimeout bounds how long the writer waits for more messages before
+// it sends a batch. Publish blocks until its batch is written, the default of
+// a second would limit a producer to about one publish per second.
+const kafkaBatchTimeout = 10 * time.Millisecond
+
+type kafkaBroker struct {
+	brokers []string
+	writer  *kafka.Writer
+}
+
+func newKafkaBroker(brokers []string) (Broker, error) {
+	if len(brokers) == 0 {
+		return nil, ErrNoBrokerURLs
+	}
+	return &kafkaBroker{
+		brokers: brokers,
+		writer: &kafka.Writer{
+			Addr: kafka.TCP(brokers...),
+			// the messages of a container key always land on the same partition
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+			BatchTimeout:           kafkaBatchTimeout,
+		},
+	}, nil
+}
+
+func (b *kafkaBroker) Publish(ctx context.Context, topic string, msgs ...*BrokerMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	kmsgs := make([]kafka.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		kmsgs = append(kmsgs, kafka.Message{
+			Topic: topic,
+			Key:   []byte(msg.Key),
+			Value: msg.Body,
+			Time:  msg.Timestamp,
+			Headers: []kafka.Header{
+				{Key: brokerHeaderChainID, Value: []byte(msg.ChainID)},
+			},
+		})
+	}
+	return b.writer.WriteMessages(ctx, kmsgs...)
+}
+
+func (b *kafkaBroker) Subscribe(topic string, group string) (BrokerSubscription, error) {
+	return &kafkaSubscription{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: b.brokers,
+			GroupID: group,
+			Topic:   topic,
+		}),
+	}, nil
+}
+
+func (b *kafkaBroker) Close() error {
+	return b.writer.Close()
+}
+
+type kafkaSubscription struct {
+	reader *kafka.Reader
+}
+
+func (s *kafkaSubscription) Next(ctx context.Context) (*BrokerMessage, error) {
+	m, err := s.reader.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msg := &BrokerMessage{
+		Key:       string(m.Key),
+		Body:      m.Value,
+		Timestamp: m.Time,
+		native:    m,
+	}
+	for _, header := range m.Headers {
+		if header.Key == brokerHeaderChainID {
+			msg.ChainID = string(header.Value)
+		}
+	}
+	return msg, nil
+}
+
+func (s *kafkaSubscription) Ack(ctx context.Context, msg *BrokerMessage) error {
+	m, ok := msg.native.(kafka.Message)
+	if !ok {
+		return nil
+	}
+	return s.reader.CommitMessages(ctx, m)
+}
+
+func (s *kafkaSubscription) Close() error {
+	return s.reader.Close()
+}
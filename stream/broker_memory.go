@@ -0,0 +1,184 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+var (
+	memoryBrokersLock sync.Mutex
+	memoryBrokers     = make(map[string]*MemoryBroker)
+)
+
+// sharedMemoryBroker returns the in process broker of the given name, so the
+// producers and the indexer of one process talk to the same one
+func sharedMemoryBroker(urls []string) Broker {
+	name := strings.Join(urls, ",")
+
+	memoryBrokersLock.Lock()
+	defer memoryBrokersLock.Unlock()
+
+	b, ok := memoryBrokers[name]
+	if !ok {
+		b = NewMemoryBroker()
+		memoryBrokers[name] = b
+	}
+	return sharedBroker{b}
+}
+
+// sharedBroker keeps a shared broker open when one of its users is closed
+type sharedBroker struct {
+	Broker
+}
+
+func (sharedBroker) Close() error { return nil }
+
+// memoryTopic keeps the messages which a group hasn't read yet. The offsets
+// of the groups count from the first message of the topic, base is the offset
+// of messages[0].
+type memoryTopic struct {
+	messages []*BrokerMessage
+	base     int
+	offsets  map[string]int
+}
+
+// trim drops the messages which every group has read
+func (t *memoryTopic) trim() {
+	if len(t.offsets) == 0 {
+		return
+	}
+	oldest := t.base + len(t.messages)
+	for _, offset := range t.offsets {
+		if offset < oldest {
+			oldest = offset
+		}
+	}
+	n := oldest - t.base
+	if n == 0 {
+		return
+	}
+	for i := 0; i < n; i++ {
+		t.messages[i] = nil
+	}
+	t.messages = t.messages[n:]
+	t.base = oldest
+}
+
+// MemoryBroker is a Broker which keeps the messages in memory, for tests and
+// single process setups. A message is dropped once every group subscribed to
+// its topic has read it, the messages of a topic without groups are kept.
+type MemoryBroker struct {
+	lock   sync.Mutex
+	topics map[string]*memoryTopic
+	closed bool
+
+	// notify is closed and replaced whenever a message is published
+	notify chan struct{}
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		topics: make(map[string]*memoryTopic),
+		notify: make(chan struct{}),
+	}
+}
+
+func (b *MemoryBroker) topic(name string) *memoryTopic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &memoryTopic{offsets: make(map[string]int)}
+		b.topics[name] = t
+	}
+	return t
+}
+
+func (b *MemoryBroker) Publish(_ context.Context, topic string, msgs ...*BrokerMessage) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		return ErrBrokerClosed
+	}
+	t := b.topic(topic)
+	t.messages = append(t.messages, msgs...)
+	close(b.notify)
+	b.notify = make(chan struct{})
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(topic string, group string) (BrokerSubscription, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		return nil, ErrBrokerClosed
+	}
+	// a new group reads the messages which are still kept
+	t := b.topic(topic)
+	if _, ok := t.offsets[group]; !ok {
+		t.offsets[group] = t.base
+	}
+	return &memorySubscription{broker: b, topic: topic, group: group}, nil
+}
+
+func (b *MemoryBroker) Close() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if !b.closed {
+		b.closed = true
+		close(b.notify)
+	}
+	return nil
+}
+
+type memorySubscription struct {
+	broker *MemoryBroker
+	topic  string
+	group  string
+}
+
+func (s *memorySubscription) Next(ctx context.Context) (*BrokerMessage, error) {
+	for {
+		s.broker.lock.Lock()
+		if s.broker.closed {
+			s.broker.lock.Unlock()
+			return nil, ErrBrokerClosed
+		}
+		t := s.broker.topics[s.topic]
+		if offset := t.offsets[s.group]; offset < t.base+len(t.messages) {
+			msg := t.messages[offset-t.base]
+			t.offsets[s.group] = offset + 1
+			t.trim()
+			s.broker.lock.Unlock()
+			return msg, nil
+		}
+		notify := s.broker.notify
+		s.broker.lock.Unlock()
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Ack is a no-op, the group offset moves on when a message is handed out
+func (s *memorySubscription) Ack(context.Context, *BrokerMessage) error { return nil }
+
+func (s *memorySubscription) Close() error { return nil }
@@ -0,0 +1,93 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	nats "github.com/nats-io/nats.go"
+)
+
+// natsBroker publishes to core nats subjects. Core nats doesn't persist
+// messages, they only reach the subscribers connected at the time.
+type natsBroker struct {
+	conn *nats.Conn
+}
+
+func newNATSBroker(urls []string) (Broker, error) {
+	if len(urls) == 0 {
+		return nil, ErrNoBrokerURLs
+	}
+	conn, err := nats.Connect(strings.Join(urls, ","))
+	if err != nil {
+		return nil, err
+	}
+	return &natsBroker{conn: conn}, nil
+}
+
+func (b *natsBroker) Publish(_ context.Context, topic string, msgs ...*BrokerMessage) error {
+	for _, msg := range msgs {
+		m := nats.NewMsg(topic)
+		m.Data = msg.Body
+		m.Header.Set(brokerHeaderKey, msg.Key)
+		m.Header.Set(brokerHeaderChainID, msg.ChainID)
+		m.Header.Set(brokerHeaderTimestamp, strconv.FormatInt(msg.Timestamp.UnixNano(), 10))
+		if err := b.conn.PublishMsg(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *natsBroker) Subscribe(topic string, group string) (BrokerSubscription, error) {
+	sub, err := b.conn.QueueSubscribeSync(topic, group)
+	if err != nil {
+		return nil, err
+	}
+	return &natsSubscription{sub: sub}, nil
+}
+
+func (b *natsBroker) Close() error {
+	b.conn.Close()
+	return nil
+}
+
+type natsSubscription struct {
+	sub *nats.Subscription
+}
+
+func (s *natsSubscription) Next(ctx context.Context) (*BrokerMessage, error) {
+	m, err := s.sub.NextMsgWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timestamp, _ := strconv.ParseInt(m.Header.Get(brokerHeaderTimestamp), 10, 64)
+	return &BrokerMessage{
+		Key:       m.Header.Get(brokerHeaderKey),
+		ChainID:   m.Header.Get(brokerHeaderChainID),
+		Body:      m.Data,
+		Timestamp: time.Unix(0, timestamp),
+		native:    m,
+	}, nil
+}
+
+// Ack is a no-op, core nats doesn't redeliver messages
+func (s *natsSubscription) Ack(context.Context, *BrokerMessage) error { return nil }
+
+func (s *natsSubscription) Close() error {
+	return s.sub.Unsubscribe()
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+)
+
+func TestMemoryBroker(t *testing.T) {
+	b := NewMemoryBroker()
+	ctx := context.Background()
+	topic := GetTopicName(1, "cid", EventTypeDecisions)
+
+	txPool := &db.TxPool{
+		NetworkID:     1,
+		ChainID:       "cid",
+		MsgKey:        "key",
+		Serialization: []byte("container"),
+		Topic:         topic,
+		CreatedAt:     time.Unix(1, 0).UTC(),
+	}
+	txPool.ComputeID()
+
+	sub1, err := b.Subscribe(topic, "group1")
+	if err != nil {
+		t.Fatal("subscribe fail", err)
+	}
+	sub2, err := b.Subscribe(topic, "group1")
+	if err != nil {
+		t.Fatal("subscribe fail", err)
+	}
+	other, err := b.Subscribe(topic, "group2")
+	if err != nil {
+		t.Fatal("subscribe fail", err)
+	}
+
+	if err = b.Publish(ctx, topic, NewBrokerMessage(txPool)); err != nil {
+		t.Fatal("publish fail", err)
+	}
+
+	// every group receives the message once
+	msg, err := sub1.Next(ctx)
+	if err != nil {
+		t.Fatal("next fail", err)
+	}
+	if got := msg.TxPool(1, topic); got.ID != txPool.ID || string(got.Serialization) != "container" || !got.CreatedAt.Equal(txPool.CreatedAt) {
+		t.Fatal("compare fail")
+	}
+	if _, err = other.Next(ctx); err != nil {
+		t.Fatal("next fail", err)
+	}
+
+	// the message is dropped once both groups read it
+	if n := len(b.topics[topic].messages); n != 0 {
+		t.Fatal("unexpected messages kept", n)
+	}
+
+	timeoutCtx, cancelFn := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancelFn()
+	if _, err = sub2.Next(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected timeout", err)
+	}
+
+	// a waiting subscriber is woken up by the next message
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = b.Publish(ctx, topic, NewBrokerMessage(txPool))
+	}()
+	if _, err = sub2.Next(ctx); err != nil {
+		t.Fatal("next fail", err)
+	}
+	if n := len(b.topics[topic].messages); n != 1 {
+		t.Fatal("unexpected messages kept", n)
+	}
+
+	_ = b.Close()
+	if _, err = sub1.Next(ctx); !errors.Is(err, ErrBrokerClosed) {
+		t.Fatal("expected closed", err)
+	}
+}
+
+func TestNewBroker(t *testing.T) {
+	b, err := NewBroker(cfg.Broker{})
+	if err != nil || b != nil {
+		t.Fatal("expected no broker", err)
+	}
+
+	if _, err = NewBroker(cfg.Broker{Driver: "unknown"}); !errors.Is(err, ErrUnknownBrokerDriver) {
+		t.Fatal("expected unknown driver", err)
+	}
+
+	// memory brokers of the same name are shared and outlive their users
+	b1, err := NewBroker(cfg.Broker{Driver: BrokerDriverMemory, URLs: []string{"test"}})
+	if err != nil {
+		t.Fatal("broker fail", err)
+	}
+	b2, err := NewBroker(cfg.Broker{Driver: BrokerDriverMemory, URLs: []string{"test"}})
+	if err != nil {
+		t.Fatal("broker fail", err)
+	}
+	sub, err := b2.Subscribe("topic", DefaultBrokerGroup)
+	if err != nil {
+		t.Fatal("subscribe fail", err)
+	}
+	_ = b1.Close()
+	if err = b1.Publish(context.Background(), "topic", &BrokerMessage{Key: "key"}); err != nil {
+		t.Fatal("publish fail", err)
+	}
+	msg, err := sub.Next(context.Background())
+	if err != nil || msg.Key != "key" {
+		t.Fatal("next fail", err)
+	}
+}
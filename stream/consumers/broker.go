@@ -0,0 +1,275 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package consumers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/servicesctrl"
+	"github.com/chain4travel/magellan/stream"
+	"github.com/chain4travel/magellan/utils"
+)
+
+const (
+	// brokerPollTimeout bounds the wait for a message, so a stopped indexer
+	// notices it in time
+	brokerPollTimeout = time.Second
+
+	brokerRetryInterval = 250 * time.Millisecond
+
+	// brokerMaxAttempts is how often a message is processed before it is
+	// moved to the dead letter topic
+	brokerMaxAttempts = 10
+
+	MetricBrokerFailureCountKey    = "consume_broker_failure"
+	MetricBrokerDeadLetterCountKey = "consume_broker_dead_letters"
+)
+
+var errIndexerStopped = errors.New("indexer stopped")
+
+// brokerIndexer runs a worker per topic, AddChain starts the workers for the
+// topics of a discovered chain
+type brokerIndexer struct {
+	sc             *servicesctrl.Control
+	config         *cfg.Config
+	broker         stream.Broker
+	group          string
+	runningControl utils.Running
+
+	factoriesChainDB []stream.ProcessorFactoryChainDB
+
+	lock    sync.Mutex
+	fsm     map[string]stream.ProcessorDB
+	workers sync.WaitGroup
+}
+
+// BrokerIndexerFactories indexes the containers the producers publish to the
+// configured broker, instead of reading them from the tx_pool table. Every
+// topic is read by its own worker with the configured consumer group.
+func BrokerIndexerFactories(
+	sc *servicesctrl.Control,
+	config *cfg.Config,
+	factoriesChainDB []stream.ProcessorFactoryChainDB,
+	factoriesInstDB []stream.ProcessorFactoryInstDB,
+	wg *sync.WaitGroup,
+	runningControl utils.Running,
+) (Indexer, error) {
+	broker, err := stream.NewBroker(config.Broker)
+	if err != nil {
+		return nil, err
+	}
+	if broker == nil {
+		return nil, stream.ErrNoBroker
+	}
+
+	group := config.Broker.Group
+	if group == "" {
+		group = stream.DefaultBrokerGroup
+	}
+
+	utils.Prometheus.CounterInit(MetricBrokerFailureCountKey, "broker messages failed")
+	utils.Prometheus.CounterInit(MetricBrokerDeadLetterCountKey, "broker messages moved to the dead letter topic")
+
+	b := &brokerIndexer{
+		sc:               sc,
+		config:           config,
+		broker:           broker,
+		group:            group,
+		runningControl:   runningControl,
+		factoriesChainDB: factoriesChainDB,
+		fsm:              make(map[string]stream.ProcessorDB),
+	}
+
+	// the broker is closed once the indexer is stopped and all workers,
+	// including the ones of discovered chains, are done
+	b.workers.Add(1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for !runningControl.IsStopped() {
+			time.Sleep(brokerPollTimeout)
+		}
+		b.workers.Done()
+		b.workers.Wait()
+		_ = broker.Close()
+	}()
+
+	for _, chainConfig := range config.Chains {
+		if err := b.AddChain(chainConfig); err != nil {
+			return nil, err
+		}
+	}
+	for _, factory := range factoriesInstDB {
+		f, err := factory(sc, *config)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.subscribe("", f); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+func (b *brokerIndexer) AddChain(chain cfg.Chain) error {
+	for _, factory := range b.factoriesChainDB {
+		f, err := factory(b.sc, *b.config, chain.VMType, chain.ID)
+		if err != nil {
+			return err
+		}
+		if err := b.subscribe(chain.ID, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscribe starts a worker for every topic of the processor, chainID is
+// empty for processors of no chain
+func (b *brokerIndexer) subscribe(chainID string, p stream.ProcessorDB) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.runningControl.IsStopped() {
+		return errIndexerStopped
+	}
+	for _, topic := range p.Topic() {
+		if _, ok := b.fsm[topic]; ok {
+			return fmt.Errorf("duplicate topic %v", topic)
+		}
+	}
+	for _, topic := range p.Topic() {
+		sub, err := b.broker.Subscribe(topic, b.group)
+		if err != nil {
+			return err
+		}
+		conns, err := b.sc.Database()
+		if err != nil {
+			_ = sub.Close()
+			return err
+		}
+		b.fsm[topic] = p
+		b.workers.Add(1)
+		go func(topic string) {
+			defer func() {
+				b.workers.Done()
+				_ = sub.Close()
+				_ = conns.Close()
+			}()
+			consumeBroker(b.sc, b.config.NetworkID, chainID, topic, p, b.broker, sub, conns, b.runningControl)
+		}(topic)
+	}
+	return nil
+}
+
+// consumeBroker processes the messages of one topic until the indexer is
+// stopped. A message is only acknowledged after it has been processed, or
+// after it failed brokerMaxAttempts times and was published to the dead letter
+// topic. Messages stay in the broker while the consumers of the chain are
+// paused.
+func consumeBroker(
+	sc *servicesctrl.Control,
+	networkID uint32,
+	chainID string,
+	topic string,
+	p stream.ProcessorDB,
+	broker stream.Broker,
+	sub stream.BrokerSubscription,
+	conns *utils.Connections,
+	runningControl utils.Running,
+) {
+	var (
+		msg      *stream.BrokerMessage
+		attempts int
+	)
+	for !runningControl.IsStopped() {
+		if chainID != "" && sc.Pauses.IsPaused(utils.PauseConsumers, chainID) {
+			time.Sleep(brokerPollTimeout)
+			continue
+		}
+		if msg == nil {
+			var err error
+			ctx, cancelFn := context.WithTimeout(context.Background(), brokerPollTimeout)
+			msg, err = sub.Next(ctx)
+			cancelFn()
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				continue
+			case err != nil:
+				sc.Log.Warn("failed reading broker",
+					zap.String("topic", topic),
+					zap.Error(err),
+				)
+				time.Sleep(brokerRetryInterval)
+				continue
+			}
+		}
+
+		txPool := msg.TxPool(networkID, topic)
+		if err := p.Process(conns, txPool); err != nil {
+			attempts++
+			_ = utils.Prometheus.CounterInc(MetricBrokerFailureCountKey)
+			sc.Log.Warn("failed processing broker message",
+				zap.String("topic", topic),
+				zap.String("key", msg.Key),
+				zap.Int("attempt", attempts),
+				zap.Error(err),
+			)
+			if attempts < brokerMaxAttempts {
+				time.Sleep(brokerRetryInterval)
+				continue
+			}
+
+			// the message is moved aside, so it doesn't block the topic
+			deadLetterTopic := stream.DeadLetterTopic(topic)
+			ctx, cancelFn := context.WithTimeout(context.Background(), cfg.DefaultConsumeProcessWriteTimeout)
+			err = broker.Publish(ctx, deadLetterTopic, msg)
+			cancelFn()
+			if err != nil {
+				sc.Log.Warn("failed publishing to dead letter topic",
+					zap.String("topic", deadLetterTopic),
+					zap.Error(err),
+				)
+				time.Sleep(brokerRetryInterval)
+				continue
+			}
+			_ = utils.Prometheus.CounterInc(MetricBrokerDeadLetterCountKey)
+			sc.Log.Error("moved broker message to dead letter topic",
+				zap.String("topic", deadLetterTopic),
+				zap.String("key", msg.Key),
+				zap.Int("attempts", attempts),
+			)
+		}
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), cfg.DefaultConsumeProcessWriteTimeout)
+		err := sub.Ack(ctx, msg)
+		cancelFn()
+		if err != nil {
+			sc.Log.Warn("failed acknowledging broker message",
+				zap.String("topic", topic),
+				zap.Error(err),
+			)
+		}
+		msg = nil
+		attempts = 0
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package consumers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/servicesctrl"
+	"github.com/chain4travel/magellan/stream"
+	"github.com/chain4travel/magellan/utils"
+)
+
+type failingProcessor struct {
+	lock     sync.Mutex
+	attempts int
+}
+
+func (p *failingProcessor) Process(*utils.Connections, *db.TxPool) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.attempts++
+	return errors.New("broken container")
+}
+
+func (*failingProcessor) Close() error    { return nil }
+func (*failingProcessor) ID() string      { return "failing" }
+func (*failingProcessor) Topic() []string { return nil }
+
+func TestConsumeBrokerDeadLetter(t *testing.T) {
+	sc := &servicesctrl.Control{Log: logging.NoLog{}, Pauses: utils.NewPauses()}
+	utils.Prometheus.CounterInit(MetricBrokerFailureCountKey, "broker messages failed")
+	utils.Prometheus.CounterInit(MetricBrokerDeadLetterCountKey, "broker messages moved to the dead letter topic")
+
+	broker := stream.NewMemoryBroker()
+	defer broker.Close()
+	topic := stream.GetTopicName(1, "cid", stream.EventTypeDecisions)
+	sub, err := broker.Subscribe(topic, stream.DefaultBrokerGroup)
+	if err != nil {
+		t.Fatal("subscribe fail", err)
+	}
+	deadLetters, err := broker.Subscribe(stream.DeadLetterTopic(topic), stream.DefaultBrokerGroup)
+	if err != nil {
+		t.Fatal("subscribe fail", err)
+	}
+
+	ctx := context.Background()
+	if err := broker.Publish(ctx, topic, &stream.BrokerMessage{Key: "bad", ChainID: "cid"}); err != nil {
+		t.Fatal("publish fail", err)
+	}
+	if err := broker.Publish(ctx, topic, &stream.BrokerMessage{Key: "next", ChainID: "cid"}); err != nil {
+		t.Fatal("publish fail", err)
+	}
+
+	p := &failingProcessor{}
+	running := utils.NewRunning()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		consumeBroker(sc, 1, "", topic, p, broker, sub, nil, running)
+	}()
+
+	// the failing message is moved aside and the next one is read
+	waitCtx, cancelFn := context.WithTimeout(ctx, 10*time.Second)
+	defer cancelFn()
+	msg, err := deadLetters.Next(waitCtx)
+	if err != nil || msg.Key != "bad" {
+		t.Fatal("dead letter fail", err)
+	}
+	running.Close()
+	<-done
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.attempts < brokerMaxAttempts {
+		t.Fatal("unexpected attempts", p.attempts)
+	}
+}
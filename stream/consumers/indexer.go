@@ -47,9 +47,9 @@ type ConsumerFactory func(uint32, string, string, *cfg.Config) (services.Consume
 var IndexerConsumer = func(networkID uint32, chainVM string, chainID string, conf *cfg.Config) (indexer services.Consumer, err error) {
 	switch chainVM {
 	case models.AVMName:
-		indexer, err = avm.NewWriter(networkID, chainID)
+		indexer, err = avm.NewWriter(networkID, chainID, conf)
 	case models.PVMName:
-		indexer, err = pvm.NewWriter(networkID, chainID)
+		indexer, err = pvm.NewWriter(networkID, chainID, conf)
 	case models.CVMName:
 		indexer, err = cvm.NewWriter(networkID, chainID, conf)
 	default:
@@ -139,10 +139,127 @@ func Bootstrap(sc *servicesctrl.Control, networkID uint32, conf *cfg.Config, fac
 	return persist.InsertKeyValueStore(ctx, sess, keyValueStore)
 }
 
+// BootstrapChain bootstraps a chain which was discovered while running. It is
+// done once per chain, independent of the bootstrap of the configured chains.
+func BootstrapChain(sc *servicesctrl.Control, networkID uint32, conf *cfg.Config, chain cfg.Chain, factories []ConsumerFactory) error {
+	if sc.IsDisableBootstrap {
+		return nil
+	}
+
+	conns, err := sc.Database()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conns.Close()
+	}()
+
+	persist := db.NewPersist()
+	ctx := context.Background()
+	sess := conns.DB().NewSessionForEventReceiver(conns.Stream().NewJob("bootstrap-key-value"))
+
+	bootstrapValue := "true"
+	key := utils.KeyValueBootstrapChain(chain.ID)
+	keyValueStore, _ := persist.QueryKeyValueStore(ctx, sess, &db.KeyValueStore{K: key})
+	if keyValueStore.V == bootstrapValue {
+		return nil
+	}
+
+	for _, factory := range factories {
+		bootstrapfactory, err := factory(networkID, chain.VMType, chain.ID, conf)
+		if err != nil {
+			return err
+		}
+		sc.Log.Info("starting bootstrap",
+			zap.Uint32("networkID", networkID),
+			zap.String("vmType", chain.VMType),
+			zap.String("chainID", chain.ID),
+		)
+		if err := bootstrapfactory.Bootstrap(ctx, conns, sc.Persist, sc.GenesisContainer); err != nil {
+			return err
+		}
+	}
+
+	return persist.InsertKeyValueStore(ctx, sess, &db.KeyValueStore{K: key, V: bootstrapValue})
+}
+
+// Indexer consumes the topics of the chains it was started with. AddChain
+// starts consuming the topics of a chain which was discovered later.
+type Indexer interface {
+	AddChain(chain cfg.Chain) error
+}
+
 type IndexerFactoryControl struct {
 	sc     *servicesctrl.Control
-	fsm    map[string]stream.ProcessorDB
+	config *cfg.Config
 	doneCh chan struct{}
+
+	factoriesChainDB []stream.ProcessorFactoryChainDB
+
+	lock       sync.RWMutex
+	fsm        map[string]stream.ProcessorDB
+	chains     map[string]string
+	topicNames []string
+}
+
+// addProcessor registers the topics of the processor, they are read from the
+// tx_pool from the next iteration on. The topics of a chain are skipped while
+// its consumers are paused, chainID is empty for processors of no chain.
+func (c *IndexerFactoryControl) addProcessor(chainID string, f stream.ProcessorDB) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, topic := range f.Topic() {
+		if _, ok := c.fsm[topic]; ok {
+			return fmt.Errorf("duplicate topic %v", topic)
+		}
+	}
+	for _, topic := range f.Topic() {
+		c.fsm[topic] = f
+		c.chains[topic] = chainID
+		c.topicNames = append(c.topicNames, topic)
+	}
+	return nil
+}
+
+func (c *IndexerFactoryControl) processor(topic string) (stream.ProcessorDB, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	p, ok := c.fsm[topic]
+	return p, ok
+}
+
+// topics returns the topics whose consumers are not paused
+func (c *IndexerFactoryControl) topics() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	topics := make([]string, 0, len(c.topicNames))
+	for _, topic := range c.topicNames {
+		if c.paused(c.chains[topic]) {
+			continue
+		}
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+func (c *IndexerFactoryControl) paused(chainID string) bool {
+	return chainID != "" && c.sc.Pauses.IsPaused(utils.PauseConsumers, chainID)
+}
+
+func (c *IndexerFactoryControl) AddChain(chain cfg.Chain) error {
+	for _, factory := range c.factoriesChainDB {
+		f, err := factory(c.sc, *c.config, chain.VMType, chain.ID)
+		if err != nil {
+			return err
+		}
+		if err := c.addProcessor(chain.ID, f); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (c *IndexerFactoryControl) removeTxPool(conns *utils.Connections, txPool *db.TxPool) error {
@@ -166,7 +283,11 @@ func (c *IndexerFactoryControl) handleTxPool(_ int, conns *utils.Connections) {
 			if txd.Errs != nil && txd.Errs.Get() != nil {
 				continue
 			}
-			if p, ok := c.fsm[txd.TxPool.Topic]; ok {
+			// kept in the tx_pool until the consumers are resumed
+			if c.paused(txd.TxPool.ChainID) {
+				continue
+			}
+			if p, ok := c.processor(txd.TxPool.Topic); ok {
 				err := p.Process(conns, txd.TxPool)
 				if err != nil {
 					if txd.Errs != nil {
@@ -196,47 +317,34 @@ func IndexerFactories(
 	factoriesInstDB []stream.ProcessorFactoryInstDB,
 	wg *sync.WaitGroup,
 	runningControl utils.Running,
-) error {
+) (Indexer, error) {
 	ctrl := &IndexerFactoryControl{
-		sc:     sc,
-		fsm:    make(map[string]stream.ProcessorDB),
-		doneCh: make(chan struct{}),
+		sc:               sc,
+		config:           config,
+		fsm:              make(map[string]stream.ProcessorDB),
+		chains:           make(map[string]string),
+		doneCh:           make(chan struct{}),
+		factoriesChainDB: factoriesChainDB,
 	}
 
-	var topicNames []string
-
-	for _, factory := range factoriesChainDB {
-		for _, chainConfig := range config.Chains {
-			f, err := factory(sc, *config, chainConfig.VMType, chainConfig.ID)
-			if err != nil {
-				return err
-			}
-			for _, topic := range f.Topic() {
-				if _, ok := ctrl.fsm[topic]; ok {
-					return fmt.Errorf("duplicate topic %v", topic)
-				}
-				ctrl.fsm[topic] = f
-				topicNames = append(topicNames, topic)
-			}
+	for _, chainConfig := range config.Chains {
+		if err := ctrl.AddChain(chainConfig); err != nil {
+			return nil, err
 		}
 	}
 	for _, factory := range factoriesInstDB {
 		f, err := factory(sc, *config)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		for _, topic := range f.Topic() {
-			if _, ok := ctrl.fsm[topic]; ok {
-				return fmt.Errorf("duplicate topic %v", topic)
-			}
-			ctrl.fsm[topic] = f
-			topicNames = append(topicNames, topic)
+		if err := ctrl.addProcessor("", f); err != nil {
+			return nil, err
 		}
 	}
 
 	conns, err := sc.Database()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for ipos := 0; ipos < MaxTheads; ipos++ {
@@ -244,7 +352,7 @@ func IndexerFactories(
 		if err != nil {
 			_ = conns.Close()
 			close(ctrl.doneCh)
-			return err
+			return nil, err
 		}
 		go ctrl.handleTxPool(ipos, conns1)
 	}
@@ -261,6 +369,13 @@ func IndexerFactories(
 				ctx, cancelCTX := context.WithTimeout(context.Background(), IteratorTimeout)
 				defer cancelCTX()
 
+				// chains discovered while running add to the topics
+				topicNames := ctrl.topics()
+				if len(topicNames) == 0 {
+					time.Sleep(2 * time.Second)
+					return
+				}
+
 				name := "tx-pool"
 				sess, err := conns.DB().NewSession(name, IteratorTimeout)
 				if err != nil {
@@ -354,5 +469,5 @@ func IndexerFactories(
 		}
 	}()
 
-	return nil
+	return ctrl, nil
 }
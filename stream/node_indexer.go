@@ -0,0 +1,65 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/utils"
+)
+
+// NodeIndexer reads the containers of an index api endpoint from the
+// configured nodes, and fails over to the next node if the one in use fails
+// or falls behind
+type NodeIndexer struct {
+	clients []indexer.Client
+	nodes   *utils.NodeSet
+}
+
+func NewNodeIndexer(name string, conf cfg.Config, endpoint string) *NodeIndexer {
+	urls := conf.NodeURLs()
+	ni := &NodeIndexer{}
+	for _, url := range urls {
+		ni.clients = append(ni.clients, indexer.NewClient(fmt.Sprintf("%s%s", url, endpoint)))
+	}
+	ni.nodes = utils.NewNodeSet(name, urls, conf.NodeFailover, ni.lastAccepted)
+	return ni
+}
+
+func (ni *NodeIndexer) lastAccepted(ctx context.Context, node int) (uint64, error) {
+	_, idx, err := ni.clients[node].GetLastAccepted(ctx)
+	return idx, err
+}
+
+func (ni *NodeIndexer) GetContainerRange(ctx context.Context, startIndex uint64, numToFetch int) ([]indexer.Container, error) {
+	node := ni.nodes.Active()
+	containers, err := ni.clients[node].GetContainerRange(ctx, startIndex, numToFetch)
+	if err != nil && !nodeCaughtUp(err) {
+		ni.nodes.Failed(node)
+	}
+	return containers, err
+}
+
+func (ni *NodeIndexer) Close() {
+	ni.nodes.Close()
+}
+
+// nodeCaughtUp returns whether the error only tells there is nothing new to
+// read yet, which is no reason to switch nodes
+func nodeCaughtUp(err error) bool {
+	return (IndexNotReady(err) && !ChainNotReady(err)) || ZeroAcceptedContainers(err)
+}
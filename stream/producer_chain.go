@@ -84,7 +84,8 @@ type producerChainContainer struct {
 	sc                      *servicesctrl.Control
 	conns                   *utils.Connections
 	runningControl          utils.Running
-	nodeIndexer             indexer.Client
+	nodeIndexer             *NodeIndexer
+	broker                  Broker
 	conf                    cfg.Config
 	nodeIndex               *db.NodeIndex
 	nodeinstance            string
@@ -98,7 +99,8 @@ type producerChainContainer struct {
 func newContainer(
 	sc *servicesctrl.Control,
 	conf cfg.Config,
-	nodeIndexer indexer.Client,
+	nodeIndexer *NodeIndexer,
+	broker Broker,
 	topic string,
 	chainID string,
 	indexerType IndexType,
@@ -118,6 +120,7 @@ func newContainer(
 		conns:                   conns,
 		sc:                      sc,
 		nodeIndexer:             nodeIndexer,
+		broker:                  broker,
 		conf:                    conf,
 		topic:                   topic,
 		nodeinstance:            conf.NodeInstance,
@@ -183,33 +186,29 @@ func (p *producerChainContainer) ProcessNextMessage() error {
 		time.Sleep(readRPCTimeout)
 		return ErrNoMessage
 	}
+
+	txPools := make([]*db.TxPool, 0, len(containers))
 	for _, container := range containers {
-		if err != nil {
-			return err
-		}
+		txPools = append(txPools, ContainerTxPool(p.conf.NetworkID, p.chainID, p.topic, p.indexerChain, container))
+	}
 
-		var id ids.ID
-		switch p.indexerChain {
-		case IndexCChain:
-			id = container.ID
-		default:
-			// x and p we compute the hash
-			id = hashing.ComputeHash256Array(container.Bytes)
+	if p.broker != nil {
+		msgs := make([]*BrokerMessage, 0, len(txPools))
+		for _, txPool := range txPools {
+			msgs = append(msgs, NewBrokerMessage(txPool))
 		}
-
-		txPool := &db.TxPool{
-			NetworkID:     p.conf.NetworkID,
-			ChainID:       p.chainID,
-			MsgKey:        id.String(),
-			Serialization: container.Bytes,
-			Topic:         p.topic,
-			CreatedAt:     time.Unix(container.Timestamp, 0),
+		if err = p.broker.Publish(ctx, p.topic, msgs...); err != nil {
+			return err
 		}
-		txPool.ComputeID()
+	}
 
-		err = UpdateTxPool(dbWriteTimeout, p.conns, p.sc.Persist, txPool, p.sc)
-		if err != nil {
-			return err
+	for _, txPool := range txPools {
+		// with Broker.Index the indexer consumes the broker instead
+		if p.broker == nil || !p.conf.Broker.Index {
+			err = UpdateTxPool(dbWriteTimeout, p.conns, p.sc.Persist, txPool, p.sc)
+			if err != nil {
+				return err
+			}
 		}
 
 		_ = utils.Prometheus.CounterInc(p.metricProcessedCountKey)
@@ -236,6 +235,41 @@ func (p *producerChainContainer) ProcessNextMessage() error {
 	return nil
 }
 
+// ContainerTxPool returns the tx_pool row for a container read from the node
+// index of the chain
+func ContainerTxPool(networkID uint32, chainID string, topic string, indexerChain IndexedChain, container indexer.Container) *db.TxPool {
+	var id ids.ID
+	switch indexerChain {
+	case IndexCChain:
+		id = container.ID
+	default:
+		// x and p we compute the hash
+		id = hashing.ComputeHash256Array(container.Bytes)
+	}
+
+	txPool := &db.TxPool{
+		NetworkID:     networkID,
+		ChainID:       chainID,
+		MsgKey:        id.String(),
+		Serialization: container.Bytes,
+		Topic:         topic,
+		CreatedAt:     time.Unix(container.Timestamp, 0),
+	}
+	txPool.ComputeID()
+	return txPool
+}
+
+// NodeIndexEndpoint returns the path of the node index api of a chain
+func NodeIndexEndpoint(indexerChain IndexedChain, indexerType IndexType) string {
+	return NodeIndexAliasEndpoint(indexerChain.String(), indexerType)
+}
+
+// NodeIndexAliasEndpoint returns the path of the node index api of the chain
+// with the alias, the chains of a subnet are indexed under their ID
+func NodeIndexAliasEndpoint(alias string, indexerType IndexType) string {
+	return fmt.Sprintf("/ext/index/%s/%s", alias, indexerType)
+}
+
 func (p *producerChainContainer) insertNodeIndex(conns *utils.Connections, nodeIndex *db.NodeIndex) error {
 	sess := conns.DB().NewSessionForEventReceiver(conns.Stream().NewJob("update-node-index"))
 
@@ -269,18 +303,30 @@ type ProducerChain struct {
 
 	topic string
 
-	nodeIndexer  indexer.Client
+	nodeIndexer  *NodeIndexer
+	broker       Broker
 	chainID      string
 	indexerType  IndexType
 	indexerChain IndexedChain
 }
 
 func NewProducerChain(sc *servicesctrl.Control, conf cfg.Config, chainID string, eventType EventType, indexerType IndexType, indexerChain IndexedChain) (*ProducerChain, error) {
+	return NewProducerChainAlias(sc, conf, chainID, eventType, indexerType, indexerChain, indexerChain.String())
+}
+
+// NewProducerChainAlias creates a producer which reads the node index of the
+// chain with the alias, indexerChain is the kind of chain the containers
+// belong to
+func NewProducerChainAlias(sc *servicesctrl.Control, conf cfg.Config, chainID string, eventType EventType, indexerType IndexType, indexerChain IndexedChain, alias string) (*ProducerChain, error) {
 	topicName := GetTopicName(conf.NetworkID, chainID, eventType)
 
-	endpoint := fmt.Sprintf("/ext/index/%s/%s", indexerChain, indexerType)
+	nodeIndexer := NewNodeIndexer(fmt.Sprintf("indexer_%s_%s", chainID, eventType), conf, NodeIndexAliasEndpoint(alias, indexerType))
 
-	nodeIndexer := indexer.NewClient(fmt.Sprintf("%s%s", conf.CaminoNode, endpoint))
+	broker, err := NewBroker(conf.Broker)
+	if err != nil {
+		nodeIndexer.Close()
+		return nil, err
+	}
 
 	p := &ProducerChain{
 		indexerType:             indexerType,
@@ -295,6 +341,7 @@ func NewProducerChain(sc *servicesctrl.Control, conf cfg.Config, chainID string,
 		id:                      fmt.Sprintf("producer %d %s %s", conf.NetworkID, chainID, eventType),
 		runningControl:          utils.NewRunning(),
 		nodeIndexer:             nodeIndexer,
+		broker:                  broker,
 	}
 	utils.Prometheus.CounterInit(p.metricProcessedCountKey, "records processed")
 	utils.Prometheus.CounterInit(p.metricSuccessCountKey, "records success")
@@ -306,6 +353,10 @@ func NewProducerChain(sc *servicesctrl.Control, conf cfg.Config, chainID string,
 
 func (p *ProducerChain) Close() error {
 	p.runningControl.Close()
+	p.nodeIndexer.Close()
+	if p.broker != nil {
+		return p.broker.Close()
+	}
 	return nil
 }
 
@@ -369,7 +420,7 @@ func (p *ProducerChain) runProcessor() error {
 		zap.String("id", id),
 	)
 
-	pc, err := newContainer(p.sc, p.conf, p.nodeIndexer, p.topic, p.chainID, p.indexerType, p.indexerChain, p.metricProcessedCountKey)
+	pc, err := newContainer(p.sc, p.conf, p.nodeIndexer, p.broker, p.topic, p.chainID, p.indexerType, p.indexerChain, p.metricProcessedCountKey)
 	if err != nil {
 		return err
 	}
@@ -424,10 +475,23 @@ func (p *ProducerChain) runProcessor() error {
 	}
 
 	// Process messages until asked to stop
+	paused := false
 	for {
 		if p.runningControl.IsStopped() || pc.runningControl.IsStopped() {
 			break
 		}
+		if p.sc.Pauses.IsPaused(utils.PauseProducers, p.chainID) {
+			paused = true
+			time.Sleep(pausedInterval)
+			continue
+		}
+		// the node index may have been reset while paused
+		if paused {
+			paused = false
+			if err := pc.getIndex(); err != nil {
+				return err
+			}
+		}
 		err := processNextMessage()
 		if err != nil {
 			return err
@@ -16,6 +16,8 @@ package stream
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/chain4travel/magellan/services"
@@ -25,6 +27,7 @@ const (
 	dbReadTimeout  = 10 * time.Second
 	dbWriteTimeout = time.Minute
 	readRPCTimeout = 500 * time.Millisecond
+	pausedInterval = time.Second
 )
 
 var (
@@ -69,3 +72,20 @@ func NewMessage(id string,
 func GetTopicName(networkID uint32, chainID string, eventType EventType) string {
 	return fmt.Sprintf("%d-%s-%s", networkID, chainID, eventType)
 }
+
+// TopicChainID returns the chain of a topic of the network
+func TopicChainID(networkID uint32, topic string) (string, error) {
+	parts := strings.Split(topic, "-")
+	if len(parts) != 3 || parts[1] == "" {
+		return "", ErrInvalidTopicName
+	}
+	if parts[0] != strconv.FormatUint(uint64(networkID), 10) {
+		return "", ErrWrongTopicNetworkID
+	}
+	switch EventType(parts[2]) {
+	case EventTypeConsensus, EventTypeDecisions:
+	default:
+		return "", ErrWrongTopicEventType
+	}
+	return parts[1], nil
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTopicChainID(t *testing.T) {
+	chainID, err := TopicChainID(5, GetTopicName(5, "cid", EventTypeConsensus))
+	if err != nil || chainID != "cid" {
+		t.Fatal("unexpected chain", chainID, err)
+	}
+
+	for topic, expected := range map[string]error{
+		"5-cid":           ErrInvalidTopicName,
+		"5--decisions":    ErrInvalidTopicName,
+		"1-cid-decisions": ErrWrongTopicNetworkID,
+		"5-cid-blocks":    ErrWrongTopicEventType,
+	} {
+		if _, err := TopicChainID(5, topic); !errors.Is(err, expected) {
+			t.Fatal("unexpected error", topic, err)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package supply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/utils"
+	"github.com/gocraft/dbr/v2"
+)
+
+const (
+	// Interval is the time between two runs, each run overwrites the supply
+	// of the current day
+	Interval = time.Hour
+
+	day = 24 * time.Hour
+)
+
+// weiPerNAVAX converts the C-chain balances to the denomination of the outputs
+var weiPerNAVAX = big.NewInt(1_000_000_000)
+
+// Calculator computes the supply of the native asset and the X-chain assets
+// from the unspent outputs. The native asset is held on the C-chain as well,
+// its balance there is derived from the C-chain genesis allocations, the
+// imports and exports of the C-chain and the fees burned by its transactions.
+type Calculator struct {
+	persist     db.Persist
+	avaxAssetID string
+	cchainID    string
+	genesisTime time.Time
+
+	// cchainGenesis is the native asset allocated by the C-chain genesis
+	cchainGenesis uint64
+}
+
+func NewCalculator(conf cfg.Config, gc *utils.GenesisContainer, persist db.Persist) (*Calculator, error) {
+	c := &Calculator{
+		persist:     persist,
+		avaxAssetID: gc.AvaxAssetID.String(),
+		cchainID:    conf.CchainID,
+		genesisTime: time.Unix(int64(gc.Time), 0).UTC(),
+	}
+	for _, chain := range gc.Genesis.Chains {
+		createChain, ok := chain.Unsigned.(*txs.CreateChainTx)
+		if !ok || createChain.VMID != constants.EVMID {
+			continue
+		}
+		alloc, err := cchainGenesisAlloc(createChain.GenesisData)
+		if err != nil {
+			return nil, err
+		}
+		c.cchainGenesis = alloc
+	}
+	return c, nil
+}
+
+// cchainGenesisAlloc sums the balances allocated by the C-chain genesis
+func cchainGenesisAlloc(genesisData []byte) (uint64, error) {
+	var genesis struct {
+		Alloc map[string]struct {
+			Balance string `json:"balance"`
+		} `json:"alloc"`
+	}
+	if err := json.Unmarshal(genesisData, &genesis); err != nil {
+		return 0, err
+	}
+	sum := new(big.Int)
+	for address, account := range genesis.Alloc {
+		balance, ok := new(big.Int).SetString(account.Balance, 0)
+		if !ok {
+			return 0, fmt.Errorf("invalid c-chain genesis balance of %s: %s", address, account.Balance)
+		}
+		sum.Add(sum, balance)
+	}
+	return sum.Div(sum, weiPerNAVAX).Uint64(), nil
+}
+
+// Run computes the supply of the assets and stores it as the supply of the
+// day of now
+func (c *Calculator) Run(ctx context.Context, conns *utils.Connections, now time.Time) ([]*db.Supply, error) {
+	dbRunner, err := conns.Primary().NewSession("supply", cfg.DBTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	supplies, err := c.Compute(ctx, dbRunner, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, supply := range supplies {
+		if err := c.persist.InsertSupply(ctx, dbRunner, supply); err != nil {
+			return nil, err
+		}
+	}
+	return supplies, nil
+}
+
+// Compute returns the current supply of the native asset and the assets
+// created on the X-chain
+func (c *Calculator) Compute(ctx context.Context, dbRunner *dbr.Session, now time.Time) ([]*db.Supply, error) {
+	var unspent []*struct {
+		AssetID   string
+		Total     uint64
+		Deposited uint64
+		Bonded    uint64
+		Locked    uint64
+	}
+	_, err := dbRunner.
+		Select(
+			"asset_id",
+			db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM(amount), 0)")+" AS total",
+			lockedColumn(dbRunner.Dialect, "deposited", models.OutputTypesLockedOutD, models.OutputTypesLockedOutDB),
+			lockedColumn(dbRunner.Dialect, "bonded", models.OutputTypesLockedOutB, models.OutputTypesLockedOutDB),
+			lockedColumn(dbRunner.Dialect, "locked", models.OutputTypesLockedOutD, models.OutputTypesLockedOutB, models.OutputTypesLockedOutDB),
+		).
+		From(db.TableOutputs).
+		Where("asset_id IN ?", dbr.Select("id").From(db.TableAssets)).
+		Where("NOT EXISTS ?", dbr.Select("1").
+			From(db.TableOutputsRedeeming).
+			Where("avm_outputs_redeeming.id = avm_outputs.id")).
+		GroupBy("asset_id").
+		OrderAsc("asset_id").
+		LoadContext(ctx, &unspent)
+	if err != nil {
+		return nil, err
+	}
+
+	var genesis []*struct {
+		AssetID string
+		Amount  uint64
+	}
+	_, err = dbRunner.
+		Select("asset_id", db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM(amount), 0)")+" AS amount").
+		From(db.TableOutputs).
+		Where("created_at <= ?", c.genesisTime).
+		GroupBy("asset_id").
+		LoadContext(ctx, &genesis)
+	if err != nil {
+		return nil, err
+	}
+	genesisByAsset := make(map[string]uint64, len(genesis))
+	for _, g := range genesis {
+		genesisByAsset[g.AssetID] = g.Amount
+	}
+
+	// holders are counted like the current holders of the api, from the
+	// balances of the balance manager. The C-chain accounts are not counted.
+	holdersByAsset := make(map[string]uint64, len(unspent))
+	for _, u := range unspent {
+		var holders uint64
+		err = dbRunner.
+			Select("COUNT(*)").
+			From(db.HolderBalances(dbRunner).
+				Where("accumulate_balances_received.asset_id = ?", u.AssetID).
+				As("holders")).
+			LoadOneContext(ctx, &holders)
+		if err != nil {
+			return nil, err
+		}
+		holdersByAsset[u.AssetID] = holders
+	}
+
+	burned, err := sumRollupTxfee(ctx, dbRunner, "")
+	if err != nil {
+		return nil, err
+	}
+	cchainBalance, err := c.cchainBalance(ctx, dbRunner)
+	if err != nil {
+		return nil, err
+	}
+
+	bucketAt := now.UTC().Truncate(day)
+	supplies := make([]*db.Supply, 0, len(unspent))
+	for _, u := range unspent {
+		supply := &db.Supply{
+			AssetID:   u.AssetID,
+			BucketAt:  bucketAt,
+			Total:     u.Total,
+			Locked:    u.Locked,
+			Deposited: u.Deposited,
+			Bonded:    u.Bonded,
+			Genesis:   genesisByAsset[u.AssetID],
+			Holders:   holdersByAsset[u.AssetID],
+			UpdatedAt: now.UTC(),
+		}
+		// only the native asset pays fees and is held on the C-chain
+		if u.AssetID == c.avaxAssetID {
+			supply.Total += cchainBalance
+			supply.Genesis += c.cchainGenesis
+			supply.Burned = burned
+		}
+		supplies = append(supplies, supply)
+	}
+	return supplies, nil
+}
+
+// cchainBalance is the native asset held by the C-chain accounts: the genesis
+// allocations and the imports less the exports and the fees burned
+func (c *Calculator) cchainBalance(ctx context.Context, dbRunner *dbr.Session) (uint64, error) {
+	if c.cchainID == "" {
+		return 0, nil
+	}
+	imported, err := sumTransfers(ctx, dbRunner, c.avaxAssetID, "destination_chain_id", c.cchainID, "import_tx_id")
+	if err != nil {
+		return 0, err
+	}
+	exported, err := sumTransfers(ctx, dbRunner, c.avaxAssetID, "source_chain_id", c.cchainID, "export_tx_id")
+	if err != nil {
+		return 0, err
+	}
+	burned, err := sumRollupTxfee(ctx, dbRunner, c.cchainID)
+	if err != nil {
+		return 0, err
+	}
+
+	credited := c.cchainGenesis + imported
+	debited := exported + burned
+	if debited > credited {
+		return 0, nil
+	}
+	return credited - debited, nil
+}
+
+// sumTransfers sums the amounts of the cross-chain transfers of the asset to
+// or from the chain which were recorded by the transaction in txColumn
+func sumTransfers(ctx context.Context, dbRunner *dbr.Session, assetID string, chainColumn string, chainID string, txColumn string) (uint64, error) {
+	var amount uint64
+	err := dbRunner.
+		Select(db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM(amount), 0)")).
+		From(db.TableCrossChainTransfers).
+		Where("asset_id = ?", assetID).
+		Where(chainColumn+" = ?", chainID).
+		Where(txColumn+" <> ''").
+		LoadOneContext(ctx, &amount)
+	return amount, err
+}
+
+// lockedColumn sums the amounts of the outputs of the locked output types
+func lockedColumn(d dbr.Dialect, name string, outputTypes ...models.OutputType) string {
+	in := ""
+	for i, outputType := range outputTypes {
+		if i != 0 {
+			in += ","
+		}
+		in += fmt.Sprintf("%d", outputType)
+	}
+	return db.CastUnsigned(d, fmt.Sprintf("COALESCE(SUM(CASE WHEN output_type IN (%s) THEN amount ELSE 0 END), 0)", in)) + " AS " + name
+}
+
+// sumRollupTxfee sums the fees of the daily aggregate rollups of the chain, or
+// of all chains if chainID is empty. The rollups of the current day are kept up
+// to date with the hourly ones.
+func sumRollupTxfee(ctx context.Context, dbRunner *dbr.Session, chainID string) (uint64, error) {
+	builder := dbRunner.
+		Select(db.CastUnsigned(dbRunner.Dialect, "COALESCE(SUM(txfee), 0)")).
+		From(db.TableAggregateRollupsDaily)
+	if chainID != "" {
+		builder = builder.Where("chain_id = ?", chainID)
+	}
+	var txfee uint64
+	err := builder.LoadOneContext(ctx, &txfee)
+	return txfee, err
+}
@@ -0,0 +1,186 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package supply
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/db"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/utils"
+	"github.com/chain4travel/magellan/utils/sqlite/sqlitetest"
+)
+
+func TestCChainGenesisAlloc(t *testing.T) {
+	alloc, err := cchainGenesisAlloc([]byte(`{"alloc":{"0x1":{"balance":"0x3b9aca00"},"0x2":{"balance":"2000000000"}}}`))
+	if err != nil {
+		t.Fatal("alloc fail", err)
+	}
+	if alloc != 3 {
+		t.Fatal("unexpected alloc", alloc)
+	}
+	if _, err := cchainGenesisAlloc([]byte(`{"alloc":{"0x1":{"balance":"abc"}}}`)); err == nil {
+		t.Fatal("expected invalid balance error")
+	}
+}
+
+func TestRun(t *testing.T) {
+	conns, sess := sqlitetest.New(t)
+
+	ctx := context.Background()
+	persist := db.NewPersist()
+	genesisTime := time.Unix(1000, 0).UTC()
+	tm := genesisTime.Add(time.Hour)
+
+	outputs := []*db.Outputs{
+		{ID: "genesis", AssetID: "avax", Amount: 100, CreatedAt: genesisTime},
+		{ID: "spent", AssetID: "avax", Amount: 50, CreatedAt: tm},
+		{ID: "deposited", AssetID: "avax", Amount: 20, OutputType: models.OutputTypesLockedOutD, CreatedAt: tm},
+		{ID: "bonded", AssetID: "avax", Amount: 10, OutputType: models.OutputTypesLockedOutB, CreatedAt: tm},
+		{ID: "token", AssetID: "token", Amount: 7, CreatedAt: tm},
+		{ID: "unknown", AssetID: "unknown", Amount: 3, CreatedAt: tm},
+	}
+	for i, output := range outputs {
+		output.ChainID = "x"
+		output.TransactionID = "tx"
+		output.OutputIndex = uint32(i)
+		output.Threshold = 1
+		if output.OutputType == 0 {
+			output.OutputType = models.OutputTypesSECP2556K1Transfer
+		}
+		if err := persist.InsertOutputs(ctx, sess, output, false); err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+	imported, exported := tm, tm
+	for _, err := range []error{
+		persist.InsertAssets(ctx, sess, &db.Assets{ID: "avax", ChainID: "x", Denomination: 9, CreatedAt: genesisTime}, false),
+		persist.InsertAssets(ctx, sess, &db.Assets{ID: "token", ChainID: "x", CreatedAt: tm}, false),
+		persist.InsertOutputsRedeeming(ctx, sess, &db.OutputsRedeeming{
+			ID: "spent", RedeemedAt: tm, RedeemingTransactionID: "tx2", Intx: "tx", AssetID: "avax", ChainID: "x", CreatedAt: tm,
+		}, false),
+		persist.InsertCrossChainTransferImport(ctx, sess, &db.CrossChainTransfer{
+			ID: "in", ImportTxID: "import", SourceChainID: "x", DestinationChainID: "c", AssetID: "avax", Amount: 40, ImportedAt: &imported, CreatedAt: tm,
+		}),
+		persist.InsertCrossChainTransferExport(ctx, sess, &db.CrossChainTransfer{
+			ID: "out", ExportTxID: "export", SourceChainID: "c", DestinationChainID: "x", AssetID: "avax", Amount: 15, ExportedAt: &exported, CreatedAt: tm,
+		}),
+		persist.InsertAggregateRollupDaily(ctx, sess, &db.AggregateRollup{ChainID: "x", BucketAt: tm.Truncate(day), Txfee: 2, UpdatedAt: tm}, false),
+		persist.InsertAggregateRollupDaily(ctx, sess, &db.AggregateRollup{ChainID: "c", BucketAt: tm.Truncate(day), Txfee: 5, UpdatedAt: tm}, false),
+	} {
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	// the holders are read from the balances, c spent all it received
+	balances := []struct {
+		table   string
+		assetID string
+		address string
+		amount  uint64
+	}{
+		{db.TableAccumulateBalancesReceived, "avax", "a", 100},
+		{db.TableAccumulateBalancesReceived, "avax", "b", 20},
+		{db.TableAccumulateBalancesReceived, "avax", "c", 50},
+		{db.TableAccumulateBalancesSent, "avax", "c", 50},
+		{db.TableAccumulateBalancesReceived, "token", "a", 7},
+	}
+	for _, b := range balances {
+		v := &db.AccumulateBalancesAmount{ChainID: "x", AssetID: b.assetID, Address: b.address}
+		v.ComputeID()
+		_, err := sess.InsertInto(b.table).
+			Pair("id", v.ID).
+			Pair("chain_id", v.ChainID).
+			Pair("asset_id", v.AssetID).
+			Pair("address", v.Address).
+			Pair("total_amount", b.amount).
+			Pair("updated_at", tm).
+			ExecContext(ctx)
+		if err != nil {
+			t.Fatal("insert fail", err)
+		}
+	}
+
+	c := &Calculator{
+		persist:       persist,
+		avaxAssetID:   "avax",
+		cchainID:      "c",
+		genesisTime:   genesisTime,
+		cchainGenesis: 30,
+	}
+	supplies, err := c.Run(ctx, conns, tm)
+	if err != nil {
+		t.Fatal("run fail", err)
+	}
+	if len(supplies) != 2 {
+		t.Fatal("unexpected supplies", len(supplies))
+	}
+
+	// 130 unspent plus 30 + 40 - 15 - 5 on the c-chain
+	avax := supplies[0]
+	if avax.AssetID != "avax" || avax.Total != 180 || avax.Locked != 30 || avax.Deposited != 20 ||
+		avax.Bonded != 10 || avax.Burned != 7 || avax.Genesis != 130 || avax.Holders != 2 {
+		t.Fatal("unexpected avax supply", avax)
+	}
+	if token := supplies[1]; token.AssetID != "token" || token.Total != 7 || token.Burned != 0 || token.Genesis != 0 || token.Holders != 1 {
+		t.Fatal("unexpected token supply", token)
+	}
+
+	stored, err := persist.QuerySupply(ctx, sess, &db.Supply{AssetID: "avax", BucketAt: tm.Truncate(day)})
+	if err != nil {
+		t.Fatal("query fail", err)
+	}
+	if stored.Total != 180 || stored.Burned != 7 {
+		t.Fatal("unexpected stored supply", stored)
+	}
+}
+
+// TestComputePostgres runs the supply queries against a migrated postgres
+// database, set by MAGELLAN_TEST_POSTGRES_DSN
+func TestComputePostgres(t *testing.T) {
+	dsn := os.Getenv("MAGELLAN_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("MAGELLAN_TEST_POSTGRES_DSN not set")
+	}
+	conns, err := utils.NewDBFromConfig(cfg.Services{
+		DB: &cfg.DB{
+			Driver: utils.DriverPostgres,
+			DSN:    dsn,
+		},
+	}, false)
+	if err != nil {
+		t.Fatal("db fail", err)
+	}
+	defer conns.Close()
+
+	sess, err := conns.DB().NewSession("test", time.Minute)
+	if err != nil {
+		t.Fatal("session fail", err)
+	}
+
+	c := &Calculator{
+		persist:     db.NewPersist(),
+		avaxAssetID: "avax",
+		cchainID:    "c",
+		genesisTime: time.Unix(1000, 0).UTC(),
+	}
+	if _, err := c.Compute(context.Background(), sess, time.Now()); err != nil {
+		t.Fatal("compute fail", err)
+	}
+}
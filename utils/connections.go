@@ -16,6 +16,7 @@ package utils
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/chain4travel/magellan/cfg"
@@ -24,6 +25,11 @@ import (
 type Connections struct {
 	Eventer *EventRcvr
 	db      *Conn
+
+	// primary and replicas are only set for read only connections to
+	// dedicated replicas
+	primary  *Conn
+	replicas *replicaSet
 }
 
 func NewDBFromConfig(conf cfg.Services, ro bool) (*Connections, error) {
@@ -35,6 +41,10 @@ func NewDBFromConfig(conf cfg.Services, ro bool) (*Connections, error) {
 	eventer := &EventRcvr{}
 
 	if conf.DB != nil || conf.DB.Driver == DriverNone {
+		if ro && HasReplicas(*conf.DB) {
+			return newReplicaConnections(eventer, *conf.DB)
+		}
+
 		// Create connection
 		dbConn, err = New(eventer, *conf.DB, ro)
 		if err != nil {
@@ -50,11 +60,76 @@ func NewDBFromConfig(conf cfg.Services, ro bool) (*Connections, error) {
 	}, nil
 }
 
+// HasReplicas reports whether read only connections go to other databases
+// than the primary
+func HasReplicas(conf cfg.DB) bool {
+	for _, dsn := range conf.RODSNs {
+		if dsn != conf.DSN {
+			return true
+		}
+	}
+	return false
+}
+
+func newReplicaConnections(eventer *EventRcvr, conf cfg.DB) (*Connections, error) {
+	primary, err := New(eventer, conf, false)
+	if err != nil {
+		return nil, err
+	}
+
+	conns := make([]*Conn, 0, len(conf.RODSNs))
+	for _, dsn := range conf.RODSNs {
+		replicaConf := conf
+		replicaConf.RODSN = dsn
+		conn, err := New(eventer, replicaConf, true)
+		if err != nil {
+			for _, c := range conns {
+				_ = c.Close(context.Background())
+			}
+			_ = primary.Close(context.Background())
+			return nil, err
+		}
+		conns = append(conns, conn)
+	}
+
+	return &Connections{
+		db:       conns[0],
+		Eventer:  eventer,
+		primary:  primary,
+		replicas: newReplicaSet(primary, conns, time.Duration(conf.MaxReplicaLag)*time.Second),
+	}, nil
+}
+
 func (c Connections) Stream() *EventRcvr { return c.Eventer }
-func (c Connections) DB() *Conn          { return c.db }
+
+// DB returns the connection to use for a new session. Read only connections
+// are balanced over the replicas which are in sync with the primary.
+func (c Connections) DB() *Conn {
+	if c.replicas != nil {
+		return c.replicas.Next()
+	}
+	return c.db
+}
+
+// Primary returns the connection to the primary database, for reads which
+// have to see the latest writes
+func (c Connections) Primary() *Conn {
+	if c.primary != nil {
+		return c.primary
+	}
+	return c.db
+}
+
+// HasReplicas reports whether DB and Primary may return different databases
+func (c Connections) HasReplicas() bool { return c.replicas != nil }
 
 func (c Connections) Close() error {
 	errs := wrappers.Errs{}
+	if c.replicas != nil {
+		errs.Add(c.replicas.Close())
+		errs.Add(c.primary.Close(context.Background()))
+		return errs.Err
+	}
 	errs.Add(c.db.Close(context.Background()))
 	return errs.Err
 }
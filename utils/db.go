@@ -16,24 +16,39 @@ package utils
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/chain4travel/magellan/cfg"
 	"github.com/gocraft/dbr/v2"
 	"github.com/gocraft/dbr/v2/dialect"
+
+	// postgres driver, registered as "postgres"
+	_ "github.com/lib/pq"
 )
 
 const (
 	DriverMysql     = "mysql"
+	DriverPostgres  = "postgres"
+	DriverSqlite    = "sqlite3"
 	DriverNone      = ""
-	RequiredVersion = 47
+	RequiredVersion = 65
 )
 
+// ErrMigrationRequired is returned for databases whose schema is older than
+// the RequiredVersion
+var ErrMigrationRequired = errors.New("migration required")
+
+// ErrSqliteDriver is returned for DriverSqlite when the binary was built
+// without the sqlite driver
+var ErrSqliteDriver = errors.New("sqlite driver not linked, build with the sqlite tag")
+
 // Conn is a wrapper around a dbr connection and a health stream
 type Conn struct {
 	conn    *dbr.Connection
 	eventer *EventRcvr
+	driver  string
 }
 
 // New creates a new DB for the given config
@@ -46,25 +61,49 @@ func New(eventer *EventRcvr, conf cfg.DB, ro bool) (*Conn, error) {
 	result := &Conn{
 		conn:    conn,
 		eventer: eventer,
+		driver:  conf.Driver,
 	}
 	session, err := result.NewSession("startup", time.Second)
 	if err != nil {
 		return nil, err
 	}
 
-	var version int64
-	if err := session.QueryRow("SELECT version FROM schema_migrations").Scan(&version); err == nil && version < RequiredVersion {
-		return nil, fmt.Errorf("migration required")
+	if conf.Driver == DriverSqlite && !ro {
+		if err := createSqliteSchema(session); err != nil {
+			return nil, err
+		}
+	}
+
+	if version, err := SchemaVersion(session); err == nil && version < RequiredVersion {
+		return nil, fmt.Errorf("%w: schema version %d, %d required", ErrMigrationRequired, version, RequiredVersion)
 	}
 	return result, nil
 }
 
+// SchemaVersion returns the version of the last migration applied to the
+// database
+func SchemaVersion(sess *dbr.Session) (int64, error) {
+	var version int64
+	err := sess.QueryRow("SELECT version FROM schema_migrations").Scan(&version)
+	return version, err
+}
+
 func (c *Conn) Close(context.Context) error {
 	return c.conn.Close()
 }
 
 func (c *Conn) NewSession(name string, timeout time.Duration) (*dbr.Session, error) {
 	session := c.NewSessionForEventReceiver(c.eventer.NewJob(name))
+	if c.driver == DriverSqlite {
+		// sqlite has no statement timeout
+		return session, nil
+	}
+	if c.driver == DriverPostgres {
+		if _, err := session.Exec(fmt.Sprintf("SET statement_timeout = %d", timeout.Milliseconds())); err != nil {
+			return nil, err
+		}
+		return session, nil
+	}
 	if _, err := session.Exec(fmt.Sprintf("SET SESSION MAX_EXECUTION_TIME=%d", timeout.Milliseconds())); err != nil {
 		if _, err := session.Exec(fmt.Sprintf("SET @@MAX_STATEMENT_TIME=%d", timeout.Milliseconds())); err != nil {
 			return nil, err
@@ -73,6 +112,11 @@ func (c *Conn) NewSession(name string, timeout time.Duration) (*dbr.Session, err
 	return session, nil
 }
 
+// Driver returns the name of the sql driver this connection was opened with
+func (c *Conn) Driver() string {
+	return c.driver
+}
+
 func (c *Conn) NewSessionForEventReceiver(er dbr.EventReceiver) *dbr.Session {
 	return c.conn.NewSession(er)
 }
@@ -111,6 +155,16 @@ func newDBRConnection(conf cfg.DB, ro bool) (*dbr.Connection, error) {
 			return nil, err
 		}
 	}
+	if conf.Driver == DriverPostgres {
+		dbrDialect = dialect.PostgreSQL
+	}
+	if conf.Driver == DriverSqlite {
+		driver = SqliteDriverName
+		dbrDialect = dialect.SQLite3
+		if !sqliteDriverRegistered() {
+			return nil, ErrSqliteDriver
+		}
+	}
 
 	// Create the underlying connection and ping it to ensure liveness
 	rawDBConn, err := sql.Open(driver, dsn)
@@ -132,3 +186,12 @@ func newDBRConnection(conf cfg.DB, ro bool) (*dbr.Connection, error) {
 		Dialect:       dbrDialect,
 	}, nil
 }
+
+func sqliteDriverRegistered() bool {
+	for _, name := range sql.Drivers() {
+		if name == SqliteDriverName {
+			return true
+		}
+	}
+	return false
+}
@@ -14,6 +14,7 @@
 package utils
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -74,3 +75,18 @@ func TestNewErrors(t *testing.T) {
 		t.Fatal("Expected i/o or context deadline timeout")
 	}
 }
+
+func TestErrIsDuplicateEntryError(t *testing.T) {
+	if !ErrIsDuplicateEntryError(errors.New("Error 1062: Duplicate entry 'x' for key 'PRIMARY'")) {
+		t.Fatal("Expected mysql duplicate entry error")
+	}
+	if !ErrIsDuplicateEntryError(errors.New(`pq: duplicate key value violates unique constraint "avm_transactions_pkey"`)) {
+		t.Fatal("Expected postgres duplicate entry error")
+	}
+	if ErrIsDuplicateEntryError(errors.New("pq: deadlock detected")) || ErrIsDuplicateEntryError(nil) {
+		t.Fatal("Unexpected duplicate entry error")
+	}
+	if !ErrIsLockError(errors.New("pq: deadlock detected")) {
+		t.Fatal("Expected postgres lock error")
+	}
+}
@@ -13,15 +13,27 @@ import (
 const (
 	DeadlockDBErrorMessage = "Deadlock found when trying to get lock; try restarting transaction"
 	TimeoutDBErrorMessage  = "Lock wait timeout exceeded; try restarting transaction"
+
+	PostgresDuplicateEntryErrorMessage = "pq: duplicate key value violates unique constraint"
+	PostgresDeadlockDBErrorMessage     = "pq: deadlock detected"
+	PostgresTimeoutDBErrorMessage      = "pq: canceling statement due to lock timeout"
+
+	SqliteDuplicateEntryErrorMessage = "UNIQUE constraint failed"
+	SqliteLockedDBErrorMessage       = "database is locked"
 )
 
 func ErrIsDuplicateEntryError(err error) bool {
-	return err != nil && strings.HasPrefix(err.Error(), "Error 1062: Duplicate entry")
+	return err != nil && (strings.HasPrefix(err.Error(), "Error 1062: Duplicate entry") ||
+		strings.HasPrefix(err.Error(), PostgresDuplicateEntryErrorMessage) ||
+		strings.HasPrefix(err.Error(), SqliteDuplicateEntryErrorMessage))
 }
 
 func ErrIsLockError(err error) bool {
 	return err != nil && (strings.Contains(err.Error(), DeadlockDBErrorMessage) ||
-		strings.Contains(err.Error(), TimeoutDBErrorMessage))
+		strings.Contains(err.Error(), TimeoutDBErrorMessage) ||
+		strings.Contains(err.Error(), PostgresDeadlockDBErrorMessage) ||
+		strings.Contains(err.Error(), PostgresTimeoutDBErrorMessage) ||
+		strings.Contains(err.Error(), SqliteLockedDBErrorMessage))
 }
 
 func ForceParseTimeParam(dsn string) (string, error) {
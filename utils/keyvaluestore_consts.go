@@ -6,3 +6,9 @@ package utils
 const (
 	KeyValueBootstrap = "bootstrap"
 )
+
+// KeyValueBootstrapChain is the key marking a chain discovered while running as
+// bootstrapped
+func KeyValueBootstrapChain(chainID string) string {
+	return KeyValueBootstrap + ":" + chainID
+}
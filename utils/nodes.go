@@ -0,0 +1,171 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+)
+
+const (
+	DefaultNodeCheckInterval = 10 * time.Second
+
+	nodeCheckTimeout = 5 * time.Second
+)
+
+// NodeHeight returns the last accepted index or height of the node at the
+// given position of a NodeSet
+type NodeHeight func(ctx context.Context, node int) (uint64, error)
+
+// NodeSet selects which of a list of nodes a client uses. The nodes are
+// preferred in the order they are configured, a node is skipped while it
+// fails or lags behind the most advanced node by more than maxLag. A node
+// which fails a request is skipped until it passes the next health check.
+type NodeSet struct {
+	urls    []string
+	height  NodeHeight
+	maxLag  uint64
+	active  atomic.Int32
+	healthy []atomic.Bool
+
+	metricActiveKey   string
+	metricFailoverKey string
+
+	quitCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewNodeSet creates the NodeSet for the urls, name identifies it in the
+// metrics. The health of the nodes is checked in the background until the set
+// is closed, unless there is only a single node.
+func NewNodeSet(name string, urls []string, conf cfg.NodeFailover, height NodeHeight) *NodeSet {
+	ns := &NodeSet{
+		urls:              urls,
+		height:            height,
+		maxLag:            conf.MaxLag,
+		healthy:           make([]atomic.Bool, len(urls)),
+		metricActiveKey:   fmt.Sprintf("node_active_%s", name),
+		metricFailoverKey: fmt.Sprintf("node_failover_%s", name),
+		quitCh:            make(chan struct{}),
+	}
+	for i := range ns.healthy {
+		ns.healthy[i].Store(true)
+	}
+	Prometheus.GaugeInit(ns.metricActiveKey, "position of the node in use")
+	Prometheus.CounterInit(ns.metricFailoverKey, "switches to another node")
+	_ = Prometheus.GaugeSet(ns.metricActiveKey, 0)
+
+	if len(urls) > 1 {
+		interval := time.Duration(conf.CheckInterval) * time.Second
+		if interval <= 0 {
+			interval = DefaultNodeCheckInterval
+		}
+		ns.Check()
+		go ns.monitor(interval)
+	}
+	return ns
+}
+
+// Active returns the position of the node to use
+func (ns *NodeSet) Active() int {
+	return int(ns.active.Load())
+}
+
+func (ns *NodeSet) URL(node int) string {
+	return ns.urls[node]
+}
+
+// Failed takes the node out of use after a request to it failed, and switches
+// to the next healthy node
+func (ns *NodeSet) Failed(node int) {
+	if len(ns.urls) < 2 {
+		return
+	}
+	ns.healthy[node].Store(false)
+	for i := 1; i < len(ns.urls); i++ {
+		next := (node + i) % len(ns.urls)
+		if ns.healthy[next].Load() {
+			ns.activate(node, next)
+			return
+		}
+	}
+	// none is healthy, keep trying the others in turn
+	ns.activate(node, (node+1)%len(ns.urls))
+}
+
+// Check updates the health of all nodes and switches to the first healthy one
+func (ns *NodeSet) Check() {
+	ctx, cancelFn := context.WithTimeout(context.Background(), nodeCheckTimeout)
+	defer cancelFn()
+
+	heights := make([]uint64, len(ns.urls))
+	errs := make([]error, len(ns.urls))
+	var wg sync.WaitGroup
+	for i := range ns.urls {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			heights[i], errs[i] = ns.height(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+
+	var best uint64
+	for i, height := range heights {
+		if errs[i] == nil && height > best {
+			best = height
+		}
+	}
+	for i, height := range heights {
+		ns.healthy[i].Store(errs[i] == nil && best-height <= ns.maxLag)
+	}
+
+	active := ns.Active()
+	for i := range ns.urls {
+		if ns.healthy[i].Load() {
+			ns.activate(active, i)
+			return
+		}
+	}
+}
+
+func (ns *NodeSet) activate(from int, to int) {
+	if from == to || !ns.active.CompareAndSwap(int32(from), int32(to)) {
+		return
+	}
+	_ = Prometheus.CounterInc(ns.metricFailoverKey)
+	_ = Prometheus.GaugeSet(ns.metricActiveKey, float64(to))
+}
+
+func (ns *NodeSet) monitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ns.Check()
+		case <-ns.quitCh:
+			return
+		}
+	}
+}
+
+func (ns *NodeSet) Close() {
+	ns.closeOnce.Do(func() { close(ns.quitCh) })
+}
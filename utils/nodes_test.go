@@ -0,0 +1,84 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/chain4travel/magellan/cfg"
+)
+
+func TestNodeSet(t *testing.T) {
+	var lock sync.Mutex
+	heights := []uint64{100, 100, 100}
+	down := []bool{false, false, false}
+	height := func(_ context.Context, node int) (uint64, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if down[node] {
+			return 0, errors.New("down")
+		}
+		return heights[node], nil
+	}
+	set := func(node int, h uint64, d bool) {
+		lock.Lock()
+		defer lock.Unlock()
+		heights[node], down[node] = h, d
+	}
+
+	ns := NewNodeSet("test", []string{"a", "b", "c"}, cfg.NodeFailover{CheckInterval: 3600, MaxLag: 10}, height)
+	defer ns.Close()
+
+	if ns.Active() != 0 {
+		t.Fatal("Expected the first node")
+	}
+
+	// a failed request switches to the next node
+	ns.Failed(0)
+	if ns.Active() != 1 {
+		t.Fatal("Expected failover to the second node")
+	}
+
+	// a lagging node is skipped, the first healthy one is preferred
+	set(0, 80, false)
+	ns.Check()
+	if ns.Active() != 1 {
+		t.Fatal("Expected lagging node to be skipped")
+	}
+	set(0, 95, false)
+	ns.Check()
+	if ns.Active() != 0 {
+		t.Fatal("Expected the first node once it caught up")
+	}
+
+	set(0, 100, true)
+	set(1, 100, true)
+	ns.Check()
+	if ns.Active() != 2 {
+		t.Fatal("Expected the only healthy node")
+	}
+}
+
+func TestNodeSetSingle(t *testing.T) {
+	ns := NewNodeSet("single", []string{"a"}, cfg.NodeFailover{}, nil)
+	defer ns.Close()
+
+	ns.Failed(0)
+	if ns.Active() != 0 {
+		t.Fatal("Expected the only node")
+	}
+}
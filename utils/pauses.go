@@ -0,0 +1,78 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"sort"
+	"sync"
+)
+
+// Worker kinds which can be paused per chain
+const (
+	PauseProducers = "producers"
+	PauseConsumers = "consumers"
+)
+
+// Pauses keeps the chains whose producers or consumers are paused, a paused
+// worker idles until its chain is resumed
+type Pauses interface {
+	Pause(kind string, chainID string)
+	Resume(kind string, chainID string)
+	IsPaused(kind string, chainID string) bool
+	Paused(kind string) []string
+}
+
+func NewPauses() Pauses {
+	return &pauses{paused: make(map[string]map[string]struct{})}
+}
+
+type pauses struct {
+	lock   sync.RWMutex
+	paused map[string]map[string]struct{}
+}
+
+func (p *pauses) Pause(kind string, chainID string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	chains, ok := p.paused[kind]
+	if !ok {
+		chains = make(map[string]struct{})
+		p.paused[kind] = chains
+	}
+	chains[chainID] = struct{}{}
+}
+
+func (p *pauses) Resume(kind string, chainID string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.paused[kind], chainID)
+}
+
+func (p *pauses) IsPaused(kind string, chainID string) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	_, ok := p.paused[kind][chainID]
+	return ok
+}
+
+func (p *pauses) Paused(kind string) []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	chains := make([]string, 0, len(p.paused[kind]))
+	for chainID := range p.paused[kind] {
+		chains = append(chains, chainID)
+	}
+	sort.Strings(chains)
+	return chains
+}
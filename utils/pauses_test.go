@@ -0,0 +1,42 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPauses(t *testing.T) {
+	p := NewPauses()
+	p.Pause(PauseProducers, "b")
+	p.Pause(PauseProducers, "a")
+	p.Pause(PauseConsumers, "a")
+
+	if !p.IsPaused(PauseProducers, "b") || p.IsPaused(PauseConsumers, "b") {
+		t.Fatal("unexpected pause")
+	}
+	if !reflect.DeepEqual(p.Paused(PauseProducers), []string{"a", "b"}) {
+		t.Fatal("unexpected paused", p.Paused(PauseProducers))
+	}
+
+	p.Resume(PauseProducers, "a")
+	p.Resume(PauseProducers, "c")
+	if p.IsPaused(PauseProducers, "a") || !p.IsPaused(PauseConsumers, "a") {
+		t.Fatal("unexpected resume")
+	}
+	if len(p.Paused("unknown")) != 0 {
+		t.Fatal("unexpected paused", p.Paused("unknown"))
+	}
+}
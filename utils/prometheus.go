@@ -23,6 +23,7 @@ func init() {
 type Metrics struct {
 	counters    map[string]*prometheus.Counter
 	histograms  map[string]*prometheus.Histogram
+	gauges      map[string]*prometheus.Gauge
 	metricsLock sync.RWMutex
 }
 
@@ -35,6 +36,9 @@ func (m *Metrics) Init() {
 	if m.histograms == nil {
 		m.histograms = make(map[string]*prometheus.Histogram)
 	}
+	if m.gauges == nil {
+		m.gauges = make(map[string]*prometheus.Gauge)
+	}
 }
 
 func (m *Metrics) CounterInit(name string, help string) {
@@ -96,6 +100,30 @@ func (m *Metrics) HistogramObserve(name string, v float64) error {
 	return fmt.Errorf("metric not found: %s", name)
 }
 
+func (m *Metrics) GaugeInit(name string, help string) {
+	m.Init()
+	m.metricsLock.Lock()
+	defer m.metricsLock.Unlock()
+	if _, ok := m.gauges[name]; ok {
+		return
+	}
+	gauge := promauto.NewGauge(prometheus.GaugeOpts{
+		Name: name,
+		Help: help,
+	})
+	m.gauges[name] = &gauge
+}
+
+func (m *Metrics) GaugeSet(name string, v float64) error {
+	m.metricsLock.RLock()
+	defer m.metricsLock.RUnlock()
+	if gauge, ok := m.gauges[name]; ok {
+		(*gauge).Set(v)
+		return nil
+	}
+	return fmt.Errorf("metric not found: %s", name)
+}
+
 type Collector interface {
 	Error()
 	Collect() error
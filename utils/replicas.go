@@ -0,0 +1,160 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+)
+
+const (
+	DefaultMaxReplicaLag = 5 * time.Second
+
+	ReplicaHeartbeatID = 1
+
+	replicaHeartbeatTable   = "replica_heartbeat"
+	replicaLagCheckInterval = 2 * time.Second
+	replicaLagQueryTimeout  = 5 * time.Second
+
+	MetricReplicaLaggingCountKey = "db_replica_lagging"
+)
+
+type replica struct {
+	conn    *Conn
+	lagging atomic.Bool
+}
+
+// replicaSet balances reads over read replicas and takes replicas out of the
+// rotation as long as they lag behind the primary by more than maxLag
+type replicaSet struct {
+	primary  *Conn
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint32
+
+	quitCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// ReplicaHeartbeatInterval is how often the heartbeat row on the primary is
+// updated, half the lag the replicas may have so a lagging replica is noticed
+// within its max lag
+func ReplicaHeartbeatInterval(conf cfg.DB) time.Duration {
+	maxLag := time.Duration(conf.MaxReplicaLag) * time.Second
+	if maxLag <= 0 {
+		maxLag = DefaultMaxReplicaLag
+	}
+	return maxLag / 2
+}
+
+func newReplicaSet(primary *Conn, conns []*Conn, maxLag time.Duration) *replicaSet {
+	if maxLag <= 0 {
+		maxLag = DefaultMaxReplicaLag
+	}
+	rs := &replicaSet{
+		primary: primary,
+		maxLag:  maxLag,
+		quitCh:  make(chan struct{}),
+	}
+	for _, conn := range conns {
+		rs.replicas = append(rs.replicas, &replica{conn: conn})
+	}
+	Prometheus.CounterInit(MetricReplicaLaggingCountKey, "replica lag checks which took a replica out of the rotation")
+	rs.checkLag()
+	go rs.monitor()
+	return rs
+}
+
+// Next returns the next replica in the rotation which is in sync with the
+// primary, or the primary if there is none
+func (rs *replicaSet) Next() *Conn {
+	n := uint32(len(rs.replicas))
+	start := rs.next.Add(1)
+	for i := uint32(0); i < n; i++ {
+		r := rs.replicas[(start+i)%n]
+		if !r.lagging.Load() {
+			return r.conn
+		}
+	}
+	return rs.primary
+}
+
+func (rs *replicaSet) monitor() {
+	ticker := time.NewTicker(replicaLagCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			rs.checkLag()
+		case <-rs.quitCh:
+			return
+		}
+	}
+}
+
+func (rs *replicaSet) checkLag() {
+	primaryTime, err := heartbeatTime(rs.primary)
+	if err != nil {
+		// without the primary there is nothing to compare to, keep the replicas
+		return
+	}
+	for _, r := range rs.replicas {
+		replicaTime, err := heartbeatTime(r.conn)
+		lagging := err != nil || primaryTime.Sub(replicaTime) > rs.maxLag
+		if lagging {
+			_ = Prometheus.CounterInc(MetricReplicaLaggingCountKey)
+		}
+		r.lagging.Store(lagging)
+	}
+}
+
+func (rs *replicaSet) Close() error {
+	rs.closeOnce.Do(func() { close(rs.quitCh) })
+
+	var err error
+	for _, r := range rs.replicas {
+		if cerr := r.conn.Close(context.Background()); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// heartbeatTime returns the last heartbeat of the stream indexer seen by the
+// database. Unlike the time of the newest block it keeps moving on an idle
+// chain, and it is the zero time as long as no indexer ran.
+func heartbeatTime(conn *Conn) (time.Time, error) {
+	sess, err := conn.NewSession("replica_lag", replicaLagQueryTimeout)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), replicaLagQueryTimeout)
+	defer cancelFn()
+
+	var updatedAt []sql.NullTime
+	_, err = sess.Select("updated_at").
+		From(replicaHeartbeatTable).
+		Where("id=?", ReplicaHeartbeatID).
+		LoadContext(ctx, &updatedAt)
+	if err != nil || len(updatedAt) == 0 || !updatedAt[0].Valid {
+		return time.Time{}, err
+	}
+	return updatedAt[0].Time, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+)
+
+func TestReplicaSetNext(t *testing.T) {
+	primary, r1, r2 := &Conn{}, &Conn{}, &Conn{}
+	rs := &replicaSet{
+		primary:  primary,
+		replicas: []*replica{{conn: r1}, {conn: r2}},
+	}
+
+	seen := map[*Conn]int{}
+	for i := 0; i < 4; i++ {
+		seen[rs.Next()]++
+	}
+	if seen[r1] != 2 || seen[r2] != 2 {
+		t.Fatal("Expected reads to be balanced over the replicas")
+	}
+
+	rs.replicas[0].lagging.Store(true)
+	for i := 0; i < 4; i++ {
+		if rs.Next() != r2 {
+			t.Fatal("Expected lagging replica to be skipped")
+		}
+	}
+
+	rs.replicas[1].lagging.Store(true)
+	if rs.Next() != primary {
+		t.Fatal("Expected fallback to the primary")
+	}
+}
+
+func TestHasReplicas(t *testing.T) {
+	conf := cfg.DB{DSN: "primary", RODSNs: []string{"primary"}}
+	if HasReplicas(conf) {
+		t.Fatal("Expected no replicas")
+	}
+	conf.RODSNs = append(conf.RODSNs, "replica")
+	if !HasReplicas(conf) {
+		t.Fatal("Expected replicas")
+	}
+}
+
+func TestReplicaHeartbeatInterval(t *testing.T) {
+	if interval := ReplicaHeartbeatInterval(cfg.DB{}); interval != DefaultMaxReplicaLag/2 {
+		t.Fatal("unexpected default interval", interval)
+	}
+	if interval := ReplicaHeartbeatInterval(cfg.DB{MaxReplicaLag: 30}); interval != 15*time.Second {
+		t.Fatal("unexpected interval", interval)
+	}
+}
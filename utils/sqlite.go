@@ -0,0 +1,94 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+// SqliteDriverName is the sql driver used for DriverSqlite, it registers the
+// mysql functions used by our queries on every new connection. The driver
+// needs cgo and is registered by the utils/sqlite package.
+const SqliteDriverName = "sqlite3_magellan"
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+// sqliteAddedColumns are added to the tables of databases created by an older
+// schema, which the create statements of the schema leave untouched
+var sqliteAddedColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"transactions_validator", "weight", "integer not null default 0"},
+	{"transactions_validator", "delegator", "smallint not null default 0"},
+	{"transactions_validator", "removed_at", "timestamp null"},
+	{"avm_outputs", "deposit_tx_id", "varchar(50) not null default ''"},
+	{"avm_outputs", "bond_tx_id", "varchar(50) not null default ''"},
+	{"cvm_transactions_txdata", "base_fee", "integer not null default 0"},
+	{"supply_history", "holders", "integer not null default 0"},
+}
+
+// createSqliteSchema brings an sqlite database to RequiredVersion. Sqlite is
+// meant for local development and tests, so instead of running the migrations
+// the embedded schema is applied as a whole.
+func createSqliteSchema(session *dbr.Session) error {
+	if version, err := SchemaVersion(session); err == nil && version >= RequiredVersion {
+		return nil
+	}
+
+	tx, err := session.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if _, err = tx.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("sqlite schema failed: %w", err)
+	}
+	if err = addSqliteColumns(tx); err != nil {
+		return fmt.Errorf("sqlite schema failed: %w", err)
+	}
+	if _, err = tx.DeleteFrom("schema_migrations").Exec(); err != nil {
+		return err
+	}
+	if _, err = tx.InsertInto("schema_migrations").
+		Pair("version", RequiredVersion).
+		Pair("dirty", false).
+		Exec(); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func addSqliteColumns(tx *dbr.Tx) error {
+	for _, c := range sqliteAddedColumns {
+		var count int
+		err := tx.QueryRow("SELECT count(*) FROM pragma_table_info(?) WHERE name = ?", c.table, c.column).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count != 0 {
+			continue
+		}
+		if _, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
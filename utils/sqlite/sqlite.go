@@ -0,0 +1,121 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package sqlite registers the sql driver used for utils.DriverSqlite. The
+// driver needs cgo, so it is only linked into the binaries that import this
+// package, magelland does so when it is built with the sqlite build tag.
+package sqlite
+
+import (
+	"database/sql"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chain4travel/magellan/utils"
+	"github.com/mattn/go-sqlite3"
+)
+
+func init() {
+	sql.Register(utils.SqliteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: registerSqliteFunctions,
+	})
+}
+
+func registerSqliteFunctions(conn *sqlite3.SQLiteConn) error {
+	functions := map[string]interface{}{
+		"unix_timestamp": sqliteUnixTimestamp,
+		"date_format":    sqliteDateFormat,
+		"floor":          sqliteFloor,
+		"greatest":       sqliteGreatest,
+	}
+	for name, impl := range functions {
+		if err := conn.RegisterFunc(name, impl, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sqliteTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case string:
+		for _, layout := range sqlite3.SQLiteTimestampFormats {
+			if tm, err := time.ParseInLocation(layout, t, time.UTC); err == nil {
+				return tm, true
+			}
+		}
+	case []byte:
+		return sqliteTime(string(t))
+	}
+	return time.Time{}, false
+}
+
+func sqliteFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	case []byte:
+		return sqliteFloat(string(n))
+	}
+	return 0, false
+}
+
+func sqliteUnixTimestamp(v interface{}) interface{} {
+	t, ok := sqliteTime(v)
+	if !ok {
+		return nil
+	}
+	return t.Unix()
+}
+
+func sqliteDateFormat(v interface{}, format string) interface{} {
+	t, ok := sqliteTime(v)
+	if !ok {
+		return nil
+	}
+	return t.Format(strings.NewReplacer("%Y", "2006", "%m", "01", "%d", "02").Replace(format))
+}
+
+func sqliteFloor(v interface{}) interface{} {
+	f, ok := sqliteFloat(v)
+	if !ok {
+		return nil
+	}
+	return int64(math.Floor(f))
+}
+
+func sqliteGreatest(values ...interface{}) interface{} {
+	var (
+		result interface{}
+		max    float64
+	)
+	for _, v := range values {
+		f, ok := sqliteFloat(v)
+		if !ok {
+			return nil
+		}
+		if result == nil || f > max {
+			result, max = v, f
+		}
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package sqlitetest provides SQLite databases for tests, so they run without
+// a database server.
+package sqlitetest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/utils"
+	"github.com/gocraft/dbr/v2"
+
+	// sqlite driver, registered as utils.SqliteDriverName
+	_ "github.com/chain4travel/magellan/utils/sqlite"
+)
+
+// Config returns the services config of a new SQLite database in a temporary
+// directory of the test
+func Config(t testing.TB) cfg.Services {
+	return cfg.Services{
+		DB: &cfg.DB{
+			Driver: utils.DriverSqlite,
+			DSN:    filepath.Join(t.TempDir(), "magellan.db"),
+		},
+	}
+}
+
+// New returns the connections to a new SQLite database with the schema in
+// place and a session on it. The connections are closed when the test ends.
+func New(t testing.TB) (*utils.Connections, *dbr.Session) {
+	conns, err := utils.NewDBFromConfig(Config(t), false)
+	if err != nil {
+		t.Fatal("db fail", err)
+	}
+	t.Cleanup(func() { _ = conns.Close() })
+
+	sess, err := conns.DB().NewSession("test", time.Minute)
+	if err != nil {
+		t.Fatal("session fail", err)
+	}
+	return conns, sess
+}
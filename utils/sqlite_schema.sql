@@ -0,0 +1,540 @@
+--
+-- Sqlite schema, equivalent to the mysql migrations up to and including
+-- RequiredVersion. It is applied by New whenever the recorded version is older,
+-- so it only creates missing tables and indexes and has to be kept in sync with
+-- new migrations.
+--
+
+create table if not exists schema_migrations
+(
+    version bigint  not null primary key,
+    dirty   boolean not null
+);
+
+--
+-- X-Chain
+--
+
+create table if not exists avm_assets
+(
+    id             varchar(50)  not null primary key,
+    chain_id       varchar(50)  not null,
+    name           varchar(255) not null,
+    symbol         varchar(255) not null,
+    alias          varchar(255) not null,
+    denomination   integer      not null,
+    created_at     timestamp    not null default current_timestamp,
+    current_supply integer      not null
+);
+
+create table if not exists avm_outputs
+(
+    id             varchar(50) not null primary key,
+    chain_id       varchar(50) not null,
+    transaction_id varchar(50) not null,
+    output_index   integer     not null,
+    asset_id       varchar(50) not null,
+    output_type    bigint      not null,
+    amount         integer     not null,
+    locktime       integer     not null,
+    threshold      bigint      not null,
+    group_id       integer     default 0,
+    payload        blob,
+    stake_locktime integer     not null default 0,
+    stake          boolean     default false,
+    frozen         boolean     default false,
+    stakeableout   boolean     default false,
+    genesisutxo    boolean     default false,
+    deposit_tx_id  varchar(50) not null default '',
+    bond_tx_id     varchar(50) not null default '',
+    created_at     timestamp   not null default current_timestamp
+);
+create index if not exists avm_outputs_chain_id_id on avm_outputs (chain_id, id);
+create unique index if not exists avm_outputs_tx_id_output_idx on avm_outputs (transaction_id, output_index);
+create index if not exists avm_outputs_asset_id_output_type on avm_outputs (asset_id, output_type);
+create index if not exists avm_outputs_chain_id_created_at on avm_outputs (chain_id, created_at desc);
+create index if not exists avm_outputs_chain_id_asset_id_created_at on avm_outputs (chain_id, asset_id, created_at desc);
+create index if not exists avm_outputs_transaction_id_asset_id_output_type on avm_outputs (transaction_id, asset_id, output_type);
+
+create table if not exists avm_output_addresses
+(
+    output_id           varchar(50) not null,
+    address             varchar(50) not null,
+    redeeming_signature blob        null,
+    created_at          timestamp   not null default current_timestamp,
+    updated_at          timestamp   not null default current_timestamp
+);
+create index if not exists avm_output_addresses_output_id on avm_output_addresses (output_id);
+create unique index if not exists avm_output_addresses_output_id_addr on avm_output_addresses (output_id, address);
+create index if not exists avm_output_addresses_address on avm_output_addresses (address);
+create index if not exists avm_output_addresses_created_at on avm_output_addresses (created_at);
+
+create table if not exists avm_outputs_redeeming
+(
+    id                       varchar(50) not null primary key,
+    redeemed_at              timestamp   not null default current_timestamp,
+    redeeming_transaction_id varchar(50) not null,
+    amount                   integer     not null,
+    output_index             bigint      not null,
+    intx                     varchar(50) default null,
+    asset_id                 varchar(50) default null,
+    chain_id                 varchar(50) default '',
+    created_at               timestamp   not null default current_timestamp
+);
+create index if not exists avm_outputs_redeeming_redeeming_transaction_id on avm_outputs_redeeming (redeeming_transaction_id);
+
+create table if not exists avm_transactions
+(
+    id                      varchar(50)  not null primary key,
+    chain_id                varchar(50)  not null,
+    type                    varchar(255) not null,
+    canonical_serialization blob,
+    memo                    blob,
+    txfee                   integer      default 0,
+    genesis                 boolean      default false,
+    network_id              bigint       default 0,
+    status                  smallint     not null default 0,
+    created_at              timestamp    not null default current_timestamp
+);
+create index if not exists avm_transactions_chain_id_created_at on avm_transactions (chain_id, created_at desc);
+create index if not exists avm_transactions_created_at_chain_id_idx on avm_transactions (created_at, chain_id);
+
+create table if not exists addresses
+(
+    address    varchar(50) not null primary key,
+    public_key blob        null,
+    created_at timestamp   not null default current_timestamp,
+    updated_at timestamp   not null default current_timestamp
+);
+
+create table if not exists address_chain
+(
+    address    varchar(50) not null,
+    chain_id   varchar(50) not null,
+    created_at timestamp   not null default current_timestamp,
+    updated_at timestamp   not null default current_timestamp,
+    primary key (address, chain_id)
+);
+
+create table if not exists addresses_bech32
+(
+    address        varchar(50)   not null primary key,
+    bech32_address varchar(100),
+    updated_at     timestamp     not null default current_timestamp
+);
+create index if not exists addresses_bech32_bech32_address on addresses_bech32 (bech32_address);
+
+create table if not exists transactions_epoch
+(
+    id         varchar(50) not null primary key,
+    epoch      integer     not null default 0,
+    vertex_id  varchar(50) default null,
+    created_at timestamp   not null default current_timestamp
+);
+create index if not exists transactions_epoch_vertex_id on transactions_epoch (vertex_id);
+
+--
+-- P-Chain
+--
+
+create table if not exists pvm_blocks
+(
+    id            varchar(50)  not null primary key,
+    type          smallint     not null,
+    parent_id     varchar(50)  not null,
+    chain_id      varchar(50)  not null,
+    serialization blob         not null,
+    height        integer      not null default 0,
+    proposer      varchar(50),
+    proposer_time timestamp    null,
+    created_at    timestamp    not null default current_timestamp
+);
+create index if not exists pvm_blocks_parent_id on pvm_blocks (parent_id);
+create index if not exists pvm_blocks_height on pvm_blocks (height);
+
+create table if not exists transactions_validator
+(
+    id         varchar(50) not null primary key,
+    node_id    varchar(50) default '',
+    start      integer     default 0,
+    "end"      integer     default 0,
+    weight     integer     not null default 0,
+    delegator  smallint    not null default 0,
+    removed_at timestamp   null,
+    created_at timestamp   not null default current_timestamp
+);
+create index if not exists transactions_validator_start_end on transactions_validator (start, "end");
+
+create table if not exists transactions_block
+(
+    id          varchar(50) not null primary key,
+    tx_block_id varchar(50) default '',
+    created_at  timestamp   not null default current_timestamp
+);
+
+create table if not exists multisig_aliases
+(
+    alias          varchar(35)  not null,
+    memo           varchar(256) not null,
+    owner          varchar(35)  not null,
+    transaction_id varchar(56)  not null,
+    created_at     timestamp    not null default current_timestamp,
+    primary key (alias, owner)
+);
+create index if not exists multisig_aliases_alias on multisig_aliases (alias);
+create index if not exists multisig_aliases_owner on multisig_aliases (owner);
+
+create table if not exists reward_owner
+(
+    address    varchar(50) not null,
+    hash       varchar(50) not null,
+    created_at timestamp   not null default current_timestamp,
+    primary key (address, hash)
+);
+create index if not exists reward_owner_address on reward_owner (address);
+create index if not exists reward_owner_hash on reward_owner (hash);
+
+create table if not exists reward
+(
+    reward_owner_bytes blob,
+    reward_owner_hash  varchar(50) not null,
+    tx_id              varchar(50) not null,
+    type               smallint    not null default 0,
+    updated_at         timestamp   not null default current_timestamp,
+    primary key (reward_owner_hash, tx_id, type)
+);
+create index if not exists reward_reward_owner_hash on reward (reward_owner_hash);
+
+--
+-- C-Chain
+--
+
+create table if not exists cvm_blocks
+(
+    block         integer      not null primary key,
+    hash          varchar(100) not null,
+    chain_id      varchar(50)  not null,
+    evm_tx        smallint     not null default 0,
+    atomic_tx     smallint     not null default 0,
+    serialization blob,
+    proposer      varchar(50),
+    proposer_time timestamp    null,
+    size          float        default 0,
+    created_at    timestamp    not null default current_timestamp
+);
+create index if not exists cvm_blocks_hash on cvm_blocks (hash);
+create index if not exists cvm_blocks_chain_id on cvm_blocks (chain_id);
+create index if not exists cvm_blocks_created_at on cvm_blocks (created_at);
+
+create table if not exists cvm_transactions_atomic
+(
+    transaction_id varchar(50) not null primary key,
+    block          integer     not null,
+    chain_id       varchar(50) not null,
+    type           smallint    not null,
+    created_at     timestamp   not null default current_timestamp
+);
+create index if not exists cvm_transactions_atomic_block on cvm_transactions_atomic (block);
+
+create table if not exists cvm_addresses
+(
+    id             varchar(50) not null primary key,
+    type           smallint    not null,
+    idx            bigint      not null,
+    transaction_id varchar(50) not null,
+    address        varchar(50) not null,
+    asset_id       varchar(50) not null,
+    amount         integer     not null default 0,
+    nonce          integer     not null default 0,
+    created_at     timestamp   not null default current_timestamp
+);
+create index if not exists cvm_address_transaction_id on cvm_addresses (transaction_id);
+
+create table if not exists cvm_accounts
+(
+    id          integer   not null primary key autoincrement,
+    address     char(42),
+    tx_count    integer   not null default 0,
+    creation_tx char(66)
+);
+create unique index if not exists cvm_accounts_address on cvm_accounts (address);
+
+create table if not exists cvm_transactions_txdata
+(
+    hash          varchar(100) not null primary key,
+    block         integer      not null,
+    idx           integer      not null,
+    id_from_addr  bigint       not null default 0,
+    id_to_addr    bigint       not null default 0,
+    nonce         integer      not null,
+    amount        integer      not null default 0,
+    status        integer      not null default 0,
+    gas_price     integer      not null default 0,
+    gas_used      bigint       not null default 0,
+    base_fee      integer      not null default 0,
+    serialization blob,
+    receipt       blob,
+    block_idx     integer      generated always as (block * 1000 + (999 - idx)) stored,
+    created_at    timestamp    not null default current_timestamp
+);
+create unique index if not exists cvm_transactions_txdata_block_idx on cvm_transactions_txdata (block_idx);
+create index if not exists cvm_transactions_txdata_created_at on cvm_transactions_txdata (created_at);
+create index if not exists ctx_id_from_addr on cvm_transactions_txdata (id_from_addr);
+create index if not exists ctx_id_to_addr on cvm_transactions_txdata (id_to_addr);
+
+create table if not exists cam_last_block_cache
+(
+    current_block integer     not null,
+    chainid       varchar(50) not null
+);
+
+--
+-- Balances
+--
+
+create table if not exists accumulate_balances_received
+(
+    id           varchar(50) not null primary key,
+    chain_id     varchar(50) not null,
+    asset_id     varchar(50) not null,
+    address      varchar(50) not null,
+    total_amount integer     not null default 0,
+    utxo_count   integer     not null default 0,
+    updated_at   timestamp   not null default current_timestamp
+);
+create index if not exists accumulate_balances_received_address_chain_id on accumulate_balances_received (address, chain_id);
+create index if not exists accumulate_balances_received_chain_id on accumulate_balances_received (chain_id);
+
+create table if not exists accumulate_balances_sent
+(
+    id           varchar(50) not null primary key,
+    chain_id     varchar(50) not null,
+    asset_id     varchar(50) not null,
+    address      varchar(50) not null,
+    total_amount integer     not null default 0,
+    utxo_count   integer     not null default 0,
+    updated_at   timestamp   not null default current_timestamp
+);
+
+create table if not exists accumulate_balances_transactions
+(
+    id                varchar(50) not null primary key,
+    chain_id          varchar(50) not null,
+    asset_id          varchar(50) not null,
+    address           varchar(50) not null,
+    transaction_count integer     not null default 0,
+    updated_at        timestamp   not null default current_timestamp
+);
+
+create table if not exists output_addresses_accumulate_out
+(
+    id             varchar(50) not null primary key,
+    output_id      varchar(50) not null,
+    address        varchar(50) not null,
+    processed      integer     not null default 0,
+    transaction_id varchar(50) not null default '',
+    output_index   integer     not null default 0,
+    created_at     timestamp   not null default current_timestamp
+);
+create index if not exists output_addresses_accumulate_out_processed on output_addresses_accumulate_out (processed asc, created_at asc);
+create index if not exists output_addresses_accumulate_out_output_id on output_addresses_accumulate_out (output_id asc);
+
+create table if not exists output_addresses_accumulate_in
+(
+    id               varchar(50) not null primary key,
+    output_id        varchar(50) not null,
+    address          varchar(50) not null,
+    processed        integer     not null default 0,
+    output_processed integer     not null default 0,
+    transaction_id   varchar(50) not null default '',
+    output_index     integer     not null default 0,
+    created_at       timestamp   not null default current_timestamp
+);
+create index if not exists output_addresses_accumulate_in_processed on output_addresses_accumulate_in (processed asc, created_at asc);
+create index if not exists output_addresses_accumulate_in_output_processed on output_addresses_accumulate_in (output_processed asc, processed asc, created_at asc);
+create index if not exists output_addresses_accumulate_in_output_id on output_addresses_accumulate_in (output_id asc);
+
+create table if not exists output_txs_accumulate
+(
+    id             varchar(50) not null primary key,
+    chain_id       varchar(50) not null,
+    asset_id       varchar(50) not null,
+    address        varchar(50) not null,
+    transaction_id varchar(50) not null,
+    processed      integer     not null default 0,
+    created_at     timestamp   not null default current_timestamp
+);
+create index if not exists output_txs_accumulate_processed on output_txs_accumulate (processed asc, created_at asc);
+
+--
+-- Infrastructure
+--
+
+create table if not exists tx_pool
+(
+    id            varchar(50)  not null primary key,
+    network_id    bigint       default 0,
+    chain_id      varchar(50)  not null,
+    msg_key       varchar(50)  not null,
+    serialization blob,
+    topic         varchar(100) not null,
+    created_at    timestamp    not null default current_timestamp
+);
+create index if not exists tx_pool_created_at on tx_pool (created_at asc);
+create index if not exists tx_pool_topic on tx_pool (topic);
+
+create table if not exists key_value_store
+(
+    k varchar(256)  not null primary key,
+    v varchar(1000) not null
+);
+
+create table if not exists node_index
+(
+    instance varchar(100) not null,
+    topic    varchar(100) not null,
+    idx      integer      default 0,
+    primary key (instance, topic)
+);
+
+--
+-- Statistics and aggregates
+--
+
+create table if not exists statistics
+(
+    date_at         date    not null primary key,
+    cvm_tx          integer default null,
+    avm_tx          integer default null,
+    token_transfer  float   default null,
+    gas_used        float   default null,
+    receive_count   integer default null,
+    send_count      integer default null,
+    active_accounts integer default null,
+    gas_price       float   default null,
+    blocks          integer default null,
+    avg_block_size  float   default null
+);
+
+create table if not exists aggregate_rollups_hourly
+(
+    chain_id          varchar(50) not null,
+    bucket_at         timestamp   not null,
+    transaction_count integer     not null default 0,
+    txfee             integer     not null default 0,
+    updated_at        timestamp   not null default current_timestamp,
+    primary key (chain_id, bucket_at)
+);
+
+create table if not exists aggregate_rollups_daily
+(
+    chain_id          varchar(50) not null,
+    bucket_at         timestamp   not null,
+    transaction_count integer     not null default 0,
+    txfee             integer     not null default 0,
+    updated_at        timestamp   not null default current_timestamp,
+    primary key (chain_id, bucket_at)
+);
+
+create table if not exists outbox_events
+(
+    id         varchar(50)  not null primary key,
+    chain_id   varchar(50)  not null,
+    tx_id      varchar(100) not null,
+    type       varchar(50)  not null,
+    payload    blob,
+    created_at timestamp    not null default current_timestamp
+);
+create index if not exists outbox_events_created_at on outbox_events (created_at asc);
+
+create table if not exists subnets
+(
+    id         varchar(50) not null primary key,
+    threshold  integer     not null default 0,
+    locktime   integer     not null default 0,
+    asset_id   varchar(50) not null default '',
+    created_at timestamp   not null default current_timestamp
+);
+
+create table if not exists subnet_owners
+(
+    subnet_id  varchar(50) not null,
+    address    varchar(50) not null,
+    created_at timestamp   not null default current_timestamp,
+    primary key (subnet_id, address)
+);
+create index if not exists subnet_owners_address on subnet_owners (address);
+
+create table if not exists blockchains
+(
+    id           varchar(50)   not null primary key,
+    subnet_id    varchar(50)   not null,
+    name         varchar(128)  not null default '',
+    vm_id        varchar(50)   not null,
+    fx_ids       varchar(1024) not null default '',
+    genesis_hash varchar(64)   not null default '',
+    created_at   timestamp     not null default current_timestamp
+);
+create index if not exists blockchains_subnet_id on blockchains (subnet_id);
+
+create table if not exists subnet_validators
+(
+    id         varchar(50) not null primary key,
+    subnet_id  varchar(50) not null,
+    node_id    varchar(50) not null,
+    weight     integer     not null default 0,
+    start      integer     not null default 0,
+    "end"      integer     not null default 0,
+    removed_at timestamp   null,
+    created_at timestamp   not null default current_timestamp
+);
+create index if not exists subnet_validators_subnet_id_node_id on subnet_validators (subnet_id, node_id);
+
+create table if not exists cross_chain_transfers
+(
+    id                   varchar(50) not null primary key,
+    export_tx_id         varchar(50) not null default '',
+    import_tx_id         varchar(50) not null default '',
+    source_chain_id      varchar(50) not null,
+    destination_chain_id varchar(50) not null,
+    asset_id             varchar(50) not null,
+    amount               integer     not null default 0,
+    exported_at          timestamp   null,
+    imported_at          timestamp   null,
+    created_at           timestamp   not null default current_timestamp
+);
+create index if not exists cross_chain_transfers_export_tx_id on cross_chain_transfers (export_tx_id);
+create index if not exists cross_chain_transfers_import_tx_id on cross_chain_transfers (import_tx_id);
+create index if not exists cross_chain_transfers_exported_at on cross_chain_transfers (exported_at);
+
+create table if not exists supply_history
+(
+    asset_id   varchar(50) not null,
+    bucket_at  timestamp   not null,
+    total      integer     not null default 0,
+    locked     integer     not null default 0,
+    deposited  integer     not null default 0,
+    bonded     integer     not null default 0,
+    burned     integer     not null default 0,
+    genesis    integer     not null default 0,
+    holders    integer     not null default 0,
+    updated_at timestamp   not null default current_timestamp,
+    primary key (asset_id, bucket_at)
+);
+
+create table if not exists address_labels
+(
+    address    varchar(100) not null primary key,
+    name       varchar(100) not null,
+    category   varchar(32)  not null default '',
+    notes      text         not null default '',
+    created_at timestamp    not null default current_timestamp,
+    updated_at timestamp    not null default current_timestamp
+);
+create index if not exists address_labels_name on address_labels (name);
+
+create table if not exists replica_heartbeat
+(
+    id         smallint  not null primary key,
+    updated_at timestamp not null default current_timestamp
+);
@@ -135,3 +135,12 @@ func (m *LCache) Get(k string) ([]byte, bool) {
 	}
 	return v, found
 }
+
+// Flush removes all items
+func (m *LCache) Flush() {
+	for _, bm := range m.buckets {
+		bm.l.Lock()
+		bm.m = make(map[string]*item)
+		bm.l.Unlock()
+	}
+}
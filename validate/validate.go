@@ -0,0 +1,281 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/chain4travel/magellan/cfg"
+	"github.com/chain4travel/magellan/discovery"
+	"github.com/chain4travel/magellan/models"
+	"github.com/chain4travel/magellan/utils"
+)
+
+const (
+	StatusOK      = "ok"
+	StatusWarning = "warning"
+	StatusError   = "error"
+	StatusSkipped = "skipped"
+
+	requestTimeout = 10 * time.Second
+
+	// geoIPProbe is the address looked up to test the geoIP endpoint
+	geoIPProbe = "1.1.1.1"
+)
+
+// Check is the result of one check of the config
+type Check struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Report lists the checks of a config file, it is valid if none of them
+// failed
+type Report struct {
+	File   string   `json:"file"`
+	Valid  bool     `json:"valid"`
+	Checks []*Check `json:"checks"`
+}
+
+func (r *Report) add(name string, status string, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, &Check{Name: name, Status: status, Message: fmt.Sprintf(format, args...)})
+	if status == StatusError {
+		r.Valid = false
+	}
+}
+
+// Validate loads the config file and checks it against the node, the
+// databases and the external services it names
+func Validate(ctx context.Context, path string) *Report {
+	r := &Report{File: path, Valid: true}
+	conf, err := cfg.NewFromFile(path)
+	if err != nil {
+		r.add("config", StatusError, "%v", err)
+		return r
+	}
+	r.add("config", StatusOK, "network %d", conf.NetworkID)
+
+	r.checkChains(ctx, conf)
+	r.checkDB(conf)
+	r.checkEndpoints(ctx, conf)
+	return r
+}
+
+func (r *Report) checkChains(ctx context.Context, conf *cfg.Config) {
+	if len(conf.Chains) == 0 {
+		r.add("chains", StatusWarning, "no chains configured")
+	}
+
+	// the P-chain needs no lookup, chains without a valid id and vm type are
+	// not looked up on the node
+	chainIDs := make([]string, 0, len(conf.Chains))
+	for id := range conf.Chains {
+		chainIDs = append(chainIDs, id)
+	}
+	sort.Strings(chainIDs)
+	var valid []cfg.Chain
+	for _, id := range chainIDs {
+		chain := conf.Chains[id]
+		name := "chains." + id
+		if _, err := ids.FromString(id); err != nil {
+			r.add(name, StatusError, "invalid chain id: %v", err)
+			continue
+		}
+		switch chain.VMType {
+		case models.PVMName:
+			if id != constants.PlatformChainID.String() {
+				r.add(name, StatusError, "the P-chain is %s", constants.PlatformChainID)
+				continue
+			}
+			r.add(name, StatusOK, "%s", chain.VMType)
+		case models.AVMName, models.CVMName:
+			valid = append(valid, chain)
+		default:
+			r.add(name, StatusError, "unknown vmType %q, expected %s, %s or %s", chain.VMType, models.AVMName, models.PVMName, models.CVMName)
+		}
+	}
+
+	urls := conf.NodeURLs()
+	if len(urls) == 0 {
+		r.add("node", StatusSkipped, "no caminoNode configured")
+		return
+	}
+	for _, url := range urls {
+		name := "node." + url
+		nodeCtx, cancelFn := context.WithTimeout(ctx, requestTimeout)
+		networkID, err := info.NewClient(url).GetNetworkID(nodeCtx)
+		cancelFn()
+		switch {
+		case err != nil:
+			r.add(name, StatusError, "unreachable: %v", err)
+		case networkID != conf.NetworkID:
+			r.add(name, StatusError, "node runs network %d, configured is %d", networkID, conf.NetworkID)
+		default:
+			r.add(name, StatusOK, "network %d", networkID)
+		}
+	}
+
+	// only the node source is used, it needs no services
+	source, err := discovery.NewSource(nil, cfg.Config{
+		CaminoNode:  conf.CaminoNode,
+		CaminoNodes: conf.CaminoNodes,
+		Discovery:   cfg.Discovery{Source: discovery.SourceNode},
+	})
+	if err != nil {
+		r.add("node.chains", StatusError, "%v", err)
+		return
+	}
+	nodeCtx, cancelFn := context.WithTimeout(ctx, requestTimeout)
+	defer cancelFn()
+	nodeChains, err := source.Chains(nodeCtx)
+	if err != nil {
+		r.add("node.chains", StatusError, "listing the blockchains failed: %v", err)
+		return
+	}
+	byID := make(map[string]discovery.Chain, len(nodeChains))
+	for _, chain := range nodeChains {
+		byID[chain.ID] = chain
+	}
+
+	for _, chain := range valid {
+		name := "chains." + chain.ID
+		nodeChain, ok := byID[chain.ID]
+		switch {
+		case !ok:
+			r.add(name, StatusError, "the node does not run the chain")
+		case nodeChain.VMType() != chain.VMType:
+			r.add(name, StatusError, "vmType is %s, the node runs the chain %s with vm %s", chain.VMType, nodeChain.Name, nodeChain.VMID)
+		default:
+			r.add(name, StatusOK, "%s %s", chain.VMType, nodeChain.Name)
+		}
+	}
+}
+
+func (r *Report) checkDB(conf *cfg.Config) {
+	if conf.DB == nil || conf.DB.Driver == utils.DriverNone {
+		r.add("db", StatusError, "no driver configured")
+		return
+	}
+
+	conn, err := utils.New(&utils.EventRcvr{}, *conf.DB, false)
+	switch {
+	case errors.Is(err, utils.ErrMigrationRequired):
+		r.add("db", StatusError, "%v", err)
+		return
+	case err != nil:
+		r.add("db", StatusError, "connection failed: %v", err)
+		return
+	}
+	defer conn.Close(context.Background())
+
+	sess, err := conn.NewSession("validate", cfg.DBTimeout)
+	if err != nil {
+		r.add("db", StatusError, "%v", err)
+		return
+	}
+	version, err := utils.SchemaVersion(sess)
+	switch {
+	case err != nil:
+		r.add("db", StatusError, "reading the schema version failed, the migrations have to be run: %v", err)
+	case version > utils.RequiredVersion:
+		r.add("db", StatusWarning, "schema version %d is newer than the %d of this binary", version, utils.RequiredVersion)
+	default:
+		r.add("db", StatusOK, "%s, schema version %d", conf.DB.Driver, version)
+	}
+
+	// the read only connections only need to answer
+	for i, dsn := range conf.DB.RODSNs {
+		if dsn == conf.DB.DSN {
+			continue
+		}
+		name := fmt.Sprintf("db.ro_dsns.%d", i)
+		roConf := *conf.DB
+		roConf.RODSN = dsn
+		roConn, err := utils.New(&utils.EventRcvr{}, roConf, true)
+		if err != nil {
+			r.add(name, StatusError, "connection failed: %v", err)
+			continue
+		}
+		_ = roConn.Close(context.Background())
+		r.add(name, StatusOK, "")
+	}
+}
+
+func (r *Report) checkEndpoints(ctx context.Context, conf *cfg.Config) {
+	// the requests the api makes
+	geoIP := conf.Services.GeoIP
+	if geoIP.URLEndpoint == "" {
+		r.add("services.geoIP", StatusSkipped, "no urlEndpoint configured")
+	} else {
+		r.checkEndpoint(ctx, "services.geoIP", geoIP, fmt.Sprintf("%s%s?key=%s", geoIP.URLEndpoint, geoIPProbe, geoIP.AuthorizationToken), false)
+	}
+
+	inmutable := conf.Services.InmutableInsights
+	if inmutable.URLEndpoint == "" {
+		r.add("services.inmutableInsights", StatusSkipped, "no urlEndpoint configured")
+	} else {
+		r.checkEndpoint(ctx, "services.inmutableInsights", inmutable, inmutable.URLEndpoint+"/misc/accessible_chains", true)
+	}
+}
+
+func (r *Report) checkEndpoint(ctx context.Context, name string, service cfg.EndpointService, target string, authorizationHeader bool) {
+	ctx, cancelFn := context.WithTimeout(ctx, requestTimeout)
+	defer cancelFn()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		r.add(name, StatusError, "invalid urlEndpoint %s: %v", service.URLEndpoint, redactURL(err))
+		return
+	}
+	if authorizationHeader {
+		req.Header.Add("Authorization", service.AuthorizationToken)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		r.add(name, StatusError, "%s unreachable: %v", service.URLEndpoint, redactURL(err))
+		return
+	}
+	_ = res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden:
+		r.add(name, StatusError, "authorizationToken rejected with status %d", res.StatusCode)
+	case res.StatusCode >= http.StatusBadRequest:
+		r.add(name, StatusError, "status %d", res.StatusCode)
+	case service.AuthorizationToken == "":
+		r.add(name, StatusWarning, "reachable, but no authorizationToken configured")
+	default:
+		r.add(name, StatusOK, "status %d", res.StatusCode)
+	}
+}
+
+// redactURL drops the url of the request from err, it may carry the token in
+// its query
+func redactURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2022, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	// sqlite driver, registered as utils.SqliteDriverName
+	_ "github.com/chain4travel/magellan/utils/sqlite"
+)
+
+func TestValidate(t *testing.T) {
+	geoIP := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "geo" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer geoIP.Close()
+	inmutable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "inmutable" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer inmutable.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	write := func(geoIPToken string) {
+		config := fmt.Sprintf(`{
+			"networkID": 12345,
+			"chains": {
+				"11111111111111111111111111111111LpoYY": {"id": "11111111111111111111111111111111LpoYY", "vmType": "pvm"},
+				"2JVSBoinj9C2J33VntvzYtVJNZdN2NKiwwKjcumHUWEb5DbBrm": {"id": "2JVSBoinj9C2J33VntvzYtVJNZdN2NKiwwKjcumHUWEb5DbBrm", "vmType": "evm"}
+			},
+			"services": {
+				"db": {"driver": "sqlite3", "dsn": %q},
+				"geoIP": {"urlEndpoint": %q, "authorizationToken": %q},
+				"inmutableInsights": {"urlEndpoint": %q, "authorizationToken": "inmutable"}
+			}
+		}`, filepath.Join(dir, "magellan.db"), geoIP.URL+"/", geoIPToken, inmutable.URL)
+		if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("geo")
+	r := Validate(context.Background(), path)
+	statuses := make(map[string]string, len(r.Checks))
+	for _, check := range r.Checks {
+		statuses[check.Name] = check.Status
+	}
+	expected := map[string]string{
+		"config": StatusOK,
+		"chains.11111111111111111111111111111111LpoYY":              StatusOK,
+		"chains.2JVSBoinj9C2J33VntvzYtVJNZdN2NKiwwKjcumHUWEb5DbBrm": StatusError,
+		"node":                       StatusSkipped,
+		"db":                         StatusOK,
+		"services.geoIP":             StatusOK,
+		"services.inmutableInsights": StatusOK,
+	}
+	for name, status := range expected {
+		if statuses[name] != status {
+			t.Fatal("unexpected check", name, statuses[name], r.Checks)
+		}
+	}
+	if r.Valid {
+		t.Fatal("report with errors is valid")
+	}
+
+	write("wrong")
+	r = Validate(context.Background(), path)
+	for _, check := range r.Checks {
+		if check.Name == "services.geoIP" && check.Status != StatusError {
+			t.Fatal("rejected token not reported", check)
+		}
+	}
+
+	// the token in the query of an unreachable endpoint isn't reported
+	geoIP.Close()
+	write("geo")
+	r = Validate(context.Background(), path)
+	for _, check := range r.Checks {
+		if check.Name == "services.geoIP" && (check.Status != StatusError || strings.Contains(check.Message, "key=geo")) {
+			t.Fatal("unexpected check", check)
+		}
+	}
+
+	r = Validate(context.Background(), filepath.Join(dir, "missing.json"))
+	if r.Valid || len(r.Checks) != 1 || r.Checks[0].Name != "config" {
+		t.Fatal("unexpected report", r.Checks)
+	}
+}